@@ -24,35 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/analytics/exercises/{id}/progress": {
             "get": {
-                "description": "Check if the API service is running and healthy",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "Service is healthy",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "/register": {
-            "post": {
-                "description": "Create a new user account with username, email, and password",
+                ],
+                "description": "Estimated 1RM, top weight, volume, distance and best pace for one exercise per period in the user's time zone",
                 "consumes": [
                     "application/json"
                 ],
@@ -60,35 +39,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Analytics"
                 ],
-                "summary": "Register a new user",
+                "summary": "Get exercise progress",
                 "parameters": [
                     {
-                        "description": "User registration data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.registerUserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size: day, week (default) or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day to include (YYYY-MM-DD), defaults to the start of the bucket 30 days, 12 weeks or 12 months back",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day to include (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Progress time series",
                         "schema": {
-                            "$ref": "#/definitions/api.UserResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.ExerciseProgressPoint"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "User already exists",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise not found",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -102,9 +112,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokens/auth": {
-            "post": {
-                "description": "Authenticate a user with username and password and return an auth token",
+        "/analytics/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aggregate volume, session count, duration, calories, cardio distance and per-muscle-group volume per week or month in the user's time zone",
                 "consumes": [
                     "application/json"
                 ],
@@ -112,35 +127,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Analytics"
                 ],
-                "summary": "Authenticate user",
+                "summary": "Get training summary",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.createTokenRequest"
-                        }
+                        "type": "string",
+                        "description": "Bucket size: day, week (default) or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day to include (YYYY-MM-DD), defaults to the start of the bucket 30 days, 12 weeks or 12 months back",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day to include (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Authentication successful",
+                        "description": "Training summary",
                         "schema": {
-                            "$ref": "#/definitions/api.TokenResponse"
+                            "$ref": "#/definitions/store.AnalyticsSummary"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid username or password",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -154,14 +184,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/athletes/{id}/workouts": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a user's information by their username",
+                "description": "List the workouts of an athlete who has granted the authenticated user coaching access, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -169,27 +199,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Coaching"
                 ],
-                "summary": "Get user by username",
+                "summary": "Get an athlete's workouts",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Username to search for",
-                        "name": "username",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Athlete user ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User found",
+                        "description": "Athlete's workouts",
                         "schema": {
-                            "$ref": "#/definitions/api.UserResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.WorkoutResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request parameters",
+                        "description": "Invalid athlete ID",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -200,8 +239,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "403": {
+                        "description": "Forbidden - not the athlete's coach",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -213,16 +252,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all users in the system",
+                "description": "Create a workout owned by an athlete who has granted the authenticated user read_write coaching access",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,17 +267,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Coaching"
+                ],
+                "summary": "Plan a workout for an athlete",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Athlete user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workout data",
+                        "name": "workout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.Workout"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
-                    "200": {
-                        "description": "List of users",
+                    "201": {
+                        "description": "Workout created for the athlete",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.UserResponse"
-                            }
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "401": {
@@ -249,8 +313,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "No users found",
+                    "403": {
+                        "description": "Forbidden - no write access to the athlete",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -264,14 +328,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
-            "put": {
+        "/body-measurements": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing user's username, email, and password",
+                "description": "List the authenticated user's body measurements, newest first. from and to are inclusive dates in the user's time zone.",
                 "consumes": [
                     "application/json"
                 ],
@@ -279,36 +343,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Body Measurements"
                 ],
-                "summary": "Update user information",
+                "summary": "List body measurements",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated user data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.registerUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User updated successfully",
+                        "description": "Measurements",
                         "schema": {
-                            "$ref": "#/definitions/api.UserResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.BodyMeasurementResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -319,12 +388,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -333,13 +396,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a user account by ID",
+                "description": "Record body weight, body fat percentage and circumferences, in the request's unit system, for the authenticated user. Omitted values are not measured, but at least one is required. The latest weight is used for calorie estimates and for volume of bodyweight exercises.",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,24 +410,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Body Measurements"
                 ],
-                "summary": "Delete user",
+                "summary": "Record a body measurement",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Measurement",
+                        "name": "measurement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.BodyMeasurementRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User deleted successfully"
+                    "201": {
+                        "description": "Measurement recorded",
+                        "schema": {
+                            "$ref": "#/definitions/api.BodyMeasurementResponse"
+                        }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid measurement",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -375,12 +449,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -390,14 +458,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/workouts": {
+        "/body-measurements/series": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all workouts in the system",
+                "description": "Return every measurement of one metric in a date range, oldest first, each with the mean of the measurements in the window days ending at it. from and to are inclusive dates in the user's time zone; the default range is the last 90 days.",
                 "consumes": [
                     "application/json"
                 ],
@@ -405,18 +473,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workouts"
+                    "Body Measurements"
                 ],
-                "summary": "Get all workouts",
-                "responses": {
-                    "200": {
-                        "description": "List of workouts",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api.WorkoutResponse"
-                            }
-                        }
+                "summary": "Get a body measurement series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "weight, body_fat_percent, neck, chest, waist, hips, arm, thigh or calf",
+                        "name": "metric",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Moving average window in days (default 7, max 365)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series",
+                        "schema": {
+                            "$ref": "#/definitions/api.MeasurementSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/body-measurements/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve one of the authenticated user's body measurements",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Body Measurements"
+                ],
+                "summary": "Get body measurement by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Measurement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Measurement",
+                        "schema": {
+                            "$ref": "#/definitions/api.BodyMeasurementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid measurement ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -424,6 +589,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Measurement not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -432,13 +603,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new workout with exercises for the authenticated user",
+                "description": "Replace every value of one of the authenticated user's body measurements; omitted values are cleared",
                 "consumes": [
                     "application/json"
                 ],
@@ -446,29 +617,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workouts"
+                    "Body Measurements"
                 ],
-                "summary": "Create a new workout",
+                "summary": "Update body measurement",
                 "parameters": [
                     {
-                        "description": "Workout data",
-                        "name": "workout",
+                        "type": "integer",
+                        "description": "Measurement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Measurement",
+                        "name": "measurement",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/store.Workout"
+                            "$ref": "#/definitions/api.BodyMeasurementRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Workout created successfully",
+                    "200": {
+                        "description": "Measurement updated",
                         "schema": {
-                            "$ref": "#/definitions/api.WorkoutResponse"
+                            "$ref": "#/definitions/api.BodyMeasurementResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid measurement",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -479,6 +663,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Measurement not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -486,16 +676,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/workouts/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a specific workout and its exercises by workout ID",
+                "description": "Delete one of the authenticated user's body measurements",
                 "consumes": [
                     "application/json"
                 ],
@@ -503,27 +691,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workouts"
+                    "Body Measurements"
                 ],
-                "summary": "Get workout by ID",
+                "summary": "Delete body measurement",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Workout ID",
+                        "description": "Measurement ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Workout details",
+                    "204": {
+                        "description": "Measurement deleted"
+                    },
+                    "400": {
+                        "description": "Invalid measurement ID",
                         "schema": {
-                            "$ref": "#/definitions/api.WorkoutResponse"
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Measurement not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/coaching/invitations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invite a user to become your coach or your athlete. The relationship grants the coach read or read_write access to the athlete's workouts once the invited user accepts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coaching"
+                ],
+                "summary": "Invite a coach or athlete",
+                "parameters": [
+                    {
+                        "description": "Invitation",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.inviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invitation created",
+                        "schema": {
+                            "$ref": "#/definitions/store.CoachRelationship"
                         }
                     },
                     "400": {
-                        "description": "Invalid workout ID",
+                        "description": "Invalid request payload",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -535,7 +783,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Workout not found",
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Relationship already exists",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -547,14 +801,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/coaching/relationships": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List pending invitations and accepted relationships where the authenticated user is the coach or the athlete",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coaching"
+                ],
+                "summary": "List coaching relationships",
+                "responses": {
+                    "200": {
+                        "description": "Coaching relationships",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.CoachRelationship"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/coaching/relationships/{id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing workout and its exercises (only by the owner)",
+                "description": "Change the access a coach has to your workouts (only by the athlete)",
                 "consumes": [
                     "application/json"
                 ],
@@ -562,36 +861,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workouts"
+                    "Coaching"
                 ],
-                "summary": "Update workout",
+                "summary": "Change coach permission",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Workout ID",
+                        "description": "Relationship ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated workout data",
-                        "name": "workout",
+                        "description": "New permission",
+                        "name": "permission",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/store.Workout"
+                            "$ref": "#/definitions/api.updatePermissionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Workout updated successfully",
+                        "description": "Updated relationship",
                         "schema": {
-                            "$ref": "#/definitions/api.WorkoutResponse"
+                            "$ref": "#/definitions/store.CoachRelationship"
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid request payload",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -603,13 +902,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - not the owner",
+                        "description": "Forbidden - not the athlete",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Workout not found",
+                        "description": "Relationship not found",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -628,7 +927,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a workout by ID (only by the owner)",
+                "description": "Decline or cancel a pending invitation, or end an accepted relationship. Either the coach or the athlete may do this.",
                 "consumes": [
                     "application/json"
                 ],
@@ -636,13 +935,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workouts"
+                    "Coaching"
                 ],
-                "summary": "Delete workout",
+                "summary": "End a coaching relationship",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Workout ID",
+                        "description": "Relationship ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -650,10 +949,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Workout deleted successfully"
+                        "description": "Relationship removed"
                     },
                     "400": {
-                        "description": "Invalid workout ID",
+                        "description": "Invalid relationship ID",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -664,14 +963,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - not the owner",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
                     "404": {
-                        "description": "Workout not found",
+                        "description": "Relationship not found",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -684,224 +977,7658 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "description": "Error message",
-                    "type": "string",
-                    "example": "Invalid request payload"
-                }
-            }
         },
-        "api.TokenResponse": {
-            "type": "object",
-            "properties": {
-                "auth_token": {
-                    "description": "JWT authentication token",
+        "/coaching/relationships/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept a pending coaching invitation sent to the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coaching"
+                ],
+                "summary": "Accept an invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Relationship ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Accepted relationship",
+                        "schema": {
+                            "$ref": "#/definitions/store.CoachRelationship"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid relationship ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the invited user",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Relationship not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation already accepted",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edit one of your own comments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Edit a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New comment text",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.commentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated comment",
+                        "schema": {
+                            "$ref": "#/definitions/store.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the author",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a comment. Authors can delete their own comments and workout owners can remove any comment on their workouts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Delete a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Comment deleted"
+                    },
+                    "400": {
+                        "description": "Invalid comment ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the author or workout owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrollments/{id}/adherence": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Report how many of the sessions due so far were completed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Get program adherence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Enrollment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Adherence report",
+                        "schema": {
+                            "$ref": "#/definitions/store.Adherence"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid enrollment ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Enrollment not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream of workout.created, workout.updated, workout.deleted, workout.restored and rest timer events for the authenticated user. Workouts are sent in the connection's unit system. Send Last-Event-ID (or last_event_id) to resume; a reset event means events were missed and data should be refetched. Comment lines are sent as heartbeats.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream workout events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alternative to the Last-Event-ID header",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Last-Event-ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exercises": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the exercise catalog with muscle groups and categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "List exercises",
+                "responses": {
+                    "200": {
+                        "description": "Exercise catalog",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Exercise"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exercises/{id}/progression": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the authenticated user's progression rules for an exercise, or the defaults (default is true) when none are saved. The increment is in the request's unit system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Get progression rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rules",
+                        "schema": {
+                            "$ref": "#/definitions/store.ProgressionRules"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid exercise ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the authenticated user's progression rules for an exercise. scheme is double (add reps from rep_min to rep_max, then weight), linear (add weight whenever every set reaches rep_max) or rpe (set the weight so rep_max reps land at target_rpe). A deload of deload_percent is recommended after deload_after consecutive failed sessions. The increment is in the request's unit system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Set progression rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Rules",
+                        "name": "rules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/progression.Rules"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rules saved",
+                        "schema": {
+                            "$ref": "#/definitions/store.ProgressionRules"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rules",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the authenticated user's progression rules for an exercise so the defaults apply again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Reset progression rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Rules deleted"
+                    },
+                    "400": {
+                        "description": "Invalid exercise ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise or rules not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exercises/{id}/recommendation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recommend the weight, reps and sets for the next session of an exercise from the authenticated user's last completed sessions of it, following their progression rules for the exercise (double progression by default). A deload is recommended after repeated failed sessions at the same weight. New weights are rounded to a pair of the smallest standard plates (2.5 kg or 5 lb); action is start when there is no history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Get exercise recommendation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommendation",
+                        "schema": {
+                            "$ref": "#/definitions/store.ExerciseRecommendation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid exercise ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exercises/{id}/rest": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the authenticated user's rest between sets of an exercise, or the default of 120 seconds (default is true) when none is saved. Entries with a target_rest_seconds of their own override it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Get rest target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rest target",
+                        "schema": {
+                            "$ref": "#/definitions/store.RestTarget"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid exercise ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the authenticated user's rest between sets of an exercise, used by the rest timer and rest compliance for entries without a target_rest_seconds of their own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Set rest target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rest target",
+                        "name": "rest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RestTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rest target saved",
+                        "schema": {
+                            "$ref": "#/definitions/store.RestTarget"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rest target",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the authenticated user's rest target for an exercise so the default applies again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Reset rest target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Rest target deleted"
+                    },
+                    "400": {
+                        "description": "Invalid exercise ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exercise or rest target not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Completed public and followers-only workouts from users you follow, newest first, with like and comment counts. Use next_cursor to page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Get activity feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed page",
+                        "schema": {
+                            "$ref": "#/definitions/api.FeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor or limit",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the authenticated user's goals, oldest first, with current progress, percent complete and the projected completion date at the current rate, together with their current and longest training streaks in days and weeks. Streaks and recurring goal windows follow the user's time zone; weeks start on Monday.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "List goals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Goals and streaks",
+                        "schema": {
+                            "$ref": "#/definitions/api.GoalsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set a goal for the authenticated user: a number of workouts or a distance per week or month or by a deadline, or a weight to lift in one exercise. Progress is tracked from completed workouts in the user's time zone and updated as workouts are saved. Distances and weights are in the request's unit system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Create a goal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Goal",
+                        "name": "goal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.GoalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Goal created with its progress",
+                        "schema": {
+                            "$ref": "#/definitions/store.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid goal",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve one of the authenticated user's goals with its current progress",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Get goal by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Goal",
+                        "schema": {
+                            "$ref": "#/definitions/store.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid goal ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Goal not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete one of the authenticated user's goals",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Goals"
+                ],
+                "summary": "Delete goal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Goal deleted"
+                    },
+                    "400": {
+                        "description": "Invalid goal ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Goal not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API service is running and healthy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Service is healthy",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/plates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the authenticated user's bar weight and plates in the unit they are marked in, or a standard gym set for their units (default is true) when none is saved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plates"
+                ],
+                "summary": "Get plate inventory",
+                "responses": {
+                    "200": {
+                        "description": "Inventory",
+                        "schema": {
+                            "$ref": "#/definitions/store.PlateInventory"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the authenticated user's bar weight and plates. unit is kg or lb and applies to every weight in the inventory; each plate's count is every plate of that weight owned, and plates are loaded in pairs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plates"
+                ],
+                "summary": "Set plate inventory",
+                "parameters": [
+                    {
+                        "description": "Inventory",
+                        "name": "inventory",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.PlateInventory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inventory saved",
+                        "schema": {
+                            "$ref": "#/definitions/store.PlateInventory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid inventory",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/plates/calculate": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Load target from the authenticated user's plate inventory: the plates per side, heaviest first, for the heaviest weight up to target that the plates make, using the fewest plates, and a warm-up ramp of the empty bar and about 40, 60 and 80 percent of it. target and bar are in the request's unit system; the result is in the inventory's unit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plates"
+                ],
+                "summary": "Calculate plates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Weight to load",
+                        "name": "target",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Bar weight; defaults to the inventory's",
+                        "name": "bar",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Loadout and warm-up",
+                        "schema": {
+                            "$ref": "#/definitions/api.PlateCalculation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid weights",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all training programs owned by the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "List training programs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of programs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Program"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a program spanning N weeks with templates assigned to weekdays and weekly progression rules",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Create a training program",
+                "parameters": [
+                    {
+                        "description": "Program data",
+                        "name": "program",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.Program"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Program created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/store.Program"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a program with its days and progression rules (only by the owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Get training program by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Program details",
+                        "schema": {
+                            "$ref": "#/definitions/store.Program"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid program ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Program not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a program together with its enrollments and planned sessions (only by the owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Delete training program",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Program deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid program ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Program not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/{id}/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a program on the given date; every session is planned up front in the user's time zone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Enroll in a training program",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Enrollment options",
+                        "name": "enrollment",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.enrollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Enrollment created",
+                        "schema": {
+                            "$ref": "#/definitions/store.ProgramEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Program not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Create a new user account with username, email, and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.registerUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List planned sessions from active and completed program enrollments between two dates in the user's time zone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Get training schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), defaults to today",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), defaults to six days after from",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Planned sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.PlannedSession"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a planned session as done by linking one of the user's workouts to it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Complete a planned session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Planned session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workout to link",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.completeSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Session completed"
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Planned session or workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Session already has a workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/{id}/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a live workout from the session's template with the program's progression applied and link it to the session; the session is completed when the workout is finished",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Programs"
+                ],
+                "summary": "Start a planned session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Planned session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Active workout started for the session",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid session ID or workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Planned session not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Session already has a workout, or another workout is active",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shared/{token}": {
+            "get": {
+                "description": "Public, read-only view of a shared workout with personal data removed. Unknown, revoked and expired tokens all return 404.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "View a shared workout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shared workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.SharedWorkoutResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Share not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable a share link so it no longer resolves",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "Revoke a share link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Share ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Share revoked"
+                    },
+                    "400": {
+                        "description": "Invalid share ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Share not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the tags on the authenticated user's workouts with how many workouts carry each, most used first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "Tags",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all workout templates owned by the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "List workout templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of templates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.WorkoutTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a reusable workout template with ordered target entries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create a workout template",
+                "parameters": [
+                    {
+                        "description": "Template data",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutTemplate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Template created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a workout template and its entries (only by the owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get workout template by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template details",
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a template's details and entries (only by the owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Update workout template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated template data",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutTemplate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a workout template by ID (only by the owner). Templates used by a program cannot be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Delete workout template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Template deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid template ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Template is used by a program",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a live workout (status active) pre-filled with the template's exercises and targets, to be logged set by set and finished like one from POST /workouts/start. The response also recommends a weight and reps for each exercise with reps or a weight from the user's recent sessions and progression rules, as GET /exercises/{id}/recommendation does. With apply_recommendations=true the recommended weights and reps replace the template's targets for exercises with history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Start a workout from a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Use the recommended weights and reps as the workout's targets",
+                        "name": "apply_recommendations",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Workout started from template",
+                        "schema": {
+                            "$ref": "#/definitions/api.StartTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another workout is active",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/auth": {
+            "post": {
+                "description": "Authenticate a user with username and password and return an auth token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Authenticate user",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Authentication successful",
+                        "schema": {
+                            "$ref": "#/definitions/api.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a user's information by their username",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by username",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username to search for",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User found",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of all users in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.UserResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No users found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the users the authenticated user has blocked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "List blocked users",
+                "responses": {
+                    "200": {
+                        "description": "Blocked users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.UserSummary"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/records": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the current personal records for every exercise, or the full record history when history=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get personal records",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Return every record ever set instead of the current bests",
+                        "name": "history",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit history to a single exercise",
+                        "name": "exercise",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Personal records",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.PersonalRecord"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing user's username, email, and password. Omitted timezone, units and profile fields keep their current values",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.registerUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a user account by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/block": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Block a user. Follows and coaching relationships between you are removed and each of you stops seeing the other's workouts, likes and comments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Block a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User blocked"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a block. Follows removed by the block are not restored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Unblock a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User unblocked"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/follow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Follow a user to see their public and followers-only workouts in your feed. Following twice has no effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Follow a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Following"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop following a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Unfollow a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No longer following"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/followers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the users following a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "List followers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Followers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.UserSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/following": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the users a user follows",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "List followed users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Followed users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.UserSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every workout visible to the authenticated user: their own, their athletes', public ones and followers-only ones from users they follow. Repeat tag to only return workouts carrying every listed tag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Get all workouts",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only workouts with all of these tags",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of workouts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.WorkoutResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new workout with exercises for the authenticated user. When calories_burned is omitted it is estimated from MET values, entry durations and the owner's weight, with calories_source met.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Create a new workout",
+                "parameters": [
+                    {
+                        "description": "Workout data",
+                        "name": "workout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.Workout"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Workout created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/active": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the authenticated user's in-progress workout with its logged sets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Get active workout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No active workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download every workout, entry and logged set of the authenticated user, oldest first. csv has one row per set with a stable column layout (see README), with weight, distance and pace columns in the request's unit system after the metric ones; json is a single array and ndjson one workout per line, both in the WorkoutResponse shape. Trashed workouts are not exported.",
+                "produces": [
+                    "text/csv",
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Export workouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default), json or ndjson",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day to include (YYYY-MM-DD, user's time zone)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day to include (YYYY-MM-DD, user's time zone)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Prefix csv output with a UTF-8 byte order mark",
+                        "name": "bom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format, bom, units or date range",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a Strong or Hevy CSV export as multipart field file. The format is detected from the header unless format is given. Rows are grouped into completed workouts, exercise names are fuzzily mapped onto the exercise catalog and rows that cannot be read are reported by line number and skipped. With dry_run=true nothing is stored and the parsed workouts are returned for review; otherwise all workouts are stored in one transaction, skipping ones imported before. Timestamps are read in the user's time zone.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Import workouts",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Force a format: strong or hevy",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview without storing anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run preview",
+                        "schema": {
+                            "$ref": "#/definitions/api.ImportResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Import result",
+                        "schema": {
+                            "$ref": "#/definitions/api.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or unreadable file, unknown format or no workouts found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/import/track": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a GPX or TCX file as multipart field file. A completed workout is created with one entry for the sport, the recording's start and end times, its device-reported calories, and a track summary: distance, moving time, elevation gain, average speed and pace, heart rate and per-kilometre splits. The raw points are kept for GET /workouts/{id}/track.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Import a GPS track",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "GPX or TCX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workout title, defaults to the recording's name or the sport",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or unreadable file",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Recording already imported",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search over titles, exercise names, descriptions and entry notes of the workouts visible to the user, best matches first. Supports quoted phrases, OR and -term.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Search workouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranked matches",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.WorkoutSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query or invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an active workout stamped with its start time. Only one workout can be active per user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Start a live workout",
+                "parameters": [
+                    {
+                        "description": "Optional title, description and planned entries",
+                        "name": "workout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/store.Workout"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Workout started",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another workout is already active",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the authenticated user's deleted workouts that have not been purged yet, most recently deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "List trashed workouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trashed workouts with deleted_at set",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.WorkoutResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific workout and its exercises by workout ID. Workouts the caller may not see are reported as not found.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Get workout by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Workout details",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing workout and its exercises (by the owner or a coach with read_write access). Calories that were estimated, or sent as 0, are estimated again from the updated entries; estimated calories sent back unchanged stay estimated, and a different calories_burned is kept as the client's.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Update workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated workout data",
+                        "name": "workout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.Workout"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Workout updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or a coach with write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a workout to the trash (by the owner or a coach with read_write access). The owner can restore it until it is purged after the retention period.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Delete workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Workout moved to the trash"
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or a coach with write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the comments on a workout, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "List comments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comments",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.Comment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a comment to a workout you can see",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Comment on a workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.commentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Comment created",
+                        "schema": {
+                            "$ref": "#/definitions/store.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/entries/{entryId}/warmup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate the warm-up ramp for an entry's working weight from the workout owner's plate inventory and log it as warm-up sets ahead of the entry's working sets, replacing any warm-ups added before. Warm-up sets are marked warmup in set_log and do not count towards the entry's sets, reps, weight or rpe. target and bar are in the request's unit system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Add warm-up sets to an entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Weights to warm up for",
+                        "name": "warmup",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.WarmupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry with its warm-up sets",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid weights",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or a coach with write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout or entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/finish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark an active workout completed, stamping ended_at and computing its duration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Finish a live workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Finished workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or a coach with write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Workout is not active",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/likes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the users who liked a workout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "List likes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users who liked the workout",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.UserSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Like a workout you can see. Liking twice has no effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Like a workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Liked"
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove your like from a workout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Social"
+                ],
+                "summary": "Unlike a workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Like removed"
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move one of the authenticated user's workouts out of the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Restore workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not in your trash",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Restoring would leave two active workouts",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the immutable revisions recorded for every change to a workout, newest first, each with its actor, timestamp and a JSON diff against the previous revision. Only the owner and their coaches can see them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "List workout revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revisions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.WorkoutRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or their coach",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/revisions/{rev}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore the title, description, duration, calories, visibility, tags, groups and entries captured in a revision. The revert is recorded as a new revision.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Revert workout to a revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reverted workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID or revision",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or a coach with write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/samples": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the workout's samples in the upload format (start, offsets and metric arrays), downsampled to at most max_points by averaging equal time buckets. The summary is computed at full resolution: average and maximum of each metric, and for heart rate the recorded time (gaps over 30 seconds excluded) and time in each of five zones. Zones use the owner's maximum heart rate (or an estimate from their age) and, when set, their resting heart rate; they are null without either.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Get sensor samples",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of points (default 500, 0 for all)",
+                        "name": "max_points",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Samples and summary",
+                        "schema": {
+                            "$ref": "#/definitions/api.SamplesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store samples as parallel arrays: start, then either offsets (whole seconds from start, increasing) or interval_seconds (default 1), and any of heart_rate (bpm), power (watts) and cadence (rpm), one value or null per sample. At most 86400 samples per request; samples at an already stored time replace it. Unless the workout's calories were supplied, they are estimated from heart rate and the owner's weight, birth year and sex, with calories_source heart_rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Upload sensor samples",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Samples",
+                        "name": "samples",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/sensor.Series"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Updated workout",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid samples",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - no write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload too large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/sets": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a completed set in an active workout. The set is added to entry_id, or to the entry for exercise_name which is created if needed. Optional started_at and completed_at time the set, and a rest.started event is sent for the entry's rest target.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Log a set",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set performed",
+                        "name": "set",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/store.LoggedSet"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entry with its updated set log",
+                        "schema": {
+                            "$ref": "#/definitions/api.WorkoutEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner or a coach with write access",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Workout is not active",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/share": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an unguessable public link to a read-only view of the workout. The token is only returned once; links can be revoked and may expire.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "Share a workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional expiry",
+                        "name": "share",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.createShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Share created, including its token",
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutShare"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the share links created for a workout, including revoked and expired ones. Tokens are not shown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sharing"
+                ],
+                "summary": "List share links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Share links",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/store.WorkoutShare"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/template": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new template that repeats an existing workout's exercises (only by the owner)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Save workout as template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit system: metric or imperial, overriding the X-Units header and the user's preference",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Template created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/store.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workouts/{id}/track": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the route of a workout imported from a GPX or TCX file as a GeoJSON Feature with a LineString geometry ([longitude, latitude, elevation]). Per-point times and heart rates are in properties.coordinateProperties, and the track summary with splits in properties.summary. Indoor recordings without positions have a null geometry.",
+                "produces": [
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "Workouts"
+                ],
+                "summary": "Get workout track",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Workout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GeoJSON Feature",
+                        "schema": {
+                            "$ref": "#/definitions/track.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid workout ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Workout not found or has no track",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.BodyMeasurementRequest": {
+            "type": "object",
+            "properties": {
+                "arm": {
+                    "type": "number",
+                    "example": 38.5
+                },
+                "body_fat_percent": {
+                    "description": "Body fat percentage",
+                    "type": "number",
+                    "example": 18.5
+                },
+                "calf": {
+                    "type": "number",
+                    "example": 39
+                },
+                "chest": {
+                    "type": "number",
+                    "example": 104
+                },
+                "hips": {
+                    "type": "number",
+                    "example": 98
+                },
+                "measured_at": {
+                    "description": "Defaults to now",
+                    "type": "string",
+                    "example": "2024-01-15T07:30:00Z"
+                },
+                "neck": {
+                    "description": "Circumferences in cm or in",
+                    "type": "number",
+                    "example": 39
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Morning, fasted"
+                },
+                "thigh": {
+                    "type": "number",
+                    "example": 60
+                },
+                "waist": {
+                    "type": "number",
+                    "example": 84
+                },
+                "weight": {
+                    "description": "Body weight in kg or lb",
+                    "type": "number",
+                    "example": 82.4
+                }
+            }
+        },
+        "api.BodyMeasurementResponse": {
+            "type": "object",
+            "properties": {
+                "arm": {
+                    "type": "number",
+                    "example": 38.5
+                },
+                "body_fat_percent": {
+                    "type": "number",
+                    "example": 18.5
+                },
+                "calf": {
+                    "type": "number",
+                    "example": 39
+                },
+                "chest": {
+                    "type": "number",
+                    "example": 104
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T07:31:00Z"
+                },
+                "hips": {
+                    "type": "number",
+                    "example": 98
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "measured_at": {
+                    "type": "string",
+                    "example": "2024-01-15T07:30:00Z"
+                },
+                "neck": {
+                    "type": "number",
+                    "example": 39
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Morning, fasted"
+                },
+                "thigh": {
+                    "type": "number",
+                    "example": 60
+                },
+                "units": {
+                    "$ref": "#/definitions/api.MeasurementUnits"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T07:31:00Z"
+                },
+                "waist": {
+                    "type": "number",
+                    "example": 84
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 82.4
+                }
+            }
+        },
+        "api.EntryGroupResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries performed in this group",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.WorkoutEntryResponse"
+                    }
+                },
+                "id": {
+                    "description": "Group ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "order_index": {
+                    "description": "Order of the group in the workout",
+                    "type": "integer",
+                    "example": 0
+                },
+                "rest_between_exercises_seconds": {
+                    "description": "Rest between exercises within a round",
+                    "type": "integer",
+                    "example": 0
+                },
+                "rest_between_rounds_seconds": {
+                    "description": "Rest after each round",
+                    "type": "integer",
+                    "example": 90
+                },
+                "rounds": {
+                    "description": "Number of rounds through the group",
+                    "type": "integer",
+                    "example": 3
+                },
+                "type": {
+                    "description": "superset, circuit or giant_set",
+                    "type": "string",
+                    "example": "superset"
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error message",
+                    "type": "string",
+                    "example": "Invalid request payload"
+                }
+            }
+        },
+        "api.FeedResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "Workouts from followed users, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.FeedItem"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Pass as cursor to fetch the next page, null on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "api.GoalRequest": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "description": "Last day (YYYY-MM-DD) for one-off goals",
+                    "type": "string",
+                    "example": "2024-06-30"
+                },
+                "exercise_name": {
+                    "description": "Required for lift goals; limits distance goals to one exercise",
+                    "type": "string",
+                    "example": "Running"
+                },
+                "period": {
+                    "description": "week or month for goals that recur",
+                    "type": "string",
+                    "example": "month"
+                },
+                "target": {
+                    "description": "Workouts, km or mi, or kg or lb",
+                    "type": "number",
+                    "example": 50
+                },
+                "type": {
+                    "description": "workouts, distance or lift",
+                    "type": "string",
+                    "example": "distance"
+                }
+            }
+        },
+        "api.GoalsResponse": {
+            "type": "object",
+            "properties": {
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.Goal"
+                    }
+                },
+                "streaks": {
+                    "$ref": "#/definitions/store.Streaks"
+                }
+            }
+        },
+        "api.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Whether this was a preview",
+                    "type": "boolean",
+                    "example": true
+                },
+                "errors": {
+                    "description": "Rows that were skipped and why",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.RowError"
+                    }
+                },
+                "exercises": {
+                    "description": "How each exercise name was mapped",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.ExerciseMapping"
+                    }
+                },
+                "format": {
+                    "description": "Detected or requested format",
+                    "type": "string",
+                    "example": "strong"
+                },
+                "imported": {
+                    "description": "Workouts stored, 0 for a dry run",
+                    "type": "integer",
+                    "example": 84
+                },
+                "rows": {
+                    "description": "Data rows read from the file",
+                    "type": "integer",
+                    "example": 1250
+                },
+                "skipped_duplicates": {
+                    "description": "Workouts already imported before, 0 for a dry run",
+                    "type": "integer",
+                    "example": 0
+                },
+                "workout_count": {
+                    "description": "Workouts found in the file",
+                    "type": "integer",
+                    "example": 84
+                },
+                "workouts": {
+                    "description": "Parsed workouts, dry run only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.Workout"
+                    }
+                }
+            }
+        },
+        "api.MeasurementSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "metric": {
+                    "type": "string",
+                    "example": "weight"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.MeasurementPoint"
+                    }
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "kg"
+                },
+                "window_days": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "api.MeasurementUnits": {
+            "type": "object",
+            "properties": {
+                "length": {
+                    "description": "cm or in",
+                    "type": "string",
+                    "example": "cm"
+                },
+                "weight": {
+                    "description": "kg or lb",
+                    "type": "string",
+                    "example": "kg"
+                }
+            }
+        },
+        "api.PlateCalculation": {
+            "type": "object",
+            "properties": {
+                "loadout": {
+                    "$ref": "#/definitions/plates.Loadout"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "kg"
+                },
+                "warmup": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/plates.WarmupSet"
+                    }
+                }
+            }
+        },
+        "api.RestTargetRequest": {
+            "type": "object",
+            "properties": {
+                "rest_seconds": {
+                    "description": "0 to 3600; 0 turns the rest timer off",
+                    "type": "integer",
+                    "example": 180
+                }
+            }
+        },
+        "api.SamplesResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of stored samples before downsampling",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "samples": {
+                    "description": "Downsampled samples in the upload format",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sensor.Series"
+                        }
+                    ]
+                },
+                "summary": {
+                    "description": "Averages, maxima and heart-rate zones at full resolution",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/sensor.Summary"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.SharedEntryResponse": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "description": "Distance in km or mi",
+                    "type": "number",
+                    "example": 5
+                },
+                "distance_meters": {
+                    "description": "Distance covered",
+                    "type": "number",
+                    "example": 5000
+                },
+                "duration_seconds": {
+                    "description": "Duration in seconds",
+                    "type": "integer",
+                    "example": 60
+                },
+                "exercise_name": {
+                    "description": "Name of the exercise",
+                    "type": "string",
+                    "example": "Squat"
+                },
+                "group_index": {
+                    "description": "Index into groups, null when ungrouped",
+                    "type": "integer",
+                    "example": 0
+                },
+                "incline_percent": {
+                    "description": "Treadmill incline",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "intervals": {
+                    "description": "Work/rest repeats",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.Intervals"
+                        }
+                    ]
+                },
+                "pace_seconds": {
+                    "description": "Pace per km or mi",
+                    "type": "integer",
+                    "example": 300
+                },
+                "pace_seconds_per_km": {
+                    "description": "Pace",
+                    "type": "integer",
+                    "example": 300
+                },
+                "reps": {
+                    "description": "Number of repetitions",
+                    "type": "integer",
+                    "example": 5
+                },
+                "resistance_level": {
+                    "description": "Machine resistance setting",
+                    "type": "integer",
+                    "example": 6
+                },
+                "set_log": {
+                    "description": "Individually logged sets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SharedSetResponse"
+                    }
+                },
+                "sets": {
+                    "description": "Number of sets",
+                    "type": "integer",
+                    "example": 5
+                },
+                "weight": {
+                    "description": "Weight in kg or lb",
+                    "type": "number",
+                    "example": 120
+                }
+            }
+        },
+        "api.SharedGroupResponse": {
+            "type": "object",
+            "properties": {
+                "rest_between_exercises_seconds": {
+                    "description": "Rest between exercises",
+                    "type": "integer",
+                    "example": 0
+                },
+                "rest_between_rounds_seconds": {
+                    "description": "Rest after each round",
+                    "type": "integer",
+                    "example": 90
+                },
+                "rounds": {
+                    "description": "Number of rounds",
+                    "type": "integer",
+                    "example": 3
+                },
+                "type": {
+                    "description": "superset, circuit or giant_set",
+                    "type": "string",
+                    "example": "superset"
+                }
+            }
+        },
+        "api.SharedSetResponse": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "description": "Duration in seconds",
+                    "type": "integer",
+                    "example": 0
+                },
+                "reps": {
+                    "description": "Repetitions performed",
+                    "type": "integer",
+                    "example": 5
+                },
+                "set_number": {
+                    "description": "Position of the set within the entry",
+                    "type": "integer",
+                    "example": 1
+                },
+                "weight": {
+                    "description": "Weight in kg or lb",
+                    "type": "number",
+                    "example": 120
+                }
+            }
+        },
+        "api.SharedWorkoutResponse": {
+            "type": "object",
+            "properties": {
+                "calories_burned": {
+                    "description": "Calories burned",
+                    "type": "integer",
+                    "example": 450
+                },
+                "date": {
+                    "description": "Day the workout was performed",
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "description": {
+                    "description": "Workout description",
+                    "type": "string",
+                    "example": "Heavy squats"
+                },
+                "duration_minutes": {
+                    "description": "Duration in minutes",
+                    "type": "integer",
+                    "example": 60
+                },
+                "entries": {
+                    "description": "Exercises in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SharedEntryResponse"
+                    }
+                },
+                "groups": {
+                    "description": "Supersets, circuits and giant sets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SharedGroupResponse"
+                    }
+                },
+                "title": {
+                    "description": "Workout title",
+                    "type": "string",
+                    "example": "Leg Day"
+                }
+            }
+        },
+        "api.StartTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.ExerciseRecommendation"
+                    }
+                },
+                "workout": {
+                    "$ref": "#/definitions/api.WorkoutResponse"
+                }
+            }
+        },
+        "api.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "auth_token": {
+                    "description": "JWT authentication token",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "api.UserResponse": {
+            "type": "object",
+            "properties": {
+                "birth_year": {
+                    "description": "Used for age-based maximum heart rate and calorie estimates",
+                    "type": "integer",
+                    "example": 1990
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "email": {
+                    "description": "Email address",
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "id": {
+                    "description": "User ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_heart_rate": {
+                    "description": "Overrides the age-based estimate",
+                    "type": "integer",
+                    "example": 188
+                },
+                "resting_heart_rate": {
+                    "description": "Places heart-rate zones within the heart-rate reserve",
+                    "type": "integer",
+                    "example": 55
+                },
+                "sex": {
+                    "description": "male or female, used for calorie estimates",
+                    "type": "string",
+                    "example": "female"
+                },
+                "timezone": {
+                    "description": "IANA time zone",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "units": {
+                    "description": "metric or imperial",
+                    "type": "string",
+                    "example": "imperial"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "username": {
+                    "description": "Username",
+                    "type": "string",
+                    "example": "johndoe"
+                },
+                "weight_kg": {
+                    "description": "Body weight in kg",
+                    "type": "number",
+                    "example": 68.5
+                }
+            }
+        },
+        "api.WarmupRequest": {
+            "type": "object",
+            "properties": {
+                "bar": {
+                    "description": "Defaults to the inventory's bar weight",
+                    "type": "number",
+                    "example": 20
+                },
+                "target": {
+                    "description": "Working weight; defaults to the entry's weight",
+                    "type": "number",
+                    "example": 100
+                }
+            }
+        },
+        "api.WorkoutEntryResponse": {
+            "type": "object",
+            "properties": {
+                "distance_meters": {
+                    "description": "Distance in meters",
+                    "type": "number",
+                    "example": 5000
+                },
+                "duration_seconds": {
+                    "description": "Duration in seconds",
+                    "type": "integer",
+                    "example": 60
+                },
+                "exercise_name": {
+                    "description": "Name of the exercise",
+                    "type": "string",
+                    "example": "Push ups"
+                },
+                "group_index": {
+                    "description": "Index into the workout's groups, null when ungrouped",
+                    "type": "integer",
+                    "example": 0
+                },
+                "id": {
+                    "description": "Entry ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "incline_percent": {
+                    "description": "Treadmill incline, -20 to 40",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "intervals": {
+                    "description": "Work/rest repeats, null for a steady effort",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.Intervals"
+                        }
+                    ]
+                },
+                "notes": {
+                    "description": "Additional notes",
+                    "type": "string",
+                    "example": "Good form maintained"
+                },
+                "order_index": {
+                    "description": "Order of exercise in workout",
+                    "type": "integer",
+                    "example": 1
+                },
+                "pace_seconds_per_km": {
+                    "description": "Pace; derived from distance and duration when omitted",
+                    "type": "integer",
+                    "example": 300
+                },
+                "reps": {
+                    "description": "Number of repetitions",
+                    "type": "integer",
+                    "example": 15
+                },
+                "resistance_level": {
+                    "description": "Machine resistance setting, 0 to 100",
+                    "type": "integer",
+                    "example": 6
+                },
+                "rpe": {
+                    "description": "Rate of perceived exertion of the hardest set, 1 to 10",
+                    "type": "number",
+                    "example": 8.5
+                },
+                "set_log": {
+                    "description": "Sets logged during a live session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.WorkoutSetResponse"
+                    }
+                },
+                "sets": {
+                    "description": "Number of sets",
+                    "type": "integer",
+                    "example": 3
+                },
+                "target_rest_seconds": {
+                    "description": "Rest between sets, 0 to 3600; null uses the exercise's rest target",
+                    "type": "integer",
+                    "example": 90
+                },
+                "weight": {
+                    "description": "Weight in kg",
+                    "type": "number",
+                    "example": 75.5
+                }
+            }
+        },
+        "api.WorkoutResponse": {
+            "type": "object",
+            "properties": {
+                "calories_burned": {
+                    "description": "Calories burned",
+                    "type": "integer",
+                    "example": 350
+                },
+                "calories_source": {
+                    "description": "How calories were estimated: heart_rate or met; null when supplied by the client",
+                    "type": "string",
+                    "example": "met"
+                },
+                "created_at": {
+                    "description": "Creation timestamp",
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "description": {
+                    "description": "Workout description",
+                    "type": "string",
+                    "example": "High intensity cardio"
+                },
+                "duration_minutes": {
+                    "description": "Duration in minutes",
+                    "type": "integer",
+                    "example": 45
+                },
+                "ended_at": {
+                    "description": "When a live session was finished",
+                    "type": "string",
+                    "example": "2024-01-01T12:45:00Z"
+                },
+                "entries": {
+                    "description": "Flat list of workout exercises",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.WorkoutEntryResponse"
+                    }
+                },
+                "groups": {
+                    "description": "Supersets, circuits and giant sets with their entries nested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EntryGroupResponse"
+                    }
+                },
+                "id": {
+                    "description": "Workout ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "started_at": {
+                    "description": "When a live session was started",
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "status": {
+                    "description": "active while a live session is in progress, otherwise completed",
+                    "type": "string",
+                    "example": "completed"
+                },
+                "tags": {
+                    "description": "Lowercase tags; omit on update to keep the current ones",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "leg day",
+                        "deload"
+                    ]
+                },
+                "template_id": {
+                    "description": "Template the workout follows; completing it fulfils that day's planned session",
+                    "type": "integer",
+                    "example": 3
+                },
+                "title": {
+                    "description": "Workout title",
+                    "type": "string",
+                    "example": "Morning Cardio"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp",
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "user_id": {
+                    "description": "User ID who owns the workout",
+                    "type": "integer",
+                    "example": 1
+                },
+                "visibility": {
+                    "description": "private, followers or public",
+                    "type": "string",
+                    "example": "followers"
+                }
+            }
+        },
+        "api.WorkoutSetResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "When the set was completed, null for generated warm-up sets",
+                    "type": "string",
+                    "example": "2024-01-01T12:05:00Z"
+                },
+                "duration_seconds": {
+                    "description": "Duration in seconds",
+                    "type": "integer",
+                    "example": 30
+                },
+                "id": {
+                    "description": "Set ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "reps": {
+                    "description": "Repetitions performed",
+                    "type": "integer",
+                    "example": 8
+                },
+                "rest_seconds": {
+                    "description": "Rest since the entry's previous working set completed; null for warm-ups, the first working set and untimed sets",
+                    "type": "integer",
+                    "example": 95
+                },
+                "rpe": {
+                    "description": "Rate of perceived exertion, 1 to 10",
+                    "type": "number",
+                    "example": 8
+                },
+                "set_number": {
+                    "description": "Position of the set within the entry",
+                    "type": "integer",
+                    "example": 1
+                },
+                "started_at": {
+                    "description": "When the set began, null when it was not timed",
+                    "type": "string",
+                    "example": "2024-01-01T12:04:20Z"
+                },
+                "warmup": {
+                    "description": "Warm-up set, left out of the entry's summary",
+                    "type": "boolean",
+                    "example": false
+                },
+                "weight": {
+                    "description": "Weight in kg",
+                    "type": "number",
+                    "example": 100
+                }
+            }
+        },
+        "api.commentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "description": "Comment text, at most 2000 characters",
+                    "type": "string",
+                    "example": "Great session!"
+                }
+            }
+        },
+        "api.completeSessionRequest": {
+            "type": "object",
+            "required": [
+                "workout_id"
+            ],
+            "properties": {
+                "workout_id": {
+                    "description": "Workout that fulfilled the planned session",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "api.createShareRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Optional expiry, must be in the future",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00Z"
+                }
+            }
+        },
+        "api.createTokenRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "description": "Password for authentication",
+                    "type": "string",
+                    "example": "SecurePass123"
+                },
+                "username": {
+                    "description": "Username for authentication",
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "api.enrollRequest": {
+            "type": "object",
+            "properties": {
+                "start_date": {
+                    "description": "First day of the program (YYYY-MM-DD), defaults to today",
+                    "type": "string",
+                    "example": "2025-01-06"
+                }
+            }
+        },
+        "api.inviteRequest": {
+            "type": "object",
+            "required": [
+                "role",
+                "username"
+            ],
+            "properties": {
+                "permission": {
+                    "description": "read (default) or read_write",
+                    "type": "string",
+                    "example": "read"
+                },
+                "role": {
+                    "description": "Role of the invited user: coach or athlete",
+                    "type": "string",
+                    "example": "athlete"
+                },
+                "username": {
+                    "description": "User being invited",
+                    "type": "string",
+                    "example": "jane"
+                }
+            }
+        },
+        "api.registerUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "birth_year": {
+                    "type": "integer"
+                },
+                "email": {
+                    "description": "Email address for the new user",
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "max_heart_rate": {
+                    "description": "when nil, estimated from age",
+                    "type": "integer"
+                },
+                "password": {
+                    "description": "Password for the new user",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 8,
+                    "example": "SecurePass123"
+                },
+                "resting_heart_rate": {
+                    "type": "integer"
+                },
+                "sex": {
+                    "description": "male or female",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "IANA time zone used for analytics and scheduling (defaults to UTC)",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "units": {
+                    "description": "metric or imperial, the default units for reading and writing values (defaults to metric)",
+                    "type": "string",
+                    "example": "imperial"
+                },
+                "username": {
+                    "description": "Username for the new user",
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3,
+                    "example": "johndoe"
+                },
+                "weight_kg": {
+                    "description": "body weight in kg",
+                    "type": "number"
+                }
+            }
+        },
+        "api.updatePermissionRequest": {
+            "type": "object",
+            "required": [
+                "permission"
+            ],
+            "properties": {
+                "permission": {
+                    "description": "read or read_write",
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                    "example": "read_write"
+                }
+            }
+        },
+        "importer.ExerciseMapping": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "matched": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "sets": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.RowError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "plates.Loadout": {
+            "type": "object",
+            "properties": {
+                "bar": {
+                    "type": "number"
+                },
+                "missing": {
+                    "description": "Target minus Total, 0 when the target is loaded exactly",
+                    "type": "number"
+                },
+                "per_side": {
+                    "description": "heaviest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/plates.Plate"
+                    }
+                },
+                "target": {
+                    "type": "number"
+                },
+                "total": {
+                    "description": "the heaviest weight the inventory loads without going over Target",
+                    "type": "number"
+                }
+            }
+        },
+        "plates.Plate": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "plates.WarmupSet": {
+            "type": "object",
+            "properties": {
+                "bar": {
+                    "type": "number"
+                },
+                "missing": {
+                    "description": "Target minus Total, 0 when the target is loaded exactly",
+                    "type": "number"
+                },
+                "per_side": {
+                    "description": "heaviest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/plates.Plate"
+                    }
+                },
+                "percent": {
+                    "description": "of the working weight, as loaded",
+                    "type": "number"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "total": {
+                    "description": "the heaviest weight the inventory loads without going over Target",
+                    "type": "number"
+                }
+            }
+        },
+        "progression.Action": {
+            "type": "string",
+            "enum": [
+                "start",
+                "increase",
+                "repeat",
+                "decrease",
+                "deload"
+            ],
+            "x-enum-comments": {
+                "ActionDecrease": "less weight, for RPE autoregulation after a hard session",
+                "ActionDeload": "back off after repeated failed sessions",
+                "ActionIncrease": "add weight",
+                "ActionRepeat": "same weight, aiming for more reps or a clean session",
+                "ActionStart": "no history to progress from"
+            },
+            "x-enum-varnames": [
+                "ActionStart",
+                "ActionIncrease",
+                "ActionRepeat",
+                "ActionDecrease",
+                "ActionDeload"
+            ]
+        },
+        "progression.Rules": {
+            "type": "object",
+            "properties": {
+                "deload_after": {
+                    "description": "consecutive failed sessions that trigger a deload",
+                    "type": "integer"
+                },
+                "deload_percent": {
+                    "description": "weight removed by a deload",
+                    "type": "number"
+                },
+                "increment": {
+                    "description": "weight added after a successful session",
+                    "type": "number"
+                },
+                "rep_max": {
+                    "description": "top of the range, and the target reps for linear and RPE schemes",
+                    "type": "integer"
+                },
+                "rep_min": {
+                    "description": "bottom of the double progression range",
+                    "type": "integer"
+                },
+                "scheme": {
+                    "$ref": "#/definitions/progression.Scheme"
+                },
+                "target_rpe": {
+                    "description": "effort of the top set for the RPE scheme, 6 to 10",
+                    "type": "number"
+                }
+            }
+        },
+        "progression.Scheme": {
+            "type": "string",
+            "enum": [
+                "double",
+                "linear",
+                "rpe"
+            ],
+            "x-enum-varnames": [
+                "Double",
+                "Linear",
+                "RPE"
+            ]
+        },
+        "sensor.HeartRateSummary": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "recorded_seconds": {
+                    "type": "integer"
+                },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sensor.ZoneTime"
+                    }
+                }
+            }
+        },
+        "sensor.MetricSummary": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                }
+            }
+        },
+        "sensor.Series": {
+            "type": "object",
+            "properties": {
+                "cadence": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "heart_rate": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "interval_seconds": {
+                    "type": "integer"
+                },
+                "offsets": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "power": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "sensor.Summary": {
+            "type": "object",
+            "properties": {
+                "cadence": {
+                    "$ref": "#/definitions/sensor.MetricSummary"
+                },
+                "heart_rate": {
+                    "$ref": "#/definitions/sensor.HeartRateSummary"
+                },
+                "power": {
+                    "$ref": "#/definitions/sensor.MetricSummary"
+                }
+            }
+        },
+        "sensor.ZoneTime": {
+            "type": "object",
+            "properties": {
+                "max_bpm": {
+                    "type": "integer"
+                },
+                "min_bpm": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "description": "of recorded heart-rate time; time below zone 1 is in no zone",
+                    "type": "number"
+                },
+                "seconds": {
+                    "type": "integer"
+                },
+                "zone": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.Adherence": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "description": "due sessions with a linked workout",
+                    "type": "integer"
+                },
+                "due": {
+                    "description": "sessions scheduled up to and including today",
+                    "type": "integer"
+                },
+                "enrollment_id": {
+                    "type": "integer"
+                },
+                "missed": {
+                    "description": "past sessions without a linked workout",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "completed / due",
+                    "type": "number"
+                },
+                "total": {
+                    "description": "every session in the program",
+                    "type": "integer"
+                }
+            }
+        },
+        "store.AnalyticsBucket": {
+            "type": "object",
+            "properties": {
+                "muscle_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.MuscleGroupVolume"
+                    }
+                },
+                "period_start": {
+                    "description": "YYYY-MM-DD in the user's timezone",
+                    "type": "string"
+                },
+                "rest_compliance": {
+                    "$ref": "#/definitions/store.RestCompliance"
+                },
+                "sessions": {
+                    "type": "integer"
+                },
+                "total_calories": {
+                    "type": "integer"
+                },
+                "total_distance": {
+                    "description": "TotalDistanceMeters in km or mi",
+                    "type": "number"
+                },
+                "total_distance_meters": {
+                    "description": "entry distances, or repeats x work distance for intervals",
+                    "type": "number"
+                },
+                "total_duration_minutes": {
+                    "type": "integer"
+                },
+                "total_volume": {
+                    "description": "sets x reps x weight, in kg, with body weight for bodyweight exercises",
+                    "type": "number"
+                }
+            }
+        },
+        "store.AnalyticsSummary": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.AnalyticsBucket"
+                    }
+                },
+                "totals": {
+                    "$ref": "#/definitions/store.AnalyticsBucket"
+                }
+            }
+        },
+        "store.CoachRelationship": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "athlete_id": {
+                    "type": "integer"
+                },
+                "athlete_username": {
+                    "type": "string"
+                },
+                "coach_id": {
+                    "type": "integer"
+                },
+                "coach_username": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invited_by": {
+                    "type": "integer"
+                },
+                "permission": {
+                    "description": "read or read_write",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending or accepted",
+                    "type": "string"
+                }
+            }
+        },
+        "store.Comment": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "workout_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.EntryGroup": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.WorkoutEntry"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_index": {
+                    "type": "integer"
+                },
+                "rest_between_exercises_seconds": {
+                    "type": "integer"
+                },
+                "rest_between_rounds_seconds": {
+                    "type": "integer"
+                },
+                "rounds": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "superset, circuit or giant_set",
+                    "type": "string"
+                }
+            }
+        },
+        "store.Exercise": {
+            "type": "object",
+            "properties": {
+                "bodyweight": {
+                    "description": "the lifter's body weight counts towards volume",
+                    "type": "boolean"
+                },
+                "category": {
+                    "description": "strength or cardio",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "muscle_group": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.ExerciseProgressPoint": {
+            "type": "object",
+            "properties": {
+                "best_pace_seconds": {
+                    "description": "BestPaceSecondsPerKm per km or mi",
+                    "type": "integer"
+                },
+                "best_pace_seconds_per_km": {
+                    "description": "fastest entry pace, nil without paced entries",
+                    "type": "integer"
+                },
+                "estimated_1rm": {
+                    "type": "number"
+                },
+                "max_weight": {
+                    "type": "number"
+                },
+                "period_start": {
+                    "description": "YYYY-MM-DD in the user's timezone",
+                    "type": "string"
+                },
+                "sessions": {
+                    "type": "integer"
+                },
+                "total_distance": {
+                    "description": "TotalDistanceMeters in km or mi",
+                    "type": "number"
+                },
+                "total_distance_meters": {
+                    "type": "number"
+                },
+                "total_reps": {
+                    "type": "integer"
+                },
+                "total_volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "store.ExerciseRecommendation": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/progression.Action"
+                },
+                "exercise_name": {
+                    "type": "string"
+                },
+                "failed_sessions": {
+                    "description": "consecutive failed sessions up to the last one",
+                    "type": "integer"
+                },
+                "last_session": {
+                    "description": "nil without history",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "$ref": "#/definitions/store.ProgressionRules"
+                },
+                "scheme": {
+                    "$ref": "#/definitions/progression.Scheme"
+                },
+                "sets": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "kg, 0 without history",
+                    "type": "number"
+                }
+            }
+        },
+        "store.FeedItem": {
+            "type": "object",
+            "properties": {
+                "comment_count": {
+                    "type": "integer"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "liked_by_me": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "workout": {
+                    "$ref": "#/definitions/store.Workout"
+                }
+            }
+        },
+        "store.Goal": {
+            "type": "object",
+            "properties": {
+                "achieved_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "number"
+                },
+                "deadline": {
+                    "description": "YYYY-MM-DD, inclusive",
+                    "type": "string"
+                },
+                "exercise_name": {
+                    "description": "required for lift goals, optional for distance goals",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "percent_complete": {
+                    "type": "number"
+                },
+                "period": {
+                    "description": "week or month for recurring goals",
+                    "type": "string"
+                },
+                "projected_completion": {
+                    "description": "day the target is reached at the current rate, nil without progress",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "workouts, meters, or kg; distances and weights are in the caller's unit through the API",
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "unit of Target and Current in the response",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "window_end": {
+                    "description": "last day counted",
+                    "type": "string"
+                },
+                "window_start": {
+                    "description": "first day counted, YYYY-MM-DD in the user's timezone",
+                    "type": "string"
+                }
+            }
+        },
+        "store.Intervals": {
+            "type": "object",
+            "properties": {
+                "repeats": {
+                    "type": "integer"
+                },
+                "rest_seconds": {
+                    "type": "integer"
+                },
+                "work_meters": {
+                    "type": "number"
+                },
+                "work_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.LoggedSet": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "defaults to now",
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "exercise_name": {
+                    "type": "string"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "rpe": {
+                    "description": "rate of perceived exertion, 1 to 10",
+                    "type": "number"
+                },
+                "started_at": {
+                    "description": "when the set began, which times the rest before it",
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "in kg",
+                    "type": "number"
+                }
+            }
+        },
+        "store.MeasurementPoint": {
+            "type": "object",
+            "properties": {
+                "measured_at": {
+                    "type": "string"
+                },
+                "moving_average": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "store.MuscleGroupVolume": {
+            "type": "object",
+            "properties": {
+                "muscle_group": {
+                    "type": "string"
+                },
+                "volume": {
+                    "description": "sets x reps x weight, in kg, with body weight for bodyweight exercises",
+                    "type": "number"
+                }
+            }
+        },
+        "store.PersonalRecord": {
+            "type": "object",
+            "properties": {
+                "achieved_at": {
+                    "type": "string"
+                },
+                "exercise_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "record_type": {
+                    "type": "string"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "kg, seconds or kg volume depending on record_type",
+                    "type": "number"
+                },
+                "weight": {
+                    "description": "weight the record was set at, nil for bodyweight",
+                    "type": "number"
+                },
+                "workout_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.PlannedSession": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "nil while the workout is still in progress",
+                    "type": "string"
+                },
+                "enrollment_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "program_id": {
+                    "type": "integer"
+                },
+                "program_name": {
+                    "type": "string"
+                },
+                "scheduled_date": {
+                    "description": "YYYY-MM-DD in the user's timezone",
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "template_title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "week_number": {
+                    "type": "integer"
+                },
+                "workout_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api.UserResponse": {
+        "store.PlateInventory": {
+            "type": "object",
+            "properties": {
+                "bar_weight": {
+                    "type": "number"
+                },
+                "default": {
+                    "description": "nothing saved yet; a standard gym set in the user's units",
+                    "type": "boolean"
+                },
+                "plates": {
+                    "description": "heaviest first; count is every plate owned",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/plates.Plate"
+                    }
+                },
+                "unit": {
+                    "description": "kg or lb",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.Program": {
             "type": "object",
             "properties": {
                 "created_at": {
-                    "description": "Creation timestamp",
-                    "type": "string",
-                    "example": "2024-01-01T12:00:00Z"
+                    "type": "string"
                 },
-                "email": {
-                    "description": "Email address",
-                    "type": "string",
-                    "example": "john@example.com"
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.ProgramDay"
+                    }
+                },
+                "description": {
+                    "type": "string"
                 },
                 "id": {
-                    "description": "User ID",
-                    "type": "integer",
-                    "example": 1
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "progressions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.ProgressionRule"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "weeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.ProgramDay": {
+            "type": "object",
+            "properties": {
+                "day_of_week": {
+                    "description": "ISO weekday, 1 = Monday",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "week_number": {
+                    "description": "1-based; nil repeats the day every week",
+                    "type": "integer"
+                }
+            }
+        },
+        "store.ProgramEnrollment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "program_id": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.ProgressionRule": {
+            "type": "object",
+            "properties": {
+                "exercise_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "weekly_increment": {
+                    "description": "kg added each week after the first",
+                    "type": "number"
+                }
+            }
+        },
+        "store.ProgressionRules": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "no rules have been saved for the exercise",
+                    "type": "boolean"
+                },
+                "deload_after": {
+                    "description": "consecutive failed sessions that trigger a deload",
+                    "type": "integer"
+                },
+                "deload_percent": {
+                    "description": "weight removed by a deload",
+                    "type": "number"
+                },
+                "exercise_name": {
+                    "type": "string"
+                },
+                "increment": {
+                    "description": "weight added after a successful session",
+                    "type": "number"
+                },
+                "rep_max": {
+                    "description": "top of the range, and the target reps for linear and RPE schemes",
+                    "type": "integer"
+                },
+                "rep_min": {
+                    "description": "bottom of the double progression range",
+                    "type": "integer"
+                },
+                "scheme": {
+                    "$ref": "#/definitions/progression.Scheme"
+                },
+                "target_rpe": {
+                    "description": "effort of the top set for the RPE scheme, 6 to 10",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.RestCompliance": {
+            "type": "object",
+            "properties": {
+                "average_rest_seconds": {
+                    "description": "nil without timed rests",
+                    "type": "integer"
+                },
+                "compliance_percent": {
+                    "description": "nil without timed rests",
+                    "type": "number"
+                },
+                "compliant": {
+                    "type": "integer"
+                },
+                "rests": {
+                    "description": "rests between sets with start and completion times",
+                    "type": "integer"
+                },
+                "too_long": {
+                    "type": "integer"
+                },
+                "too_short": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.RestTarget": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "no target has been saved for the exercise",
+                    "type": "boolean"
+                },
+                "exercise_name": {
+                    "type": "string"
+                },
+                "rest_seconds": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.Streaks": {
+            "type": "object",
+            "properties": {
+                "current_days": {
+                    "type": "integer"
+                },
+                "current_weeks": {
+                    "type": "integer"
+                },
+                "last_workout_date": {
+                    "description": "YYYY-MM-DD, nil without workouts",
+                    "type": "string"
+                },
+                "longest_days": {
+                    "type": "integer"
+                },
+                "longest_weeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.Tag": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "workout_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "store.TemplateEntry": {
+            "type": "object",
+            "properties": {
+                "exercise_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_index": {
+                    "type": "integer"
+                },
+                "target_duration_seconds": {
+                    "type": "integer"
+                },
+                "target_reps": {
+                    "type": "integer"
+                },
+                "target_sets": {
+                    "type": "integer"
+                },
+                "target_weight": {
+                    "description": "in kg",
+                    "type": "number"
+                }
+            }
+        },
+        "store.UserSummary": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "store.Workout": {
+            "type": "object",
+            "properties": {
+                "calories_burned": {
+                    "description": "in kcal",
+                    "type": "integer"
+                },
+                "calories_source": {
+                    "description": "how calories were estimated, nil when supplied by the client",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "set while the workout is in the trash",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.WorkoutEntry"
+                    }
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.EntryGroup"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active while the session is in progress, then completed",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "on update, nil keeps the current tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "template_id": {
+                    "description": "template the workout follows, which links it to planned sessions",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "track": {
+                    "description": "set for workouts imported from a GPS recording",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/track.Summary"
+                        }
+                    ]
                 },
                 "updated_at": {
-                    "description": "Last update timestamp",
-                    "type": "string",
-                    "example": "2024-01-01T12:00:00Z"
+                    "type": "string"
                 },
-                "username": {
-                    "description": "Username",
-                    "type": "string",
-                    "example": "johndoe"
+                "user_id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "description": "private, followers or public",
+                    "type": "string"
                 }
             }
         },
-        "api.WorkoutEntryResponse": {
+        "store.WorkoutEntry": {
             "type": "object",
             "properties": {
+                "distance": {
+                    "description": "Distance and PaceSeconds are DistanceMeters and PaceSecondsPerKm in\nthe caller's units (km or mi); they are never stored.",
+                    "type": "number"
+                },
+                "distance_meters": {
+                    "type": "number"
+                },
                 "duration_seconds": {
-                    "description": "Duration in seconds",
-                    "type": "integer",
-                    "example": 60
+                    "type": "integer"
                 },
                 "exercise_name": {
-                    "description": "Name of the exercise",
-                    "type": "string",
-                    "example": "Push ups"
+                    "type": "string"
+                },
+                "group_index": {
+                    "description": "index into Workout.Groups, nil when ungrouped",
+                    "type": "integer"
                 },
                 "id": {
-                    "description": "Entry ID",
-                    "type": "integer",
-                    "example": 1
+                    "type": "integer"
+                },
+                "incline_percent": {
+                    "description": "treadmill incline, negative for a decline",
+                    "type": "number"
+                },
+                "intervals": {
+                    "description": "work/rest repeats, nil for a steady effort",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.Intervals"
+                        }
+                    ]
                 },
                 "notes": {
-                    "description": "Additional notes",
-                    "type": "string",
-                    "example": "Good form maintained"
+                    "type": "string"
                 },
                 "order_index": {
-                    "description": "Order of exercise in workout",
-                    "type": "integer",
-                    "example": 1
+                    "type": "integer"
+                },
+                "pace_seconds": {
+                    "type": "integer"
+                },
+                "pace_seconds_per_km": {
+                    "description": "derived from distance and duration when omitted",
+                    "type": "integer"
                 },
                 "reps": {
-                    "description": "Number of repetitions",
-                    "type": "integer",
-                    "example": 15
+                    "type": "integer"
+                },
+                "resistance_level": {
+                    "description": "machine resistance setting",
+                    "type": "integer"
+                },
+                "rpe": {
+                    "description": "rate of perceived exertion of the hardest set, 1 to 10",
+                    "type": "number"
+                },
+                "set_log": {
+                    "description": "individual sets logged during a live session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/store.WorkoutSet"
+                    }
                 },
                 "sets": {
-                    "description": "Number of sets",
-                    "type": "integer",
-                    "example": 3
+                    "type": "integer"
+                },
+                "target_rest_seconds": {
+                    "description": "rest between sets; nil uses the exercise's rest target",
+                    "type": "integer"
                 },
                 "weight": {
-                    "description": "Weight in kg",
-                    "type": "number",
-                    "example": 75.5
+                    "description": "in kg, or the caller's unit through the API",
+                    "type": "number"
                 }
             }
         },
-        "api.WorkoutResponse": {
+        "store.WorkoutRevision": {
             "type": "object",
             "properties": {
-                "calories_burned": {
-                    "description": "Calories burned",
-                    "type": "integer",
-                    "example": 350
+                "action": {
+                    "type": "string"
                 },
-                "created_at": {
-                    "description": "Creation timestamp",
-                    "type": "string",
-                    "example": "2024-01-01T12:00:00Z"
+                "actor_id": {
+                    "type": "integer"
                 },
-                "description": {
-                    "description": "Workout description",
-                    "type": "string",
-                    "example": "High intensity cardio"
+                "actor_username": {
+                    "type": "string"
                 },
-                "duration_minutes": {
-                    "description": "Duration in minutes",
-                    "type": "integer",
-                    "example": 45
+                "created_at": {
+                    "type": "string"
                 },
-                "entries": {
-                    "description": "List of workout exercises",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api.WorkoutEntryResponse"
-                    }
+                "diff": {
+                    "type": "object"
                 },
                 "id": {
-                    "description": "Workout ID",
-                    "type": "integer",
-                    "example": 1
-                },
-                "title": {
-                    "description": "Workout title",
-                    "type": "string",
-                    "example": "Morning Cardio"
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "description": "Last update timestamp",
-                    "type": "string",
-                    "example": "2024-01-01T12:00:00Z"
+                "revision": {
+                    "type": "integer"
                 },
-                "user_id": {
-                    "description": "User ID who owns the workout",
-                    "type": "integer",
-                    "example": 1
+                "workout_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api.createTokenRequest": {
+        "store.WorkoutSearchResult": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "description": "Password for authentication",
-                    "type": "string",
-                    "example": "SecurePass123"
+                "rank": {
+                    "type": "number"
                 },
-                "username": {
-                    "description": "Username for authentication",
-                    "type": "string",
-                    "example": "johndoe"
+                "workout": {
+                    "$ref": "#/definitions/store.Workout"
                 }
             }
         },
-        "api.registerUserRequest": {
+        "store.WorkoutSet": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "description": "Email address for the new user",
-                    "type": "string",
-                    "example": "john@example.com"
+                "completed_at": {
+                    "description": "nil for generated warm-up sets",
+                    "type": "string"
                 },
-                "password": {
-                    "description": "Password for the new user",
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 8,
-                    "example": "SecurePass123"
+                "duration_seconds": {
+                    "type": "integer"
                 },
-                "username": {
-                    "description": "Username for the new user",
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 3,
-                    "example": "johndoe"
+                "id": {
+                    "type": "integer"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "rest_seconds": {
+                    "description": "from the entry's previous working set completing to this one starting; set on read",
+                    "type": "integer"
+                },
+                "rpe": {
+                    "type": "number"
+                },
+                "set_number": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "nil when the client did not time the set",
+                    "type": "string"
+                },
+                "warmup": {
+                    "description": "left out of the entry's summary",
+                    "type": "boolean"
+                },
+                "weight": {
+                    "description": "in kg, or the caller's unit through the API",
+                    "type": "number"
                 }
             }
         },
-        "store.Workout": {
+        "store.WorkoutShare": {
             "type": "object",
             "properties": {
-                "calories_burned": {
-                    "description": "in kcal",
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
                     "type": "integer"
                 },
-                "created_at": {
+                "expires_at": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
+                    "type": "integer"
+                },
+                "revoked_at": {
                     "type": "string"
                 },
-                "duration_minutes": {
+                "token": {
+                    "type": "string"
+                },
+                "workout_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "store.WorkoutTemplate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
                 },
                 "entries": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/store.WorkoutEntry"
+                        "$ref": "#/definitions/store.TemplateEntry"
                     }
                 },
                 "id": {
@@ -918,33 +8645,108 @@ const docTemplate = `{
                 }
             }
         },
-        "store.WorkoutEntry": {
+        "track.Feature": {
             "type": "object",
             "properties": {
-                "duration_seconds": {
-                    "type": "integer"
+                "geometry": {
+                    "description": "null for tracks without positions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/track.LineString"
+                        }
+                    ]
                 },
-                "exercise_name": {
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "type": {
                     "type": "string"
+                }
+            }
+        },
+        "track.LineString": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "description": "[longitude, latitude] or [longitude, latitude, elevation]",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
                 },
-                "id": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "track.Split": {
+            "type": "object",
+            "properties": {
+                "average_heart_rate": {
                     "type": "integer"
                 },
-                "notes": {
-                    "type": "string"
+                "distance_meters": {
+                    "type": "number"
                 },
-                "order_index": {
+                "elevation_gain_meters": {
+                    "type": "number"
+                },
+                "moving_seconds": {
                     "type": "integer"
                 },
-                "reps": {
+                "number": {
                     "type": "integer"
                 },
-                "sets": {
+                "pace_seconds_per_km": {
                     "type": "integer"
                 },
-                "weight": {
-                    "description": "in kg",
+                "speed_kmh": {
+                    "type": "number"
+                }
+            }
+        },
+        "track.Summary": {
+            "type": "object",
+            "properties": {
+                "average_heart_rate": {
+                    "description": "mean of the recorded samples",
+                    "type": "integer"
+                },
+                "average_pace_seconds_per_km": {
+                    "description": "over moving time, 0 without distance",
+                    "type": "integer"
+                },
+                "average_speed_kmh": {
+                    "description": "over moving time",
                     "type": "number"
+                },
+                "distance_meters": {
+                    "type": "number"
+                },
+                "elapsed_seconds": {
+                    "type": "integer"
+                },
+                "elevation_gain_meters": {
+                    "type": "number"
+                },
+                "max_heart_rate": {
+                    "type": "integer"
+                },
+                "moving_seconds": {
+                    "type": "integer"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/track.Split"
+                    }
+                },
+                "sport": {
+                    "type": "string"
                 }
             }
         }
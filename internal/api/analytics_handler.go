@@ -0,0 +1,212 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type AnalyticsHandler struct {
+	analyticsStore store.AnalyticsStore
+	exerciseStore  store.ExerciseStore
+	logger         *log.Logger
+}
+
+func NewAnalyticsHandler(analyticsStore store.AnalyticsStore, exerciseStore store.ExerciseStore, logger *log.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsStore: analyticsStore,
+		exerciseStore:  exerciseStore,
+		logger:         logger,
+	}
+}
+
+// userLocation returns the user's configured time zone, falling back to UTC.
+func userLocation(user *store.User) *time.Location {
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil || user.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// readAnalyticsQuery parses period, from and to. Dates are inclusive and
+// interpreted in the user's time zone; the default range is the last twelve
+// periods up to and including today.
+func readAnalyticsQuery(r *http.Request, user *store.User) (store.AnalyticsQuery, error) {
+	loc := userLocation(user)
+	q := store.AnalyticsQuery{
+		UserID:   user.ID,
+		Period:   r.URL.Query().Get("period"),
+		Timezone: loc.String(),
+	}
+
+	if q.Period == "" {
+		q.Period = store.PeriodWeek
+	}
+	if q.Period != store.PeriodDay && q.Period != store.PeriodWeek && q.Period != store.PeriodMonth {
+		return q, errors.New("period must be one of day, week or month")
+	}
+
+	to, ok, err := utils.ReadDateParam(r, "to", loc)
+	if err != nil {
+		return q, err
+	}
+	if !ok {
+		now := time.Now().In(loc)
+		to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	}
+	q.To = to.AddDate(0, 0, 1)
+
+	from, ok, err := utils.ReadDateParam(r, "from", loc)
+	if err != nil {
+		return q, err
+	}
+	if !ok {
+		switch q.Period {
+		case store.PeriodDay:
+			from = q.To.AddDate(0, 0, -30)
+		case store.PeriodWeek:
+			from = q.To.AddDate(0, 0, -12*7)
+		case store.PeriodMonth:
+			from = q.To.AddDate(0, -12, 0)
+		}
+		// start on a bucket boundary so the first bucket is not partial
+		from = store.PeriodStart(from, q.Period)
+	}
+	q.From = from
+
+	if !q.From.Before(q.To) {
+		return q, errors.New("from must not be after to")
+	}
+	return q, nil
+}
+
+// HandleGetSummary returns aggregated training metrics
+//
+//	@Summary		Get training summary
+//	@Description	Aggregate volume, session count, duration, calories, cardio distance and per-muscle-group volume per week or month in the user's time zone
+//	@Tags			Analytics
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			period	query		string					false	"Bucket size: day, week (default) or month"
+//	@Param			from	query		string					false	"First day to include (YYYY-MM-DD), defaults to the start of the bucket 30 days, 12 weeks or 12 months back"
+//	@Param			to		query		string					false	"Last day to include (YYYY-MM-DD), defaults to today"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	store.AnalyticsSummary	"Training summary"
+//	@Failure		400		{object}	ErrorResponse			"Invalid query parameters"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/analytics/summary [get]
+func (h *AnalyticsHandler) HandleGetSummary(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+
+	q, err := readAnalyticsQuery(r, currentUser)
+	if err != nil {
+		h.logger.Printf("Invalid analytics query: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.analyticsStore.GetSummary(q)
+	if err != nil {
+		h.logger.Printf("Error retrieving analytics summary: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve analytics summary"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{
+		"period":   q.Period,
+		"timezone": q.Timezone,
+		"from":     q.From.Format("2006-01-02"),
+		"to":       q.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		"units":    system,
+		"summary":  summary.InUnits(system),
+	})
+}
+
+// HandleGetExerciseProgress returns an exercise's progress over time
+//
+//	@Summary		Get exercise progress
+//	@Description	Estimated 1RM, top weight, volume, distance and best pace for one exercise per period in the user's time zone
+//	@Tags			Analytics
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int							true	"Exercise ID"
+//	@Param			period	query		string						false	"Bucket size: day, week (default) or month"
+//	@Param			from	query		string						false	"First day to include (YYYY-MM-DD), defaults to the start of the bucket 30 days, 12 weeks or 12 months back"
+//	@Param			to		query		string						false	"Last day to include (YYYY-MM-DD), defaults to today"
+//	@Param			units	query		string						false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		store.ExerciseProgressPoint	"Progress time series"
+//	@Failure		400		{object}	ErrorResponse				"Invalid query parameters"
+//	@Failure		401		{object}	ErrorResponse				"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse				"Exercise not found"
+//	@Failure		500		{object}	ErrorResponse				"Internal server error"
+//	@Router			/analytics/exercises/{id}/progress [get]
+func (h *AnalyticsHandler) HandleGetExerciseProgress(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	exerciseId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading exercise ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid exercise ID"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+
+	q, err := readAnalyticsQuery(r, currentUser)
+	if err != nil {
+		h.logger.Printf("Invalid analytics query: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	exercise, err := h.exerciseStore.GetExerciseByID(exerciseId)
+	if err != nil {
+		h.logger.Printf("Error retrieving exercise: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve exercise"})
+		return
+	}
+	if exercise == nil {
+		h.logger.Printf("Exercise with ID %d not found", exerciseId)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Exercise not found"})
+		return
+	}
+
+	points, err := h.analyticsStore.GetExerciseProgress(q, exercise.Name)
+	if err != nil {
+		h.logger.Printf("Error retrieving exercise progress: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve exercise progress"})
+		return
+	}
+
+	for i := range points {
+		points[i] = points[i].InUnits(system)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{
+		"exercise": exercise,
+		"period":   q.Period,
+		"timezone": q.Timezone,
+		"from":     q.From.Format("2006-01-02"),
+		"to":       q.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		"units":    system,
+		"progress": points,
+	})
+}
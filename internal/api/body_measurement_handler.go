@@ -0,0 +1,496 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+const (
+	defaultSeriesWindowDays = 7
+	maxSeriesWindowDays     = 365
+)
+
+type BodyMeasurementHandler struct {
+	measurementStore store.BodyMeasurementStore
+	logger           *log.Logger
+}
+
+func NewBodyMeasurementHandler(measurementStore store.BodyMeasurementStore, logger *log.Logger) *BodyMeasurementHandler {
+	return &BodyMeasurementHandler{
+		measurementStore: measurementStore,
+		logger:           logger,
+	}
+}
+
+// BodyMeasurementRequest is a measurement in the request's unit system: kg
+// and cm, or lb and in. Omitted values are not measured; at least one is
+// required.
+type BodyMeasurementRequest struct {
+	MeasuredAt     *time.Time `json:"measured_at" example:"2024-01-15T07:30:00Z"` // Defaults to now
+	Weight         *float64   `json:"weight" example:"82.4"`                      // Body weight in kg or lb
+	BodyFatPercent *float64   `json:"body_fat_percent" example:"18.5"`            // Body fat percentage
+	Neck           *float64   `json:"neck" example:"39"`                          // Circumferences in cm or in
+	Chest          *float64   `json:"chest" example:"104"`
+	Waist          *float64   `json:"waist" example:"84"`
+	Hips           *float64   `json:"hips" example:"98"`
+	Arm            *float64   `json:"arm" example:"38.5"`
+	Thigh          *float64   `json:"thigh" example:"60"`
+	Calf           *float64   `json:"calf" example:"39"`
+	Notes          string     `json:"notes" example:"Morning, fasted"`
+}
+
+// MeasurementUnits names the units of a response's values.
+type MeasurementUnits struct {
+	Weight string `json:"weight" example:"kg"` // kg or lb
+	Length string `json:"length" example:"cm"` // cm or in
+}
+
+// BodyMeasurementResponse is a measurement in the requested unit system.
+type BodyMeasurementResponse struct {
+	ID             int64            `json:"id" example:"1"`
+	MeasuredAt     time.Time        `json:"measured_at" example:"2024-01-15T07:30:00Z"`
+	Weight         *float64         `json:"weight" example:"82.4"`
+	BodyFatPercent *float64         `json:"body_fat_percent" example:"18.5"`
+	Neck           *float64         `json:"neck" example:"39"`
+	Chest          *float64         `json:"chest" example:"104"`
+	Waist          *float64         `json:"waist" example:"84"`
+	Hips           *float64         `json:"hips" example:"98"`
+	Arm            *float64         `json:"arm" example:"38.5"`
+	Thigh          *float64         `json:"thigh" example:"60"`
+	Calf           *float64         `json:"calf" example:"39"`
+	Notes          string           `json:"notes" example:"Morning, fasted"`
+	Units          MeasurementUnits `json:"units"`
+	CreatedAt      time.Time        `json:"created_at" example:"2024-01-15T07:31:00Z"`
+	UpdatedAt      time.Time        `json:"updated_at" example:"2024-01-15T07:31:00Z"`
+}
+
+// MeasurementSeriesResponse is one metric over time with trailing moving
+// averages.
+type MeasurementSeriesResponse struct {
+	Metric     string                   `json:"metric" example:"weight"`
+	Unit       string                   `json:"unit" example:"kg"`
+	WindowDays int                      `json:"window_days" example:"7"`
+	Points     []store.MeasurementPoint `json:"points"`
+}
+
+// toMeasurement converts the request from system's units to a metric
+// measurement.
+func (req *BodyMeasurementRequest) toMeasurement(system units.System) *store.BodyMeasurement {
+	convert := func(value *float64, to func(float64) float64) *float64 {
+		if value == nil {
+			return nil
+		}
+		converted := to(*value)
+		return &converted
+	}
+	m := &store.BodyMeasurement{
+		WeightKg:       convert(req.Weight, system.Kilograms),
+		BodyFatPercent: req.BodyFatPercent,
+		NeckCm:         convert(req.Neck, system.Centimeters),
+		ChestCm:        convert(req.Chest, system.Centimeters),
+		WaistCm:        convert(req.Waist, system.Centimeters),
+		HipsCm:         convert(req.Hips, system.Centimeters),
+		ArmCm:          convert(req.Arm, system.Centimeters),
+		ThighCm:        convert(req.Thigh, system.Centimeters),
+		CalfCm:         convert(req.Calf, system.Centimeters),
+		Notes:          req.Notes,
+	}
+	if req.MeasuredAt != nil {
+		m.MeasuredAt = *req.MeasuredAt
+	}
+	return m
+}
+
+func measurementResponse(m *store.BodyMeasurement, system units.System) *BodyMeasurementResponse {
+	convert := func(value *float64, to func(float64) float64) *float64 {
+		if value == nil {
+			return nil
+		}
+		converted := units.Round(to(*value), 1)
+		return &converted
+	}
+	return &BodyMeasurementResponse{
+		ID:             m.ID,
+		MeasuredAt:     m.MeasuredAt,
+		Weight:         convert(m.WeightKg, system.Weight),
+		BodyFatPercent: m.BodyFatPercent,
+		Neck:           convert(m.NeckCm, system.Length),
+		Chest:          convert(m.ChestCm, system.Length),
+		Waist:          convert(m.WaistCm, system.Length),
+		Hips:           convert(m.HipsCm, system.Length),
+		Arm:            convert(m.ArmCm, system.Length),
+		Thigh:          convert(m.ThighCm, system.Length),
+		Calf:           convert(m.CalfCm, system.Length),
+		Notes:          m.Notes,
+		Units:          MeasurementUnits{Weight: system.WeightUnit(), Length: system.LengthUnit()},
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// getOwnedMeasurement loads the measurement named by the id URL parameter
+// and writes the error response itself when it is missing or not the
+// caller's.
+func (h *BodyMeasurementHandler) getOwnedMeasurement(w http.ResponseWriter, r *http.Request) (*store.BodyMeasurement, bool) {
+	measurementID, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading measurement ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid measurement ID"})
+		return nil, false
+	}
+
+	measurement, err := h.measurementStore.GetMeasurementByID(measurementID)
+	if err != nil {
+		h.logger.Printf("Error retrieving measurement: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve measurement"})
+		return nil, false
+	}
+	// other users' measurements are reported as missing rather than forbidden
+	if measurement == nil || measurement.UserID != middleware.GetUser(r).ID {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Measurement not found"})
+		return nil, false
+	}
+
+	return measurement, true
+}
+
+// HandleCreateMeasurement records a body measurement
+//
+//	@Summary		Record a body measurement
+//	@Description	Record body weight, body fat percentage and circumferences, in the request's unit system, for the authenticated user. Omitted values are not measured, but at least one is required. The latest weight is used for calorie estimates and for volume of bodyweight exercises.
+//	@Tags			Body Measurements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units		query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Param			measurement	body		BodyMeasurementRequest	true	"Measurement"
+//	@Success		201			{object}	BodyMeasurementResponse	"Measurement recorded"
+//	@Failure		400			{object}	ErrorResponse			"Invalid measurement"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/body-measurements [post]
+func (h *BodyMeasurementHandler) HandleCreateMeasurement(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	var req BodyMeasurementRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	measurement := req.toMeasurement(system)
+	measurement.UserID = middleware.GetUser(r).ID
+	measurement, err = h.measurementStore.CreateMeasurement(measurement)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidMeasurement) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error creating measurement: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to record measurement"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"measurement": measurementResponse(measurement, system)})
+}
+
+// HandleGetMeasurements lists the authenticated user's body measurements
+//
+//	@Summary		List body measurements
+//	@Description	List the authenticated user's body measurements, newest first. from and to are inclusive dates in the user's time zone.
+//	@Tags			Body Measurements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			from	query		string					false	"First day (YYYY-MM-DD)"
+//	@Param			to		query		string					false	"Last day (YYYY-MM-DD)"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		BodyMeasurementResponse	"Measurements"
+//	@Failure		400		{object}	ErrorResponse			"Invalid query parameters"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/body-measurements [get]
+func (h *BodyMeasurementHandler) HandleGetMeasurements(w http.ResponseWriter, r *http.Request) {
+	currentUser := middleware.GetUser(r)
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	loc := userLocation(currentUser)
+	var from, to *time.Time
+	date, ok, err := utils.ReadDateParam(r, "from", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if ok {
+		from = &date
+	}
+	date, ok, err = utils.ReadDateParam(r, "to", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if ok {
+		end := date.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	measurements, err := h.measurementStore.GetMeasurementsForUser(currentUser.ID, from, to)
+	if err != nil {
+		h.logger.Printf("Error retrieving measurements: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve measurements"})
+		return
+	}
+
+	responses := make([]*BodyMeasurementResponse, len(measurements))
+	for i, measurement := range measurements {
+		responses[i] = measurementResponse(measurement, system)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"measurements": responses})
+}
+
+// HandleGetMeasurementSeries returns one metric over time
+//
+//	@Summary		Get a body measurement series
+//	@Description	Return every measurement of one metric in a date range, oldest first, each with the mean of the measurements in the window days ending at it. from and to are inclusive dates in the user's time zone; the default range is the last 90 days.
+//	@Tags			Body Measurements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			metric	query		string						true	"weight, body_fat_percent, neck, chest, waist, hips, arm, thigh or calf"
+//	@Param			window	query		int							false	"Moving average window in days (default 7, max 365)"
+//	@Param			from	query		string						false	"First day (YYYY-MM-DD)"
+//	@Param			to		query		string						false	"Last day (YYYY-MM-DD)"
+//	@Param			units	query		string						false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	MeasurementSeriesResponse	"Series"
+//	@Failure		400		{object}	ErrorResponse				"Invalid query parameters"
+//	@Failure		401		{object}	ErrorResponse				"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse				"Internal server error"
+//	@Router			/body-measurements/series [get]
+func (h *BodyMeasurementHandler) HandleGetMeasurementSeries(w http.ResponseWriter, r *http.Request) {
+	currentUser := middleware.GetUser(r)
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	metric := r.URL.Query().Get("metric")
+	if _, ok := store.MeasurementMetrics[metric]; !ok {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "metric must be one of weight, body_fat_percent, neck, chest, waist, hips, arm, thigh or calf"})
+		return
+	}
+
+	windowDays := defaultSeriesWindowDays
+	if value := r.URL.Query().Get("window"); value != "" {
+		windowDays, err = strconv.Atoi(value)
+		if err != nil || windowDays <= 0 || windowDays > maxSeriesWindowDays {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "window must be between 1 and 365 days"})
+			return
+		}
+	}
+
+	loc := userLocation(currentUser)
+	to, ok, err := utils.ReadDateParam(r, "to", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if !ok {
+		now := time.Now().In(loc)
+		to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	}
+	to = to.AddDate(0, 0, 1)
+	from, ok, err := utils.ReadDateParam(r, "from", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if !ok {
+		from = to.AddDate(0, 0, -90)
+	}
+	if !from.Before(to) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "from must not be after to"})
+		return
+	}
+
+	points, err := h.measurementStore.GetMeasurementSeries(store.MeasurementSeriesQuery{
+		UserID: currentUser.ID,
+		Metric: metric,
+		From:   from,
+		To:     to,
+		Window: time.Duration(windowDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		h.logger.Printf("Error retrieving measurement series: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve measurement series"})
+		return
+	}
+
+	unit := "%"
+	convert := func(value float64) float64 { return value }
+	switch metric {
+	case "weight":
+		unit, convert = system.WeightUnit(), system.Weight
+	case "body_fat_percent":
+	default:
+		unit, convert = system.LengthUnit(), system.Length
+	}
+	for i := range points {
+		points[i].Value = units.Round(convert(points[i].Value), 1)
+		points[i].MovingAverage = units.Round(convert(points[i].MovingAverage), 1)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"series": MeasurementSeriesResponse{
+		Metric:     metric,
+		Unit:       unit,
+		WindowDays: windowDays,
+		Points:     points,
+	}})
+}
+
+// HandleGetMeasurementByID returns one of the authenticated user's body
+// measurements
+//
+//	@Summary		Get body measurement by ID
+//	@Description	Retrieve one of the authenticated user's body measurements
+//	@Tags			Body Measurements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Measurement ID"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	BodyMeasurementResponse	"Measurement"
+//	@Failure		400		{object}	ErrorResponse			"Invalid measurement ID"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse			"Measurement not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/body-measurements/{id} [get]
+func (h *BodyMeasurementHandler) HandleGetMeasurementByID(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	measurement, ok := h.getOwnedMeasurement(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"measurement": measurementResponse(measurement, system)})
+}
+
+// HandleUpdateMeasurement replaces a body measurement
+//
+//	@Summary		Update body measurement
+//	@Description	Replace every value of one of the authenticated user's body measurements; omitted values are cleared
+//	@Tags			Body Measurements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Measurement ID"
+//	@Param			units		query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Param			measurement	body		BodyMeasurementRequest	true	"Measurement"
+//	@Success		200			{object}	BodyMeasurementResponse	"Measurement updated"
+//	@Failure		400			{object}	ErrorResponse			"Invalid measurement"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		404			{object}	ErrorResponse			"Measurement not found"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/body-measurements/{id} [put]
+func (h *BodyMeasurementHandler) HandleUpdateMeasurement(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.getOwnedMeasurement(w, r)
+	if !ok {
+		return
+	}
+
+	var req BodyMeasurementRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	measurement := req.toMeasurement(system)
+	measurement.ID = existing.ID
+	if req.MeasuredAt == nil {
+		measurement.MeasuredAt = existing.MeasuredAt
+	}
+	err = h.measurementStore.UpdateMeasurement(measurement)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidMeasurement) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Measurement not found"})
+			return
+		}
+		h.logger.Printf("Error updating measurement: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to update measurement"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"measurement": measurementResponse(measurement, system)})
+}
+
+// HandleDeleteMeasurement deletes a body measurement
+//
+//	@Summary		Delete body measurement
+//	@Description	Delete one of the authenticated user's body measurements
+//	@Tags			Body Measurements
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Measurement ID"
+//	@Success		204	"Measurement deleted"
+//	@Failure		400	{object}	ErrorResponse	"Invalid measurement ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Measurement not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/body-measurements/{id} [delete]
+func (h *BodyMeasurementHandler) HandleDeleteMeasurement(w http.ResponseWriter, r *http.Request) {
+	measurement, ok := h.getOwnedMeasurement(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.measurementStore.DeleteMeasurement(measurement.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Measurement not found"})
+			return
+		}
+		h.logger.Printf("Error deleting measurement: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete measurement"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+const (
+	RoleCoach   = "coach"
+	RoleAthlete = "athlete"
+)
+
+type inviteRequest struct {
+	Username   string `json:"username" example:"jane" validate:"required"` // User being invited
+	Role       string `json:"role" example:"athlete" validate:"required"`  // Role of the invited user: coach or athlete
+	Permission string `json:"permission" example:"read"`                   // read (default) or read_write
+}
+
+type updatePermissionRequest struct {
+	Permission string `json:"permission" example:"read_write" validate:"required"` // read or read_write
+}
+
+type CoachingHandler struct {
+	coachingStore store.CoachingStore
+	userStore     store.UserStore
+	logger        *log.Logger
+}
+
+func NewCoachingHandler(coachingStore store.CoachingStore, userStore store.UserStore, logger *log.Logger) *CoachingHandler {
+	return &CoachingHandler{
+		coachingStore: coachingStore,
+		userStore:     userStore,
+		logger:        logger,
+	}
+}
+
+func validPermission(permission string) bool {
+	return permission == store.PermissionRead || permission == store.PermissionReadWrite
+}
+
+// getRelationship loads the relationship named by the id URL parameter and
+// writes the error response itself when it is missing or does not involve
+// the caller.
+func (h *CoachingHandler) getRelationship(w http.ResponseWriter, r *http.Request) (*store.CoachRelationship, bool) {
+	relationshipId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading relationship ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid relationship ID"})
+		return nil, false
+	}
+
+	rel, err := h.coachingStore.GetRelationshipByID(relationshipId)
+	if err != nil {
+		h.logger.Printf("Error retrieving relationship: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve relationship"})
+		return nil, false
+	}
+
+	currentUser := middleware.GetUser(r)
+	if rel == nil || !rel.Involves(currentUser.ID) {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Relationship not found"})
+		return nil, false
+	}
+
+	return rel, true
+}
+
+// HandleCreateInvitation invites another user to a coaching relationship
+//
+//	@Summary		Invite a coach or athlete
+//	@Description	Invite a user to become your coach or your athlete. The relationship grants the coach read or read_write access to the athlete's workouts once the invited user accepts.
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			invitation	body		inviteRequest			true	"Invitation"
+//	@Success		201			{object}	store.CoachRelationship	"Invitation created"
+//	@Failure		400			{object}	ErrorResponse			"Invalid request payload"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		404			{object}	ErrorResponse			"User not found"
+//	@Failure		409			{object}	ErrorResponse			"Relationship already exists"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/coaching/invitations [post]
+func (h *CoachingHandler) HandleCreateInvitation(w http.ResponseWriter, r *http.Request) {
+	var req inviteRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	if req.Permission == "" {
+		req.Permission = store.PermissionRead
+	}
+	if !validPermission(req.Permission) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "permission must be read or read_write"})
+		return
+	}
+	if req.Role != RoleCoach && req.Role != RoleAthlete {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "role must be coach or athlete"})
+		return
+	}
+
+	invitee, err := h.userStore.GetUserByUsername(req.Username)
+	if err != nil {
+		h.logger.Printf("Error retrieving user: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve user"})
+		return
+	}
+	if invitee == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "User not found"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	if invitee.ID == currentUser.ID {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "You cannot invite yourself"})
+		return
+	}
+
+	rel := &store.CoachRelationship{
+		CoachID:    currentUser.ID,
+		AthleteID:  invitee.ID,
+		InvitedBy:  currentUser.ID,
+		Permission: req.Permission,
+	}
+	if req.Role == RoleCoach {
+		rel.CoachID, rel.AthleteID = invitee.ID, currentUser.ID
+	}
+
+	result, err := h.coachingStore.CreateInvitation(rel)
+	if err != nil {
+		if errors.Is(err, store.ErrRelationshipExists) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error creating invitation: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create invitation"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"relationship": result})
+}
+
+// HandleGetRelationships lists the caller's coaching relationships
+//
+//	@Summary		List coaching relationships
+//	@Description	List pending invitations and accepted relationships where the authenticated user is the coach or the athlete
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		store.CoachRelationship	"Coaching relationships"
+//	@Failure		401	{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse			"Internal server error"
+//	@Router			/coaching/relationships [get]
+func (h *CoachingHandler) HandleGetRelationships(w http.ResponseWriter, r *http.Request) {
+	relationships, err := h.coachingStore.GetRelationshipsForUser(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving relationships: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve relationships"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"relationships": relationships})
+}
+
+// HandleAcceptInvitation accepts a pending invitation
+//
+//	@Summary		Accept an invitation
+//	@Description	Accept a pending coaching invitation sent to the authenticated user
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int						true	"Relationship ID"
+//	@Success		200	{object}	store.CoachRelationship	"Accepted relationship"
+//	@Failure		400	{object}	ErrorResponse			"Invalid relationship ID"
+//	@Failure		401	{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse			"Forbidden - not the invited user"
+//	@Failure		404	{object}	ErrorResponse			"Relationship not found"
+//	@Failure		409	{object}	ErrorResponse			"Invitation already accepted"
+//	@Failure		500	{object}	ErrorResponse			"Internal server error"
+//	@Router			/coaching/relationships/{id}/accept [post]
+func (h *CoachingHandler) HandleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	rel, ok := h.getRelationship(w, r)
+	if !ok {
+		return
+	}
+
+	if rel.Invitee() != middleware.GetUser(r).ID {
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Only the invited user can accept"})
+		return
+	}
+
+	err := h.coachingStore.AcceptInvitation(rel.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": "Invitation is not pending"})
+			return
+		}
+		h.logger.Printf("Error accepting invitation: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to accept invitation"})
+		return
+	}
+
+	rel, err = h.coachingStore.GetRelationshipByID(rel.ID)
+	if err != nil || rel == nil {
+		h.logger.Printf("Error retrieving relationship: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve relationship"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"relationship": rel})
+}
+
+// HandleUpdatePermission changes the coach's access level
+//
+//	@Summary		Change coach permission
+//	@Description	Change the access a coach has to your workouts (only by the athlete)
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Relationship ID"
+//	@Param			permission	body		updatePermissionRequest	true	"New permission"
+//	@Success		200			{object}	store.CoachRelationship	"Updated relationship"
+//	@Failure		400			{object}	ErrorResponse			"Invalid request payload"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403			{object}	ErrorResponse			"Forbidden - not the athlete"
+//	@Failure		404			{object}	ErrorResponse			"Relationship not found"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/coaching/relationships/{id} [put]
+func (h *CoachingHandler) HandleUpdatePermission(w http.ResponseWriter, r *http.Request) {
+	rel, ok := h.getRelationship(w, r)
+	if !ok {
+		return
+	}
+
+	var req updatePermissionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+	if !validPermission(req.Permission) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "permission must be read or read_write"})
+		return
+	}
+
+	if rel.AthleteID != middleware.GetUser(r).ID {
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Only the athlete can change permissions"})
+		return
+	}
+
+	err = h.coachingStore.UpdatePermission(rel.ID, req.Permission)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Relationship not found"})
+			return
+		}
+		h.logger.Printf("Error updating permission: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to update permission"})
+		return
+	}
+
+	rel.Permission = req.Permission
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"relationship": rel})
+}
+
+// HandleDeleteRelationship declines, cancels or ends a relationship
+//
+//	@Summary		End a coaching relationship
+//	@Description	Decline or cancel a pending invitation, or end an accepted relationship. Either the coach or the athlete may do this.
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Relationship ID"
+//	@Success		204	"Relationship removed"
+//	@Failure		400	{object}	ErrorResponse	"Invalid relationship ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Relationship not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/coaching/relationships/{id} [delete]
+func (h *CoachingHandler) HandleDeleteRelationship(w http.ResponseWriter, r *http.Request) {
+	rel, ok := h.getRelationship(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.coachingStore.DeleteRelationship(rel.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Relationship not found"})
+			return
+		}
+		h.logger.Printf("Error deleting relationship: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete relationship"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/events"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+const (
+	heartbeatInterval = 15 * time.Second
+	// writeWindow is how long each write to the stream may take. The deadline
+	// is pushed forward before every write so the server's WriteTimeout does
+	// not cut off long-lived connections.
+	writeWindow = 10 * time.Second
+	// retryMillis tells EventSource clients how soon to reconnect.
+	retryMillis = 3000
+)
+
+type EventHandler struct {
+	broker    *events.Broker
+	logger    *log.Logger
+	heartbeat time.Duration
+}
+
+func NewEventHandler(broker *events.Broker, logger *log.Logger) *EventHandler {
+	return &EventHandler{broker: broker, logger: logger, heartbeat: heartbeatInterval}
+}
+
+// HandleEvents streams the caller's workout events
+//
+//	@Summary		Stream workout events
+//	@Description	Server-Sent Events stream of workout.created, workout.updated, workout.deleted, workout.restored and rest timer events for the authenticated user. Workouts are sent in the connection's unit system. Send Last-Event-ID (or last_event_id) to resume; a reset event means events were missed and data should be refetched. Comment lines are sent as heartbeats.
+//	@Tags			Events
+//	@Produce		text/event-stream
+//	@Security		BearerAuth
+//	@Param			Last-Event-ID	header		int				false	"ID of the last event received"
+//	@Param			last_event_id	query		int				false	"Alternative to the Last-Event-ID header"
+//	@Param			units			query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200				{string}	string			"Event stream"
+//	@Failure		400				{object}	ErrorResponse	"Invalid Last-Event-ID"
+//	@Failure		401				{object}	ErrorResponse	"Unauthorized"
+//	@Router			/events [get]
+func (h *EventHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	lastEventID, err := readLastEventID(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid Last-Event-ID"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	sub, backlog, complete := h.broker.Subscribe(currentUser.ID, lastEventID)
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(write func() error) bool {
+		if err := rc.SetWriteDeadline(time.Now().Add(writeWindow)); err != nil {
+			h.logger.Printf("Error extending event stream deadline: %v", err)
+			return false
+		}
+		if err := write(); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	ok := send(func() error {
+		_, err := fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+		return err
+	})
+	if !ok {
+		return
+	}
+
+	if !complete {
+		ok = send(func() error {
+			_, err := fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+			return err
+		})
+		if !ok {
+			return
+		}
+	}
+	for _, event := range backlog {
+		if !send(func() error { return writeEvent(w, event, system) }) {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-sub.C:
+			if !open {
+				// Dropped for falling behind; the client resumes from the log.
+				return
+			}
+			if !send(func() error { return writeEvent(w, event, system) }) {
+				return
+			}
+		case <-ticker.C:
+			ok := send(func() error {
+				_, err := fmt.Fprint(w, ": heartbeat\n\n")
+				return err
+			})
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func readLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// writeEvent writes one event. Workouts are published in metric and shared
+// by every subscriber, so each stream converts its own copy.
+func writeEvent(w http.ResponseWriter, event events.Event, system units.System) error {
+	payload := event.Data
+	if workout, ok := payload.(*store.Workout); ok {
+		payload = workout.InUnits(system)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
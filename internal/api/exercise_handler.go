@@ -0,0 +1,65 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type ExerciseHandler struct {
+	exerciseStore store.ExerciseStore
+	logger        *log.Logger
+}
+
+func NewExerciseHandler(store store.ExerciseStore, logger *log.Logger) *ExerciseHandler {
+	return &ExerciseHandler{exerciseStore: store, logger: logger}
+}
+
+// getExercise loads the exercise named by the id URL parameter and writes the
+// error response itself when it is missing.
+func getExercise(w http.ResponseWriter, r *http.Request, exercises store.ExerciseStore, logger *log.Logger) (*store.Exercise, bool) {
+	exerciseID, err := utils.ReadIdParam(r)
+	if err != nil {
+		logger.Printf("Error reading exercise ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid exercise ID"})
+		return nil, false
+	}
+
+	exercise, err := exercises.GetExerciseByID(exerciseID)
+	if err != nil {
+		logger.Printf("Error retrieving exercise: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve exercise"})
+		return nil, false
+	}
+	if exercise == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Exercise not found"})
+		return nil, false
+	}
+
+	return exercise, true
+}
+
+// HandleGetAllExercises lists the exercise catalog
+//
+//	@Summary		List exercises
+//	@Description	Retrieve the exercise catalog with muscle groups and categories
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		store.Exercise	"Exercise catalog"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/exercises [get]
+func (h *ExerciseHandler) HandleGetAllExercises(w http.ResponseWriter, r *http.Request) {
+	exercises, err := h.exerciseStore.GetAllExercises()
+	if err != nil {
+		h.logger.Printf("Error retrieving exercises: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve exercises"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"exercises": exercises})
+}
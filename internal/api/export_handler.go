@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/export"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type ExportHandler struct {
+	workoutStore store.WorkoutStore
+	logger       *log.Logger
+}
+
+func NewExportHandler(workoutStore store.WorkoutStore, logger *log.Logger) *ExportHandler {
+	return &ExportHandler{
+		workoutStore: workoutStore,
+		logger:       logger,
+	}
+}
+
+// HandleExportWorkouts streams the caller's training log as a download
+//
+//	@Summary		Export workouts
+//	@Description	Download every workout, entry and logged set of the authenticated user, oldest first. csv has one row per set with a stable column layout (see README), with weight, distance and pace columns in the request's unit system after the metric ones; json is a single array and ndjson one workout per line, both in the WorkoutResponse shape. Trashed workouts are not exported.
+//	@Tags			Workouts
+//	@Produce		text/csv
+//	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Security		BearerAuth
+//	@Param			format	query		string			false	"csv (default), json or ndjson"
+//	@Param			from	query		string			false	"First day to include (YYYY-MM-DD, user's time zone)"
+//	@Param			to		query		string			false	"Last day to include (YYYY-MM-DD, user's time zone)"
+//	@Param			bom		query		bool			false	"Prefix csv output with a UTF-8 byte order mark"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{file}		file			"Export file"
+//	@Failure		400		{object}	ErrorResponse	"Invalid format, bom, units or date range"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/export [get]
+func (h *ExportHandler) HandleExportWorkouts(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r)
+	loc := userLocation(user)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatJSON && format != export.FormatNDJSON {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": export.ErrUnknownFormat.Error()})
+		return
+	}
+
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	opts := export.Options{Location: loc, Units: system}
+	if value := r.URL.Query().Get("bom"); value != "" {
+		bom, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "bom must be true or false"})
+			return
+		}
+		opts.BOM = bom
+	}
+
+	filename := "training-log"
+	var from, to *time.Time
+	fromDate, ok, err := utils.ReadDateParam(r, "from", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if ok {
+		from = &fromDate
+		filename += "-" + fromDate.Format("2006-01-02")
+	}
+	toDate, ok, err := utils.ReadDateParam(r, "to", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if ok {
+		filename += "-" + toDate.Format("2006-01-02")
+		end := toDate.AddDate(0, 0, 1)
+		to = &end
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "from must not be after to"})
+		return
+	}
+
+	// Headers are only committed once the first workout has been read, so a
+	// failure before then can still be reported as a normal error response.
+	var writer export.Writer
+	start := func() error {
+		w.Header().Set("Content-Type", export.ContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+		w.WriteHeader(http.StatusOK)
+		var err error
+		writer, err = export.NewWriter(format, w, opts)
+		return err
+	}
+
+	rc := http.NewResponseController(w)
+	err = h.workoutStore.ExportWorkouts(user.ID, from, to, func(workout *store.Workout) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		// large exports outlive the server's WriteTimeout, so the deadline is
+		// pushed forward as each workout is written
+		if err := rc.SetWriteDeadline(time.Now().Add(writeWindow)); err != nil {
+			return err
+		}
+		return writer.WriteWorkout(workout)
+	})
+	if err != nil && writer == nil {
+		h.logger.Printf("Error exporting workouts: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to export workouts"})
+		return
+	}
+	if err != nil {
+		// the response is already under way; the truncated file is all the
+		// client will see
+		h.logger.Printf("Error exporting workouts for user %d: %v", user.ID, err)
+		return
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			h.logger.Printf("Error exporting workouts for user %d: %v", user.ID, err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		h.logger.Printf("Error exporting workouts for user %d: %v", user.ID, err)
+	}
+}
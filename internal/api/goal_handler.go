@@ -0,0 +1,226 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type GoalHandler struct {
+	goalStore store.GoalStore
+	logger    *log.Logger
+}
+
+func NewGoalHandler(goalStore store.GoalStore, logger *log.Logger) *GoalHandler {
+	return &GoalHandler{
+		goalStore: goalStore,
+		logger:    logger,
+	}
+}
+
+// GoalRequest is a goal in the request's unit system. Workout and distance
+// goals need either a period or a deadline; lift goals need an exercise.
+type GoalRequest struct {
+	Type         string  `json:"type" example:"distance"`         // workouts, distance or lift
+	ExerciseName *string `json:"exercise_name" example:"Running"` // Required for lift goals; limits distance goals to one exercise
+	Target       float64 `json:"target" example:"50"`             // Workouts, km or mi, or kg or lb
+	Period       *string `json:"period" example:"month"`          // week or month for goals that recur
+	Deadline     *string `json:"deadline" example:"2024-06-30"`   // Last day (YYYY-MM-DD) for one-off goals
+}
+
+// GoalsResponse lists goals with the user's training streaks.
+type GoalsResponse struct {
+	Goals   []*store.Goal  `json:"goals"`
+	Streaks *store.Streaks `json:"streaks"`
+}
+
+// getOwnedGoal loads the goal named by the id URL parameter and writes the
+// error response itself when it is missing or not the caller's.
+func (h *GoalHandler) getOwnedGoal(w http.ResponseWriter, r *http.Request) (*store.Goal, bool) {
+	goalID, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading goal ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid goal ID"})
+		return nil, false
+	}
+
+	goal, err := h.goalStore.GetGoalByID(goalID)
+	if err != nil {
+		h.logger.Printf("Error retrieving goal: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve goal"})
+		return nil, false
+	}
+	// other users' goals are reported as missing rather than forbidden
+	if goal == nil || goal.UserID != middleware.GetUser(r).ID {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Goal not found"})
+		return nil, false
+	}
+
+	return goal, true
+}
+
+// HandleCreateGoal sets a goal
+//
+//	@Summary		Create a goal
+//	@Description	Set a goal for the authenticated user: a number of workouts or a distance per week or month or by a deadline, or a weight to lift in one exercise. Progress is tracked from completed workouts in the user's time zone and updated as workouts are saved. Distances and weights are in the request's unit system.
+//	@Tags			Goals
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Param			goal	body		GoalRequest		true	"Goal"
+//	@Success		201		{object}	store.Goal		"Goal created with its progress"
+//	@Failure		400		{object}	ErrorResponse	"Invalid goal"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/goals [post]
+func (h *GoalHandler) HandleCreateGoal(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	var req GoalRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	goal := &store.Goal{
+		UserID:       middleware.GetUser(r).ID,
+		Type:         req.Type,
+		ExerciseName: req.ExerciseName,
+		Target:       req.Target,
+		Period:       req.Period,
+		Deadline:     req.Deadline,
+	}
+	goal.FromUnits(system)
+	goal, err = h.goalStore.CreateGoal(goal)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidGoal) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error creating goal: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create goal"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"goal": goal.InUnits(system)})
+}
+
+// HandleGetGoals lists the authenticated user's goals with their progress
+//
+//	@Summary		List goals
+//	@Description	List the authenticated user's goals, oldest first, with current progress, percent complete and the projected completion date at the current rate, together with their current and longest training streaks in days and weeks. Streaks and recurring goal windows follow the user's time zone; weeks start on Monday.
+//	@Tags			Goals
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	GoalsResponse	"Goals and streaks"
+//	@Failure		400		{object}	ErrorResponse	"Invalid query parameters"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/goals [get]
+func (h *GoalHandler) HandleGetGoals(w http.ResponseWriter, r *http.Request) {
+	currentUser := middleware.GetUser(r)
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	goals, err := h.goalStore.GetGoalsForUser(currentUser.ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving goals: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve goals"})
+		return
+	}
+	streaks, err := h.goalStore.GetStreaks(currentUser.ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving streaks: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve streaks"})
+		return
+	}
+
+	for i, goal := range goals {
+		goals[i] = goal.InUnits(system)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"goals": goals, "streaks": streaks})
+}
+
+// HandleGetGoalByID returns one of the authenticated user's goals
+//
+//	@Summary		Get goal by ID
+//	@Description	Retrieve one of the authenticated user's goals with its current progress
+//	@Tags			Goals
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Goal ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	store.Goal		"Goal"
+//	@Failure		400		{object}	ErrorResponse	"Invalid goal ID"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse	"Goal not found"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/goals/{id} [get]
+func (h *GoalHandler) HandleGetGoalByID(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	goal, ok := h.getOwnedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"goal": goal.InUnits(system)})
+}
+
+// HandleDeleteGoal deletes a goal
+//
+//	@Summary		Delete goal
+//	@Description	Delete one of the authenticated user's goals
+//	@Tags			Goals
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Goal ID"
+//	@Success		204	"Goal deleted"
+//	@Failure		400	{object}	ErrorResponse	"Invalid goal ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Goal not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/goals/{id} [delete]
+func (h *GoalHandler) HandleDeleteGoal(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.getOwnedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.goalStore.DeleteGoal(goal.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Goal not found"})
+			return
+		}
+		h.logger.Printf("Error deleting goal: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete goal"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
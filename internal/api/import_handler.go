@@ -0,0 +1,272 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mounis-bhat/rest-api-go/internal/events"
+	"github.com/mounis-bhat/rest-api-go/internal/importer"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/track"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+// maxImportSize bounds uploaded import files; years of logs from other apps
+// and long GPS recordings are a few megabytes.
+const maxImportSize = 20 << 20
+
+// ImportResponse reports what an import found in the file and, unless it
+// was a dry run, what was stored.
+type ImportResponse struct {
+	DryRun            bool                       `json:"dry_run" example:"true"`         // Whether this was a preview
+	Format            string                     `json:"format" example:"strong"`        // Detected or requested format
+	Rows              int                        `json:"rows" example:"1250"`            // Data rows read from the file
+	WorkoutCount      int                        `json:"workout_count" example:"84"`     // Workouts found in the file
+	Imported          int                        `json:"imported" example:"84"`          // Workouts stored, 0 for a dry run
+	SkippedDuplicates int                        `json:"skipped_duplicates" example:"0"` // Workouts already imported before, 0 for a dry run
+	Workouts          []*store.Workout           `json:"workouts,omitempty"`             // Parsed workouts, dry run only
+	Exercises         []importer.ExerciseMapping `json:"exercises"`                      // How each exercise name was mapped
+	Errors            []importer.RowError        `json:"errors"`                         // Rows that were skipped and why
+}
+
+type ImportHandler struct {
+	workoutStore  store.WorkoutStore
+	exerciseStore store.ExerciseStore
+	publisher     events.Publisher
+	logger        *log.Logger
+}
+
+func NewImportHandler(workoutStore store.WorkoutStore, exerciseStore store.ExerciseStore, publisher events.Publisher, logger *log.Logger) *ImportHandler {
+	return &ImportHandler{
+		workoutStore:  workoutStore,
+		exerciseStore: exerciseStore,
+		publisher:     publisher,
+		logger:        logger,
+	}
+}
+
+// readUpload returns the multipart field "file", writing an error response
+// when the request has none or is too large.
+func readUpload(w http.ResponseWriter, r *http.Request) (multipart.File, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteJSON(w, http.StatusRequestEntityTooLarge, utils.Envelope{"error": "file must be at most 20 MB"})
+			return nil, false
+		}
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "expected a multipart form with a file field"})
+		return nil, false
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "file is required"})
+		return nil, false
+	}
+	return file, true
+}
+
+// HandleImportWorkouts imports workouts from another app's CSV export
+//
+//	@Summary		Import workouts
+//	@Description	Upload a Strong or Hevy CSV export as multipart field file. The format is detected from the header unless format is given. Rows are grouped into completed workouts, exercise names are fuzzily mapped onto the exercise catalog and rows that cannot be read are reported by line number and skipped. With dry_run=true nothing is stored and the parsed workouts are returned for review; otherwise all workouts are stored in one transaction, skipping ones imported before. Timestamps are read in the user's time zone.
+//	@Tags			Workouts
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			file	formData	file			true	"CSV export"
+//	@Param			format	formData	string			false	"Force a format: strong or hevy"
+//	@Param			dry_run	query		bool			false	"Preview without storing anything"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	ImportResponse	"Dry run preview"
+//	@Success		201		{object}	ImportResponse	"Import result"
+//	@Failure		400		{object}	ErrorResponse	"Missing or unreadable file, unknown format or no workouts found"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		413		{object}	ErrorResponse	"File too large"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/import [post]
+func (h *ImportHandler) HandleImportWorkouts(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	user := middleware.GetUser(r)
+
+	file, ok := readUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	dryRun := false
+	if value := r.FormValue("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+
+	exercises, err := h.exerciseStore.GetAllExercises()
+	if err != nil {
+		h.logger.Printf("Error retrieving exercises: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to import workouts"})
+		return
+	}
+	names := make([]string, len(exercises))
+	for i, exercise := range exercises {
+		names[i] = exercise.Name
+	}
+
+	result, err := importer.Parse(file, importer.Options{
+		Format:    r.FormValue("format"),
+		Location:  userLocation(user),
+		Exercises: names,
+	})
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if len(result.Workouts) == 0 {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "no workouts found in file", "errors": result.Errors})
+		return
+	}
+
+	response := ImportResponse{
+		DryRun:       dryRun,
+		Format:       result.Format,
+		Rows:         result.Rows,
+		WorkoutCount: len(result.Workouts),
+		Exercises:    result.Exercises,
+		Errors:       result.Errors,
+	}
+	if dryRun {
+		response.Workouts = workoutsInUnits(result.Workouts, system)
+		utils.WriteJSON(w, http.StatusOK, utils.Envelope{"import": response})
+		return
+	}
+
+	imported, skipped, err := h.workoutStore.ImportWorkouts(user.ID, result.Workouts, user.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error importing workouts: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to import workouts"})
+		return
+	}
+
+	response.Imported = len(imported)
+	response.SkippedDuplicates = skipped
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"import": response})
+}
+
+// trackExercises names the entry created for each sport.
+var trackExercises = map[string]string{
+	track.SportRunning:  "Running",
+	track.SportCycling:  "Cycling",
+	track.SportWalking:  "Walking",
+	track.SportHiking:   "Hiking",
+	track.SportSwimming: "Swimming",
+	track.SportRowing:   "Rowing",
+	track.SportOther:    "Cardio",
+}
+
+// HandleImportTrack creates a workout from a GPX or TCX recording
+//
+//	@Summary		Import a GPS track
+//	@Description	Upload a GPX or TCX file as multipart field file. A completed workout is created with one entry for the sport, the recording's start and end times, its device-reported calories, and a track summary: distance, moving time, elevation gain, average speed and pace, heart rate and per-kilometre splits. The raw points are kept for GET /workouts/{id}/track.
+//	@Tags			Workouts
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			file	formData	file			true	"GPX or TCX file"
+//	@Param			title	formData	string			false	"Workout title, defaults to the recording's name or the sport"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	WorkoutResponse	"Created workout"
+//	@Failure		400		{object}	ErrorResponse	"Missing or unreadable file"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	ErrorResponse	"Recording already imported"
+//	@Failure		413		{object}	ErrorResponse	"File too large"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/import/track [post]
+func (h *ImportHandler) HandleImportTrack(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	user := middleware.GetUser(r)
+
+	file, ok := readUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	activity, err := track.Parse(file)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid track file: " + err.Error()})
+		return
+	}
+	summary := track.Summarize(activity)
+
+	exerciseName := trackExercises[activity.Sport]
+	title := strings.TrimSpace(r.FormValue("title"))
+	if title == "" {
+		title = activity.Name
+	}
+	if title == "" {
+		title = exerciseName
+	}
+
+	startedAt := activity.Points[0].Time
+	endedAt := activity.Points[len(activity.Points)-1].Time
+	moving := summary.MovingSeconds
+	entry := store.WorkoutEntry{ExerciseName: exerciseName, Sets: 1, DurationSeconds: &moving}
+	if summary.DistanceMeters > 0 {
+		entry.DistanceMeters = &summary.DistanceMeters
+	}
+	if summary.AveragePaceSecondsPerKm > 0 {
+		entry.PaceSecondsPerKm = &summary.AveragePaceSecondsPerKm
+	}
+	workout := &store.Workout{
+		UserID:          user.ID,
+		Title:           title,
+		DurationMinutes: int(math.Round(float64(summary.ElapsedSeconds) / 60)),
+		CaloriesBurned:  activity.Calories,
+		StartedAt:       &startedAt,
+		EndedAt:         &endedAt,
+		Entries:         []store.WorkoutEntry{entry},
+	}
+
+	result, err := h.workoutStore.ImportTrackWorkout(workout, summary, activity.Points, user.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrWorkoutAlreadyImported) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error importing track: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to import track"})
+		return
+	}
+
+	h.publisher.Publish(user.ID, events.WorkoutCreated, result)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": result.InUnits(system)})
+}
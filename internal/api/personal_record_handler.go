@@ -0,0 +1,62 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type PersonalRecordHandler struct {
+	recordStore store.PersonalRecordStore
+	logger      *log.Logger
+}
+
+func NewPersonalRecordHandler(store store.PersonalRecordStore, logger *log.Logger) *PersonalRecordHandler {
+	return &PersonalRecordHandler{recordStore: store, logger: logger}
+}
+
+// HandleGetMyRecords retrieves the authenticated user's personal records
+//
+//	@Summary		Get personal records
+//	@Description	Retrieve the current personal records for every exercise, or the full record history when history=true
+//	@Tags			Records
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			history		query		bool					false	"Return every record ever set instead of the current bests"
+//	@Param			exercise	query		string					false	"Limit history to a single exercise"
+//	@Param			units		query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200			{array}		store.PersonalRecord	"Personal records"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/users/me/records [get]
+func (h *PersonalRecordHandler) HandleGetMyRecords(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+
+	var records []*store.PersonalRecord
+	if r.URL.Query().Get("history") == "true" {
+		records, err = h.recordStore.GetPersonalRecordHistory(currentUser.ID, r.URL.Query().Get("exercise"))
+	} else {
+		records, err = h.recordStore.GetPersonalRecords(currentUser.ID)
+	}
+	if err != nil {
+		h.logger.Printf("Error retrieving personal records: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve personal records"})
+		return
+	}
+
+	converted := make([]*store.PersonalRecord, len(records))
+	for i, record := range records {
+		converted[i] = record.InUnits(system)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"records": converted})
+}
@@ -0,0 +1,337 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mounis-bhat/rest-api-go/internal/events"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/plates"
+	"github.com/mounis-bhat/rest-api-go/internal/policy"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type PlateHandler struct {
+	plateStore   store.PlateStore
+	workoutStore store.WorkoutStore
+	policy       *policy.WorkoutPolicy
+	publisher    events.Publisher
+	logger       *log.Logger
+}
+
+func NewPlateHandler(plateStore store.PlateStore, workoutStore store.WorkoutStore, policy *policy.WorkoutPolicy, publisher events.Publisher, logger *log.Logger) *PlateHandler {
+	return &PlateHandler{
+		plateStore:   plateStore,
+		workoutStore: workoutStore,
+		policy:       policy,
+		publisher:    publisher,
+		logger:       logger,
+	}
+}
+
+// PlateCalculation is how to load a target weight and warm up to it, in the
+// unit of the plate inventory.
+type PlateCalculation struct {
+	Unit    string             `json:"unit" example:"kg"`
+	Loadout plates.Loadout     `json:"loadout"`
+	Warmup  []plates.WarmupSet `json:"warmup"`
+}
+
+// WarmupRequest overrides the weights warm-up sets are generated for, in the
+// request's unit system.
+type WarmupRequest struct {
+	Target *float64 `json:"target" example:"100"` // Working weight; defaults to the entry's weight
+	Bar    *float64 `json:"bar" example:"20"`     // Defaults to the inventory's bar weight
+}
+
+// toInventoryUnit converts a weight in the request's unit system to the
+// unit the inventory's plates are marked in.
+func toInventoryUnit(weight float64, system units.System, inventory *store.PlateInventory) float64 {
+	return units.Round(inventory.System().Weight(system.Kilograms(weight)), 3)
+}
+
+// readWeightParam parses an optional weight query parameter.
+func readWeightParam(r *http.Request, name string) (*float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errors.New(name + " must be a number")
+	}
+	return &weight, nil
+}
+
+// HandleGetPlateInventory returns the authenticated user's plates
+//
+//	@Summary		Get plate inventory
+//	@Description	Return the authenticated user's bar weight and plates in the unit they are marked in, or a standard gym set for their units (default is true) when none is saved
+//	@Tags			Plates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	store.PlateInventory	"Inventory"
+//	@Failure		401	{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse			"Internal server error"
+//	@Router			/plates [get]
+func (h *PlateHandler) HandleGetPlateInventory(w http.ResponseWriter, r *http.Request) {
+	inventory, err := h.plateStore.GetPlateInventory(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving plate inventory: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve plate inventory"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"inventory": inventory})
+}
+
+// HandleUpdatePlateInventory replaces the authenticated user's plates
+//
+//	@Summary		Set plate inventory
+//	@Description	Replace the authenticated user's bar weight and plates. unit is kg or lb and applies to every weight in the inventory; each plate's count is every plate of that weight owned, and plates are loaded in pairs.
+//	@Tags			Plates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			inventory	body		store.PlateInventory	true	"Inventory"
+//	@Success		200			{object}	store.PlateInventory	"Inventory saved"
+//	@Failure		400			{object}	ErrorResponse			"Invalid inventory"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/plates [put]
+func (h *PlateHandler) HandleUpdatePlateInventory(w http.ResponseWriter, r *http.Request) {
+	var inventory store.PlateInventory
+	err := json.NewDecoder(r.Body).Decode(&inventory)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	inventory.Unit = strings.ToLower(inventory.Unit)
+	err = h.plateStore.SavePlateInventory(middleware.GetUser(r).ID, &inventory)
+	if err != nil {
+		if errors.Is(err, plates.ErrInvalidInventory) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error saving plate inventory: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to save plate inventory"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"inventory": inventory})
+}
+
+// HandleCalculatePlates works out how to load a target weight
+//
+//	@Summary		Calculate plates
+//	@Description	Load target from the authenticated user's plate inventory: the plates per side, heaviest first, for the heaviest weight up to target that the plates make, using the fewest plates, and a warm-up ramp of the empty bar and about 40, 60 and 80 percent of it. target and bar are in the request's unit system; the result is in the inventory's unit.
+//	@Tags			Plates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			target	query		number				true	"Weight to load"
+//	@Param			bar		query		number				false	"Bar weight; defaults to the inventory's"
+//	@Param			units	query		string				false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	PlateCalculation	"Loadout and warm-up"
+//	@Failure		400		{object}	ErrorResponse		"Invalid weights"
+//	@Failure		401		{object}	ErrorResponse		"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse		"Internal server error"
+//	@Router			/plates/calculate [get]
+func (h *PlateHandler) HandleCalculatePlates(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	target, err := readWeightParam(r, "target")
+	if err == nil && target == nil {
+		err = errors.New("target is required")
+	}
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	bar, err := readWeightParam(r, "bar")
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	inventory, err := h.plateStore.GetPlateInventory(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving plate inventory: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve plate inventory"})
+		return
+	}
+
+	calculation, err := calculatePlates(inventory, toInventoryUnit(*target, system, inventory), bar, system)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"unit": calculation.Unit, "loadout": calculation.Loadout, "warmup": calculation.Warmup})
+}
+
+// calculatePlates loads target, in the inventory's unit, on bar, in the
+// request's unit system or the inventory's bar when nil.
+func calculatePlates(inventory *store.PlateInventory, target float64, bar *float64, system units.System) (*PlateCalculation, error) {
+	barWeight := inventory.BarWeight
+	if bar != nil {
+		barWeight = toInventoryUnit(*bar, system, inventory)
+	}
+
+	loadout, err := plates.Load(target, barWeight, inventory.Plates)
+	if err != nil {
+		return nil, err
+	}
+	warmup, err := plates.Warmup(target, barWeight, inventory.Plates, plates.Ramp)
+	if err != nil {
+		return nil, err
+	}
+	return &PlateCalculation{Unit: inventory.Unit, Loadout: loadout, Warmup: warmup}, nil
+}
+
+// HandleSetWarmupSets adds generated warm-up sets to a workout entry
+//
+//	@Summary		Add warm-up sets to an entry
+//	@Description	Generate the warm-up ramp for an entry's working weight from the workout owner's plate inventory and log it as warm-up sets ahead of the entry's working sets, replacing any warm-ups added before. Warm-up sets are marked warmup in set_log and do not count towards the entry's sets, reps, weight or rpe. target and bar are in the request's unit system.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Workout ID"
+//	@Param			entryId	path		int						true	"Entry ID"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Param			warmup	body		WarmupRequest			false	"Weights to warm up for"
+//	@Success		200		{object}	WorkoutEntryResponse	"Entry with its warm-up sets"
+//	@Failure		400		{object}	ErrorResponse			"Invalid weights"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse			"Forbidden - not the owner or a coach with write access"
+//	@Failure		404		{object}	ErrorResponse			"Workout or entry not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/workouts/{id}/entries/{entryId}/warmup [post]
+func (h *PlateHandler) HandleSetWarmupSets(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+	entryId, err := strconv.ParseInt(chi.URLParam(r, "entryId"), 10, 64)
+	if err != nil || entryId <= 0 {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid entry ID"})
+		return
+	}
+
+	var req WarmupRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	ownerID, err := h.policy.Authorize(currentUser.ID, workoutId, policy.WriteWorkout)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		if errors.Is(err, policy.ErrForbidden) {
+			h.logger.Printf("User %d is not authorized to %s workout %d", currentUser.ID, policy.WriteWorkout, workoutId)
+			utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+			return
+		}
+		h.logger.Printf("Error authorizing workout access: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout owner"})
+		return
+	}
+
+	workout, err := h.workoutStore.GetWorkoutById(workoutId)
+	if err != nil {
+		h.logger.Printf("Error retrieving workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout"})
+		return
+	}
+	var entry *store.WorkoutEntry
+	for i := range workout.Entries {
+		if int64(workout.Entries[i].ID) == entryId {
+			entry = &workout.Entries[i]
+		}
+	}
+	if entry == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Entry not found"})
+		return
+	}
+
+	// the lifter's plates, not a coach's
+	inventory, err := h.plateStore.GetPlateInventory(ownerID)
+	if err != nil {
+		h.logger.Printf("Error retrieving plate inventory: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve plate inventory"})
+		return
+	}
+
+	var target float64
+	switch {
+	case req.Target != nil:
+		target = toInventoryUnit(*req.Target, system, inventory)
+	case entry.Weight != nil:
+		target = units.Round(inventory.System().Weight(*entry.Weight), 3)
+	default:
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "target is required for entries without a weight"})
+		return
+	}
+
+	calculation, err := calculatePlates(inventory, target, req.Bar, system)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	sets := make([]store.WorkoutSet, 0, len(calculation.Warmup))
+	for _, warmup := range calculation.Warmup {
+		reps := warmup.Reps
+		weight := inventory.System().Kilograms(warmup.Total)
+		sets = append(sets, store.WorkoutSet{Reps: &reps, Weight: &weight, Warmup: true})
+	}
+
+	entry, err = h.workoutStore.SetWarmupSets(workoutId, entryId, sets, currentUser.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Entry not found"})
+			return
+		}
+		h.logger.Printf("Error adding warm-up sets: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to add warm-up sets"})
+		return
+	}
+
+	if updated, err := h.workoutStore.GetWorkoutById(workoutId); err != nil || updated == nil {
+		h.logger.Printf("Error loading workout %d for event: %v", workoutId, err)
+	} else {
+		h.publisher.Publish(ownerID, events.WorkoutUpdated, updated)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"entry": entry.InUnits(system), "unit": calculation.Unit, "warmup": calculation.Warmup})
+}
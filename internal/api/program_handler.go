@@ -0,0 +1,601 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/events"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type enrollRequest struct {
+	StartDate string `json:"start_date" example:"2025-01-06"` // First day of the program (YYYY-MM-DD), defaults to today
+}
+
+type completeSessionRequest struct {
+	WorkoutID int `json:"workout_id" example:"42" validate:"required"` // Workout that fulfilled the planned session
+}
+
+type ProgramHandler struct {
+	programStore  store.ProgramStore
+	templateStore store.TemplateStore
+	workoutStore  store.WorkoutStore
+	publisher     events.Publisher
+	logger        *log.Logger
+}
+
+func NewProgramHandler(programStore store.ProgramStore, templateStore store.TemplateStore, workoutStore store.WorkoutStore, publisher events.Publisher, logger *log.Logger) *ProgramHandler {
+	return &ProgramHandler{
+		programStore:  programStore,
+		templateStore: templateStore,
+		workoutStore:  workoutStore,
+		publisher:     publisher,
+		logger:        logger,
+	}
+}
+
+func (h *ProgramHandler) validateProgram(program *store.Program, userID int64) error {
+	if program.Name == "" {
+		return errors.New("name is required")
+	}
+	if program.Weeks <= 0 {
+		return errors.New("weeks must be greater than 0")
+	}
+	for _, day := range program.Days {
+		if day.DayOfWeek < 1 || day.DayOfWeek > 7 {
+			return errors.New("day_of_week must be between 1 (Monday) and 7 (Sunday)")
+		}
+		if day.WeekNumber != nil && (*day.WeekNumber < 1 || *day.WeekNumber > program.Weeks) {
+			return errors.New("week_number must be within the program's weeks")
+		}
+		template, err := h.templateStore.GetTemplateByID(int64(day.TemplateID))
+		if err != nil {
+			return err
+		}
+		if template == nil || template.UserID != userID {
+			return errors.New("template not found")
+		}
+	}
+	for _, rule := range program.Progressions {
+		if rule.ExerciseName == "" {
+			return errors.New("progression exercise_name is required")
+		}
+	}
+	return nil
+}
+
+// getOwnedProgram loads the program named by the id URL parameter and writes
+// the error response itself when it is missing or not the caller's.
+func (h *ProgramHandler) getOwnedProgram(w http.ResponseWriter, r *http.Request) (*store.Program, bool) {
+	programId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading program ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid program ID"})
+		return nil, false
+	}
+
+	program, err := h.programStore.GetProgramByID(programId)
+	if err != nil {
+		h.logger.Printf("Error retrieving program: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve program"})
+		return nil, false
+	}
+	if program == nil {
+		h.logger.Printf("Program with ID %d not found", programId)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Program not found"})
+		return nil, false
+	}
+
+	currentUser := middleware.GetUser(r)
+	if program.UserID != currentUser.ID {
+		h.logger.Printf("User %d is not authorized to access program %d", currentUser.ID, programId)
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return nil, false
+	}
+
+	return program, true
+}
+
+// getOwnedSession loads the planned session named by the id URL parameter
+// and writes the error response itself when it is missing or not the caller's.
+func (h *ProgramHandler) getOwnedSession(w http.ResponseWriter, r *http.Request) (*store.PlannedSession, bool) {
+	sessionId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading session ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid session ID"})
+		return nil, false
+	}
+
+	session, err := h.programStore.GetPlannedSessionByID(sessionId)
+	if err != nil {
+		h.logger.Printf("Error retrieving planned session: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve planned session"})
+		return nil, false
+	}
+	if session == nil {
+		h.logger.Printf("Planned session with ID %d not found", sessionId)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Planned session not found"})
+		return nil, false
+	}
+
+	currentUser := middleware.GetUser(r)
+	if session.UserID != currentUser.ID {
+		h.logger.Printf("User %d is not authorized to access planned session %d", currentUser.ID, sessionId)
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return nil, false
+	}
+
+	return session, true
+}
+
+// HandleCreateProgram creates a multi-week training program
+//
+//	@Summary		Create a training program
+//	@Description	Create a program spanning N weeks with templates assigned to weekdays and weekly progression rules
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			program	body		store.Program	true	"Program data"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	store.Program	"Program created successfully"
+//	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/programs [post]
+func (h *ProgramHandler) HandleCreateProgram(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	var program store.Program
+	err = json.NewDecoder(r.Body).Decode(&program)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	program.FromUnits(system)
+	currentUser := middleware.GetUser(r)
+	program.UserID = currentUser.ID
+
+	err = h.validateProgram(&program, currentUser.ID)
+	if err != nil {
+		h.logger.Printf("Validation error: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	result, err := h.programStore.CreateProgram(&program)
+	if err != nil {
+		h.logger.Printf("Error creating program: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create program"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"program": result.InUnits(system)})
+}
+
+// HandleGetPrograms lists the authenticated user's programs
+//
+//	@Summary		List training programs
+//	@Description	Retrieve all training programs owned by the authenticated user
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		store.Program	"List of programs"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/programs [get]
+func (h *ProgramHandler) HandleGetPrograms(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	programs, err := h.programStore.GetProgramsForUser(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving programs: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve programs"})
+		return
+	}
+
+	converted := make([]*store.Program, len(programs))
+	for i, program := range programs {
+		converted[i] = program.InUnits(system)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"programs": converted})
+}
+
+// HandleGetProgramByID retrieves a program by ID
+//
+//	@Summary		Get training program by ID
+//	@Description	Retrieve a program with its days and progression rules (only by the owner)
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Program ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	store.Program	"Program details"
+//	@Failure		400		{object}	ErrorResponse	"Invalid program ID"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404		{object}	ErrorResponse	"Program not found"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/programs/{id} [get]
+func (h *ProgramHandler) HandleGetProgramByID(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	program, ok := h.getOwnedProgram(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"program": program.InUnits(system)})
+}
+
+// HandleDeleteProgram deletes a program
+//
+//	@Summary		Delete training program
+//	@Description	Delete a program together with its enrollments and planned sessions (only by the owner)
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Program ID"
+//	@Success		204	"Program deleted successfully"
+//	@Failure		400	{object}	ErrorResponse	"Invalid program ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404	{object}	ErrorResponse	"Program not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/programs/{id} [delete]
+func (h *ProgramHandler) HandleDeleteProgram(w http.ResponseWriter, r *http.Request) {
+	program, ok := h.getOwnedProgram(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.programStore.DeleteProgram(int64(program.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Program not found"})
+			return
+		}
+		h.logger.Printf("Error deleting program: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete program"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleEnroll enrolls the authenticated user in a program
+//
+//	@Summary		Enroll in a training program
+//	@Description	Start a program on the given date; every session is planned up front in the user's time zone
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Program ID"
+//	@Param			enrollment	body		enrollRequest			false	"Enrollment options"
+//	@Success		201			{object}	store.ProgramEnrollment	"Enrollment created"
+//	@Failure		400			{object}	ErrorResponse			"Invalid request payload"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403			{object}	ErrorResponse			"Forbidden - not the owner"
+//	@Failure		404			{object}	ErrorResponse			"Program not found"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/programs/{id}/enroll [post]
+func (h *ProgramHandler) HandleEnroll(w http.ResponseWriter, r *http.Request) {
+	program, ok := h.getOwnedProgram(w, r)
+	if !ok {
+		return
+	}
+
+	var req enrollRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.logger.Printf("Error decoding request body: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+			return
+		}
+	}
+
+	currentUser := middleware.GetUser(r)
+	if req.StartDate == "" {
+		req.StartDate = time.Now().In(userLocation(currentUser)).Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", req.StartDate); err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "start_date must be YYYY-MM-DD"})
+		return
+	}
+
+	enrollment, err := h.programStore.Enroll(program, currentUser.ID, req.StartDate)
+	if err != nil {
+		h.logger.Printf("Error enrolling in program: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to enroll in program"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"enrollment": enrollment})
+}
+
+// HandleGetAdherence reports adherence for an enrollment
+//
+//	@Summary		Get program adherence
+//	@Description	Report how many of the sessions due so far were completed
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int				true	"Enrollment ID"
+//	@Success		200	{object}	store.Adherence	"Adherence report"
+//	@Failure		400	{object}	ErrorResponse	"Invalid enrollment ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404	{object}	ErrorResponse	"Enrollment not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/enrollments/{id}/adherence [get]
+func (h *ProgramHandler) HandleGetAdherence(w http.ResponseWriter, r *http.Request) {
+	enrollmentId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading enrollment ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid enrollment ID"})
+		return
+	}
+
+	enrollment, err := h.programStore.GetEnrollmentByID(enrollmentId)
+	if err != nil {
+		h.logger.Printf("Error retrieving enrollment: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve enrollment"})
+		return
+	}
+	if enrollment == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Enrollment not found"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	if enrollment.UserID != currentUser.ID {
+		h.logger.Printf("User %d is not authorized to access enrollment %d", currentUser.ID, enrollmentId)
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return
+	}
+
+	today := time.Now().In(userLocation(currentUser)).Format("2006-01-02")
+	adherence, err := h.programStore.GetAdherence(enrollmentId, today)
+	if err != nil {
+		h.logger.Printf("Error retrieving adherence: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve adherence"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"enrollment": enrollment, "adherence": adherence})
+}
+
+// HandleGetSchedule lists planned sessions
+//
+//	@Summary		Get training schedule
+//	@Description	List planned sessions from active and completed program enrollments between two dates in the user's time zone
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			from	query		string					false	"First day (YYYY-MM-DD), defaults to today"
+//	@Param			to		query		string					false	"Last day (YYYY-MM-DD), defaults to six days after from"
+//	@Success		200		{array}		store.PlannedSession	"Planned sessions"
+//	@Failure		400		{object}	ErrorResponse			"Invalid query parameters"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/schedule [get]
+func (h *ProgramHandler) HandleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	currentUser := middleware.GetUser(r)
+	loc := userLocation(currentUser)
+
+	from, ok, err := utils.ReadDateParam(r, "from", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if !ok {
+		now := time.Now().In(loc)
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	}
+
+	to, ok, err := utils.ReadDateParam(r, "to", loc)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+	if !ok {
+		to = from.AddDate(0, 0, 6)
+	}
+	if to.Before(from) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "from must not be after to"})
+		return
+	}
+
+	today := time.Now().In(loc).Format("2006-01-02")
+	sessions, err := h.programStore.GetSchedule(currentUser.ID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		h.logger.Printf("Error retrieving schedule: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve schedule"})
+		return
+	}
+
+	type scheduledSession struct {
+		*store.PlannedSession
+		Status string `json:"status"` // completed, in_progress, missed or planned
+	}
+	schedule := make([]scheduledSession, 0, len(sessions))
+	for _, session := range sessions {
+		status := "planned"
+		if session.CompletedAt != nil {
+			status = "completed"
+		} else if session.WorkoutID != nil {
+			status = "in_progress"
+		} else if session.ScheduledDate < today {
+			status = "missed"
+		}
+		schedule = append(schedule, scheduledSession{PlannedSession: session, Status: status})
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{
+		"timezone": loc.String(),
+		"from":     from.Format("2006-01-02"),
+		"to":       to.Format("2006-01-02"),
+		"sessions": schedule,
+	})
+}
+
+// HandleStartPlannedSession starts the live workout for a planned session
+//
+//	@Summary		Start a planned session
+//	@Description	Start a live workout from the session's template with the program's progression applied and link it to the session; the session is completed when the workout is finished
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Planned session ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	WorkoutResponse	"Active workout started for the session"
+//	@Failure		400		{object}	ErrorResponse	"Invalid session ID or workout"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404		{object}	ErrorResponse	"Planned session not found"
+//	@Failure		409		{object}	ErrorResponse	"Session already has a workout, or another workout is active"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/schedule/{id}/start [post]
+func (h *ProgramHandler) HandleStartPlannedSession(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	session, ok := h.getOwnedSession(w, r)
+	if !ok {
+		return
+	}
+
+	template, err := h.templateStore.GetTemplateByID(int64(session.TemplateID))
+	if err != nil || template == nil {
+		h.logger.Printf("Error retrieving template %d for session %d: %v", session.TemplateID, session.ID, err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve template"})
+		return
+	}
+
+	program, err := h.programStore.GetProgramByID(int64(session.ProgramID))
+	if err != nil || program == nil {
+		h.logger.Printf("Error retrieving program %d for session %d: %v", session.ProgramID, session.ID, err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve program"})
+		return
+	}
+
+	workout := template.ToWorkout(session.UserID)
+	program.ApplyProgression(workout, session.WeekNumber)
+
+	workout, err = h.workoutStore.StartPlannedWorkout(int64(session.ID), workout)
+	if err != nil {
+		if errors.Is(err, store.ErrSessionTaken) || errors.Is(err, store.ErrActiveWorkoutExists) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Planned session not found"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error starting workout for session: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to start workout"})
+		return
+	}
+
+	h.publisher.Publish(workout.UserID, events.WorkoutCreated, workout)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": workout.InUnits(system)})
+}
+
+// HandleCompletePlannedSession links an existing workout to a planned session
+//
+//	@Summary		Complete a planned session
+//	@Description	Mark a planned session as done by linking one of the user's workouts to it
+//	@Tags			Programs
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path	int						true	"Planned session ID"
+//	@Param			request	body	completeSessionRequest	true	"Workout to link"
+//	@Success		204		"Session completed"
+//	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404		{object}	ErrorResponse	"Planned session or workout not found"
+//	@Failure		409		{object}	ErrorResponse	"Session already has a workout"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/schedule/{id}/complete [post]
+func (h *ProgramHandler) HandleCompletePlannedSession(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.getOwnedSession(w, r)
+	if !ok {
+		return
+	}
+
+	var req completeSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.WorkoutID <= 0 {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "workout_id is required"})
+		return
+	}
+
+	workoutOwner, err := h.workoutStore.GetWorkoutOwner(int64(req.WorkoutID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		h.logger.Printf("Error retrieving workout owner: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout owner"})
+		return
+	}
+	if int64(workoutOwner) != session.UserID {
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return
+	}
+
+	err = h.programStore.CompletePlannedSession(int64(session.ID), req.WorkoutID)
+	if err != nil {
+		if errors.Is(err, store.ErrSessionTaken) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Planned session not found"})
+			return
+		}
+		h.logger.Printf("Error completing planned session: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to complete planned session"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/progression"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type ProgressionHandler struct {
+	exerciseStore    store.ExerciseStore
+	progressionStore store.ProgressionStore
+	logger           *log.Logger
+}
+
+func NewProgressionHandler(exerciseStore store.ExerciseStore, progressionStore store.ProgressionStore, logger *log.Logger) *ProgressionHandler {
+	return &ProgressionHandler{
+		exerciseStore:    exerciseStore,
+		progressionStore: progressionStore,
+		logger:           logger,
+	}
+}
+
+// HandleGetRecommendation recommends the next session of an exercise
+//
+//	@Summary		Get exercise recommendation
+//	@Description	Recommend the weight, reps and sets for the next session of an exercise from the authenticated user's last completed sessions of it, following their progression rules for the exercise (double progression by default). A deload is recommended after repeated failed sessions at the same weight. New weights are rounded to a pair of the smallest standard plates (2.5 kg or 5 lb); action is start when there is no history.
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int								true	"Exercise ID"
+//	@Param			units	query		string							false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	store.ExerciseRecommendation	"Recommendation"
+//	@Failure		400		{object}	ErrorResponse					"Invalid exercise ID"
+//	@Failure		401		{object}	ErrorResponse					"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse					"Exercise not found"
+//	@Failure		500		{object}	ErrorResponse					"Internal server error"
+//	@Router			/exercises/{id}/recommendation [get]
+func (h *ProgressionHandler) HandleGetRecommendation(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	rec, err := h.progressionStore.GetRecommendation(middleware.GetUser(r).ID, exercise.Name, system.Kilograms(system.PlateStep()))
+	if err != nil {
+		h.logger.Printf("Error computing recommendation: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to compute recommendation"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"recommendation": rec.InUnits(system), "units": system})
+}
+
+// HandleGetProgressionRules returns the progression rules for an exercise
+//
+//	@Summary		Get progression rules
+//	@Description	Return the authenticated user's progression rules for an exercise, or the defaults (default is true) when none are saved. The increment is in the request's unit system.
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Exercise ID"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	store.ProgressionRules	"Rules"
+//	@Failure		400		{object}	ErrorResponse			"Invalid exercise ID"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse			"Exercise not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/exercises/{id}/progression [get]
+func (h *ProgressionHandler) HandleGetProgressionRules(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	rules, err := h.progressionStore.GetRules(middleware.GetUser(r).ID, exercise.Name)
+	if err != nil {
+		h.logger.Printf("Error retrieving progression rules: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve progression rules"})
+		return
+	}
+	if rules.Default {
+		rules.Increment = system.Kilograms(system.PlateStep())
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"rules": rules.InUnits(system)})
+}
+
+// HandleUpdateProgressionRules sets the progression rules for an exercise
+//
+//	@Summary		Set progression rules
+//	@Description	Replace the authenticated user's progression rules for an exercise. scheme is double (add reps from rep_min to rep_max, then weight), linear (add weight whenever every set reaches rep_max) or rpe (set the weight so rep_max reps land at target_rpe). A deload of deload_percent is recommended after deload_after consecutive failed sessions. The increment is in the request's unit system.
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Exercise ID"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Param			rules	body		progression.Rules		true	"Rules"
+//	@Success		200		{object}	store.ProgressionRules	"Rules saved"
+//	@Failure		400		{object}	ErrorResponse			"Invalid rules"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse			"Exercise not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/exercises/{id}/progression [put]
+func (h *ProgressionHandler) HandleUpdateProgressionRules(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	rules := &store.ProgressionRules{ExerciseName: exercise.Name}
+	err = json.NewDecoder(r.Body).Decode(&rules.Rules)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	rules.FromUnits(system)
+	err = h.progressionStore.SaveRules(middleware.GetUser(r).ID, rules)
+	if err != nil {
+		if errors.Is(err, progression.ErrInvalidRules) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error saving progression rules: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to save progression rules"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"rules": rules.InUnits(system)})
+}
+
+// HandleDeleteProgressionRules resets an exercise to the default rules
+//
+//	@Summary		Reset progression rules
+//	@Description	Delete the authenticated user's progression rules for an exercise so the defaults apply again
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Exercise ID"
+//	@Success		204	"Rules deleted"
+//	@Failure		400	{object}	ErrorResponse	"Invalid exercise ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Exercise or rules not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/exercises/{id}/progression [delete]
+func (h *ProgressionHandler) HandleDeleteProgressionRules(w http.ResponseWriter, r *http.Request) {
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	err := h.progressionStore.DeleteRules(middleware.GetUser(r).ID, exercise.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "No progression rules saved for this exercise"})
+			return
+		}
+		h.logger.Printf("Error deleting progression rules: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete progression rules"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
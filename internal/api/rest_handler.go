@@ -0,0 +1,143 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type RestHandler struct {
+	exerciseStore store.ExerciseStore
+	restStore     store.RestStore
+	logger        *log.Logger
+}
+
+func NewRestHandler(exerciseStore store.ExerciseStore, restStore store.RestStore, logger *log.Logger) *RestHandler {
+	return &RestHandler{
+		exerciseStore: exerciseStore,
+		restStore:     restStore,
+		logger:        logger,
+	}
+}
+
+// RestTargetRequest sets the rest between sets of an exercise.
+type RestTargetRequest struct {
+	RestSeconds int `json:"rest_seconds" example:"180"` // 0 to 3600; 0 turns the rest timer off
+}
+
+// HandleGetRestTarget returns the rest target for an exercise
+//
+//	@Summary		Get rest target
+//	@Description	Return the authenticated user's rest between sets of an exercise, or the default of 120 seconds (default is true) when none is saved. Entries with a target_rest_seconds of their own override it.
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"Exercise ID"
+//	@Success		200	{object}	store.RestTarget	"Rest target"
+//	@Failure		400	{object}	ErrorResponse		"Invalid exercise ID"
+//	@Failure		401	{object}	ErrorResponse		"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse		"Exercise not found"
+//	@Failure		500	{object}	ErrorResponse		"Internal server error"
+//	@Router			/exercises/{id}/rest [get]
+func (h *RestHandler) HandleGetRestTarget(w http.ResponseWriter, r *http.Request) {
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	target, err := h.restStore.GetRestTarget(middleware.GetUser(r).ID, exercise.Name)
+	if err != nil {
+		h.logger.Printf("Error retrieving rest target: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve rest target"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"rest_target": target})
+}
+
+// HandleUpdateRestTarget sets the rest target for an exercise
+//
+//	@Summary		Set rest target
+//	@Description	Set the authenticated user's rest between sets of an exercise, used by the rest timer and rest compliance for entries without a target_rest_seconds of their own
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int					true	"Exercise ID"
+//	@Param			rest	body		RestTargetRequest	true	"Rest target"
+//	@Success		200		{object}	store.RestTarget	"Rest target saved"
+//	@Failure		400		{object}	ErrorResponse		"Invalid rest target"
+//	@Failure		401		{object}	ErrorResponse		"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse		"Exercise not found"
+//	@Failure		500		{object}	ErrorResponse		"Internal server error"
+//	@Router			/exercises/{id}/rest [put]
+func (h *RestHandler) HandleUpdateRestTarget(w http.ResponseWriter, r *http.Request) {
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	var req RestTargetRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	target := &store.RestTarget{ExerciseName: exercise.Name, RestSeconds: req.RestSeconds}
+	err = h.restStore.SaveRestTarget(middleware.GetUser(r).ID, target)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidRestTarget) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error saving rest target: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to save rest target"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"rest_target": target})
+}
+
+// HandleDeleteRestTarget resets an exercise to the default rest target
+//
+//	@Summary		Reset rest target
+//	@Description	Delete the authenticated user's rest target for an exercise so the default applies again
+//	@Tags			Exercises
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Exercise ID"
+//	@Success		204	"Rest target deleted"
+//	@Failure		400	{object}	ErrorResponse	"Invalid exercise ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Exercise or rest target not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/exercises/{id}/rest [delete]
+func (h *RestHandler) HandleDeleteRestTarget(w http.ResponseWriter, r *http.Request) {
+	exercise, ok := getExercise(w, r, h.exerciseStore, h.logger)
+	if !ok {
+		return
+	}
+
+	err := h.restStore.DeleteRestTarget(middleware.GetUser(r).ID, exercise.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "No rest target saved for this exercise"})
+			return
+		}
+		h.logger.Printf("Error deleting rest target: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete rest target"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type createShareRequest struct {
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-02-01T00:00:00Z"` // Optional expiry, must be in the future
+}
+
+// SharedWorkoutResponse is the public, read-only view of a shared workout.
+// IDs, the owner, notes and timestamps other than the workout date are left
+// out.
+type SharedWorkoutResponse struct {
+	Title           string                `json:"title" example:"Leg Day"`            // Workout title
+	Description     string                `json:"description" example:"Heavy squats"` // Workout description
+	DurationMinutes int                   `json:"duration_minutes" example:"60"`      // Duration in minutes
+	CaloriesBurned  int                   `json:"calories_burned" example:"450"`      // Calories burned
+	Date            string                `json:"date" example:"2024-01-15"`          // Day the workout was performed
+	Entries         []SharedEntryResponse `json:"entries"`                            // Exercises in order
+	Groups          []SharedGroupResponse `json:"groups"`                             // Supersets, circuits and giant sets
+}
+
+type SharedEntryResponse struct {
+	ExerciseName    string              `json:"exercise_name" example:"Squat"` // Name of the exercise
+	Sets            int                 `json:"sets" example:"5"`              // Number of sets
+	Reps            *int                `json:"reps" example:"5"`              // Number of repetitions
+	DurationSeconds *int                `json:"duration_seconds" example:"60"` // Duration in seconds
+	Weight          *float64            `json:"weight" example:"120"`          // Weight in kg or lb
+	GroupIndex      *int                `json:"group_index" example:"0"`       // Index into groups, null when ungrouped
+	SetLog          []SharedSetResponse `json:"set_log"`                       // Individually logged sets
+
+	DistanceMeters   *float64         `json:"distance_meters" example:"5000"`       // Distance covered
+	PaceSecondsPerKm *int             `json:"pace_seconds_per_km" example:"300"`    // Pace
+	InclinePercent   *float64         `json:"incline_percent" example:"1.5"`        // Treadmill incline
+	ResistanceLevel  *int             `json:"resistance_level" example:"6"`         // Machine resistance setting
+	Intervals        *store.Intervals `json:"intervals"`                            // Work/rest repeats
+	Distance         *float64         `json:"distance,omitempty" example:"5"`       // Distance in km or mi
+	PaceSeconds      *int             `json:"pace_seconds,omitempty" example:"300"` // Pace per km or mi
+}
+
+type SharedSetResponse struct {
+	SetNumber       int      `json:"set_number" example:"1"`       // Position of the set within the entry
+	Reps            *int     `json:"reps" example:"5"`             // Repetitions performed
+	Weight          *float64 `json:"weight" example:"120"`         // Weight in kg or lb
+	DurationSeconds *int     `json:"duration_seconds" example:"0"` // Duration in seconds
+}
+
+type SharedGroupResponse struct {
+	Type                        string `json:"type" example:"superset"`                    // superset, circuit or giant_set
+	Rounds                      int    `json:"rounds" example:"3"`                         // Number of rounds
+	RestBetweenExercisesSeconds *int   `json:"rest_between_exercises_seconds" example:"0"` // Rest between exercises
+	RestBetweenRoundsSeconds    *int   `json:"rest_between_rounds_seconds" example:"90"`   // Rest after each round
+}
+
+func newSharedWorkoutResponse(workout *store.Workout) SharedWorkoutResponse {
+	performed := workout.CreatedAt
+	if workout.StartedAt != nil {
+		performed = *workout.StartedAt
+	}
+
+	response := SharedWorkoutResponse{
+		Title:           workout.Title,
+		Description:     workout.Description,
+		DurationMinutes: workout.DurationMinutes,
+		CaloriesBurned:  workout.CaloriesBurned,
+		Date:            performed.Format("2006-01-02"),
+		Entries:         []SharedEntryResponse{},
+		Groups:          []SharedGroupResponse{},
+	}
+
+	for _, entry := range workout.Entries {
+		shared := SharedEntryResponse{
+			ExerciseName:    entry.ExerciseName,
+			Sets:            entry.Sets,
+			Reps:            entry.Reps,
+			DurationSeconds: entry.DurationSeconds,
+			Weight:          entry.Weight,
+			GroupIndex:      entry.GroupIndex,
+			SetLog:          []SharedSetResponse{},
+
+			DistanceMeters:   entry.DistanceMeters,
+			PaceSecondsPerKm: entry.PaceSecondsPerKm,
+			InclinePercent:   entry.InclinePercent,
+			ResistanceLevel:  entry.ResistanceLevel,
+			Intervals:        entry.Intervals,
+			Distance:         entry.Distance,
+			PaceSeconds:      entry.PaceSeconds,
+		}
+		for _, set := range entry.SetLog {
+			shared.SetLog = append(shared.SetLog, SharedSetResponse{
+				SetNumber:       set.SetNumber,
+				Reps:            set.Reps,
+				Weight:          set.Weight,
+				DurationSeconds: set.DurationSeconds,
+			})
+		}
+		response.Entries = append(response.Entries, shared)
+	}
+
+	for _, group := range workout.Groups {
+		response.Groups = append(response.Groups, SharedGroupResponse{
+			Type:                        group.GroupType,
+			Rounds:                      group.Rounds,
+			RestBetweenExercisesSeconds: group.RestBetweenExercisesSeconds,
+			RestBetweenRoundsSeconds:    group.RestBetweenRoundsSeconds,
+		})
+	}
+
+	return response
+}
+
+type ShareHandler struct {
+	shareStore   store.ShareStore
+	workoutStore store.WorkoutStore
+	logger       *log.Logger
+}
+
+func NewShareHandler(shareStore store.ShareStore, workoutStore store.WorkoutStore, logger *log.Logger) *ShareHandler {
+	return &ShareHandler{
+		shareStore:   shareStore,
+		workoutStore: workoutStore,
+		logger:       logger,
+	}
+}
+
+// requireWorkoutOwner writes the error response itself and returns false
+// unless the current user owns the workout. Only owners manage share links.
+func (h *ShareHandler) requireWorkoutOwner(w http.ResponseWriter, r *http.Request, workoutId int64) bool {
+	workoutOwner, err := h.workoutStore.GetWorkoutOwner(workoutId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return false
+		}
+		h.logger.Printf("Error retrieving workout owner: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout owner"})
+		return false
+	}
+
+	currentUser := middleware.GetUser(r)
+	if int64(workoutOwner) != currentUser.ID {
+		h.logger.Printf("User %d is not authorized to share workout %d", currentUser.ID, workoutId)
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return false
+	}
+
+	return true
+}
+
+// HandleCreateShare creates a public share link for a workout
+//
+//	@Summary		Share a workout
+//	@Description	Create an unguessable public link to a read-only view of the workout. The token is only returned once; links can be revoked and may expire.
+//	@Tags			Sharing
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int					true	"Workout ID"
+//	@Param			share	body		createShareRequest	false	"Optional expiry"
+//	@Success		201		{object}	store.WorkoutShare	"Share created, including its token"
+//	@Failure		400		{object}	ErrorResponse		"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse		"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse		"Forbidden - not the owner"
+//	@Failure		404		{object}	ErrorResponse		"Workout not found"
+//	@Failure		500		{object}	ErrorResponse		"Internal server error"
+//	@Router			/workouts/{id}/share [post]
+func (h *ShareHandler) HandleCreateShare(w http.ResponseWriter, r *http.Request) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	var req createShareRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "expires_at must be in the future"})
+		return
+	}
+
+	if !h.requireWorkoutOwner(w, r, workoutId) {
+		return
+	}
+
+	share, err := h.shareStore.CreateShare(workoutId, middleware.GetUser(r).ID, req.ExpiresAt)
+	if err != nil {
+		h.logger.Printf("Error creating share: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create share"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"share": share, "path": "/shared/" + share.Token})
+}
+
+// HandleGetShares lists a workout's share links
+//
+//	@Summary		List share links
+//	@Description	List the share links created for a workout, including revoked and expired ones. Tokens are not shown.
+//	@Tags			Sharing
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"Workout ID"
+//	@Success		200	{array}		store.WorkoutShare	"Share links"
+//	@Failure		400	{object}	ErrorResponse		"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse		"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse		"Forbidden - not the owner"
+//	@Failure		404	{object}	ErrorResponse		"Workout not found"
+//	@Failure		500	{object}	ErrorResponse		"Internal server error"
+//	@Router			/workouts/{id}/shares [get]
+func (h *ShareHandler) HandleGetShares(w http.ResponseWriter, r *http.Request) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	if !h.requireWorkoutOwner(w, r, workoutId) {
+		return
+	}
+
+	shares, err := h.shareStore.GetSharesForWorkout(workoutId)
+	if err != nil {
+		h.logger.Printf("Error retrieving shares: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve shares"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"shares": shares})
+}
+
+// HandleRevokeShare revokes a share link
+//
+//	@Summary		Revoke a share link
+//	@Description	Disable a share link so it no longer resolves
+//	@Tags			Sharing
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Share ID"
+//	@Success		204	"Share revoked"
+//	@Failure		400	{object}	ErrorResponse	"Invalid share ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404	{object}	ErrorResponse	"Share not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/shares/{id} [delete]
+func (h *ShareHandler) HandleRevokeShare(w http.ResponseWriter, r *http.Request) {
+	shareId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading share ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid share ID"})
+		return
+	}
+
+	share, err := h.shareStore.GetShareByID(shareId)
+	if err != nil {
+		h.logger.Printf("Error retrieving share: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve share"})
+		return
+	}
+	if share == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Share not found"})
+		return
+	}
+
+	if !h.requireWorkoutOwner(w, r, share.WorkoutID) {
+		return
+	}
+
+	err = h.shareStore.RevokeShare(share.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Share not found"})
+			return
+		}
+		h.logger.Printf("Error revoking share: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to revoke share"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetSharedWorkout serves a shared workout without authentication
+//
+//	@Summary		View a shared workout
+//	@Description	Public, read-only view of a shared workout with personal data removed. Unknown, revoked and expired tokens all return 404.
+//	@Tags			Sharing
+//	@Produce		json
+//	@Param			token	path		string					true	"Share token"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	SharedWorkoutResponse	"Shared workout"
+//	@Failure		404		{object}	ErrorResponse			"Share not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/shared/{token} [get]
+func (h *ShareHandler) HandleGetSharedWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	workout, err := h.shareStore.GetSharedWorkout(chi.URLParam(r, "token"))
+	if err != nil {
+		h.logger.Printf("Error retrieving shared workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout"})
+		return
+	}
+	if workout == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Share not found"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": newSharedWorkoutResponse(workout.InUnits(system))})
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedWorkoutResponse(t *testing.T) {
+	startedAt := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+	endedAt := startedAt.Add(time.Hour)
+	source := "met"
+	workout := &store.Workout{
+		ID:              4211,
+		UserID:          9377,
+		Title:           "Leg day",
+		Description:     "Heavy squats",
+		DurationMinutes: 60,
+		CaloriesBurned:  450,
+		CaloriesSource:  &source,
+		Status:          store.WorkoutCompleted,
+		Visibility:      "private",
+		StartedAt:       &startedAt,
+		EndedAt:         &endedAt,
+		CreatedAt:       startedAt.Add(-time.Minute),
+		UpdatedAt:       endedAt,
+		TemplateID:      utils.IntPtr(5813),
+		Tags:            []string{"secret-tag"},
+		Groups: []store.EntryGroup{
+			{ID: 6301, GroupType: store.GroupSuperset, Rounds: 3, RestBetweenRoundsSeconds: utils.IntPtr(90), OrderIndex: 0},
+		},
+		Entries: []store.WorkoutEntry{
+			{
+				ID:                7529,
+				ExerciseName:      "Squat",
+				Sets:              2,
+				Reps:              utils.IntPtr(5),
+				Weight:            utils.Float64Ptr(100),
+				Notes:             "private note",
+				GroupIndex:        utils.IntPtr(0),
+				RPE:               utils.Float64Ptr(8.5),
+				TargetRestSeconds: utils.IntPtr(180),
+				SetLog: []store.WorkoutSet{
+					{ID: 8861, SetNumber: 1, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(60), Warmup: true},
+					{ID: 8862, SetNumber: 2, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100), RPE: utils.Float64Ptr(8.5),
+						StartedAt: &startedAt, CompletedAt: &endedAt, RestSeconds: utils.IntPtr(175)},
+				},
+			},
+			{
+				ID:               7530,
+				ExerciseName:     "Running",
+				Sets:             1,
+				DurationSeconds:  utils.IntPtr(1500),
+				DistanceMeters:   utils.Float64Ptr(5000),
+				PaceSecondsPerKm: utils.IntPtr(300),
+				Notes:            "another note",
+			},
+		},
+	}
+
+	response := newSharedWorkoutResponse(workout)
+	assert.Equal(t, "Leg day", response.Title)
+	assert.Equal(t, "2024-01-15", response.Date)
+	require.Len(t, response.Entries, 2)
+	require.Len(t, response.Entries[0].SetLog, 2)
+	assert.Equal(t, 100.0, *response.Entries[0].SetLog[1].Weight)
+	assert.Equal(t, 5000.0, *response.Entries[1].DistanceMeters)
+	require.Len(t, response.Groups, 1)
+
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	for _, private := range []string{"4211", "9377", "5813", "6301", "7529", "8861", "private note", "another note", "8.5", "secret-tag", "T18:00:00Z", "T19:00:00Z"} {
+		assert.NotContains(t, string(body), private)
+	}
+
+	var decoded any
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assertNoKeys(t, decoded, "id", "user_id", "username", "template_id", "notes", "rpe", "started_at", "completed_at", "ended_at", "created_at", "updated_at", "visibility", "tags", "calories_source")
+}
+
+// assertNoKeys fails if any object nested in value has one of the keys.
+func assertNoKeys(t *testing.T, value any, keys ...string) {
+	t.Helper()
+	switch value := value.(type) {
+	case map[string]any:
+		for key, nested := range value {
+			for _, forbidden := range keys {
+				assert.False(t, strings.EqualFold(key, forbidden), "shared workouts must not include %q", key)
+			}
+			assertNoKeys(t, nested, keys...)
+		}
+	case []any:
+		for _, nested := range value {
+			assertNoKeys(t, nested, keys...)
+		}
+	}
+}
@@ -0,0 +1,695 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/policy"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+)
+
+type commentRequest struct {
+	Body string `json:"body" example:"Great session!" validate:"required"` // Comment text, at most 2000 characters
+}
+
+type FeedResponse struct {
+	Items      []store.FeedItem `json:"items"`       // Workouts from followed users, newest first
+	NextCursor *string          `json:"next_cursor"` // Pass as cursor to fetch the next page, null on the last page
+}
+
+type SocialHandler struct {
+	socialStore store.SocialStore
+	policy      *policy.WorkoutPolicy
+	logger      *log.Logger
+}
+
+func NewSocialHandler(socialStore store.SocialStore, policy *policy.WorkoutPolicy, logger *log.Logger) *SocialHandler {
+	return &SocialHandler{
+		socialStore: socialStore,
+		policy:      policy,
+		logger:      logger,
+	}
+}
+
+// readUserParam reads the id URL parameter as a user ID and writes the error
+// response itself when it is invalid or the user is blocked either way.
+func (h *SocialHandler) readUserParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading user ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid user ID"})
+		return 0, false
+	}
+
+	currentUser := middleware.GetUser(r)
+	if userId == currentUser.ID {
+		return userId, true
+	}
+
+	blocked, err := h.socialStore.IsBlocked(currentUser.ID, userId)
+	if err != nil {
+		h.logger.Printf("Error checking block: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve user"})
+		return 0, false
+	}
+	if blocked {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "User not found"})
+		return 0, false
+	}
+
+	return userId, true
+}
+
+// readVisibleWorkout reads the id URL parameter as a workout the caller may
+// see and writes the error response itself otherwise.
+func (h *SocialHandler) readVisibleWorkout(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return 0, 0, false
+	}
+
+	ownerID, err := h.policy.Authorize(middleware.GetUser(r).ID, workoutId, policy.ReadWorkout)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, policy.ErrForbidden) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return 0, 0, false
+		}
+		h.logger.Printf("Error authorizing workout access: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout"})
+		return 0, 0, false
+	}
+
+	return workoutId, ownerID, true
+}
+
+// HandleFollow follows a user
+//
+//	@Summary		Follow a user
+//	@Description	Follow a user to see their public and followers-only workouts in your feed. Following twice has no effect.
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204	"Following"
+//	@Failure		400	{object}	ErrorResponse	"Invalid user ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"User not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/follow [post]
+func (h *SocialHandler) HandleFollow(w http.ResponseWriter, r *http.Request) {
+	userId, ok := h.readUserParam(w, r)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	if userId == currentUser.ID {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "You cannot follow yourself"})
+		return
+	}
+
+	err := h.socialStore.Follow(currentUser.ID, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, store.ErrBlocked) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "User not found"})
+			return
+		}
+		h.logger.Printf("Error following user: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to follow user"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUnfollow stops following a user
+//
+//	@Summary		Unfollow a user
+//	@Description	Stop following a user
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204	"No longer following"
+//	@Failure		400	{object}	ErrorResponse	"Invalid user ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/follow [delete]
+func (h *SocialHandler) HandleUnfollow(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading user ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid user ID"})
+		return
+	}
+
+	err = h.socialStore.Unfollow(middleware.GetUser(r).ID, userId)
+	if err != nil {
+		h.logger.Printf("Error unfollowing user: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to unfollow user"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetFollowers lists a user's followers
+//
+//	@Summary		List followers
+//	@Description	List the users following a user
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"User ID"
+//	@Success		200	{array}		store.UserSummary	"Followers"
+//	@Failure		400	{object}	ErrorResponse		"Invalid user ID"
+//	@Failure		401	{object}	ErrorResponse		"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse		"User not found"
+//	@Failure		500	{object}	ErrorResponse		"Internal server error"
+//	@Router			/users/{id}/followers [get]
+func (h *SocialHandler) HandleGetFollowers(w http.ResponseWriter, r *http.Request) {
+	userId, ok := h.readUserParam(w, r)
+	if !ok {
+		return
+	}
+
+	followers, err := h.socialStore.GetFollowers(userId, middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving followers: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve followers"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"followers": followers})
+}
+
+// HandleGetFollowing lists who a user follows
+//
+//	@Summary		List followed users
+//	@Description	List the users a user follows
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"User ID"
+//	@Success		200	{array}		store.UserSummary	"Followed users"
+//	@Failure		400	{object}	ErrorResponse		"Invalid user ID"
+//	@Failure		401	{object}	ErrorResponse		"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse		"User not found"
+//	@Failure		500	{object}	ErrorResponse		"Internal server error"
+//	@Router			/users/{id}/following [get]
+func (h *SocialHandler) HandleGetFollowing(w http.ResponseWriter, r *http.Request) {
+	userId, ok := h.readUserParam(w, r)
+	if !ok {
+		return
+	}
+
+	following, err := h.socialStore.GetFollowing(userId, middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving followed users: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve followed users"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"following": following})
+}
+
+// HandleBlock blocks a user
+//
+//	@Summary		Block a user
+//	@Description	Block a user. Follows and coaching relationships between you are removed and each of you stops seeing the other's workouts, likes and comments.
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204	"User blocked"
+//	@Failure		400	{object}	ErrorResponse	"Invalid user ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"User not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/block [post]
+func (h *SocialHandler) HandleBlock(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading user ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid user ID"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	if userId == currentUser.ID {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "You cannot block yourself"})
+		return
+	}
+
+	err = h.socialStore.Block(currentUser.ID, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "User not found"})
+			return
+		}
+		h.logger.Printf("Error blocking user: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to block user"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUnblock removes a block
+//
+//	@Summary		Unblock a user
+//	@Description	Remove a block. Follows removed by the block are not restored.
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204	"User unblocked"
+//	@Failure		400	{object}	ErrorResponse	"Invalid user ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/block [delete]
+func (h *SocialHandler) HandleUnblock(w http.ResponseWriter, r *http.Request) {
+	userId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading user ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid user ID"})
+		return
+	}
+
+	err = h.socialStore.Unblock(middleware.GetUser(r).ID, userId)
+	if err != nil {
+		h.logger.Printf("Error unblocking user: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to unblock user"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetBlockedUsers lists the users the caller has blocked
+//
+//	@Summary		List blocked users
+//	@Description	List the users the authenticated user has blocked
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		store.UserSummary	"Blocked users"
+//	@Failure		401	{object}	ErrorResponse		"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse		"Internal server error"
+//	@Router			/users/me/blocks [get]
+func (h *SocialHandler) HandleGetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	blocked, err := h.socialStore.GetBlockedUsers(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving blocked users: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve blocked users"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"blocked": blocked})
+}
+
+// HandleGetFeed returns recent workouts from followed users
+//
+//	@Summary		Get activity feed
+//	@Description	Completed public and followers-only workouts from users you follow, newest first, with like and comment counts. Use next_cursor to page.
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			cursor	query		string			false	"Cursor from a previous page's next_cursor"
+//	@Param			limit	query		int				false	"Page size (default 20, max 100)"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	FeedResponse	"Feed page"
+//	@Failure		400		{object}	ErrorResponse	"Invalid cursor or limit"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/feed [get]
+func (h *SocialHandler) HandleGetFeed(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	limit, err := utils.ReadLimitParam(r, defaultFeedLimit, maxFeedLimit)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	var cursor *store.FeedCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		cursor, err = store.DecodeFeedCursor(value)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid cursor"})
+			return
+		}
+	}
+
+	// fetch one extra item to learn whether another page exists
+	items, err := h.socialStore.GetFeed(middleware.GetUser(r).ID, cursor, limit+1)
+	if err != nil {
+		h.logger.Printf("Error retrieving feed: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve feed"})
+		return
+	}
+
+	var nextCursor *string
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1].Workout
+		encoded := store.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+		nextCursor = &encoded
+	}
+
+	for i, item := range items {
+		converted := *item
+		converted.Workout = item.Workout.InUnits(system)
+		items[i] = &converted
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"items": items, "next_cursor": nextCursor})
+}
+
+// HandleLikeWorkout likes a workout
+//
+//	@Summary		Like a workout
+//	@Description	Like a workout you can see. Liking twice has no effect.
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Workout ID"
+//	@Success		204	"Liked"
+//	@Failure		400	{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Workout not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/likes [post]
+func (h *SocialHandler) HandleLikeWorkout(w http.ResponseWriter, r *http.Request) {
+	workoutId, _, ok := h.readVisibleWorkout(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.socialStore.LikeWorkout(workoutId, middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error liking workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to like workout"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUnlikeWorkout removes the caller's like
+//
+//	@Summary		Unlike a workout
+//	@Description	Remove your like from a workout
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Workout ID"
+//	@Success		204	"Like removed"
+//	@Failure		400	{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/likes [delete]
+func (h *SocialHandler) HandleUnlikeWorkout(w http.ResponseWriter, r *http.Request) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	err = h.socialStore.UnlikeWorkout(workoutId, middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error unliking workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to unlike workout"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetLikes lists who liked a workout
+//
+//	@Summary		List likes
+//	@Description	List the users who liked a workout
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"Workout ID"
+//	@Success		200	{array}		store.UserSummary	"Users who liked the workout"
+//	@Failure		400	{object}	ErrorResponse		"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse		"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse		"Workout not found"
+//	@Failure		500	{object}	ErrorResponse		"Internal server error"
+//	@Router			/workouts/{id}/likes [get]
+func (h *SocialHandler) HandleGetLikes(w http.ResponseWriter, r *http.Request) {
+	workoutId, _, ok := h.readVisibleWorkout(w, r)
+	if !ok {
+		return
+	}
+
+	likes, err := h.socialStore.GetLikes(workoutId, middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving likes: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve likes"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"likes": likes})
+}
+
+// HandleGetComments lists a workout's comments
+//
+//	@Summary		List comments
+//	@Description	List the comments on a workout, oldest first
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int				true	"Workout ID"
+//	@Success		200	{array}		store.Comment	"Comments"
+//	@Failure		400	{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Workout not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/comments [get]
+func (h *SocialHandler) HandleGetComments(w http.ResponseWriter, r *http.Request) {
+	workoutId, _, ok := h.readVisibleWorkout(w, r)
+	if !ok {
+		return
+	}
+
+	comments, err := h.socialStore.GetComments(workoutId, middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving comments: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve comments"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"comments": comments})
+}
+
+// HandleCreateComment comments on a workout
+//
+//	@Summary		Comment on a workout
+//	@Description	Add a comment to a workout you can see
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Workout ID"
+//	@Param			comment	body		commentRequest	true	"Comment"
+//	@Success		201		{object}	store.Comment	"Comment created"
+//	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse	"Workout not found"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/comments [post]
+func (h *SocialHandler) HandleCreateComment(w http.ResponseWriter, r *http.Request) {
+	var req commentRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	workoutId, _, ok := h.readVisibleWorkout(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := h.socialStore.CreateComment(&store.Comment{
+		WorkoutID: workoutId,
+		UserID:    middleware.GetUser(r).ID,
+		Body:      req.Body,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidComment) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error creating comment: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create comment"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"comment": comment})
+}
+
+// getComment loads the comment named by the id URL parameter and
+// writes the error response itself when it is missing or its workout is not
+// visible to the caller. It also returns the workout's owner.
+func (h *SocialHandler) getComment(w http.ResponseWriter, r *http.Request) (*store.Comment, int64, bool) {
+	commentId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading comment ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid comment ID"})
+		return nil, 0, false
+	}
+
+	comment, err := h.socialStore.GetCommentByID(commentId)
+	if err != nil {
+		h.logger.Printf("Error retrieving comment: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve comment"})
+		return nil, 0, false
+	}
+	if comment == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Comment not found"})
+		return nil, 0, false
+	}
+
+	ownerID, err := h.policy.Authorize(middleware.GetUser(r).ID, comment.WorkoutID, policy.ReadWorkout)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, policy.ErrForbidden) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Comment not found"})
+			return nil, 0, false
+		}
+		h.logger.Printf("Error authorizing workout access: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve comment"})
+		return nil, 0, false
+	}
+
+	return comment, ownerID, true
+}
+
+// HandleUpdateComment edits a comment
+//
+//	@Summary		Edit a comment
+//	@Description	Edit one of your own comments
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Comment ID"
+//	@Param			comment	body		commentRequest	true	"New comment text"
+//	@Success		200		{object}	store.Comment	"Updated comment"
+//	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the author"
+//	@Failure		404		{object}	ErrorResponse	"Comment not found"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/comments/{id} [put]
+func (h *SocialHandler) HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	var req commentRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	comment, _, ok := h.getComment(w, r)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != middleware.GetUser(r).ID {
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Only the author can edit a comment"})
+		return
+	}
+
+	comment, err = h.socialStore.UpdateComment(comment.ID, req.Body)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidComment) {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Comment not found"})
+			return
+		}
+		h.logger.Printf("Error updating comment: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to update comment"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"comment": comment})
+}
+
+// HandleDeleteComment deletes a comment
+//
+//	@Summary		Delete a comment
+//	@Description	Delete a comment. Authors can delete their own comments and workout owners can remove any comment on their workouts.
+//	@Tags			Social
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Comment ID"
+//	@Success		204	"Comment deleted"
+//	@Failure		400	{object}	ErrorResponse	"Invalid comment ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the author or workout owner"
+//	@Failure		404	{object}	ErrorResponse	"Comment not found"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/comments/{id} [delete]
+func (h *SocialHandler) HandleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	comment, ownerID, ok := h.getComment(w, r)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	if comment.UserID != currentUser.ID && ownerID != currentUser.ID {
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Only the author or the workout owner can delete a comment"})
+		return
+	}
+
+	err := h.socialStore.DeleteComment(comment.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Comment not found"})
+			return
+		}
+		h.logger.Printf("Error deleting comment: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete comment"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,437 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mounis-bhat/rest-api-go/internal/events"
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/progression"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+)
+
+type TemplateHandler struct {
+	templateStore    store.TemplateStore
+	workoutStore     store.WorkoutStore
+	progressionStore store.ProgressionStore
+	publisher        events.Publisher
+	logger           *log.Logger
+}
+
+func NewTemplateHandler(templateStore store.TemplateStore, workoutStore store.WorkoutStore, progressionStore store.ProgressionStore, publisher events.Publisher, logger *log.Logger) *TemplateHandler {
+	return &TemplateHandler{
+		templateStore:    templateStore,
+		workoutStore:     workoutStore,
+		progressionStore: progressionStore,
+		publisher:        publisher,
+		logger:           logger,
+	}
+}
+
+// StartTemplateResponse is a workout started from a template with the
+// recommended targets for its exercises.
+type StartTemplateResponse struct {
+	Workout         WorkoutResponse                 `json:"workout"`
+	Recommendations []*store.ExerciseRecommendation `json:"recommendations"`
+}
+
+// getOwnedTemplate loads the template named by the id URL parameter and
+// writes the error response itself when it is missing or not the caller's.
+func (h *TemplateHandler) getOwnedTemplate(w http.ResponseWriter, r *http.Request) (*store.WorkoutTemplate, bool) {
+	templateId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading template ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid template ID"})
+		return nil, false
+	}
+
+	template, err := h.templateStore.GetTemplateByID(templateId)
+	if err != nil {
+		h.logger.Printf("Error retrieving template: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve template"})
+		return nil, false
+	}
+	if template == nil {
+		h.logger.Printf("Template with ID %d not found", templateId)
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Template not found"})
+		return nil, false
+	}
+
+	currentUser := middleware.GetUser(r)
+	if template.UserID != currentUser.ID {
+		h.logger.Printf("User %d is not authorized to access template %d", currentUser.ID, templateId)
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return nil, false
+	}
+
+	return template, true
+}
+
+// HandleCreateTemplate creates a new workout template
+//
+//	@Summary		Create a workout template
+//	@Description	Create a reusable workout template with ordered target entries
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			template	body		store.WorkoutTemplate	true	"Template data"
+//	@Param			units		query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201			{object}	store.WorkoutTemplate	"Template created successfully"
+//	@Failure		400			{object}	ErrorResponse			"Invalid request payload"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/templates [post]
+func (h *TemplateHandler) HandleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	var template store.WorkoutTemplate
+	err = json.NewDecoder(r.Body).Decode(&template)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	if template.Title == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "title is required"})
+		return
+	}
+
+	template.FromUnits(system)
+	template.UserID = middleware.GetUser(r).ID
+
+	result, err := h.templateStore.CreateTemplate(&template)
+	if err != nil {
+		h.logger.Printf("Error creating template: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create template"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"template": result.InUnits(system)})
+}
+
+// HandleGetTemplates lists the authenticated user's templates
+//
+//	@Summary		List workout templates
+//	@Description	Retrieve all workout templates owned by the authenticated user
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		store.WorkoutTemplate	"List of templates"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/templates [get]
+func (h *TemplateHandler) HandleGetTemplates(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	templates, err := h.templateStore.GetTemplatesForUser(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving templates: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve templates"})
+		return
+	}
+
+	converted := make([]*store.WorkoutTemplate, len(templates))
+	for i, template := range templates {
+		converted[i] = template.InUnits(system)
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"templates": converted})
+}
+
+// HandleGetTemplateByID retrieves a template by ID
+//
+//	@Summary		Get workout template by ID
+//	@Description	Retrieve a workout template and its entries (only by the owner)
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Template ID"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	store.WorkoutTemplate	"Template details"
+//	@Failure		400		{object}	ErrorResponse			"Invalid template ID"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse			"Forbidden - not the owner"
+//	@Failure		404		{object}	ErrorResponse			"Template not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/templates/{id} [get]
+func (h *TemplateHandler) HandleGetTemplateByID(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	template, ok := h.getOwnedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"template": template.InUnits(system)})
+}
+
+// HandleUpdateTemplate replaces a template
+//
+//	@Summary		Update workout template
+//	@Description	Replace a template's details and entries (only by the owner)
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int						true	"Template ID"
+//	@Param			template	body		store.WorkoutTemplate	true	"Updated template data"
+//	@Param			units		query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200			{object}	store.WorkoutTemplate	"Template updated successfully"
+//	@Failure		400			{object}	ErrorResponse			"Invalid request data"
+//	@Failure		401			{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403			{object}	ErrorResponse			"Forbidden - not the owner"
+//	@Failure		404			{object}	ErrorResponse			"Template not found"
+//	@Failure		500			{object}	ErrorResponse			"Internal server error"
+//	@Router			/templates/{id} [put]
+func (h *TemplateHandler) HandleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	existing, ok := h.getOwnedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	var template store.WorkoutTemplate
+	err = json.NewDecoder(r.Body).Decode(&template)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	if template.Title == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "title is required"})
+		return
+	}
+
+	template.FromUnits(system)
+	template.ID = existing.ID
+
+	err = h.templateStore.UpdateTemplate(&template)
+	if err != nil {
+		h.logger.Printf("Error updating template: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to update template"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"template": template.InUnits(system)})
+}
+
+// HandleDeleteTemplate deletes a template
+//
+//	@Summary		Delete workout template
+//	@Description	Delete a workout template by ID (only by the owner). Templates used by a program cannot be deleted
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Template ID"
+//	@Success		204	"Template deleted successfully"
+//	@Failure		400	{object}	ErrorResponse	"Invalid template ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		404	{object}	ErrorResponse	"Template not found"
+//	@Failure		409	{object}	ErrorResponse	"Template is used by a program"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/templates/{id} [delete]
+func (h *TemplateHandler) HandleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	template, ok := h.getOwnedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.templateStore.DeleteTemplate(int64(template.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Template not found"})
+			return
+		}
+		if errors.Is(err, store.ErrTemplateInUse) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error deleting template: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to delete template"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleStartTemplate starts a live workout from a template
+//
+//	@Summary		Start a workout from a template
+//	@Description	Start a live workout (status active) pre-filled with the template's exercises and targets, to be logged set by set and finished like one from POST /workouts/start. The response also recommends a weight and reps for each exercise with reps or a weight from the user's recent sessions and progression rules, as GET /exercises/{id}/recommendation does. With apply_recommendations=true the recommended weights and reps replace the template's targets for exercises with history.
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id						path		int						true	"Template ID"
+//	@Param			apply_recommendations	query		bool					false	"Use the recommended weights and reps as the workout's targets"
+//	@Param			units					query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201						{object}	StartTemplateResponse	"Workout started from template"
+//	@Failure		400						{object}	ErrorResponse			"Invalid template ID"
+//	@Failure		401						{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403						{object}	ErrorResponse			"Forbidden - not the owner"
+//	@Failure		404						{object}	ErrorResponse			"Template not found"
+//	@Failure		409						{object}	ErrorResponse			"Another workout is active"
+//	@Failure		500						{object}	ErrorResponse			"Internal server error"
+//	@Router			/templates/{id}/start [post]
+func (h *TemplateHandler) HandleStartTemplate(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	apply := false
+	if value := r.URL.Query().Get("apply_recommendations"); value != "" {
+		apply, err = strconv.ParseBool(value)
+		if err != nil {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "apply_recommendations must be true or false"})
+			return
+		}
+	}
+	template, ok := h.getOwnedTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	workout := template.ToWorkout(currentUser.ID)
+
+	// recommendations come from history, so they are computed before the new
+	// workout joins it
+	recommendations := []*store.ExerciseRecommendation{}
+	byExercise := map[string]*store.ExerciseRecommendation{}
+	for i := range workout.Entries {
+		entry := &workout.Entries[i]
+		if entry.Reps == nil && entry.Weight == nil {
+			continue
+		}
+		key := strings.ToLower(entry.ExerciseName)
+		rec, ok := byExercise[key]
+		if !ok {
+			rec, err = h.progressionStore.GetRecommendation(currentUser.ID, entry.ExerciseName, system.Kilograms(system.PlateStep()))
+			if err != nil {
+				h.logger.Printf("Error computing recommendation: %v", err)
+				utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to compute recommendations"})
+				return
+			}
+			byExercise[key] = rec
+			recommendations = append(recommendations, rec)
+		}
+		if apply && rec.Action != progression.ActionStart {
+			weight, reps := rec.Weight, rec.Reps
+			entry.Weight, entry.Reps = &weight, &reps
+		}
+	}
+
+	workout, err = h.workoutStore.StartWorkout(workout)
+	if err != nil {
+		if errors.Is(err, store.ErrActiveWorkoutExists) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error starting workout from template: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to start workout"})
+		return
+	}
+
+	h.publisher.Publish(workout.UserID, events.WorkoutCreated, workout)
+	for i, rec := range recommendations {
+		recommendations[i] = rec.InUnits(system)
+	}
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": workout.InUnits(system), "recommendations": recommendations})
+}
+
+// HandleSaveWorkoutAsTemplate creates a template from an existing workout
+//
+//	@Summary		Save workout as template
+//	@Description	Create a new template that repeats an existing workout's exercises (only by the owner)
+//	@Tags			Templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Workout ID"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	store.WorkoutTemplate	"Template created successfully"
+//	@Failure		400		{object}	ErrorResponse			"Invalid workout ID"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse			"Forbidden - not the owner"
+//	@Failure		404		{object}	ErrorResponse			"Workout not found"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/workouts/{id}/template [post]
+func (h *TemplateHandler) HandleSaveWorkoutAsTemplate(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	workout, err := h.workoutStore.GetWorkoutById(workoutId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.logger.Printf("Workout with ID %d not found", workoutId)
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		h.logger.Printf("Error retrieving workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	if workout.UserID != currentUser.ID {
+		h.logger.Printf("User %d is not authorized to copy workout %d", currentUser.ID, workoutId)
+		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+		return
+	}
+
+	template, err := h.templateStore.CreateTemplate(store.TemplateFromWorkout(workout))
+	if err != nil {
+		h.logger.Printf("Error creating template from workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create template"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"template": template.InUnits(system)})
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+)
+
+// unitsHeader overrides the caller's unit system for one request, like the
+// units query parameter.
+const unitsHeader = "X-Units"
+
+// readUnits returns the unit system a request is read and answered in: the
+// units query parameter, else the X-Units header, else the signed-in user's
+// preference, else metric.
+func readUnits(r *http.Request) (units.System, error) {
+	value := r.URL.Query().Get("units")
+	if value == "" {
+		value = r.Header.Get(unitsHeader)
+	}
+	if value == "" {
+		// unauthenticated routes, such as shared workouts, have no user
+		if user, ok := r.Context().Value(middleware.UserContextKey).(*store.User); ok && user.Units != "" {
+			value = user.Units
+		}
+	}
+	if value == "" {
+		return units.Metric, nil
+	}
+	system, err := units.Parse(value)
+	if err != nil {
+		return "", errors.New("units must be metric or imperial")
+	}
+	return system, nil
+}
+
+// workoutsInUnits converts each workout for the response.
+func workoutsInUnits(workouts []*store.Workout, system units.System) []*store.Workout {
+	converted := make([]*store.Workout, len(workouts))
+	for i, workout := range workouts {
+		converted[i] = workout.InUnits(system)
+	}
+	return converted
+}
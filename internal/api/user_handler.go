@@ -1,13 +1,16 @@
 package api
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
 	"github.com/mounis-bhat/rest-api-go/internal/utils"
 )
 
@@ -15,14 +18,25 @@ type registerUserRequest struct {
 	Username string `json:"username" example:"johndoe" validate:"required,min=3,max=20"`       // Username for the new user
 	Email    string `json:"email" example:"john@example.com" validate:"required,email"`        // Email address for the new user
 	Password string `json:"password" example:"SecurePass123" validate:"required,min=8,max=20"` // Password for the new user
+	Timezone string `json:"timezone" example:"Europe/Berlin"`                                  // IANA time zone used for analytics and scheduling (defaults to UTC)
+	Units    string `json:"units" example:"imperial"`                                          // metric or imperial, the default units for reading and writing values (defaults to metric)
+	store.UserProfile
 }
 
 type UserResponse struct {
 	ID        int64  `json:"id" example:"1"`                            // User ID
 	Username  string `json:"username" example:"johndoe"`                // Username
 	Email     string `json:"email" example:"john@example.com"`          // Email address
+	Timezone  string `json:"timezone" example:"Europe/Berlin"`          // IANA time zone
+	Units     string `json:"units" example:"imperial"`                  // metric or imperial
 	CreatedAt string `json:"created_at" example:"2024-01-01T12:00:00Z"` // Creation timestamp
 	UpdatedAt string `json:"updated_at" example:"2024-01-01T12:00:00Z"` // Last update timestamp
+
+	BirthYear        *int     `json:"birth_year" example:"1990"`       // Used for age-based maximum heart rate and calorie estimates
+	Sex              *string  `json:"sex" example:"female"`            // male or female, used for calorie estimates
+	WeightKg         *float64 `json:"weight_kg" example:"68.5"`        // Body weight in kg
+	RestingHeartRate *int     `json:"resting_heart_rate" example:"55"` // Places heart-rate zones within the heart-rate reserve
+	MaxHeartRate     *int     `json:"max_heart_rate" example:"188"`    // Overrides the age-based estimate
 }
 
 type ErrorResponse struct {
@@ -77,6 +91,41 @@ func (h *UserHandler) validateRegisterRequest(reg *registerUserRequest) error {
 		return errors.New("password must be at most 20 characters long")
 	}
 
+	if reg.Timezone != "" {
+		if _, err := time.LoadLocation(reg.Timezone); err != nil {
+			return errors.New("invalid timezone")
+		}
+	}
+	if reg.Units != "" {
+		system, err := units.Parse(reg.Units)
+		if err != nil {
+			return errors.New("units must be metric or imperial")
+		}
+		reg.Units = string(system)
+	}
+
+	return validateProfile(reg.UserProfile)
+}
+
+func validateProfile(profile store.UserProfile) error {
+	if profile.BirthYear != nil && (*profile.BirthYear < 1900 || *profile.BirthYear > time.Now().Year()) {
+		return errors.New("birth_year must be between 1900 and the current year")
+	}
+	if profile.Sex != nil && *profile.Sex != store.SexMale && *profile.Sex != store.SexFemale {
+		return errors.New("sex must be male or female")
+	}
+	if profile.WeightKg != nil && (*profile.WeightKg < 20 || *profile.WeightKg > 500) {
+		return errors.New("weight_kg must be between 20 and 500")
+	}
+	if profile.RestingHeartRate != nil && (*profile.RestingHeartRate < 25 || *profile.RestingHeartRate > 120) {
+		return errors.New("resting_heart_rate must be between 25 and 120")
+	}
+	if profile.MaxHeartRate != nil && (*profile.MaxHeartRate < 100 || *profile.MaxHeartRate > 240) {
+		return errors.New("max_heart_rate must be between 100 and 240")
+	}
+	if profile.RestingHeartRate != nil && profile.MaxHeartRate != nil && *profile.RestingHeartRate >= *profile.MaxHeartRate {
+		return errors.New("resting_heart_rate must be below max_heart_rate")
+	}
 	return nil
 }
 
@@ -110,8 +159,11 @@ func (h *UserHandler) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user := &store.User{
-		Username: reg.Username,
-		Email:    reg.Email,
+		Username:    reg.Username,
+		Email:       reg.Email,
+		Timezone:    reg.Timezone,
+		Units:       reg.Units,
+		UserProfile: reg.UserProfile,
 	}
 
 	err = user.PasswordHash.Set(reg.Password)
@@ -134,7 +186,7 @@ func (h *UserHandler) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
 // HandleUpdateUser updates an existing user's information
 //
 //	@Summary		Update user information
-//	@Description	Update an existing user's username, email, and password
+//	@Description	Update an existing user's username, email, and password. Omitted timezone, units and profile fields keep their current values
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -169,9 +221,12 @@ func (h *UserHandler) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	user := &store.User{
-		ID:       userId,
-		Username: reg.Username,
-		Email:    reg.Email,
+		ID:          userId,
+		Username:    reg.Username,
+		Email:       reg.Email,
+		Timezone:    reg.Timezone,
+		Units:       reg.Units,
+		UserProfile: reg.UserProfile,
 	}
 
 	err = user.PasswordHash.Set(reg.Password)
@@ -182,6 +237,10 @@ func (h *UserHandler) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.userStore.UpdateUser(user)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "User not found"})
+			return
+		}
 		h.logger.Printf("Error updating user: %v", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to update user"})
 		return
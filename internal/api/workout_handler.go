@@ -4,23 +4,66 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/mounis-bhat/rest-api-go/internal/events"
 	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/policy"
+	"github.com/mounis-bhat/rest-api-go/internal/sensor"
 	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/track"
 	"github.com/mounis-bhat/rest-api-go/internal/utils"
 )
 
 type WorkoutEntryResponse struct {
-	ID              int      `json:"id" example:"1"`                       // Entry ID
-	ExerciseName    string   `json:"exercise_name" example:"Push ups"`     // Name of the exercise
-	Sets            int      `json:"sets" example:"3"`                     // Number of sets
-	Reps            *int     `json:"reps" example:"15"`                    // Number of repetitions
-	DurationSeconds *int     `json:"duration_seconds" example:"60"`        // Duration in seconds
-	Weight          *float64 `json:"weight" example:"75.5"`                // Weight in kg
-	Notes           string   `json:"notes" example:"Good form maintained"` // Additional notes
-	OrderIndex      int      `json:"order_index" example:"1"`              // Order of exercise in workout
+	ID              int                  `json:"id" example:"1"`                       // Entry ID
+	ExerciseName    string               `json:"exercise_name" example:"Push ups"`     // Name of the exercise
+	Sets            int                  `json:"sets" example:"3"`                     // Number of sets
+	Reps            *int                 `json:"reps" example:"15"`                    // Number of repetitions
+	DurationSeconds *int                 `json:"duration_seconds" example:"60"`        // Duration in seconds
+	Weight          *float64             `json:"weight" example:"75.5"`                // Weight in kg
+	RPE             *float64             `json:"rpe" example:"8.5"`                    // Rate of perceived exertion of the hardest set, 1 to 10
+	Notes           string               `json:"notes" example:"Good form maintained"` // Additional notes
+	OrderIndex      int                  `json:"order_index" example:"1"`              // Order of exercise in workout
+	GroupIndex      *int                 `json:"group_index" example:"0"`              // Index into the workout's groups, null when ungrouped
+	SetLog          []WorkoutSetResponse `json:"set_log"`                              // Sets logged during a live session
+
+	DistanceMeters   *float64         `json:"distance_meters" example:"5000"`    // Distance in meters
+	PaceSecondsPerKm *int             `json:"pace_seconds_per_km" example:"300"` // Pace; derived from distance and duration when omitted
+	InclinePercent   *float64         `json:"incline_percent" example:"1.5"`     // Treadmill incline, -20 to 40
+	ResistanceLevel  *int             `json:"resistance_level" example:"6"`      // Machine resistance setting, 0 to 100
+	Intervals        *store.Intervals `json:"intervals"`                         // Work/rest repeats, null for a steady effort
+
+	TargetRestSeconds *int `json:"target_rest_seconds" example:"90"` // Rest between sets, 0 to 3600; null uses the exercise's rest target
+}
+
+type WorkoutSetResponse struct {
+	ID              int      `json:"id" example:"1"`                              // Set ID
+	SetNumber       int      `json:"set_number" example:"1"`                      // Position of the set within the entry
+	Reps            *int     `json:"reps" example:"8"`                            // Repetitions performed
+	Weight          *float64 `json:"weight" example:"100"`                        // Weight in kg
+	DurationSeconds *int     `json:"duration_seconds" example:"30"`               // Duration in seconds
+	RPE             *float64 `json:"rpe" example:"8"`                             // Rate of perceived exertion, 1 to 10
+	Warmup          bool     `json:"warmup" example:"false"`                      // Warm-up set, left out of the entry's summary
+	StartedAt       *string  `json:"started_at" example:"2024-01-01T12:04:20Z"`   // When the set began, null when it was not timed
+	CompletedAt     *string  `json:"completed_at" example:"2024-01-01T12:05:00Z"` // When the set was completed, null for generated warm-up sets
+	RestSeconds     *int     `json:"rest_seconds" example:"95"`                   // Rest since the entry's previous working set completed; null for warm-ups, the first working set and untimed sets
+}
+
+type EntryGroupResponse struct {
+	ID                          int                    `json:"id" example:"1"`                             // Group ID
+	Type                        string                 `json:"type" example:"superset"`                    // superset, circuit or giant_set
+	Rounds                      int                    `json:"rounds" example:"3"`                         // Number of rounds through the group
+	RestBetweenExercisesSeconds *int                   `json:"rest_between_exercises_seconds" example:"0"` // Rest between exercises within a round
+	RestBetweenRoundsSeconds    *int                   `json:"rest_between_rounds_seconds" example:"90"`   // Rest after each round
+	OrderIndex                  int                    `json:"order_index" example:"0"`                    // Order of the group in the workout
+	Entries                     []WorkoutEntryResponse `json:"entries"`                                    // Entries performed in this group
 }
 
 type WorkoutResponse struct {
@@ -30,36 +73,134 @@ type WorkoutResponse struct {
 	Description     string                 `json:"description" example:"High intensity cardio"` // Workout description
 	DurationMinutes int                    `json:"duration_minutes" example:"45"`               // Duration in minutes
 	CaloriesBurned  int                    `json:"calories_burned" example:"350"`               // Calories burned
+	CaloriesSource  *string                `json:"calories_source" example:"met"`               // How calories were estimated: heart_rate or met; null when supplied by the client
+	Status          string                 `json:"status" example:"completed"`                  // active while a live session is in progress, otherwise completed
+	Visibility      string                 `json:"visibility" example:"followers"`              // private, followers or public
+	StartedAt       *string                `json:"started_at" example:"2024-01-01T12:00:00Z"`   // When a live session was started
+	EndedAt         *string                `json:"ended_at" example:"2024-01-01T12:45:00Z"`     // When a live session was finished
 	CreatedAt       string                 `json:"created_at" example:"2024-01-01T12:00:00Z"`   // Creation timestamp
 	UpdatedAt       string                 `json:"updated_at" example:"2024-01-01T12:00:00Z"`   // Last update timestamp
-	Entries         []WorkoutEntryResponse `json:"entries"`                                     // List of workout exercises
+	TemplateID      *int                   `json:"template_id" example:"3"`                     // Template the workout follows; completing it fulfils that day's planned session
+	Tags            []string               `json:"tags" example:"leg day,deload"`               // Lowercase tags; omit on update to keep the current ones
+	Entries         []WorkoutEntryResponse `json:"entries"`                                     // Flat list of workout exercises
+	Groups          []EntryGroupResponse   `json:"groups"`                                      // Supersets, circuits and giant sets with their entries nested
 }
 
 type WorkoutHandler struct {
 	workoutStore store.WorkoutStore
+	restStore    store.RestStore
+	policy       *policy.WorkoutPolicy
+	publisher    events.Publisher
+	timers       *events.RestTimers
 	logger       *log.Logger
 }
 
-func NewWorkoutHandler(store store.WorkoutStore, logger *log.Logger) *WorkoutHandler {
-	return &WorkoutHandler{workoutStore: store, logger: logger}
+func NewWorkoutHandler(store store.WorkoutStore, restStore store.RestStore, policy *policy.WorkoutPolicy, publisher events.Publisher, timers *events.RestTimers, logger *log.Logger) *WorkoutHandler {
+	return &WorkoutHandler{workoutStore: store, restStore: restStore, policy: policy, publisher: publisher, timers: timers, logger: logger}
+}
+
+// publishWorkoutUpdated reloads the workout and publishes it. Failures are
+// only logged since the change itself has already been committed.
+func (h *WorkoutHandler) publishWorkoutUpdated(userID, workoutId int64) {
+	workout, err := h.workoutStore.GetWorkoutById(workoutId)
+	if err != nil || workout == nil {
+		h.logger.Printf("Error loading workout %d for event: %v", workoutId, err)
+		return
+	}
+	h.publisher.Publish(userID, events.WorkoutUpdated, workout)
+}
+
+// startRestTimer starts the owner's rest timer after the entry's latest set,
+// for the entry's target rest or else the exercise's. Failures are only
+// logged since the set has already been saved.
+func (h *WorkoutHandler) startRestTimer(ownerID, workoutId int64, entry *store.WorkoutEntry) {
+	if len(entry.SetLog) == 0 {
+		return
+	}
+	var targetSeconds int
+	if entry.TargetRestSeconds != nil {
+		targetSeconds = *entry.TargetRestSeconds
+	} else {
+		target, err := h.restStore.GetRestTarget(ownerID, entry.ExerciseName)
+		if err != nil {
+			h.logger.Printf("Error retrieving rest target for workout %d: %v", workoutId, err)
+			return
+		}
+		targetSeconds = target.RestSeconds
+	}
+	if targetSeconds == 0 {
+		return
+	}
+
+	set := entry.SetLog[len(entry.SetLog)-1]
+	if set.CompletedAt == nil {
+		return
+	}
+	h.timers.Start(ownerID, events.RestTimer{
+		WorkoutID:     workoutId,
+		EntryID:       entry.ID,
+		ExerciseName:  entry.ExerciseName,
+		SetNumber:     set.SetNumber,
+		TargetSeconds: targetSeconds,
+		StartedAt:     *set.CompletedAt,
+		EndsAt:        set.CompletedAt.Add(time.Duration(targetSeconds) * time.Second),
+	})
+}
+
+// authorizeWorkout checks the workout policy for the current user and returns
+// the workout's owner. It writes the error response itself and returns false
+// when the workout is missing or the action is not allowed.
+func (h *WorkoutHandler) authorizeWorkout(w http.ResponseWriter, r *http.Request, workoutId int64, action policy.Action) (int64, bool) {
+	currentUser := middleware.GetUser(r)
+
+	ownerID, err := h.policy.Authorize(currentUser.ID, workoutId, action)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.logger.Printf("Workout with ID %d not found", workoutId)
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return 0, false
+		}
+		if errors.Is(err, policy.ErrForbidden) && action == policy.ReadWorkout {
+			// Workouts the user cannot see are indistinguishable from missing ones.
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return 0, false
+		}
+		if errors.Is(err, policy.ErrForbidden) {
+			h.logger.Printf("User %d is not authorized to %s workout %d", currentUser.ID, action, workoutId)
+			utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+			return 0, false
+		}
+		h.logger.Printf("Error authorizing workout access: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout owner"})
+		return 0, false
+	}
+
+	return ownerID, true
 }
 
 // HandleGetWorkoutByID retrieves a specific workout by ID
 //
 //	@Summary		Get workout by ID
-//	@Description	Retrieve a specific workout and its exercises by workout ID
+//	@Description	Retrieve a specific workout and its exercises by workout ID. Workouts the caller may not see are reported as not found.
 //	@Tags			Workouts
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			id	path		int				true	"Workout ID"
-//	@Success		200	{object}	WorkoutResponse	"Workout details"
-//	@Failure		400	{object}	ErrorResponse	"Invalid workout ID"
-//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
-//	@Failure		404	{object}	ErrorResponse	"Workout not found"
-//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Param			id		path		int				true	"Workout ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	WorkoutResponse	"Workout details"
+//	@Failure		400		{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse	"Workout not found"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
 //	@Router			/workouts/{id} [get]
 func (h *WorkoutHandler) HandleGetWorkoutByID(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
 	workoutId, err := utils.ReadIdParam(r)
 	if err != nil {
 		h.logger.Printf("Error reading workout ID: %v", err)
@@ -67,6 +208,11 @@ func (h *WorkoutHandler) HandleGetWorkoutByID(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	_, ok := h.authorizeWorkout(w, r, workoutId, policy.ReadWorkout)
+	if !ok {
+		return
+	}
+
 	workout, err := h.workoutStore.GetWorkoutById(workoutId)
 	if err != nil {
 		h.logger.Printf("Error retrieving workout: %v", err)
@@ -80,26 +226,33 @@ func (h *WorkoutHandler) HandleGetWorkoutByID(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout})
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout.InUnits(system)})
 }
 
 // HandleCreateWorkout creates a new workout
 //
 //	@Summary		Create a new workout
-//	@Description	Create a new workout with exercises for the authenticated user
+//	@Description	Create a new workout with exercises for the authenticated user. When calories_burned is omitted it is estimated from MET values, entry durations and the owner's weight, with calories_source met.
 //	@Tags			Workouts
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			workout	body		store.Workout	true	"Workout data"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
 //	@Success		201		{object}	WorkoutResponse	"Workout created successfully"
 //	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
 //	@Failure		401		{object}	ErrorResponse	"Unauthorized"
 //	@Failure		500		{object}	ErrorResponse	"Internal server error"
 //	@Router			/workouts [post]
 func (h *WorkoutHandler) HandleCreateWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
 	var workout store.Workout
-	err := json.NewDecoder(r.Body).Decode(&workout)
+	err = json.NewDecoder(r.Body).Decode(&workout)
 
 	if err != nil {
 		h.logger.Printf("Error decoding request body: %v", err)
@@ -114,36 +267,50 @@ func (h *WorkoutHandler) HandleCreateWorkout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	workout.FromUnits(system)
 	workout.UserID = currentUser.ID
 
-	result, err := h.workoutStore.CreateWorkout(&workout)
+	result, err := h.workoutStore.CreateWorkout(&workout, currentUser.ID)
 	if err != nil {
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
 		h.logger.Printf("Error creating workout: %v", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create workout"})
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": result})
+	h.publisher.Publish(currentUser.ID, events.WorkoutCreated, result)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": result.InUnits(system)})
 }
 
 // HandleUpdateWorkout updates an existing workout
 //
 //	@Summary		Update workout
-//	@Description	Update an existing workout and its exercises (only by the owner)
+//	@Description	Update an existing workout and its exercises (by the owner or a coach with read_write access). Calories that were estimated, or sent as 0, are estimated again from the updated entries; estimated calories sent back unchanged stay estimated, and a different calories_burned is kept as the client's.
 //	@Tags			Workouts
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			id		path		int				true	"Workout ID"
 //	@Param			workout	body		store.Workout	true	"Updated workout data"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
 //	@Success		200		{object}	WorkoutResponse	"Workout updated successfully"
 //	@Failure		400		{object}	ErrorResponse	"Invalid request data"
 //	@Failure		401		{object}	ErrorResponse	"Unauthorized"
-//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner or a coach with write access"
 //	@Failure		404		{object}	ErrorResponse	"Workout not found"
 //	@Failure		500		{object}	ErrorResponse	"Internal server error"
 //	@Router			/workouts/{id} [put]
 func (h *WorkoutHandler) HandleUpdateWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
 	workoutId, err := utils.ReadIdParam(r)
 	if err != nil {
 		h.logger.Printf("Error reading workout ID: %v", err)
@@ -159,6 +326,7 @@ func (h *WorkoutHandler) HandleUpdateWorkout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	workout.FromUnits(system)
 	workout.ID = int(workoutId)
 
 	currentUser := middleware.GetUser(r)
@@ -168,47 +336,40 @@ func (h *WorkoutHandler) HandleUpdateWorkout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	workoutOwner, err := h.workoutStore.GetWorkoutOwner(workoutId)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			h.logger.Printf("Workout with ID %d not found", workoutId)
-			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
-			return
-		}
-		h.logger.Printf("Error retrieving workout owner: %v", err)
-		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout owner"})
-		return
-	}
-
-	if int64(workoutOwner) != currentUser.ID {
-		h.logger.Printf("User %d is not authorized to update workout %d", currentUser.ID, workoutId)
-		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+	ownerID, ok := h.authorizeWorkout(w, r, workoutId, policy.WriteWorkout)
+	if !ok {
 		return
 	}
 
-	err = h.workoutStore.UpdateWorkout(&workout)
+	err = h.workoutStore.UpdateWorkout(&workout, currentUser.ID)
 	if err != nil {
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
 		h.logger.Printf("Error updating workout: %v", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to update workout"})
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout})
+	h.publishWorkoutUpdated(ownerID, workoutId)
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout.InUnits(system)})
 }
 
-// HandleDeleteWorkout deletes a workout
+// HandleDeleteWorkout moves a workout to the trash
 //
 //	@Summary		Delete workout
-//	@Description	Delete a workout by ID (only by the owner)
+//	@Description	Move a workout to the trash (by the owner or a coach with read_write access). The owner can restore it until it is purged after the retention period.
 //	@Tags			Workouts
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			id	path	int	true	"Workout ID"
-//	@Success		204	"Workout deleted successfully"
+//	@Success		204	"Workout moved to the trash"
 //	@Failure		400	{object}	ErrorResponse	"Invalid workout ID"
 //	@Failure		401	{object}	ErrorResponse	"Unauthorized"
-//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the owner"
+//	@Failure		403	{object}	ErrorResponse	"Forbidden - not the owner or a coach with write access"
 //	@Failure		404	{object}	ErrorResponse	"Workout not found"
 //	@Failure		500	{object}	ErrorResponse	"Internal server error"
 //	@Router			/workouts/{id} [delete]
@@ -227,25 +388,12 @@ func (h *WorkoutHandler) HandleDeleteWorkout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	workoutOwner, err := h.workoutStore.GetWorkoutOwner(workoutId)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			h.logger.Printf("Workout with ID %d not found", workoutId)
-			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
-			return
-		}
-		h.logger.Printf("Error retrieving workout owner: %v", err)
-		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout owner"})
-		return
-	}
-
-	if int64(workoutOwner) != currentUser.ID {
-		h.logger.Printf("User %d is not authorized to delete workout %d", currentUser.ID, workoutId)
-		utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+	ownerID, ok := h.authorizeWorkout(w, r, workoutId, policy.WriteWorkout)
+	if !ok {
 		return
 	}
 
-	err = h.workoutStore.DeleteWorkout(workoutId)
+	err = h.workoutStore.DeleteWorkout(workoutId, currentUser.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			h.logger.Printf("Workout with ID %d not found for deletion", workoutId)
@@ -257,28 +405,853 @@ func (h *WorkoutHandler) HandleDeleteWorkout(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	h.timers.Stop(ownerID, workoutId)
+	h.publisher.Publish(ownerID, events.WorkoutDeleted, utils.Envelope{"id": workoutId})
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // HandleGetAllWorkouts retrieves all workouts
 //
 //	@Summary		Get all workouts
-//	@Description	Retrieve a list of all workouts in the system
+//	@Description	Retrieve every workout visible to the authenticated user: their own, their athletes', public ones and followers-only ones from users they follow. Repeat tag to only return workouts carrying every listed tag.
 //	@Tags			Workouts
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Success		200	{array}		WorkoutResponse	"List of workouts"
-//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
-//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Param			tag		query		[]string		false	"Only workouts with all of these tags"	collectionFormat(multi)
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		WorkoutResponse	"List of workouts"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
 //	@Router			/workouts [get]
 func (h *WorkoutHandler) HandleGetAllWorkouts(w http.ResponseWriter, r *http.Request) {
-	workouts, err := h.workoutStore.GetAllWorkouts()
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	filter := store.WorkoutFilter{Tags: r.URL.Query()["tag"]}
+
+	workouts, err := h.workoutStore.GetAllWorkouts(middleware.GetUser(r).ID, filter)
 	if err != nil {
 		h.logger.Printf("Error retrieving workouts: %v", err)
 		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workouts"})
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workouts": workouts})
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workouts": workoutsInUnits(workouts, system)})
+}
+
+// HandleSearchWorkouts runs a full-text search over workouts
+//
+//	@Summary		Search workouts
+//	@Description	Full-text search over titles, exercise names, descriptions and entry notes of the workouts visible to the user, best matches first. Supports quoted phrases, OR and -term.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			q		query		string						true	"Search query"
+//	@Param			limit	query		int							false	"Maximum results (default 20, max 100)"
+//	@Param			units	query		string						false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		store.WorkoutSearchResult	"Ranked matches"
+//	@Failure		400		{object}	ErrorResponse				"Missing query or invalid limit"
+//	@Failure		401		{object}	ErrorResponse				"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse				"Internal server error"
+//	@Router			/workouts/search [get]
+func (h *WorkoutHandler) HandleSearchWorkouts(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	search := strings.TrimSpace(r.URL.Query().Get("q"))
+	if search == "" {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "q is required"})
+		return
+	}
+
+	limit, err := utils.ReadLimitParam(r, 20, 100)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	results, err := h.workoutStore.SearchWorkouts(middleware.GetUser(r).ID, search, limit)
+	if err != nil {
+		h.logger.Printf("Error searching workouts: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to search workouts"})
+		return
+	}
+
+	for i, result := range results {
+		converted := *result
+		converted.Workout = result.Workout.InUnits(system)
+		results[i] = &converted
+	}
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"results": results})
+}
+
+// HandleGetTags lists the user's workout tags
+//
+//	@Summary		List tags
+//	@Description	List the tags on the authenticated user's workouts with how many workouts carry each, most used first
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		store.Tag		"Tags"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/tags [get]
+func (h *WorkoutHandler) HandleGetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.workoutStore.GetTags(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving tags: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve tags"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"tags": tags})
+}
+
+// HandleGetTrash lists the user's trashed workouts
+//
+//	@Summary		List trashed workouts
+//	@Description	List the authenticated user's deleted workouts that have not been purged yet, most recently deleted first
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		WorkoutResponse	"Trashed workouts with deleted_at set"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/trash [get]
+func (h *WorkoutHandler) HandleGetTrash(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workouts, err := h.workoutStore.GetTrashedWorkouts(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving trashed workouts: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workouts"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workouts": workoutsInUnits(workouts, system)})
+}
+
+// HandleRestoreWorkout restores a workout from the trash
+//
+//	@Summary		Restore workout
+//	@Description	Move one of the authenticated user's workouts out of the trash
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Workout ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	WorkoutResponse	"Restored workout"
+//	@Failure		400		{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse	"Workout not in your trash"
+//	@Failure		409		{object}	ErrorResponse	"Restoring would leave two active workouts"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/restore [post]
+func (h *WorkoutHandler) HandleRestoreWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	workout, err := h.workoutStore.RestoreWorkout(workoutId, currentUser.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found in trash"})
+			return
+		}
+		if errors.Is(err, store.ErrActiveWorkoutExists) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error restoring workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to restore workout"})
+		return
+	}
+
+	h.publisher.Publish(currentUser.ID, events.WorkoutRestored, workout)
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout.InUnits(system)})
+}
+
+// HandleGetTrack returns a workout's GPS track as GeoJSON
+//
+//	@Summary		Get workout track
+//	@Description	Return the route of a workout imported from a GPX or TCX file as a GeoJSON Feature with a LineString geometry ([longitude, latitude, elevation]). Per-point times and heart rates are in properties.coordinateProperties, and the track summary with splits in properties.summary. Indoor recordings without positions have a null geometry.
+//	@Tags			Workouts
+//	@Produce		application/geo+json
+//	@Security		BearerAuth
+//	@Param			id	path		int				true	"Workout ID"
+//	@Success		200	{object}	track.Feature	"GeoJSON Feature"
+//	@Failure		400	{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	ErrorResponse	"Workout not found or has no track"
+//	@Failure		500	{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/track [get]
+func (h *WorkoutHandler) HandleGetTrack(w http.ResponseWriter, r *http.Request) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	if _, ok := h.authorizeWorkout(w, r, workoutId, policy.ReadWorkout); !ok {
+		return
+	}
+
+	workout, err := h.workoutStore.GetWorkoutById(workoutId)
+	if err != nil {
+		h.logger.Printf("Error retrieving workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve track"})
+		return
+	}
+	if workout.Track == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout has no track"})
+		return
+	}
+
+	points, err := h.workoutStore.GetTrackPoints(workoutId)
+	if err != nil {
+		h.logger.Printf("Error retrieving track points: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve track"})
+		return
+	}
+
+	feature := track.GeoJSON(points, map[string]any{
+		"workout_id": workout.ID,
+		"title":      workout.Title,
+		"started_at": workout.StartedAt,
+		"summary":    workout.Track,
+	})
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(feature); err != nil {
+		h.logger.Printf("Error writing track: %v", err)
+	}
+}
+
+// maxSamplesBodySize bounds sample uploads; a full day of one-second samples
+// with every metric is a few megabytes.
+const maxSamplesBodySize = 8 << 20
+
+type SamplesResponse struct {
+	Count   int            `json:"count" example:"3600"` // Number of stored samples before downsampling
+	Samples sensor.Series  `json:"samples"`              // Downsampled samples in the upload format
+	Summary sensor.Summary `json:"summary"`              // Averages, maxima and heart-rate zones at full resolution
+}
+
+// defaultSamplePoints is how many points GET /workouts/{id}/samples returns
+// when max_points is not given, enough for a chart.
+const defaultSamplePoints = 500
+
+// HandleAddSamples stores heart-rate, power and cadence samples for a workout
+//
+//	@Summary		Upload sensor samples
+//	@Description	Store samples as parallel arrays: start, then either offsets (whole seconds from start, increasing) or interval_seconds (default 1), and any of heart_rate (bpm), power (watts) and cadence (rpm), one value or null per sample. At most 86400 samples per request; samples at an already stored time replace it. Unless the workout's calories were supplied, they are estimated from heart rate and the owner's weight, birth year and sex, with calories_source heart_rate.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Workout ID"
+//	@Param			samples	body		sensor.Series	true	"Samples"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	WorkoutResponse	"Updated workout"
+//	@Failure		400		{object}	ErrorResponse	"Invalid samples"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - no write access"
+//	@Failure		404		{object}	ErrorResponse	"Workout not found"
+//	@Failure		413		{object}	ErrorResponse	"Upload too large"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/samples [post]
+func (h *WorkoutHandler) HandleAddSamples(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	ownerID, ok := h.authorizeWorkout(w, r, workoutId, policy.WriteWorkout)
+	if !ok {
+		return
+	}
+
+	var series sensor.Series
+	err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSamplesBodySize)).Decode(&series)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteJSON(w, http.StatusRequestEntityTooLarge, utils.Envelope{"error": "upload must be at most 8 MB"})
+			return
+		}
+		h.logger.Printf("Error decoding samples: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+	samples, err := series.Samples()
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	currentUser := middleware.GetUser(r)
+	stored, err := h.workoutStore.AddSamples(workoutId, samples, currentUser.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		h.logger.Printf("Error storing samples: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to store samples"})
+		return
+	}
+
+	workout, err := h.workoutStore.GetWorkoutById(workoutId)
+	if err != nil {
+		h.logger.Printf("Error retrieving workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workout"})
+		return
+	}
+
+	h.publisher.Publish(ownerID, events.WorkoutUpdated, workout)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"stored": stored, "workout": workout.InUnits(system)})
+}
+
+// HandleGetSamples returns a workout's sensor samples with a summary
+//
+//	@Summary		Get sensor samples
+//	@Description	Return the workout's samples in the upload format (start, offsets and metric arrays), downsampled to at most max_points by averaging equal time buckets. The summary is computed at full resolution: average and maximum of each metric, and for heart rate the recorded time (gaps over 30 seconds excluded) and time in each of five zones. Zones use the owner's maximum heart rate (or an estimate from their age) and, when set, their resting heart rate; they are null without either.
+//	@Tags			Workouts
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int				true	"Workout ID"
+//	@Param			max_points	query		int				false	"Maximum number of points (default 500, 0 for all)"
+//	@Success		200			{object}	SamplesResponse	"Samples and summary"
+//	@Failure		400			{object}	ErrorResponse	"Invalid parameters"
+//	@Failure		401			{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	ErrorResponse	"Workout not found"
+//	@Failure		500			{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/samples [get]
+func (h *WorkoutHandler) HandleGetSamples(w http.ResponseWriter, r *http.Request) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	maxPoints := defaultSamplePoints
+	if value := r.URL.Query().Get("max_points"); value != "" {
+		maxPoints, err = strconv.Atoi(value)
+		if err != nil || maxPoints < 0 {
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "max_points must be a non-negative integer"})
+			return
+		}
+	}
+
+	if _, ok := h.authorizeWorkout(w, r, workoutId, policy.ReadWorkout); !ok {
+		return
+	}
+
+	result, err := h.workoutStore.GetSamples(workoutId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		h.logger.Printf("Error retrieving samples: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve samples"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{
+		"count":   len(result.Samples),
+		"samples": sensor.Compact(sensor.Downsample(result.Samples, maxPoints)),
+		"summary": sensor.Summarize(result.Samples, result.Zones),
+	})
+}
+
+// HandleGetRevisions lists a workout's edit history
+//
+//	@Summary		List workout revisions
+//	@Description	List the immutable revisions recorded for every change to a workout, newest first, each with its actor, timestamp and a JSON diff against the previous revision. Only the owner and their coaches can see them.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int						true	"Workout ID"
+//	@Success		200	{array}		store.WorkoutRevision	"Revisions"
+//	@Failure		400	{object}	ErrorResponse			"Invalid workout ID"
+//	@Failure		401	{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403	{object}	ErrorResponse			"Forbidden - not the owner or their coach"
+//	@Failure		404	{object}	ErrorResponse			"Workout not found"
+//	@Failure		500	{object}	ErrorResponse			"Internal server error"
+//	@Router			/workouts/{id}/revisions [get]
+func (h *WorkoutHandler) HandleGetRevisions(w http.ResponseWriter, r *http.Request) {
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	ownerID, ok := h.authorizeWorkout(w, r, workoutId, policy.ReadWorkout)
+	if !ok {
+		return
+	}
+
+	// Seeing a public workout does not entitle anyone to its edit history.
+	err = h.policy.AuthorizeUser(middleware.GetUser(r).ID, ownerID, policy.ReadWorkout)
+	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+			return
+		}
+		h.logger.Printf("Error authorizing revision access: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve revisions"})
+		return
+	}
+
+	revisions, err := h.workoutStore.GetRevisions(workoutId)
+	if err != nil {
+		h.logger.Printf("Error retrieving revisions: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve revisions"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"revisions": revisions})
+}
+
+// HandleRevertWorkout reverts a workout to an earlier revision
+//
+//	@Summary		Revert workout to a revision
+//	@Description	Restore the title, description, duration, calories, visibility, tags, groups and entries captured in a revision. The revert is recorded as a new revision.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Workout ID"
+//	@Param			rev		path		int				true	"Revision number"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	WorkoutResponse	"Reverted workout"
+//	@Failure		400		{object}	ErrorResponse	"Invalid workout ID or revision"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner or a coach with write access"
+//	@Failure		404		{object}	ErrorResponse	"Workout or revision not found"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/revisions/{rev}/revert [post]
+func (h *WorkoutHandler) HandleRevertWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "rev"))
+	if err != nil || revision <= 0 {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid revision"})
+		return
+	}
+
+	ownerID, ok := h.authorizeWorkout(w, r, workoutId, policy.WriteWorkout)
+	if !ok {
+		return
+	}
+
+	workout, err := h.workoutStore.RevertWorkout(workoutId, revision, middleware.GetUser(r).ID)
+	if err != nil {
+		if errors.Is(err, store.ErrRevisionNotFound) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Revision not found"})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		h.logger.Printf("Error reverting workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to revert workout"})
+		return
+	}
+
+	h.publisher.Publish(ownerID, events.WorkoutUpdated, workout)
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout.InUnits(system)})
+}
+
+// HandleStartWorkout starts a live workout session
+//
+//	@Summary		Start a live workout
+//	@Description	Create an active workout stamped with its start time. Only one workout can be active per user.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			workout	body		store.Workout	false	"Optional title, description and planned entries"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	WorkoutResponse	"Workout started"
+//	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		409		{object}	ErrorResponse	"Another workout is already active"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/start [post]
+func (h *WorkoutHandler) HandleStartWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	var workout store.Workout
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&workout)
+		if err != nil && !errors.Is(err, io.EOF) {
+			h.logger.Printf("Error decoding request body: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+			return
+		}
+	}
+
+	if workout.Title == "" {
+		workout.Title = "Workout"
+	}
+	workout.FromUnits(system)
+	workout.UserID = middleware.GetUser(r).ID
+
+	result, err := h.workoutStore.StartWorkout(&workout)
+	if err != nil {
+		if errors.Is(err, store.ErrActiveWorkoutExists) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error starting workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to start workout"})
+		return
+	}
+
+	h.publisher.Publish(workout.UserID, events.WorkoutCreated, result)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": result.InUnits(system)})
+}
+
+// HandleGetActiveWorkout returns the current user's live workout
+//
+//	@Summary		Get active workout
+//	@Description	Retrieve the authenticated user's in-progress workout with its logged sets
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	WorkoutResponse	"Active workout"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	ErrorResponse	"No active workout"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/active [get]
+func (h *WorkoutHandler) HandleGetActiveWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workout, err := h.workoutStore.GetActiveWorkout(middleware.GetUser(r).ID)
+	if err != nil {
+		h.logger.Printf("Error retrieving active workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve active workout"})
+		return
+	}
+
+	if workout == nil {
+		utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "No active workout"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout.InUnits(system)})
+}
+
+// HandleLogSet logs a set against an active workout
+//
+//	@Summary		Log a set
+//	@Description	Record a completed set in an active workout. The set is added to entry_id, or to the entry for exercise_name which is created if needed. Optional started_at and completed_at time the set, and a rest.started event is sent for the entry's rest target.
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int						true	"Workout ID"
+//	@Param			set		body		store.LoggedSet			true	"Set performed"
+//	@Param			units	query		string					false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	WorkoutEntryResponse	"Entry with its updated set log"
+//	@Failure		400		{object}	ErrorResponse			"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse			"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse			"Forbidden - not the owner or a coach with write access"
+//	@Failure		404		{object}	ErrorResponse			"Workout not found"
+//	@Failure		409		{object}	ErrorResponse			"Workout is not active"
+//	@Failure		500		{object}	ErrorResponse			"Internal server error"
+//	@Router			/workouts/{id}/sets [post]
+func (h *WorkoutHandler) HandleLogSet(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	var set store.LoggedSet
+	err = json.NewDecoder(r.Body).Decode(&set)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	set.FromUnits(system)
+
+	ownerID, ok := h.authorizeWorkout(w, r, workoutId, policy.WriteWorkout)
+	if !ok {
+		return
+	}
+
+	entry, err := h.workoutStore.LogSet(workoutId, &set, middleware.GetUser(r).ID)
+	if err != nil {
+		if errors.Is(err, store.ErrWorkoutNotActive) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error logging set: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to log set"})
+		return
+	}
+
+	h.publishWorkoutUpdated(ownerID, workoutId)
+	h.startRestTimer(ownerID, workoutId, entry)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"entry": entry.InUnits(system)})
+}
+
+// HandleFinishWorkout completes a live workout
+//
+//	@Summary		Finish a live workout
+//	@Description	Mark an active workout completed, stamping ended_at and computing its duration
+//	@Tags			Workouts
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Workout ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{object}	WorkoutResponse	"Finished workout"
+//	@Failure		400		{object}	ErrorResponse	"Invalid workout ID"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the owner or a coach with write access"
+//	@Failure		404		{object}	ErrorResponse	"Workout not found"
+//	@Failure		409		{object}	ErrorResponse	"Workout is not active"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/workouts/{id}/finish [post]
+func (h *WorkoutHandler) HandleFinishWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	workoutId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading workout ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid workout ID"})
+		return
+	}
+
+	_, ok := h.authorizeWorkout(w, r, workoutId, policy.WriteWorkout)
+	if !ok {
+		return
+	}
+
+	workout, err := h.workoutStore.FinishWorkout(workoutId, middleware.GetUser(r).ID)
+	if err != nil {
+		if errors.Is(err, store.ErrWorkoutNotActive) {
+			utils.WriteJSON(w, http.StatusConflict, utils.Envelope{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSON(w, http.StatusNotFound, utils.Envelope{"error": "Workout not found"})
+			return
+		}
+		h.logger.Printf("Error finishing workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to finish workout"})
+		return
+	}
+
+	h.timers.Stop(workout.UserID, workoutId)
+	h.publisher.Publish(workout.UserID, events.WorkoutUpdated, workout)
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workout": workout.InUnits(system)})
+}
+
+// authorizeAthlete checks that the current user may perform action on the
+// workouts of the user named by the id URL parameter and returns that user's
+// ID. It writes the error response itself when access is denied.
+func (h *WorkoutHandler) authorizeAthlete(w http.ResponseWriter, r *http.Request, action policy.Action) (int64, bool) {
+	athleteId, err := utils.ReadIdParam(r)
+	if err != nil {
+		h.logger.Printf("Error reading athlete ID: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid athlete ID"})
+		return 0, false
+	}
+
+	currentUser := middleware.GetUser(r)
+	err = h.policy.AuthorizeUser(currentUser.ID, athleteId, action)
+	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			h.logger.Printf("User %d is not authorized to %s workouts of user %d", currentUser.ID, action, athleteId)
+			utils.WriteJSON(w, http.StatusForbidden, utils.Envelope{"error": "Forbidden"})
+			return 0, false
+		}
+		h.logger.Printf("Error authorizing athlete access: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to check coaching access"})
+		return 0, false
+	}
+
+	return athleteId, true
+}
+
+// HandleGetAthleteWorkouts lists an athlete's workouts for their coach
+//
+//	@Summary		Get an athlete's workouts
+//	@Description	List the workouts of an athlete who has granted the authenticated user coaching access, newest first
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Athlete user ID"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		200		{array}		WorkoutResponse	"Athlete's workouts"
+//	@Failure		400		{object}	ErrorResponse	"Invalid athlete ID"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - not the athlete's coach"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/athletes/{id}/workouts [get]
+func (h *WorkoutHandler) HandleGetAthleteWorkouts(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	athleteId, ok := h.authorizeAthlete(w, r, policy.ReadWorkout)
+	if !ok {
+		return
+	}
+
+	workouts, err := h.workoutStore.GetWorkoutsForUser(athleteId)
+	if err != nil {
+		h.logger.Printf("Error retrieving athlete workouts: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to retrieve workouts"})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, utils.Envelope{"workouts": workoutsInUnits(workouts, system)})
+}
+
+// HandleCreateAthleteWorkout creates a workout on behalf of an athlete
+//
+//	@Summary		Plan a workout for an athlete
+//	@Description	Create a workout owned by an athlete who has granted the authenticated user read_write coaching access
+//	@Tags			Coaching
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int				true	"Athlete user ID"
+//	@Param			workout	body		store.Workout	true	"Workout data"
+//	@Param			units	query		string			false	"Unit system: metric or imperial, overriding the X-Units header and the user's preference"
+//	@Success		201		{object}	WorkoutResponse	"Workout created for the athlete"
+//	@Failure		400		{object}	ErrorResponse	"Invalid request payload"
+//	@Failure		401		{object}	ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	ErrorResponse	"Forbidden - no write access to the athlete"
+//	@Failure		500		{object}	ErrorResponse	"Internal server error"
+//	@Router			/athletes/{id}/workouts [post]
+func (h *WorkoutHandler) HandleCreateAthleteWorkout(w http.ResponseWriter, r *http.Request) {
+	system, err := readUnits(r)
+	if err != nil {
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+		return
+	}
+
+	athleteId, ok := h.authorizeAthlete(w, r, policy.WriteWorkout)
+	if !ok {
+		return
+	}
+
+	var workout store.Workout
+	err = json.NewDecoder(r.Body).Decode(&workout)
+	if err != nil {
+		h.logger.Printf("Error decoding request body: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": "Invalid request payload"})
+		return
+	}
+
+	workout.FromUnits(system)
+	workout.UserID = athleteId
+
+	result, err := h.workoutStore.CreateWorkout(&workout, middleware.GetUser(r).ID)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidWorkout) {
+			h.logger.Printf("Validation error: %v", err)
+			utils.WriteJSON(w, http.StatusBadRequest, utils.Envelope{"error": err.Error()})
+			return
+		}
+		h.logger.Printf("Error creating athlete workout: %v", err)
+		utils.WriteJSON(w, http.StatusInternalServerError, utils.Envelope{"error": "Failed to create workout"})
+		return
+	}
+
+	h.publisher.Publish(athleteId, events.WorkoutCreated, result)
+	utils.WriteJSON(w, http.StatusCreated, utils.Envelope{"workout": result.InUnits(system)})
 }
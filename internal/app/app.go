@@ -7,19 +7,44 @@ import (
 	"os"
 
 	"github.com/mounis-bhat/rest-api-go/internal/api"
+	"github.com/mounis-bhat/rest-api-go/internal/config"
+	"github.com/mounis-bhat/rest-api-go/internal/events"
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
 	"github.com/mounis-bhat/rest-api-go/internal/middleware"
+	"github.com/mounis-bhat/rest-api-go/internal/policy"
 	"github.com/mounis-bhat/rest-api-go/internal/store"
 	"github.com/mounis-bhat/rest-api-go/internal/utils"
 	"github.com/mounis-bhat/rest-api-go/migrations"
 )
 
+// eventLogSize is how many recent events are kept for Last-Event-ID resume.
+const eventLogSize = 1000
+
 type Application struct {
-	Logger         *log.Logger
-	WorkoutHandler *api.WorkoutHandler
-	UserHandler    *api.UserHandler
-	TokenHandler   *api.TokenHandler
-	Middleware     middleware.UserMiddleware
-	DB             *sql.DB
+	Logger             *log.Logger
+	WorkoutHandler     *api.WorkoutHandler
+	UserHandler        *api.UserHandler
+	TokenHandler       *api.TokenHandler
+	RecordHandler      *api.PersonalRecordHandler
+	ExerciseHandler    *api.ExerciseHandler
+	AnalyticsHandler   *api.AnalyticsHandler
+	TemplateHandler    *api.TemplateHandler
+	ProgramHandler     *api.ProgramHandler
+	EventHandler       *api.EventHandler
+	CoachingHandler    *api.CoachingHandler
+	SocialHandler      *api.SocialHandler
+	ShareHandler       *api.ShareHandler
+	ExportHandler      *api.ExportHandler
+	ImportHandler      *api.ImportHandler
+	BodyHandler        *api.BodyMeasurementHandler
+	GoalHandler        *api.GoalHandler
+	ProgressionHandler *api.ProgressionHandler
+	PlateHandler       *api.PlateHandler
+	RestHandler        *api.RestHandler
+	Middleware         middleware.UserMiddleware
+	DB                 *sql.DB
+
+	workoutStore store.WorkoutStore
 }
 
 func NewApplication() (*Application, error) {
@@ -37,25 +62,93 @@ func NewApplication() (*Application, error) {
 	logger := log.New(os.Stdout, "", log.Ldate|log.Ltime)
 
 	workoutStore := store.NewPostgresWorkoutStore(db)
+	if path := config.GetFitnessConfig().METTablePath; path != "" {
+		mets, err := loadMETTable(path)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		workoutStore.SetMETTable(mets)
+	}
 	userStore := store.NewPostgresUserStore(db)
 	tokenStore := store.NewPostgresTokenStore(db)
+	recordStore := store.NewPostgresPersonalRecordStore(db)
+	exerciseStore := store.NewPostgresExerciseStore(db)
+	analyticsStore := store.NewPostgresAnalyticsStore(db)
+	templateStore := store.NewPostgresTemplateStore(db)
+	programStore := store.NewPostgresProgramStore(db)
+	coachingStore := store.NewPostgresCoachingStore(db)
+	socialStore := store.NewPostgresSocialStore(db)
+	shareStore := store.NewPostgresShareStore(db)
+	measurementStore := store.NewPostgresBodyMeasurementStore(db)
+	goalStore := store.NewPostgresGoalStore(db)
+	progressionStore := store.NewPostgresProgressionStore(db)
+	plateStore := store.NewPostgresPlateStore(db)
+	restStore := store.NewPostgresRestStore(db)
 
-	workoutHandler := api.NewWorkoutHandler(workoutStore, logger)
+	broker := events.NewBroker(eventLogSize)
+	restTimers := events.NewRestTimers(broker)
+	workoutPolicy := policy.NewWorkoutPolicy(workoutStore, coachingStore)
+
+	workoutHandler := api.NewWorkoutHandler(workoutStore, restStore, workoutPolicy, broker, restTimers, logger)
 	userHandler := api.NewUserHandler(userStore, logger)
 	tokenHandler := api.NewTokenHandler(userStore, tokenStore, logger)
+	recordHandler := api.NewPersonalRecordHandler(recordStore, logger)
+	exerciseHandler := api.NewExerciseHandler(exerciseStore, logger)
+	analyticsHandler := api.NewAnalyticsHandler(analyticsStore, exerciseStore, logger)
+	templateHandler := api.NewTemplateHandler(templateStore, workoutStore, progressionStore, broker, logger)
+	programHandler := api.NewProgramHandler(programStore, templateStore, workoutStore, broker, logger)
+	eventHandler := api.NewEventHandler(broker, logger)
+	coachingHandler := api.NewCoachingHandler(coachingStore, userStore, logger)
+	socialHandler := api.NewSocialHandler(socialStore, workoutPolicy, logger)
+	shareHandler := api.NewShareHandler(shareStore, workoutStore, logger)
+	exportHandler := api.NewExportHandler(workoutStore, logger)
+	importHandler := api.NewImportHandler(workoutStore, exerciseStore, broker, logger)
+	bodyHandler := api.NewBodyMeasurementHandler(measurementStore, logger)
+	goalHandler := api.NewGoalHandler(goalStore, logger)
+	progressionHandler := api.NewProgressionHandler(exerciseStore, progressionStore, logger)
+	plateHandler := api.NewPlateHandler(plateStore, workoutStore, workoutPolicy, broker, logger)
+	restHandler := api.NewRestHandler(exerciseStore, restStore, logger)
 	middlewareHandler := middleware.UserMiddleware{UserStore: userStore}
 
 	app := &Application{
-		Logger:         logger,
-		WorkoutHandler: workoutHandler,
-		UserHandler:    userHandler,
-		TokenHandler:   tokenHandler,
-		Middleware:     middlewareHandler,
-		DB:             db,
+		Logger:             logger,
+		WorkoutHandler:     workoutHandler,
+		UserHandler:        userHandler,
+		TokenHandler:       tokenHandler,
+		RecordHandler:      recordHandler,
+		ExerciseHandler:    exerciseHandler,
+		AnalyticsHandler:   analyticsHandler,
+		TemplateHandler:    templateHandler,
+		ProgramHandler:     programHandler,
+		EventHandler:       eventHandler,
+		CoachingHandler:    coachingHandler,
+		SocialHandler:      socialHandler,
+		ShareHandler:       shareHandler,
+		ExportHandler:      exportHandler,
+		ImportHandler:      importHandler,
+		BodyHandler:        bodyHandler,
+		GoalHandler:        goalHandler,
+		ProgressionHandler: progressionHandler,
+		PlateHandler:       plateHandler,
+		RestHandler:        restHandler,
+		Middleware:         middlewareHandler,
+		DB:                 db,
+		workoutStore:       workoutStore,
 	}
 	return app, nil
 }
 
+// loadMETTable reads the MET overrides at path.
+func loadMETTable(path string) (*fitness.METTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return fitness.LoadMETTable(file)
+}
+
 // HealthCheckHandler provides a health check endpoint
 //
 //	@Summary		Health check
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/config"
+)
+
+// StartBackgroundJobs launches the periodic maintenance jobs. They run for
+// the lifetime of the process.
+func (a *Application) StartBackgroundJobs() {
+	cfg := config.GetJobsConfig()
+
+	go a.runEvery(cfg.CleanupInterval, func() {
+		count, err := a.workoutStore.CleanupAbandonedWorkouts(cfg.AbandonedWorkoutTimeout)
+		if err != nil {
+			a.Logger.Printf("Error cleaning up abandoned workouts: %v", err)
+			return
+		}
+		if count > 0 {
+			a.Logger.Printf("Closed %d abandoned workouts", count)
+		}
+	})
+
+	go a.runEvery(cfg.CleanupInterval, func() {
+		count, err := a.workoutStore.PurgeTrashedWorkouts(cfg.TrashRetention)
+		if err != nil {
+			a.Logger.Printf("Error purging trashed workouts: %v", err)
+			return
+		}
+		if count > 0 {
+			a.Logger.Printf("Purged %d trashed workouts", count)
+		}
+	})
+}
+
+func (a *Application) runEvery(interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	job()
+	for range ticker.C {
+		job()
+	}
+}
@@ -0,0 +1,14 @@
+package config
+
+import "os"
+
+type FitnessConfig struct {
+	// JSON file merged over the built-in MET table, empty to use it as is
+	METTablePath string
+}
+
+func GetFitnessConfig() FitnessConfig {
+	return FitnessConfig{
+		METTablePath: os.Getenv("MET_TABLE_PATH"),
+	}
+}
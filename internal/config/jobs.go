@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type JobsConfig struct {
+	AbandonedWorkoutTimeout time.Duration
+	CleanupInterval         time.Duration
+	TrashRetention          time.Duration
+}
+
+func GetJobsConfig() JobsConfig {
+	return JobsConfig{
+		// Live workouts left active longer than this are closed automatically
+		AbandonedWorkoutTimeout: durationEnv("ABANDONED_WORKOUT_TIMEOUT", 12*time.Hour),
+		CleanupInterval:         durationEnv("CLEANUP_INTERVAL", 15*time.Minute),
+		// Deleted workouts stay restorable from the trash for this long
+		TrashRetention: durationEnv("TRASH_RETENTION", 30*24*time.Hour),
+	}
+}
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	WorkoutCreated  = "workout.created"
+	WorkoutUpdated  = "workout.updated"
+	WorkoutDeleted  = "workout.deleted"
+	WorkoutRestored = "workout.restored"
+)
+
+// subscriberBuffer is how many undelivered events a subscriber may queue
+// before it is dropped. Dropped clients reconnect and resume from the log.
+const subscriberBuffer = 32
+
+type Event struct {
+	ID        uint64    `json:"id"`
+	Type      string    `json:"type"`
+	UserID    int64     `json:"-"`
+	Data      any       `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Publisher is the side of the broker used by handlers that change data.
+type Publisher interface {
+	Publish(userID int64, eventType string, data any) Event
+}
+
+// Broker is an in-process pub/sub for per-user events. It keeps the most
+// recent events in a bounded log so that reconnecting clients can resume.
+type Broker struct {
+	mu          sync.Mutex
+	nextID      uint64
+	log         []Event // ring buffer, oldest at head
+	head        int
+	size        int
+	subscribers map[int64]map[*Subscription]struct{}
+}
+
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	userID int64
+	broker *Broker
+	closed bool
+}
+
+func NewBroker(logSize int) *Broker {
+	if logSize < 1 {
+		logSize = 1
+	}
+	return &Broker{
+		nextID:      1,
+		log:         make([]Event, logSize),
+		subscribers: make(map[int64]map[*Subscription]struct{}),
+	}
+}
+
+// Publish records an event for userID and fans it out to that user's
+// subscribers. Subscribers whose buffer is full are dropped rather than
+// blocking the publisher.
+func (b *Broker) Publish(userID int64, eventType string, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event := Event{
+		ID:        b.nextID,
+		Type:      eventType,
+		UserID:    userID,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+	b.nextID++
+	b.append(event)
+
+	for sub := range b.subscribers[userID] {
+		select {
+		case sub.ch <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+
+	return event
+}
+
+// Subscribe registers a subscriber for userID. Events for that user logged
+// after lastEventID are returned as the backlog; complete is false when the
+// log no longer reaches back that far and events may have been missed.
+// A lastEventID of 0 means a fresh connection with no backlog.
+func (b *Broker) Subscribe(userID int64, lastEventID uint64) (sub *Subscription, backlog []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastEventID > 0 {
+		backlog, complete = b.since(userID, lastEventID)
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	sub = &Subscription{C: ch, ch: ch, userID: userID, broker: b}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][sub] = struct{}{}
+
+	return sub, backlog, complete
+}
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+func (b *Broker) append(event Event) {
+	if b.size < len(b.log) {
+		b.log[(b.head+b.size)%len(b.log)] = event
+		b.size++
+		return
+	}
+	b.log[b.head] = event
+	b.head = (b.head + 1) % len(b.log)
+}
+
+func (b *Broker) since(userID int64, lastEventID uint64) ([]Event, bool) {
+	if lastEventID >= b.nextID {
+		// The client saw IDs from a previous process, so nothing can be resumed.
+		return []Event{}, false
+	}
+
+	// IDs are sequential, so nothing was missed as long as the oldest retained
+	// event directly follows, or precedes, lastEventID.
+	complete := b.log[b.head].ID <= lastEventID+1
+
+	backlog := []Event{}
+	for i := 0; i < b.size; i++ {
+		event := b.log[(b.head+i)%len(b.log)]
+		if event.ID > lastEventID && event.UserID == userID {
+			backlog = append(backlog, event)
+		}
+	}
+	return backlog, complete
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+
+	subs := b.subscribers[sub.userID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.userID)
+	}
+}
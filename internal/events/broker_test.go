@@ -0,0 +1,88 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventIDs(events []Event) []uint64 {
+	ids := []uint64{}
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestPublishDeliversToUserSubscribers(t *testing.T) {
+	broker := NewBroker(10)
+
+	sub, backlog, complete := broker.Subscribe(1, 0)
+	defer sub.Close()
+	other, _, _ := broker.Subscribe(2, 0)
+	defer other.Close()
+
+	assert.Empty(t, backlog)
+	assert.True(t, complete)
+
+	broker.Publish(1, WorkoutCreated, map[string]int{"id": 7})
+
+	event := <-sub.C
+	assert.Equal(t, uint64(1), event.ID)
+	assert.Equal(t, WorkoutCreated, event.Type)
+	assert.Empty(t, other.C)
+}
+
+func TestSubscribeResumesFromLog(t *testing.T) {
+	broker := NewBroker(10)
+	broker.Publish(1, WorkoutCreated, nil) // 1
+	broker.Publish(2, WorkoutCreated, nil) // 2
+	broker.Publish(1, WorkoutUpdated, nil) // 3
+	broker.Publish(1, WorkoutDeleted, nil) // 4
+
+	sub, backlog, complete := broker.Subscribe(1, 1)
+	defer sub.Close()
+
+	assert.True(t, complete)
+	assert.Equal(t, []uint64{3, 4}, eventIDs(backlog))
+}
+
+func TestSubscribeReportsTruncatedLog(t *testing.T) {
+	broker := NewBroker(3)
+	for i := 0; i < 5; i++ {
+		broker.Publish(1, WorkoutUpdated, nil)
+	}
+
+	sub, backlog, complete := broker.Subscribe(1, 1)
+	defer sub.Close()
+	assert.False(t, complete, "event 2 fell out of the log")
+	assert.Equal(t, []uint64{3, 4, 5}, eventIDs(backlog))
+
+	sub2, backlog, complete := broker.Subscribe(1, 2)
+	defer sub2.Close()
+	assert.True(t, complete)
+	assert.Equal(t, []uint64{3, 4, 5}, eventIDs(backlog))
+
+	sub3, backlog, complete := broker.Subscribe(1, 99)
+	defer sub3.Close()
+	assert.False(t, complete, "ID from a previous process")
+	assert.Empty(t, backlog)
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	broker := NewBroker(100)
+	sub, _, _ := broker.Subscribe(1, 0)
+
+	for i := 0; i < subscriberBuffer+1; i++ {
+		broker.Publish(1, WorkoutUpdated, nil)
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	require.Equal(t, subscriberBuffer, received)
+
+	sub.Close() // closing again after the broker dropped it is a no-op
+}
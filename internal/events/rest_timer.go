@@ -0,0 +1,84 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	RestStarted   = "rest.started"
+	RestFinished  = "rest.finished"
+	RestCancelled = "rest.cancelled"
+)
+
+// RestTimer is the data of rest timer events: the rest after a set, from
+// its completion to EndsAt.
+type RestTimer struct {
+	WorkoutID     int64     `json:"workout_id"`
+	EntryID       int       `json:"entry_id"`
+	ExerciseName  string    `json:"exercise_name"`
+	SetNumber     int       `json:"set_number"` // the set the rest follows
+	TargetSeconds int       `json:"target_seconds"`
+	StartedAt     time.Time `json:"started_at"`
+	EndsAt        time.Time `json:"ends_at"`
+}
+
+// RestTimers runs at most one rest timer per user and publishes its events:
+// RestStarted when it starts and RestFinished when it runs out. Starting a
+// timer replaces the user's running one without further events.
+type RestTimers struct {
+	publisher Publisher
+
+	mu     sync.Mutex
+	timers map[int64]*restTimer
+}
+
+type restTimer struct {
+	timer *time.Timer
+	rest  RestTimer
+}
+
+func NewRestTimers(publisher Publisher) *RestTimers {
+	return &RestTimers{publisher: publisher, timers: make(map[int64]*restTimer)}
+}
+
+// Start starts userID's rest timer, which finishes at rest.EndsAt.
+func (t *RestTimers) Start(userID int64, rest RestTimer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if running, ok := t.timers[userID]; ok {
+		running.timer.Stop()
+	}
+	running := &restTimer{rest: rest}
+	running.timer = time.AfterFunc(time.Until(rest.EndsAt), func() { t.finish(userID, running) })
+	t.timers[userID] = running
+	t.publisher.Publish(userID, RestStarted, rest)
+}
+
+// Stop cancels userID's rest timer if it is running for workoutID, and
+// publishes RestCancelled.
+func (t *RestTimers) Stop(userID, workoutID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	running, ok := t.timers[userID]
+	if !ok || running.rest.WorkoutID != workoutID {
+		return
+	}
+	running.timer.Stop()
+	delete(t.timers, userID)
+	t.publisher.Publish(userID, RestCancelled, running.rest)
+}
+
+func (t *RestTimers) finish(userID int64, running *restTimer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// a timer replaced or stopped after it fired is no longer current
+	if t.timers[userID] != running {
+		return
+	}
+	delete(t.timers, userID)
+	t.publisher.Publish(userID, RestFinished, running.rest)
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nextEvent(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event")
+		return Event{}
+	}
+}
+
+func rest(workoutID int64, setNumber int, after time.Duration) RestTimer {
+	now := time.Now()
+	return RestTimer{WorkoutID: workoutID, SetNumber: setNumber, StartedAt: now, EndsAt: now.Add(after)}
+}
+
+func TestRestTimerFinishes(t *testing.T) {
+	broker := NewBroker(10)
+	sub, _, _ := broker.Subscribe(1, 0)
+	defer sub.Close()
+	timers := NewRestTimers(broker)
+
+	timers.Start(1, rest(7, 1, 10*time.Millisecond))
+
+	started := nextEvent(t, sub)
+	assert.Equal(t, RestStarted, started.Type)
+	finished := nextEvent(t, sub)
+	assert.Equal(t, RestFinished, finished.Type)
+	assert.Equal(t, 1, finished.Data.(RestTimer).SetNumber)
+}
+
+func TestRestTimerIsReplaced(t *testing.T) {
+	broker := NewBroker(10)
+	sub, _, _ := broker.Subscribe(1, 0)
+	defer sub.Close()
+	timers := NewRestTimers(broker)
+
+	timers.Start(1, rest(7, 1, 10*time.Millisecond))
+	timers.Start(1, rest(7, 2, 30*time.Millisecond))
+
+	assert.Equal(t, RestStarted, nextEvent(t, sub).Type)
+	assert.Equal(t, RestStarted, nextEvent(t, sub).Type)
+	finished := nextEvent(t, sub)
+	assert.Equal(t, RestFinished, finished.Type)
+	assert.Equal(t, 2, finished.Data.(RestTimer).SetNumber, "only the replacing timer finishes")
+}
+
+func TestRestTimerStop(t *testing.T) {
+	broker := NewBroker(10)
+	sub, _, _ := broker.Subscribe(1, 0)
+	defer sub.Close()
+	timers := NewRestTimers(broker)
+
+	timers.Start(1, rest(7, 1, 20*time.Millisecond))
+	timers.Stop(1, 8) // another workout's timer is left running
+	timers.Stop(1, 7)
+
+	assert.Equal(t, RestStarted, nextEvent(t, sub).Type)
+	assert.Equal(t, RestCancelled, nextEvent(t, sub).Type)
+	time.Sleep(40 * time.Millisecond)
+	assert.Empty(t, sub.C, "a stopped timer does not finish")
+}
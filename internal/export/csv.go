@@ -0,0 +1,234 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+)
+
+// CSVColumns is the CSV header. The layout is part of the API: columns are
+// only ever appended, never renamed, removed or reordered.
+//
+// There is one row per set. Sets logged during a live session are exported
+// as logged (set_logged is true) next to the entry's generated warm-up sets,
+// which are flagged by warmup so totals can leave them out; other entries
+// expand to one row per planned set carrying the entry's reps, weight and
+// duration; cardio entries without sets get a single row. The cardio columns
+// describe the whole entry and repeat on each of its rows. A workout
+// without entries still gets a single row with the exercise columns empty.
+// Workout and entry columns repeat on every row so each row stands alone.
+var CSVColumns = []string{
+	"workout_id",       // workout ID
+	"date",             // day the workout was created, in the user's time zone (YYYY-MM-DD)
+	"started_at",       // RFC 3339 start of a live session, empty otherwise
+	"title",            // workout title
+	"description",      // workout description
+	"tags",             // tags separated by "; "
+	"duration_minutes", // workout duration
+	"calories_burned",  // workout calories in kcal
+	"entry_order",      // position of the exercise in the workout, from 0
+	"exercise_name",    // exercise name
+	"group_type",       // superset, circuit or giant_set, empty when ungrouped
+	"set_number",       // position of the set within the entry, from 1
+	"reps",             // repetitions, empty when not applicable
+	"weight_kg",        // weight in kilograms, empty when not applicable
+	"duration_seconds", // set duration, empty when not applicable
+	"set_logged",       // true for sets logged individually during a live session
+	"completed_at",     // RFC 3339 time a logged set was completed
+	"notes",            // entry notes
+
+	"distance_meters",       // entry distance
+	"pace_seconds_per_km",   // entry pace
+	"incline_percent",       // treadmill incline
+	"resistance_level",      // machine resistance setting
+	"interval_repeats",      // number of work bouts, empty for steady efforts
+	"interval_work_seconds", // duration of each work bout
+	"interval_work_meters",  // distance of each work bout
+	"interval_rest_seconds", // rest between work bouts
+
+	"weight",        // weight in weight_unit, as workouts return it
+	"weight_unit",   // kg or lb, the export's unit system
+	"distance",      // entry distance in distance_unit
+	"distance_unit", // km or mi
+	"pace_seconds",  // entry pace in seconds per distance_unit
+
+	"warmup", // true for warm-up sets, which do not count towards the entry
+}
+
+const utf8BOM = "\xef\xbb\xbf"
+
+type csvWriter struct {
+	w             *csv.Writer
+	loc           *time.Location
+	units         units.System
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer, opts Options) (*csvWriter, error) {
+	if opts.BOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	writer := csv.NewWriter(w)
+	// CRLF line endings are what RFC 4180 and spreadsheet programs expect.
+	writer.UseCRLF = true
+	return &csvWriter{w: writer, loc: loc, units: opts.Units}, nil
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(CSVColumns)
+}
+
+func (c *csvWriter) WriteWorkout(workout *store.Workout) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	workoutColumns := []string{
+		strconv.Itoa(workout.ID),
+		workout.CreatedAt.In(c.loc).Format("2006-01-02"),
+		formatTime(workout.StartedAt),
+		spreadsheetSafe(workout.Title),
+		spreadsheetSafe(workout.Description),
+		spreadsheetSafe(strings.Join(workout.Tags, "; ")),
+		strconv.Itoa(workout.DurationMinutes),
+		strconv.Itoa(workout.CaloriesBurned),
+	}
+
+	if len(workout.Entries) == 0 {
+		return c.w.Write(append(workoutColumns, make([]string, len(CSVColumns)-len(workoutColumns))...))
+	}
+
+	converted := workout.InUnits(c.units)
+	for i, entry := range workout.Entries {
+		inUnits := converted.Entries[i]
+		unitColumns := func(weight *float64) []string {
+			return []string{
+				formatFloat(weight),
+				c.units.WeightUnit(),
+				formatFloat(inUnits.Distance),
+				c.units.DistanceUnit(),
+				formatInt(inUnits.PaceSeconds),
+			}
+		}
+		groupType := ""
+		if entry.GroupIndex != nil && *entry.GroupIndex < len(workout.Groups) {
+			groupType = workout.Groups[*entry.GroupIndex].GroupType
+		}
+		entryColumns := []string{
+			strconv.Itoa(entry.OrderIndex),
+			spreadsheetSafe(entry.ExerciseName),
+			groupType,
+		}
+		cardioColumns := []string{
+			formatFloat(entry.DistanceMeters),
+			formatInt(entry.PaceSecondsPerKm),
+			formatFloat(entry.InclinePercent),
+			formatInt(entry.ResistanceLevel),
+			"", "", "", "",
+		}
+		if intervals := entry.Intervals; intervals != nil {
+			cardioColumns[4] = strconv.Itoa(intervals.Repeats)
+			cardioColumns[5] = formatInt(intervals.WorkSeconds)
+			cardioColumns[6] = formatFloat(intervals.WorkMeters)
+			cardioColumns[7] = strconv.Itoa(intervals.RestSeconds)
+		}
+
+		if len(entry.SetLog) > 0 {
+			for j, set := range entry.SetLog {
+				row := concat(workoutColumns, entryColumns, []string{
+					strconv.Itoa(set.SetNumber),
+					formatInt(set.Reps),
+					formatFloat(set.Weight),
+					formatInt(set.DurationSeconds),
+					strconv.FormatBool(set.CompletedAt != nil), // generated warm-ups are not performed yet
+					formatTime(set.CompletedAt),
+					spreadsheetSafe(entry.Notes),
+				}, cardioColumns, unitColumns(inUnits.SetLog[j].Weight), []string{strconv.FormatBool(set.Warmup)})
+				if err := c.w.Write(row); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for setNumber := 1; setNumber <= max(entry.Sets, 1); setNumber++ {
+			row := concat(workoutColumns, entryColumns, []string{
+				strconv.Itoa(setNumber),
+				formatInt(entry.Reps),
+				formatFloat(entry.Weight),
+				formatInt(entry.DurationSeconds),
+				"false",
+				"",
+				spreadsheetSafe(entry.Notes),
+			}, cardioColumns, unitColumns(inUnits.Weight), []string{"false"})
+			if err := c.w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func concat(parts ...[]string) []string {
+	row := make([]string, 0, len(CSVColumns))
+	for _, part := range parts {
+		row = append(row, part...)
+	}
+	return row
+}
+
+// spreadsheetSafe stops spreadsheet programs from evaluating user text as a
+// formula by prefixing cells that start with a formula character with an
+// apostrophe. Quoting and escaping are left to encoding/csv.
+func spreadsheetSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
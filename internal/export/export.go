@@ -0,0 +1,118 @@
+// Package export writes a user's training log in downloadable formats. Each
+// Writer streams one workout at a time so exports never need the whole log
+// in memory.
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+var ErrUnknownFormat = errors.New("format must be csv, json or ndjson")
+
+type Options struct {
+	// Location is used for the CSV date column.
+	Location *time.Location
+	// BOM prefixes CSV output with a UTF-8 byte order mark so spreadsheet
+	// programs detect the encoding.
+	BOM bool
+	// Units is the unit system weights, distances and paces are written in,
+	// defaulting to metric. CSV also keeps its metric columns.
+	Units units.System
+}
+
+type Writer interface {
+	WriteWorkout(workout *store.Workout) error
+	// Close writes anything that has to follow the last workout and flushes
+	// buffered output. It does not close the underlying writer.
+	Close() error
+}
+
+// NewWriter returns a Writer for format, which must be one of the Format
+// constants.
+func NewWriter(format string, w io.Writer, opts Options) (Writer, error) {
+	if opts.Units == "" {
+		opts.Units = units.Metric
+	}
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, opts)
+	case FormatJSON:
+		return &jsonWriter{w: w, units: opts.Units}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w), units: opts.Units}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType returns the MIME type for format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// jsonWriter streams workouts as the elements of a single JSON array.
+type jsonWriter struct {
+	w       io.Writer
+	units   units.System
+	started bool
+}
+
+func (j *jsonWriter) WriteWorkout(workout *store.Workout) error {
+	data, err := json.Marshal(workout.InUnits(j.units))
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if !j.started {
+		separator = "[\n"
+		j.started = true
+	}
+	if _, err := io.WriteString(j.w, separator); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.started {
+		_, err := io.WriteString(j.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+// ndjsonWriter writes one workout object per line.
+type ndjsonWriter struct {
+	encoder *json.Encoder
+	units   units.System
+}
+
+func (n *ndjsonWriter) WriteWorkout(workout *store.Workout) error {
+	return n.encoder.Encode(workout.InUnits(n.units))
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,231 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkout() *store.Workout {
+	group := 0
+	completedAt := time.Date(2024, 1, 15, 23, 40, 0, 0, time.UTC)
+	return &store.Workout{
+		ID:              7,
+		Title:           `Leg "Day", heavy`,
+		Description:     "=HYPERLINK(\"x\")",
+		DurationMinutes: 60,
+		CaloriesBurned:  450,
+		Tags:            []string{"legs", "strength"},
+		CreatedAt:       time.Date(2024, 1, 15, 23, 30, 0, 0, time.UTC),
+		Groups:          []store.EntryGroup{{GroupType: "superset", Rounds: 3}},
+		Entries: []store.WorkoutEntry{
+			{
+				ExerciseName: "Squat",
+				Sets:         2,
+				Reps:         utils.IntPtr(5),
+				Weight:       utils.Float64Ptr(102.5),
+				Notes:        "line one\nline two",
+				OrderIndex:   0,
+				GroupIndex:   &group,
+			},
+			{
+				ExerciseName: "Bench Press",
+				Sets:         3,
+				OrderIndex:   1,
+				SetLog: []store.WorkoutSet{
+					{SetNumber: 1, Reps: utils.IntPtr(8), Weight: utils.Float64Ptr(80), CompletedAt: &completedAt},
+				},
+			},
+		},
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	writer, err := NewWriter(FormatCSV, &buf, Options{Location: loc, BOM: true})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteWorkout(testWorkout()))
+	require.NoError(t, writer.Close())
+
+	require.True(t, strings.HasPrefix(buf.String(), utf8BOM))
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), utf8BOM))).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 4)
+	assert.Equal(t, CSVColumns, rows[0])
+
+	squat := rows[1]
+	assert.Equal(t, "7", squat[0])
+	assert.Equal(t, "2024-01-16", squat[1], "date should be in the user's time zone")
+	assert.Equal(t, `Leg "Day", heavy`, squat[3])
+	assert.Equal(t, `'=HYPERLINK("x")`, squat[4], "formulas should be neutralised")
+	assert.Equal(t, "legs; strength", squat[5])
+	assert.Equal(t, []string{"0", "Squat", "superset", "1", "5", "102.5", "", "false", "", "line one\nline two"}, squat[8:18])
+	assert.Equal(t, "2", rows[2][11])
+
+	bench := rows[3]
+	assert.Equal(t, []string{"1", "Bench Press", "", "1", "8", "80", "", "true", "2024-01-15T23:40:00Z", ""}, bench[8:18])
+}
+
+func TestCSVWriterCardio(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, Options{})
+	require.NoError(t, err)
+
+	workout := testWorkout()
+	workout.Entries = []store.WorkoutEntry{
+		{ExerciseName: "Running", DistanceMeters: utils.Float64Ptr(5000), DurationSeconds: utils.IntPtr(1500), PaceSecondsPerKm: utils.IntPtr(300), InclinePercent: utils.Float64Ptr(1.5)},
+		{ExerciseName: "Rowing", OrderIndex: 1, ResistanceLevel: utils.IntPtr(6), Intervals: &store.Intervals{Repeats: 8, WorkMeters: utils.Float64Ptr(500), RestSeconds: 90}},
+	}
+	require.NoError(t, writer.WriteWorkout(workout))
+	require.NoError(t, writer.Close())
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3, "cardio entries without sets get one row each")
+	assert.Equal(t, []string{"5000", "300", "1.5", "", "", "", "", ""}, rows[1][18:26])
+	assert.Equal(t, []string{"", "kg", "5", "km", "300", "false"}, rows[1][26:])
+	assert.Equal(t, []string{"", "", "", "6", "8", "", "500", "90"}, rows[2][18:26])
+}
+
+func TestCSVWriterImperial(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, Options{Units: units.Imperial})
+	require.NoError(t, err)
+
+	workout := testWorkout()
+	workout.Entries = append(workout.Entries, store.WorkoutEntry{ExerciseName: "Running", OrderIndex: 2, DistanceMeters: utils.Float64Ptr(5000), PaceSecondsPerKm: utils.IntPtr(300)})
+	require.NoError(t, writer.WriteWorkout(workout))
+	require.NoError(t, writer.Close())
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, "102.5", rows[1][13], "weight_kg stays metric")
+	assert.Equal(t, []string{"225.97", "lb", "", "mi", ""}, rows[1][26:31])
+	assert.Equal(t, []string{"176.37", "lb", "", "mi", ""}, rows[3][26:31], "logged sets use their own weight")
+	assert.Equal(t, []string{"", "lb", "3.11", "mi", "483"}, rows[4][26:31])
+}
+
+func TestCSVWriterWarmups(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, Options{})
+	require.NoError(t, err)
+
+	completedAt := time.Date(2024, 1, 15, 23, 40, 0, 0, time.UTC)
+	workout := testWorkout()
+	workout.Entries = []store.WorkoutEntry{
+		{
+			ExerciseName: "Squat",
+			Sets:         1,
+			SetLog: []store.WorkoutSet{
+				{SetNumber: 1, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(60), Warmup: true},
+				{SetNumber: 2, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100), CompletedAt: &completedAt},
+			},
+		},
+	}
+	require.NoError(t, writer.WriteWorkout(workout))
+	require.NoError(t, writer.Close())
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	warmup, working := rows[1], rows[2]
+	assert.Equal(t, []string{"false", ""}, warmup[15:17], "generated warm-ups are not logged")
+	assert.Equal(t, "true", warmup[len(warmup)-1])
+	assert.Equal(t, []string{"true", "2024-01-15T23:40:00Z"}, working[15:17])
+	assert.Equal(t, "false", working[len(working)-1])
+}
+
+func TestCSVWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, Options{})
+	require.NoError(t, err)
+
+	workout := testWorkout()
+	workout.Entries = nil
+	require.NoError(t, writer.WriteWorkout(workout))
+	require.NoError(t, writer.Close())
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Len(t, rows[1], len(CSVColumns))
+	assert.Equal(t, "", rows[1][9])
+}
+
+func TestJSONWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		workouts int
+	}{
+		{name: "empty", workouts: 0},
+		{name: "one", workouts: 1},
+		{name: "several", workouts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewWriter(FormatJSON, &buf, Options{})
+			require.NoError(t, err)
+			for i := 0; i < tt.workouts; i++ {
+				require.NoError(t, writer.WriteWorkout(testWorkout()))
+			}
+			require.NoError(t, writer.Close())
+
+			var workouts []store.Workout
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &workouts))
+			assert.Len(t, workouts, tt.workouts)
+		})
+	}
+}
+
+func TestJSONWriterImperial(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatJSON, &buf, Options{Units: units.Imperial})
+	require.NoError(t, err)
+	workout := testWorkout()
+	require.NoError(t, writer.WriteWorkout(workout))
+	require.NoError(t, writer.Close())
+
+	var workouts []store.Workout
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &workouts))
+	require.Len(t, workouts, 1)
+	assert.Equal(t, 225.97, *workouts[0].Entries[0].Weight)
+	assert.Equal(t, 102.5, *workout.Entries[0].Weight, "the exported workout is not modified")
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatNDJSON, &buf, Options{})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteWorkout(testWorkout()))
+	require.NoError(t, writer.WriteWorkout(testWorkout()))
+	require.NoError(t, writer.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		var workout store.Workout
+		require.NoError(t, json.Unmarshal([]byte(line), &workout))
+		assert.Equal(t, 7, workout.ID)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xlsx", &bytes.Buffer{}, Options{})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,39 @@
+package fitness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateMaxHeartRate(t *testing.T) {
+	assert.Equal(t, 187, EstimateMaxHeartRate(30))
+	assert.Equal(t, 173, EstimateMaxHeartRate(50))
+}
+
+func TestHeartRateZones(t *testing.T) {
+	zones := HeartRateZones(200, 0)
+	assert.Equal(t, []HeartRateZone{
+		{Zone: 1, MinBPM: 100, MaxBPM: 120},
+		{Zone: 2, MinBPM: 120, MaxBPM: 140},
+		{Zone: 3, MinBPM: 140, MaxBPM: 160},
+		{Zone: 4, MinBPM: 160, MaxBPM: 180},
+		{Zone: 5, MinBPM: 180, MaxBPM: 200},
+	}, zones)
+
+	karvonen := HeartRateZones(190, 60)
+	assert.Equal(t, 125, karvonen[0].MinBPM, "zones sit within the heart-rate reserve")
+	assert.Equal(t, 177, karvonen[4].MinBPM)
+
+	assert.Equal(t, 0, ZoneFor(zones, 99))
+	assert.Equal(t, 1, ZoneFor(zones, 100))
+	assert.Equal(t, 3, ZoneFor(zones, 159))
+	assert.Equal(t, 5, ZoneFor(zones, 210), "the top zone is open ended")
+}
+
+func TestCaloriesFromHeartRate(t *testing.T) {
+	assert.Equal(t, 672, CaloriesFromHeartRate(150, 45, 80, 35, "male"))
+	assert.Equal(t, 448, CaloriesFromHeartRate(150, 45, 60, 35, "female"))
+	assert.Equal(t, 543, CaloriesFromHeartRate(150, 45, 70, 35, ""), "unknown sex averages both equations")
+	assert.Equal(t, 0, CaloriesFromHeartRate(40, 45, 80, 35, "male"), "negative estimates are clamped")
+}
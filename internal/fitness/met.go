@@ -0,0 +1,129 @@
+package fitness
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// defaultMETs is the built-in table, with values from the Compendium of
+// Physical Activities.
+//
+//go:embed met.json
+var defaultMETs []byte
+
+// ReferenceWeightKg is used for calorie estimates when the user's weight is
+// unknown.
+const ReferenceWeightKg = 70
+
+// METTable maps exercises to metabolic equivalents: the energy cost of an
+// activity as a multiple of resting metabolism, so that kcal = MET x kg x
+// hours. Exercise names are matched case-insensitively.
+type METTable struct {
+	Default    float64               `json:"default"`     // for exercises and categories not in the table
+	SetSeconds int                   `json:"set_seconds"` // time a strength set takes including rest, for entries without a duration
+	Categories map[string]float64    `json:"categories"`  // by exercise category: strength or cardio
+	Exercises  map[string]float64    `json:"exercises"`   // by exercise name
+	Speeds     map[string][]SpeedMET `json:"speeds"`      // by exercise name, sorted by speed, used when the speed is known
+}
+
+// SpeedMET is the MET of an activity at a speed in km/h. It is encoded as a
+// [speed, met] pair.
+type SpeedMET [2]float64
+
+// DefaultMETTable returns a copy of the embedded table.
+func DefaultMETTable() *METTable {
+	table := &METTable{}
+	if err := json.Unmarshal(defaultMETs, table); err != nil {
+		panic(fmt.Sprintf("fitness: invalid embedded MET table: %v", err))
+	}
+	return table.normalize()
+}
+
+// LoadMETTable reads a table in the embedded format and merges it over the
+// default: scalar values that are set replace the defaults, and exercise,
+// category and speed entries are added or replaced one by one.
+func LoadMETTable(r io.Reader) (*METTable, error) {
+	override := &METTable{}
+	if err := json.NewDecoder(r).Decode(override); err != nil {
+		return nil, fmt.Errorf("invalid MET table: %w", err)
+	}
+	override.normalize()
+
+	table := DefaultMETTable()
+	if override.Default > 0 {
+		table.Default = override.Default
+	}
+	if override.SetSeconds > 0 {
+		table.SetSeconds = override.SetSeconds
+	}
+	maps.Copy(table.Categories, override.Categories)
+	maps.Copy(table.Exercises, override.Exercises)
+	maps.Copy(table.Speeds, override.Speeds)
+	for name, met := range table.Exercises {
+		if met <= 0 {
+			return nil, fmt.Errorf("invalid MET table: %q must have a positive MET", name)
+		}
+	}
+	return table, nil
+}
+
+// normalize lowercases keys and sorts speed curves.
+func (t *METTable) normalize() *METTable {
+	t.Categories = lowerKeys(t.Categories)
+	t.Exercises = lowerKeys(t.Exercises)
+	t.Speeds = lowerKeys(t.Speeds)
+	for _, curve := range t.Speeds {
+		sort.Slice(curve, func(i, j int) bool { return curve[i][0] < curve[j][0] })
+	}
+	return t
+}
+
+func lowerKeys[V any](m map[string]V) map[string]V {
+	lowered := make(map[string]V, len(m))
+	for key, value := range m {
+		lowered[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return lowered
+}
+
+// Lookup returns the MET of an exercise. When speedKmh is positive and the
+// exercise has a speed curve, the MET is interpolated along it (and held at
+// its ends); otherwise the exercise's value is used, then its category's,
+// then the default.
+func (t *METTable) Lookup(exercise, category string, speedKmh float64) float64 {
+	name := strings.ToLower(strings.TrimSpace(exercise))
+	if curve := t.Speeds[name]; speedKmh > 0 && len(curve) > 0 {
+		return interpolate(curve, speedKmh)
+	}
+	if met, ok := t.Exercises[name]; ok {
+		return met
+	}
+	if met, ok := t.Categories[strings.ToLower(category)]; ok {
+		return met
+	}
+	return t.Default
+}
+
+func interpolate(curve []SpeedMET, speed float64) float64 {
+	if speed <= curve[0][0] {
+		return curve[0][1]
+	}
+	for i := 1; i < len(curve); i++ {
+		if speed <= curve[i][0] {
+			low, high := curve[i-1], curve[i]
+			return low[1] + (high[1]-low[1])*(speed-low[0])/(high[0]-low[0])
+		}
+	}
+	return curve[len(curve)-1][1]
+}
+
+// CaloriesFromMET returns the kcal burned doing an activity of the given MET
+// for seconds at a body weight.
+func CaloriesFromMET(met, weightKg float64, seconds int) float64 {
+	return met * weightKg * float64(seconds) / 3600
+}
@@ -0,0 +1,50 @@
+package fitness
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMETLookup(t *testing.T) {
+	table := DefaultMETTable()
+
+	assert.Equal(t, 6.0, table.Lookup("Squat", "strength", 0), "names match case-insensitively")
+	assert.Equal(t, 5.0, table.Lookup("Zercher Squat", "strength", 0), "unknown exercises use their category")
+	assert.Equal(t, 7.0, table.Lookup("Assault Bike", "cardio", 0))
+	assert.Equal(t, table.Default, table.Lookup("Zercher Squat", "", 0))
+
+	assert.Equal(t, 9.8, table.Lookup("Running", "cardio", 0), "without a speed the flat value is used")
+	assert.Equal(t, 9.8, table.Lookup("Running", "cardio", 9.7))
+	assert.InDelta(t, 10.15, table.Lookup("Running", "cardio", 10.25), 0.01, "speeds are interpolated")
+	assert.Equal(t, 6.0, table.Lookup("Running", "cardio", 3), "curves are held at their ends")
+	assert.Equal(t, 19.0, table.Lookup("Running", "cardio", 25))
+}
+
+func TestLoadMETTable(t *testing.T) {
+	table, err := LoadMETTable(strings.NewReader(`{
+		"set_seconds": 90,
+		"exercises": {"Bench Press": 6.0, "Sled Push": 8.0},
+		"speeds": {"Rowing": [[12, 12.0], [8, 6.0]]}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 90, table.SetSeconds)
+	assert.Equal(t, 5.0, table.Default, "unset values keep the defaults")
+	assert.Equal(t, 6.0, table.Lookup("bench press", "strength", 0))
+	assert.Equal(t, 8.0, table.Lookup("Sled Push", "", 0))
+	assert.Equal(t, 6.0, table.Lookup("Squat", "strength", 0), "other exercises are kept")
+	assert.Equal(t, 9.0, table.Lookup("Rowing", "cardio", 10), "curves are sorted by speed")
+
+	_, err = LoadMETTable(strings.NewReader(`{"exercises": {"Squat": 0}}`))
+	assert.Error(t, err)
+	_, err = LoadMETTable(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
+
+func TestCaloriesFromMET(t *testing.T) {
+	assert.InDelta(t, 490, CaloriesFromMET(9.8, 50, 3600), 0.001)
+	assert.InDelta(t, 175, CaloriesFromMET(5, 70, 1800), 0.001)
+}
@@ -0,0 +1,45 @@
+package fitness
+
+// EstimateOneRepMax estimates a one-rep max from a set of reps at a given
+// weight. Brzycki is used up to 10 reps where it tracks tested maxes more
+// closely, Epley above that. A single rep is returned as-is.
+func EstimateOneRepMax(weight float64, reps int) float64 {
+	if weight <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+	if reps <= 10 {
+		return Brzycki(weight, reps)
+	}
+	return Epley(weight, reps)
+}
+
+// Epley estimates a one-rep max as weight * (1 + reps/30).
+func Epley(weight float64, reps int) float64 {
+	return weight * (1 + float64(reps)/30)
+}
+
+// Brzycki estimates a one-rep max as weight * 36 / (37 - reps).
+func Brzycki(weight float64, reps int) float64 {
+	if reps >= 37 {
+		return Epley(weight, reps)
+	}
+	return weight * 36 / (37 - float64(reps))
+}
+
+// WeightForReps inverts EstimateOneRepMax: the weight that can be lifted for
+// reps given a one-rep max.
+func WeightForReps(oneRepMax float64, reps int) float64 {
+	if oneRepMax <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return oneRepMax
+	}
+	if reps <= 10 {
+		return oneRepMax * (37 - float64(reps)) / 36
+	}
+	return oneRepMax / (1 + float64(reps)/30)
+}
@@ -0,0 +1,21 @@
+package fitness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	assert.Equal(t, 100.0, EstimateOneRepMax(100, 1))
+	assert.InDelta(t, 116.13, EstimateOneRepMax(100, 6), 0.01, "Brzycki up to 10 reps")
+	assert.InDelta(t, 140, EstimateOneRepMax(100, 12), 0.01, "Epley above")
+	assert.Zero(t, EstimateOneRepMax(100, 0))
+}
+
+func TestWeightForReps(t *testing.T) {
+	for reps := 1; reps <= 20; reps++ {
+		assert.InDelta(t, 100, WeightForReps(EstimateOneRepMax(100, reps), reps), 1e-9, "%d reps round trip", reps)
+	}
+	assert.Zero(t, WeightForReps(0, 5))
+}
@@ -0,0 +1,172 @@
+package importer
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// matchThreshold is the lowest score at which a file's exercise name is
+// replaced by a catalog name.
+const matchThreshold = 0.8
+
+// equipmentWords qualify an exercise rather than name it. Other apps often
+// put them in parentheses, as in "Bench Press (Barbell)".
+var equipmentWords = map[string]bool{
+	"barbell": true, "dumbbell": true, "cable": true, "machine": true, "smith": true,
+	"kettlebell": true, "band": true, "bodyweight": true, "weighted": true, "assisted": true, "ez": true,
+	"bar": true, "rope": true, "straight": true,
+}
+
+// wordSynonyms expands abbreviations and joined spellings.
+var wordSynonyms = map[string][]string{
+	"db": {"dumbbell"}, "bb": {"barbell"}, "kb": {"kettlebell"}, "bw": {"bodyweight"},
+	"ohp": {"overhead", "press"}, "rdl": {"romanian", "deadlift"},
+	"pullup": {"pull", "up"}, "chinup": {"chin", "up"}, "pushup": {"push", "up"},
+}
+
+// ExerciseMatcher maps exercise names from other apps onto catalog names.
+// Names are compared as sets of words: the words that name the movement
+// decide most of the score and equipment words break ties, so
+// "Bench Press (Dumbbell)" matches "Dumbbell Bench Press" and
+// "Squat (Barbell)" still matches "Squat". Words one typo apart count as the
+// same word.
+type ExerciseMatcher struct {
+	names []string
+	words []exerciseWords
+}
+
+type exerciseWords struct {
+	movement  []string
+	equipment []string
+}
+
+func NewExerciseMatcher(names []string) *ExerciseMatcher {
+	matcher := &ExerciseMatcher{names: names}
+	for _, name := range names {
+		matcher.words = append(matcher.words, splitExerciseName(name))
+	}
+	return matcher
+}
+
+// Match returns the closest catalog name and its score between 0 and 1.
+// When nothing scores at least matchThreshold, the name is returned as is
+// and matched is false.
+func (m *ExerciseMatcher) Match(name string) (match string, score float64, matched bool) {
+	words := splitExerciseName(name)
+	best := -1
+	for i, candidate := range m.words {
+		s := words.similarity(candidate)
+		if s > score {
+			best, score = i, s
+		}
+	}
+	score = math.Round(score*100) / 100
+	if best < 0 || score < matchThreshold {
+		return name, score, false
+	}
+	return m.names[best], score, true
+}
+
+func splitExerciseName(name string) exerciseWords {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := exerciseWords{}
+	expanded := []string{}
+	for _, word := range fields {
+		if synonym, ok := wordSynonyms[stemWord(word)]; ok {
+			expanded = append(expanded, synonym...)
+		} else {
+			expanded = append(expanded, word)
+		}
+	}
+	for _, word := range expanded {
+		word = stemWord(word)
+		if equipmentWords[word] {
+			words.equipment = append(words.equipment, word)
+		} else {
+			words.movement = append(words.movement, word)
+		}
+	}
+	return words
+}
+
+// stemWord drops a plural "s" so "Lunges" and "Lunge" compare equal.
+func stemWord(word string) string {
+	if len(word) > 2 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func (w exerciseWords) similarity(other exerciseWords) float64 {
+	movement := wordOverlap(w.movement, other.movement)
+
+	equipment := 0.5
+	switch {
+	case len(w.equipment) > 0 && len(other.equipment) > 0:
+		equipment = wordOverlap(w.equipment, other.equipment)
+	case len(w.equipment) == 0 && len(other.equipment) == 0:
+		equipment = 1
+	}
+	return 0.85*movement + 0.15*equipment
+}
+
+// wordOverlap is the Dice coefficient of two word sets.
+func wordOverlap(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	used := make([]bool, len(b))
+	shared := 0
+	for _, word := range a {
+		for j, candidate := range b {
+			if !used[j] && sameWord(word, candidate) {
+				used[j] = true
+				shared++
+				break
+			}
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// sameWord treats words of five or more letters that are one edit apart as
+// equal to absorb typos and spelling variants.
+func sameWord(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 5 || len(b) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= 1
+}
+
+// editDistance counts insertions, deletions, substitutions and swaps of
+// adjacent letters.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
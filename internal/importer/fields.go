@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const kgPerPound = 0.45359237
+
+// parseTime tries each layout in turn, interpreting the value in loc.
+func parseTime(value string, loc *time.Location, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseCount parses a whole number that may be written as a decimal, as in
+// "5.0". Empty and zero values are nil.
+func parseCount(value, name string) (*int, error) {
+	f, err := parseAmount(value, name)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	if *f != math.Trunc(*f) {
+		return nil, fmt.Errorf("%s must be a whole number", name)
+	}
+	n := int(*f)
+	return &n, nil
+}
+
+// parseAmount parses a decimal that may use a comma as the decimal
+// separator. Empty and zero values are nil, since exports write 0 for
+// fields that do not apply.
+func parseAmount(value, name string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("invalid %s %q", name, value)
+	}
+	if f == 0 {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// parseWeight parses a weight and converts pounds to kg, rounded to the
+// database's two decimal places.
+func parseWeight(value string, pounds bool) (*float64, error) {
+	weight, err := parseAmount(value, "weight")
+	if err != nil || weight == nil {
+		return nil, err
+	}
+	if pounds {
+		kg := math.Round(*weight*kgPerPound*100) / 100
+		weight = &kg
+	}
+	return weight, nil
+}
+
+// parseDuration reads durations written as "1h 5m", "45m 30s" or a plain
+// number of seconds.
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+func isPounds(unit string) bool {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "lb", "lbs", "pound", "pounds":
+		return true
+	}
+	return false
+}
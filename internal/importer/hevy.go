@@ -0,0 +1,60 @@
+package importer
+
+import (
+	"time"
+)
+
+// HevyMapper reads exports from Hevy, which have one row per set with
+// snake_case columns and a weight column named for the user's unit.
+type HevyMapper struct{}
+
+func (HevyMapper) Name() string {
+	return "hevy"
+}
+
+func (HevyMapper) Detect(header []string) bool {
+	return hasColumns(header, "title", "start_time", "exercise_title", "set_index")
+}
+
+var hevyTimeLayouts = []string{"2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+func (HevyMapper) MapRow(record Record, loc *time.Location) (Row, bool, error) {
+	row := Row{
+		WorkoutTitle:  record.Get("title"),
+		WorkoutNotes:  record.Get("description"),
+		ExerciseName:  record.Get("exercise_title"),
+		ExerciseNotes: record.Get("exercise_notes"),
+	}
+
+	var err error
+	row.StartedAt, err = parseTime(record.Get("start_time"), loc, hevyTimeLayouts...)
+	if err != nil {
+		return Row{}, false, err
+	}
+	if value := record.Get("end_time"); value != "" {
+		end, err := parseTime(value, loc, hevyTimeLayouts...)
+		if err != nil {
+			return Row{}, false, err
+		}
+		row.EndedAt = &end
+	}
+
+	if record.Has("weight_lbs") {
+		row.Weight, err = parseWeight(record.Get("weight_lbs"), true)
+	} else {
+		row.Weight, err = parseWeight(record.Get("weight_kg"), false)
+	}
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	row.Reps, err = parseCount(record.Get("reps"), "reps")
+	if err != nil {
+		return Row{}, false, err
+	}
+	row.DurationSeconds, err = parseCount(record.Get("duration_seconds"), "duration_seconds")
+	if err != nil {
+		return Row{}, false, err
+	}
+	return row, true, nil
+}
@@ -0,0 +1,402 @@
+// Package importer turns training logs exported from other apps into
+// workouts. Each supported CSV layout is described by a Mapper; Parse picks
+// the mapper whose columns match the file's header, groups its rows into
+// workouts and maps exercise names onto the exercise catalog.
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unrecognised CSV format")
+	ErrEmptyFile     = errors.New("file has no rows")
+)
+
+// maxWeight is the largest weight the database can store.
+const maxWeight = 999.99
+
+// Row is one set as read by a Mapper. Weight is always in kg.
+type Row struct {
+	WorkoutTitle    string
+	WorkoutNotes    string
+	StartedAt       time.Time
+	EndedAt         *time.Time
+	Duration        time.Duration // used when EndedAt is unknown
+	ExerciseName    string
+	ExerciseNotes   string
+	Reps            *int
+	Weight          *float64
+	DurationSeconds *int
+}
+
+// Mapper reads one app's CSV layout.
+type Mapper interface {
+	// Name identifies the format in results and in the format override.
+	Name() string
+	// Detect reports whether the header, as normalised by Record, is this
+	// mapper's layout.
+	Detect(header []string) bool
+	// MapRow converts a record to a Row. Rows that carry no set, such as
+	// rest timers, return ok false and are skipped without an error.
+	MapRow(record Record, loc *time.Location) (row Row, ok bool, err error)
+}
+
+// DefaultMappers are the formats Parse detects when Options.Mappers is nil.
+var DefaultMappers = []Mapper{StrongMapper{}, HevyMapper{}}
+
+// Record gives a Mapper access to one CSV row by column name.
+type Record struct {
+	columns map[string]int
+	fields  []string
+}
+
+// Get returns the trimmed value of the named column, or "" when the file
+// has no such column.
+func (r Record) Get(column string) string {
+	i, ok := r.columns[normalizeColumn(column)]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+// Has reports whether the file has the named column.
+func (r Record) Has(column string) bool {
+	_, ok := r.columns[normalizeColumn(column)]
+	return ok
+}
+
+func normalizeColumn(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
+
+// hasColumns reports whether header contains every one of columns.
+func hasColumns(header []string, columns ...string) bool {
+	present := map[string]bool{}
+	for _, column := range header {
+		present[column] = true
+	}
+	for _, column := range columns {
+		if !present[normalizeColumn(column)] {
+			return false
+		}
+	}
+	return true
+}
+
+type Options struct {
+	// Mappers are tried in order; nil means DefaultMappers.
+	Mappers []Mapper
+	// Format forces the mapper with this name instead of detecting one.
+	Format string
+	// Location is the time zone of the file's timestamps, which carry none.
+	Location *time.Location
+	// Exercises are the catalog names that exercise names are mapped onto.
+	Exercises []string
+}
+
+// RowError reports a row that was skipped. Row is the line in the file,
+// counting the header as line 1.
+type RowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ExerciseMapping records how an exercise name in the file was resolved.
+// Matched is false when no catalog exercise was close enough and the name
+// was kept as is.
+type ExerciseMapping struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Matched bool    `json:"matched"`
+	Score   float64 `json:"score"`
+	Sets    int     `json:"sets"`
+}
+
+type Result struct {
+	Format    string            `json:"format"`
+	Rows      int               `json:"rows"`
+	Workouts  []*store.Workout  `json:"workouts"`
+	Exercises []ExerciseMapping `json:"exercises"`
+	Errors    []RowError        `json:"errors"`
+}
+
+// Parse reads a CSV export and groups its rows into completed workouts.
+// Rows that cannot be read are reported in Result.Errors and left out; the
+// rest of the file is still imported. Rows belong to the same workout when
+// they share a start time and title, and consecutive rows of the same
+// exercise form one entry with a logged set per row.
+func Parse(r io.Reader, opts Options) (*Result, error) {
+	mappers := opts.Mappers
+	if mappers == nil {
+		mappers = DefaultMappers
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	reader, err := newCSVReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		header[i] = normalizeColumn(column)
+		columns[header[i]] = i
+	}
+
+	mapper, err := selectMapper(mappers, opts.Format, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Format: mapper.Name(), Workouts: []*store.Workout{}, Exercises: []ExerciseMapping{}, Errors: []RowError{}}
+	matcher := NewExerciseMatcher(opts.Exercises)
+	mappings := map[string]*ExerciseMapping{}
+	workouts := map[string]*store.Workout{}
+
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.Errors = append(result.Errors, RowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, err
+		}
+		result.Rows++
+		line, _ := reader.FieldPos(0)
+
+		row, ok, err := mapper.MapRow(Record{columns: columns, fields: fields}, loc)
+		if err == nil && ok {
+			err = validateRow(row)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{Row: line, Message: err.Error()})
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		mapping, seen := mappings[row.ExerciseName]
+		if !seen {
+			name, score, matched := matcher.Match(row.ExerciseName)
+			mapping = &ExerciseMapping{From: row.ExerciseName, To: name, Matched: matched, Score: score}
+			mappings[row.ExerciseName] = mapping
+		}
+		mapping.Sets++
+
+		key := row.StartedAt.UTC().Format(time.RFC3339) + "\x00" + row.WorkoutTitle
+		workout, seen := workouts[key]
+		if !seen {
+			workout = newWorkout(row)
+			workouts[key] = workout
+			result.Workouts = append(result.Workouts, workout)
+		}
+		addSet(workout, row, mapping.To)
+	}
+
+	for _, workout := range result.Workouts {
+		summarizeEntries(workout)
+	}
+	sort.SliceStable(result.Workouts, func(i, j int) bool {
+		return result.Workouts[i].StartedAt.Before(*result.Workouts[j].StartedAt)
+	})
+	for _, mapping := range mappings {
+		result.Exercises = append(result.Exercises, *mapping)
+	}
+	sort.Slice(result.Exercises, func(i, j int) bool {
+		return result.Exercises[i].From < result.Exercises[j].From
+	})
+	return result, nil
+}
+
+// newCSVReader strips a byte order mark and detects whether the file is
+// comma or semicolon separated from its header line.
+func newCSVReader(r io.Reader) (*csv.Reader, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		buffered.Discard(3)
+	}
+	first, err := buffered.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	reader := csv.NewReader(io.MultiReader(strings.NewReader(first), buffered))
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader, nil
+}
+
+func selectMapper(mappers []Mapper, format string, header []string) (Mapper, error) {
+	for _, mapper := range mappers {
+		if format != "" {
+			if strings.EqualFold(mapper.Name(), format) {
+				return mapper, nil
+			}
+			continue
+		}
+		if mapper.Detect(header) {
+			return mapper, nil
+		}
+	}
+	if format != "" {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrUnknownFormat, format)
+	}
+	return nil, ErrUnknownFormat
+}
+
+func validateRow(row Row) error {
+	if row.ExerciseName == "" {
+		return errors.New("exercise name is missing")
+	}
+	if row.StartedAt.IsZero() {
+		return errors.New("workout date is missing")
+	}
+	if row.Reps == nil && row.Weight == nil && row.DurationSeconds == nil {
+		return errors.New("set has no reps, weight or duration")
+	}
+	if row.Reps != nil && *row.Reps < 0 {
+		return errors.New("reps must not be negative")
+	}
+	if row.Weight != nil && (*row.Weight < 0 || *row.Weight > maxWeight) {
+		return fmt.Errorf("weight must be between 0 and %g kg", maxWeight)
+	}
+	if row.DurationSeconds != nil && *row.DurationSeconds < 0 {
+		return errors.New("duration must not be negative")
+	}
+	return nil
+}
+
+func newWorkout(row Row) *store.Workout {
+	startedAt := row.StartedAt
+	endedAt := row.EndedAt
+	if endedAt == nil && row.Duration > 0 {
+		end := startedAt.Add(row.Duration)
+		endedAt = &end
+	}
+	duration := 0
+	if endedAt != nil && endedAt.After(startedAt) {
+		duration = int(endedAt.Sub(startedAt).Round(time.Minute) / time.Minute)
+	}
+
+	title := row.WorkoutTitle
+	if title == "" {
+		title = "Imported workout"
+	}
+
+	return &store.Workout{
+		Title:           title,
+		Description:     row.WorkoutNotes,
+		DurationMinutes: duration,
+		Status:          store.WorkoutCompleted,
+		Visibility:      store.VisibilityPrivate,
+		StartedAt:       &startedAt,
+		EndedAt:         endedAt,
+		CreatedAt:       startedAt,
+		Entries:         []store.WorkoutEntry{},
+		Groups:          []store.EntryGroup{},
+	}
+}
+
+// addSet appends the row as a logged set, starting a new entry when the
+// exercise differs from the previous row's.
+func addSet(workout *store.Workout, row Row, exerciseName string) {
+	last := len(workout.Entries) - 1
+	if last < 0 || workout.Entries[last].ExerciseName != exerciseName {
+		workout.Entries = append(workout.Entries, store.WorkoutEntry{
+			ExerciseName: exerciseName,
+			Notes:        row.ExerciseNotes,
+			OrderIndex:   len(workout.Entries),
+			SetLog:       []store.WorkoutSet{},
+		})
+		last++
+	}
+
+	entry := &workout.Entries[last]
+	if entry.Notes == "" {
+		entry.Notes = row.ExerciseNotes
+	}
+	entry.SetLog = append(entry.SetLog, store.WorkoutSet{
+		SetNumber:       len(entry.SetLog) + 1,
+		Reps:            row.Reps,
+		Weight:          row.Weight,
+		DurationSeconds: row.DurationSeconds,
+		CompletedAt:     workout.StartedAt,
+	})
+}
+
+// summarizeEntries fills in each entry's summary columns the way logging a
+// set does: sets is the number of sets and reps, weight and duration come
+// from the top set.
+func summarizeEntries(workout *store.Workout) {
+	for i := range workout.Entries {
+		entry := &workout.Entries[i]
+		entry.Sets = len(entry.SetLog)
+		top := entry.SetLog[0]
+		for _, set := range entry.SetLog[1:] {
+			if betterSet(set, top) {
+				top = set
+			}
+		}
+		entry.Reps = top.Reps
+		entry.Weight = top.Weight
+		entry.DurationSeconds = top.DurationSeconds
+	}
+}
+
+func betterSet(a, b store.WorkoutSet) bool {
+	if c := compareNullable(a.Weight, b.Weight); c != 0 {
+		return c > 0
+	}
+	if c := compareNullable(a.Reps, b.Reps); c != 0 {
+		return c > 0
+	}
+	return compareNullable(a.DurationSeconds, b.DurationSeconds) > 0
+}
+
+// compareNullable orders nil below every value.
+func compareNullable[T int | float64](a, b *T) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case *a > *b:
+		return 1
+	case *a < *b:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var catalog = []string{
+	"Bench Press", "Dumbbell Bench Press", "Incline Bench Press", "Squat", "Front Squat", "Deadlift",
+	"Romanian Deadlift", "Pull Ups", "Lat Pulldown", "Overhead Press", "Tricep Pushdown", "Plank", "Running",
+}
+
+func parseFixture(t *testing.T, name string) *Result {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	result, err := Parse(file, Options{Location: time.UTC, Exercises: catalog})
+	require.NoError(t, err)
+	return result
+}
+
+func TestParseStrong(t *testing.T) {
+	result := parseFixture(t, "strong.csv")
+
+	assert.Equal(t, "strong", result.Format)
+	assert.Equal(t, 8, result.Rows)
+	assert.Equal(t, []RowError{
+		{Row: 7, Message: `invalid weight "abc"`},
+		{Row: 9, Message: "set has no reps, weight or duration"},
+	}, result.Errors)
+
+	require.Len(t, result.Workouts, 2)
+	push := result.Workouts[0]
+	assert.Equal(t, "Push Day", push.Title)
+	assert.Equal(t, "Felt strong", push.Description)
+	assert.Equal(t, 65, push.DurationMinutes)
+	assert.Equal(t, time.Date(2023, 3, 6, 18, 5, 9, 0, time.UTC), *push.StartedAt)
+
+	require.Len(t, push.Entries, 3)
+	bench := push.Entries[0]
+	assert.Equal(t, "Bench Press", bench.ExerciseName)
+	assert.Equal(t, 2, bench.Sets)
+	assert.Equal(t, 102.5, *bench.Weight)
+	assert.Equal(t, 3, *bench.Reps)
+	require.Len(t, bench.SetLog, 2, "rest timer rows are skipped")
+	assert.Equal(t, 2, bench.SetLog[1].SetNumber)
+
+	assert.Equal(t, "Tricep Pushdown", push.Entries[1].ExerciseName)
+	assert.Equal(t, "Slow negatives", push.Entries[1].Notes)
+	assert.Equal(t, 60, *push.Entries[2].DurationSeconds)
+	assert.Nil(t, push.Entries[2].Weight)
+
+	legs := result.Workouts[1]
+	assert.Equal(t, 45, legs.DurationMinutes)
+	require.Len(t, legs.Entries, 1)
+	assert.Equal(t, "Squat", legs.Entries[0].ExerciseName)
+	assert.Equal(t, 102.06, *legs.Entries[0].Weight, "pounds are converted to kg")
+}
+
+func TestParseHevy(t *testing.T) {
+	result := parseFixture(t, "hevy.csv")
+
+	assert.Equal(t, "hevy", result.Format)
+	assert.Equal(t, []RowError{{Row: 5, Message: "reps must be a whole number"}}, result.Errors)
+
+	require.Len(t, result.Workouts, 2)
+	run := result.Workouts[0]
+	assert.Equal(t, "Morning Run", run.Title, "workouts are ordered by start time")
+	assert.Equal(t, 31, run.DurationMinutes)
+	assert.Equal(t, 1860, *run.Entries[0].DurationSeconds)
+
+	upper := result.Workouts[1]
+	names := []string{}
+	for _, entry := range upper.Entries {
+		names = append(names, entry.ExerciseName)
+	}
+	assert.Equal(t, []string{"Pull Ups", "Overhead Press", "Lat Pulldown"}, names)
+	assert.Equal(t, 10.0, *upper.Entries[0].Weight)
+	assert.Equal(t, 6, *upper.Entries[0].Reps)
+	assert.Equal(t, 1, upper.Entries[1].Sets)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader(""), Options{})
+	assert.ErrorIs(t, err, ErrEmptyFile)
+
+	_, err = Parse(strings.NewReader("a,b,c\n1,2,3\n"), Options{})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = Parse(strings.NewReader("a,b,c\n1,2,3\n"), Options{Format: "fitbod"})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestParseBOMAndFormatOverride(t *testing.T) {
+	csv := "\xef\xbb\xbfDate,Workout Name,Exercise Name,Set Order,Weight,Reps\n2023-03-06 18:05:09,A,Deadlift,1,180,1\n"
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	result, err := Parse(strings.NewReader(csv), Options{Format: "Strong", Location: loc})
+	require.NoError(t, err)
+	require.Len(t, result.Workouts, 1)
+	assert.Equal(t, "2023-03-06T17:05:09Z", result.Workouts[0].StartedAt.UTC().Format(time.RFC3339), "times are read in the given zone")
+}
+
+func TestExerciseMatcher(t *testing.T) {
+	matcher := NewExerciseMatcher(catalog)
+
+	tests := []struct {
+		name    string
+		want    string
+		matched bool
+	}{
+		{name: "Bench Press (Barbell)", want: "Bench Press", matched: true},
+		{name: "Bench Press (Dumbbell)", want: "Dumbbell Bench Press", matched: true},
+		{name: "DB Bench Press", want: "Dumbbell Bench Press", matched: true},
+		{name: "squat", want: "Squat", matched: true},
+		{name: "Pullups", want: "Pull Ups", matched: true},
+		{name: "Deadlfit", want: "Deadlift", matched: true},
+		{name: "RDL", want: "Romanian Deadlift", matched: true},
+		{name: "Zercher Carry", want: "Zercher Carry", matched: false},
+		{name: "Bulgarian Split Squat", want: "Bulgarian Split Squat", matched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, matched := matcher.Match(tt.name)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"strings"
+	"time"
+)
+
+// StrongMapper reads exports from Strong. Older versions separate fields
+// with semicolons and add "Weight Unit" and "Workout Duration" columns;
+// newer ones use commas and a "Duration" column. Weights are in kg unless
+// the unit column says otherwise.
+type StrongMapper struct{}
+
+func (StrongMapper) Name() string {
+	return "strong"
+}
+
+func (StrongMapper) Detect(header []string) bool {
+	return hasColumns(header, "Date", "Workout Name", "Exercise Name", "Set Order")
+}
+
+func (StrongMapper) MapRow(record Record, loc *time.Location) (Row, bool, error) {
+	// Strong writes a row for every rest timer between sets
+	if strings.EqualFold(record.Get("Set Order"), "Rest Timer") {
+		return Row{}, false, nil
+	}
+
+	row := Row{
+		WorkoutTitle:  record.Get("Workout Name"),
+		WorkoutNotes:  record.Get("Workout Notes"),
+		ExerciseName:  record.Get("Exercise Name"),
+		ExerciseNotes: record.Get("Notes"),
+	}
+
+	var err error
+	row.StartedAt, err = parseTime(record.Get("Date"), loc, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05")
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	duration := record.Get("Duration")
+	if duration == "" {
+		duration = record.Get("Workout Duration")
+	}
+	row.Duration, err = parseDuration(duration)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	weightColumn, pounds := "Weight", isPounds(record.Get("Weight Unit"))
+	switch {
+	case record.Has("Weight (lbs)"):
+		weightColumn, pounds = "Weight (lbs)", true
+	case record.Has("Weight (kg)"):
+		weightColumn = "Weight (kg)"
+	}
+	row.Weight, err = parseWeight(record.Get(weightColumn), pounds)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	row.Reps, err = parseCount(record.Get("Reps"), "reps")
+	if err != nil {
+		return Row{}, false, err
+	}
+	row.DurationSeconds, err = parseCount(record.Get("Seconds"), "seconds")
+	if err != nil {
+		return Row{}, false, err
+	}
+	return row, true, nil
+}
@@ -0,0 +1,223 @@
+// Package plates works out how to load a barbell from a plate inventory and
+// the warm-up sets that ramp up to a working weight. Weights are in the
+// inventory's unit, kg or lb.
+package plates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	// ErrInvalidInventory is wrapped by inventory validation errors.
+	ErrInvalidInventory = errors.New("invalid plate inventory")
+	// ErrInvalidWeight is wrapped when a target or bar weight is out of range.
+	ErrInvalidWeight = errors.New("invalid weight")
+)
+
+const (
+	// resolution is the finest plate weight told apart; every plate weight is
+	// a multiple of it, which covers 0.625 lb micro plates.
+	resolution = 0.005
+	// maxPlates bounds the plates in an inventory, and so the search.
+	maxPlates      = 200
+	maxPlateWeight = 100
+	maxBarWeight   = 100
+	maxTarget      = 1000
+)
+
+// Plate is a plate weight with how many of it there are. In an inventory
+// Count is every plate owned; plates go on the bar in pairs, so an odd one
+// out is never used. In a loadout Count is per side.
+type Plate struct {
+	Weight float64 `json:"weight"`
+	Count  int     `json:"count"`
+}
+
+// Loadout is how to load the bar for a target weight.
+type Loadout struct {
+	Target  float64 `json:"target"`
+	Bar     float64 `json:"bar"`
+	Total   float64 `json:"total"`    // the heaviest weight the inventory loads without going over Target
+	PerSide []Plate `json:"per_side"` // heaviest first
+	Missing float64 `json:"missing"`  // Target minus Total, 0 when the target is loaded exactly
+}
+
+// Step is one warm-up set of a ramp, at Percent of the working weight; 0
+// is the empty bar.
+type Step struct {
+	Percent float64
+	Reps    int
+}
+
+// Ramp is the default warm-up: the empty bar, then 40, 60 and 80 percent
+// of the working weight with fewer reps as the weight climbs.
+var Ramp = []Step{{0, 10}, {40, 5}, {60, 3}, {80, 2}}
+
+// WarmupSet is a warm-up set with its loadout.
+type WarmupSet struct {
+	Percent float64 `json:"percent"` // of the working weight, as loaded
+	Reps    int     `json:"reps"`
+	Loadout
+}
+
+// Validate checks every plate weight is positive, a multiple of 0.005 and
+// listed once, and that there are at most 200 plates in all.
+func Validate(inventory []Plate) error {
+	seen := map[int]bool{}
+	total := 0
+	for _, plate := range inventory {
+		if plate.Weight <= 0 || plate.Weight > maxPlateWeight {
+			return fmt.Errorf("%w: plate weights must be between 0 and %d", ErrInvalidInventory, maxPlateWeight)
+		}
+		if math.Abs(plate.Weight/resolution-math.Round(plate.Weight/resolution)) > 1e-6 {
+			return fmt.Errorf("%w: plate weight %g is not a multiple of %g", ErrInvalidInventory, plate.Weight, resolution)
+		}
+		if plate.Count < 0 {
+			return fmt.Errorf("%w: plate counts must not be negative", ErrInvalidInventory)
+		}
+		if seen[grid(plate.Weight)] {
+			return fmt.Errorf("%w: plate weight %g is listed twice", ErrInvalidInventory, plate.Weight)
+		}
+		seen[grid(plate.Weight)] = true
+		total += plate.Count
+	}
+	if total > maxPlates {
+		return fmt.Errorf("%w: at most %d plates", ErrInvalidInventory, maxPlates)
+	}
+	return nil
+}
+
+// Load returns the loadout closest to target without going over it, using
+// the fewest plates when several reach the same weight.
+func Load(target, bar float64, inventory []Plate) (Loadout, error) {
+	if err := check(target, bar, inventory); err != nil {
+		return Loadout{}, err
+	}
+	return load(target, bar, inventory), nil
+}
+
+// Warmup returns the ramp of warm-up sets before a working set at target,
+// lightest first. Steps that load no lighter than the step before, or as
+// heavy as the working set, are left out.
+func Warmup(target, bar float64, inventory []Plate, ramp []Step) ([]WarmupSet, error) {
+	if err := check(target, bar, inventory); err != nil {
+		return nil, err
+	}
+
+	working := load(target, bar, inventory).Total
+	sets := []WarmupSet{}
+	for _, step := range ramp {
+		weight := math.Max(bar, working*step.Percent/100)
+		loadout := load(weight, bar, inventory)
+		if loadout.Total <= 0 || loadout.Total >= working-1e-9 {
+			continue
+		}
+		if len(sets) > 0 && loadout.Total <= sets[len(sets)-1].Total+1e-9 {
+			continue
+		}
+		sets = append(sets, WarmupSet{
+			Percent: math.Round(loadout.Total / working * 100),
+			Reps:    step.Reps,
+			Loadout: loadout,
+		})
+	}
+	return sets, nil
+}
+
+func check(target, bar float64, inventory []Plate) error {
+	if err := Validate(inventory); err != nil {
+		return err
+	}
+	if bar < 0 || bar > maxBarWeight {
+		return fmt.Errorf("%w: bar must be between 0 and %d", ErrInvalidWeight, maxBarWeight)
+	}
+	if target < bar || target > maxTarget {
+		return fmt.Errorf("%w: target must be between the bar weight and %d", ErrInvalidWeight, maxTarget)
+	}
+	return nil
+}
+
+// load searches every way of loading the pairs in the inventory. Weights
+// are counted in steps of the largest unit every plate is a multiple of, and
+// for each plate weight in turn the fewest plates reaching each per-side
+// weight is kept along with how many of that plate it took.
+func load(target, bar float64, inventory []Plate) Loadout {
+	pairs := []Plate{}
+	for _, plate := range inventory {
+		if plate.Count >= 2 {
+			pairs = append(pairs, Plate{Weight: plate.Weight, Count: plate.Count / 2})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Weight > pairs[j].Weight })
+
+	unit := 0
+	for _, plate := range pairs {
+		unit = gcd(unit, grid(plate.Weight))
+	}
+	if unit == 0 {
+		unit = 1
+	}
+
+	capacity := int(math.Floor((target-bar)/2/resolution/float64(unit) + 1e-6))
+	fewest := make([]int, capacity+1)
+	for s := range fewest {
+		fewest[s] = -1
+	}
+	fewest[0] = 0
+	used := make([][]uint8, len(pairs))
+	for t, plate := range pairs {
+		weight := grid(plate.Weight) / unit
+		next := make([]int, capacity+1)
+		used[t] = make([]uint8, capacity+1)
+		for s := range next {
+			next[s] = -1
+			for k := 0; k <= plate.Count && k*weight <= s; k++ {
+				if before := fewest[s-k*weight]; before >= 0 && (next[s] < 0 || before+k < next[s]) {
+					next[s] = before + k
+					used[t][s] = uint8(k)
+				}
+			}
+		}
+		fewest = next
+	}
+
+	reached := capacity
+	for fewest[reached] < 0 {
+		reached--
+	}
+
+	loadout := Loadout{Target: target, Bar: bar, PerSide: []Plate{}}
+	perSide := []Plate{}
+	for s, t := reached, len(pairs)-1; t >= 0; t-- {
+		if k := int(used[t][s]); k > 0 {
+			perSide = append(perSide, Plate{Weight: pairs[t].Weight, Count: k})
+			s -= k * grid(pairs[t].Weight) / unit
+		}
+	}
+	for i := len(perSide) - 1; i >= 0; i-- {
+		loadout.PerSide = append(loadout.PerSide, perSide[i])
+	}
+	loadout.Total = roundWeight(bar + 2*float64(reached*unit)*resolution)
+	loadout.Missing = roundWeight(math.Max(0, target-loadout.Total))
+	return loadout
+}
+
+// grid converts a weight to a whole number of resolution steps.
+func grid(weight float64) int {
+	return int(math.Round(weight / resolution))
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// roundWeight drops the float noise of adding up plates.
+func roundWeight(weight float64) float64 {
+	return math.Round(weight*1000) / 1000
+}
@@ -0,0 +1,210 @@
+package plates
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var standardKg = []Plate{{25, 8}, {20, 2}, {15, 2}, {10, 2}, {5, 2}, {2.5, 2}, {1.25, 2}}
+
+// scenario is a random inventory, bar and target for property tests.
+type scenario struct {
+	Inventory []Plate
+	Bar       float64
+	Target    float64
+}
+
+func (scenario) Generate(r *rand.Rand, size int) reflect.Value {
+	weights := []float64{45, 25, 20, 15, 10, 5, 2.5, 1.25, 0.625, 0.5, 0.25}
+	s := scenario{Inventory: []Plate{}}
+	for _, weight := range weights {
+		if r.Intn(2) == 0 {
+			s.Inventory = append(s.Inventory, Plate{Weight: weight, Count: r.Intn(9)})
+		}
+	}
+	s.Bar = []float64{0, 10, 15, 20, 45}[r.Intn(5)]
+	s.Target = s.Bar + r.Float64()*300
+	return reflect.ValueOf(s)
+}
+
+func perSideWeight(plates []Plate) float64 {
+	weight := 0.0
+	for _, plate := range plates {
+		weight += plate.Weight * float64(plate.Count)
+	}
+	return weight
+}
+
+func pairsOf(inventory []Plate, weight float64) int {
+	for _, plate := range inventory {
+		if plate.Weight == weight {
+			return plate.Count / 2
+		}
+	}
+	return 0
+}
+
+func TestLoad(t *testing.T) {
+	loadout, err := Load(102.5, 20, standardKg)
+	require.NoError(t, err)
+	assert.Equal(t, []Plate{{25, 1}, {15, 1}, {1.25, 1}}, loadout.PerSide)
+	assert.Equal(t, 102.5, loadout.Total)
+	assert.Equal(t, 0.0, loadout.Missing)
+
+	loadout, err = Load(103, 20, standardKg)
+	require.NoError(t, err)
+	assert.Equal(t, 102.5, loadout.Total, "never loads over the target")
+	assert.Equal(t, 0.5, loadout.Missing)
+
+	loadout, err = Load(20, 20, standardKg)
+	require.NoError(t, err)
+	assert.Empty(t, loadout.PerSide)
+
+	// greedy would take a 25 and be stuck at 70; two 15s per side reach 80
+	loadout, err = Load(80, 20, []Plate{{25, 2}, {15, 4}})
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, loadout.Total)
+
+	_, err = Load(15, 20, standardKg)
+	assert.ErrorIs(t, err, ErrInvalidWeight)
+	_, err = Load(100, 20, []Plate{{1.001, 2}})
+	assert.ErrorIs(t, err, ErrInvalidInventory)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(standardKg))
+	assert.NoError(t, Validate(nil))
+	assert.ErrorIs(t, Validate([]Plate{{0, 2}}), ErrInvalidInventory)
+	assert.ErrorIs(t, Validate([]Plate{{5, -2}}), ErrInvalidInventory)
+	assert.ErrorIs(t, Validate([]Plate{{5, 2}, {5, 4}}), ErrInvalidInventory)
+	assert.ErrorIs(t, Validate([]Plate{{5, 150}, {10, 60}}), ErrInvalidInventory)
+}
+
+func TestWarmup(t *testing.T) {
+	sets, err := Warmup(100, 20, standardKg, Ramp)
+	require.NoError(t, err)
+	totals := []float64{}
+	for _, set := range sets {
+		totals = append(totals, set.Total)
+	}
+	assert.Equal(t, []float64{20, 40, 60, 80}, totals)
+	assert.Equal(t, 10, sets[0].Reps)
+
+	sets, err = Warmup(20, 20, standardKg, Ramp)
+	require.NoError(t, err)
+	assert.Empty(t, sets, "no warm-up for the empty bar")
+}
+
+// Property: a loadout uses only pairs the inventory has, adds up to its
+// total, and never goes over the target.
+func TestLoadIsConsistent(t *testing.T) {
+	property := func(s scenario) bool {
+		loadout, err := Load(s.Target, s.Bar, s.Inventory)
+		if err != nil {
+			return false
+		}
+		for i, plate := range loadout.PerSide {
+			if plate.Count < 1 || plate.Count > pairsOf(s.Inventory, plate.Weight) {
+				return false
+			}
+			if i > 0 && plate.Weight >= loadout.PerSide[i-1].Weight {
+				return false
+			}
+		}
+		return math.Abs(loadout.Total-(s.Bar+2*perSideWeight(loadout.PerSide))) < 1e-6 &&
+			loadout.Total <= s.Target+1e-6 &&
+			math.Abs(loadout.Missing-(s.Target-loadout.Total)) < 1e-3
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+// Property: a target made of pairs from the inventory is loaded exactly.
+func TestLoadReachesLoadableTargets(t *testing.T) {
+	property := func(s scenario, seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		target := s.Bar
+		for _, plate := range s.Inventory {
+			target += 2 * plate.Weight * float64(r.Intn(plate.Count/2+1))
+		}
+		if target > maxTarget {
+			return true
+		}
+		loadout, err := Load(target, s.Bar, s.Inventory)
+		return err == nil && loadout.Missing == 0
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+// Property: no way of loading the inventory gets closer to the target, or
+// reaches the same weight with fewer plates. Checked by brute force over
+// small inventories.
+func TestLoadIsOptimal(t *testing.T) {
+	property := func(s scenario) bool {
+		inventory := s.Inventory
+		if len(inventory) > 4 {
+			inventory = inventory[:4]
+		}
+		s.Target = s.Bar + math.Mod(s.Target-s.Bar, 150)
+		loadout, err := Load(s.Target, s.Bar, inventory)
+		if err != nil {
+			return false
+		}
+		plates := 0
+		for _, plate := range loadout.PerSide {
+			plates += plate.Count
+		}
+
+		var search func(i int, weight float64, count int) bool
+		search = func(i int, weight float64, count int) bool {
+			if i == len(inventory) {
+				total := s.Bar + 2*weight
+				if total > s.Target+1e-6 {
+					return true
+				}
+				if total > loadout.Total+1e-6 {
+					return false
+				}
+				return math.Abs(total-loadout.Total) > 1e-6 || count >= plates
+			}
+			for k := 0; k <= inventory[i].Count/2; k++ {
+				if !search(i+1, weight+float64(k)*inventory[i].Weight, count+k) {
+					return false
+				}
+			}
+			return true
+		}
+		return search(0, 0, 0)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+// Property: warm-ups climb from at least the bar to below the working
+// weight, and each is a valid loadout.
+func TestWarmupRamps(t *testing.T) {
+	property := func(s scenario) bool {
+		sets, err := Warmup(s.Target, s.Bar, s.Inventory, Ramp)
+		if err != nil || len(sets) > len(Ramp) {
+			return false
+		}
+		working, _ := Load(s.Target, s.Bar, s.Inventory)
+		for i, set := range sets {
+			if set.Total < s.Bar || set.Total >= working.Total {
+				return false
+			}
+			if i > 0 && set.Total <= sets[i-1].Total {
+				return false
+			}
+			if math.Abs(set.Total-(s.Bar+2*perSideWeight(set.PerSide))) > 1e-6 {
+				return false
+			}
+		}
+		return true
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
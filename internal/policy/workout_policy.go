@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"errors"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+)
+
+type Action string
+
+const (
+	ReadWorkout  Action = "read"
+	WriteWorkout Action = "write" // update, delete, log sets, create on behalf of
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// WorkoutPolicy decides who may act on a workout. Owners can do anything;
+// coaches get the access granted by an accepted coaching relationship, and
+// other users may read workouts whose visibility allows it.
+type WorkoutPolicy struct {
+	workoutStore  store.WorkoutStore
+	coachingStore store.CoachingStore
+}
+
+func NewWorkoutPolicy(workoutStore store.WorkoutStore, coachingStore store.CoachingStore) *WorkoutPolicy {
+	return &WorkoutPolicy{workoutStore: workoutStore, coachingStore: coachingStore}
+}
+
+// Authorize returns the workout's owner if userID may perform action on it.
+// It returns sql.ErrNoRows when the workout does not exist and ErrForbidden
+// when access is denied.
+func (p *WorkoutPolicy) Authorize(userID, workoutID int64, action Action) (int64, error) {
+	ownerID, err := p.workoutStore.GetWorkoutOwner(workoutID)
+	if err != nil {
+		return 0, err
+	}
+
+	if action == ReadWorkout && userID != int64(ownerID) {
+		// Reads follow the workout's visibility, which also covers coaches.
+		visible, err := p.workoutStore.CanViewWorkout(workoutID, userID)
+		if err != nil {
+			return 0, err
+		}
+		if !visible {
+			return 0, ErrForbidden
+		}
+		return int64(ownerID), nil
+	}
+
+	err = p.AuthorizeUser(userID, int64(ownerID), action)
+	if err != nil {
+		return 0, err
+	}
+	return int64(ownerID), nil
+}
+
+// AuthorizeUser checks whether userID may perform action on ownerID's
+// workouts, returning ErrForbidden when it may not.
+func (p *WorkoutPolicy) AuthorizeUser(userID, ownerID int64, action Action) error {
+	if userID == ownerID {
+		return nil
+	}
+
+	permission, err := p.coachingStore.GetCoachPermission(userID, ownerID)
+	if err != nil {
+		return err
+	}
+	if !permits(permission, action) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// permits reports whether a coaching permission covers action.
+func permits(permission string, action Action) bool {
+	switch permission {
+	case store.PermissionReadWrite:
+		return action == ReadWorkout || action == WriteWorkout
+	case store.PermissionRead:
+		return action == ReadWorkout
+	default:
+		return false
+	}
+}
@@ -0,0 +1,29 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/mounis-bhat/rest-api-go/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermits(t *testing.T) {
+	tests := []struct {
+		permission string
+		action     Action
+		want       bool
+	}{
+		{store.PermissionRead, ReadWorkout, true},
+		{store.PermissionRead, WriteWorkout, false},
+		{store.PermissionReadWrite, ReadWorkout, true},
+		{store.PermissionReadWrite, WriteWorkout, true},
+		{"", ReadWorkout, false},
+		{"", WriteWorkout, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission+"/"+string(tt.action), func(t *testing.T) {
+			assert.Equal(t, tt.want, permits(tt.permission, tt.action))
+		})
+	}
+}
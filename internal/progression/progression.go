@@ -0,0 +1,289 @@
+// Package progression recommends the weight and reps for an exercise's next
+// session from the lifter's recent sessions of it.
+package progression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+)
+
+// ErrInvalidRules is wrapped by rule validation errors.
+var ErrInvalidRules = errors.New("invalid progression rules")
+
+type Scheme string
+
+const (
+	// Double progression works within a rep range: reps are added at a
+	// weight until every set reaches the top of the range, then the weight
+	// goes up and reps drop back to the bottom.
+	Double Scheme = "double"
+	// Linear progression adds weight after every session in which every set
+	// reached the target reps.
+	Linear Scheme = "linear"
+	// RPE autoregulation sets the weight from the one-rep max the last top
+	// set implies, so the next top set lands at the target RPE.
+	RPE Scheme = "rpe"
+)
+
+type Action string
+
+const (
+	ActionStart    Action = "start"    // no history to progress from
+	ActionIncrease Action = "increase" // add weight
+	ActionRepeat   Action = "repeat"   // same weight, aiming for more reps or a clean session
+	ActionDecrease Action = "decrease" // less weight, for RPE autoregulation after a hard session
+	ActionDeload   Action = "deload"   // back off after repeated failed sessions
+)
+
+// Rules configures the progression of one exercise. Weights are in kg.
+type Rules struct {
+	Scheme        Scheme  `json:"scheme"`
+	RepMin        int     `json:"rep_min"`        // bottom of the double progression range
+	RepMax        int     `json:"rep_max"`        // top of the range, and the target reps for linear and RPE schemes
+	Increment     float64 `json:"increment"`      // weight added after a successful session
+	TargetRPE     float64 `json:"target_rpe"`     // effort of the top set for the RPE scheme, 6 to 10
+	DeloadAfter   int     `json:"deload_after"`   // consecutive failed sessions that trigger a deload
+	DeloadPercent float64 `json:"deload_percent"` // weight removed by a deload
+}
+
+// DefaultRules are used for exercises without rules of their own.
+func DefaultRules() Rules {
+	return Rules{
+		Scheme:        Double,
+		RepMin:        8,
+		RepMax:        12,
+		Increment:     2.5,
+		TargetRPE:     8,
+		DeloadAfter:   3,
+		DeloadPercent: 10,
+	}
+}
+
+// Validate checks every rule is in range.
+func (r Rules) Validate() error {
+	switch r.Scheme {
+	case Double, Linear, RPE:
+	default:
+		return fmt.Errorf("%w: scheme must be double, linear or rpe", ErrInvalidRules)
+	}
+	if r.RepMin < 1 || r.RepMax < r.RepMin || r.RepMax > 30 {
+		return fmt.Errorf("%w: reps must satisfy 1 <= rep_min <= rep_max <= 30", ErrInvalidRules)
+	}
+	if r.Increment <= 0 || r.Increment > 50 {
+		return fmt.Errorf("%w: increment must be between 0 and 50 kg", ErrInvalidRules)
+	}
+	if r.TargetRPE < 6 || r.TargetRPE > 10 {
+		return fmt.Errorf("%w: target_rpe must be between 6 and 10", ErrInvalidRules)
+	}
+	if r.DeloadAfter < 1 || r.DeloadAfter > 10 {
+		return fmt.Errorf("%w: deload_after must be between 1 and 10 sessions", ErrInvalidRules)
+	}
+	if r.DeloadPercent <= 0 || r.DeloadPercent > 50 {
+		return fmt.Errorf("%w: deload_percent must be between 0 and 50", ErrInvalidRules)
+	}
+	return nil
+}
+
+// Set is one set performed. RPE is nil when it was not recorded.
+type Set struct {
+	Weight float64
+	Reps   int
+	RPE    *float64
+}
+
+// Session is every set of the exercise performed in one workout.
+type Session struct {
+	Date time.Time
+	Sets []Set
+}
+
+// Recommendation is the target for the next session.
+type Recommendation struct {
+	Scheme         Scheme  `json:"scheme"`
+	Action         Action  `json:"action"`
+	Weight         float64 `json:"weight"` // kg, 0 without history
+	Reps           int     `json:"reps"`
+	Sets           int     `json:"sets"`
+	FailedSessions int     `json:"failed_sessions"` // consecutive failed sessions up to the last one
+	Reason         string  `json:"reason"`
+}
+
+// defaultSets is recommended when there is no history to copy the set count
+// from.
+const defaultSets = 3
+
+// Recommend returns the next session's target from sessions, oldest first.
+// New weights are rounded to step kg, the smallest change the lifter's
+// plates allow.
+func Recommend(rules Rules, sessions []Session, step float64) Recommendation {
+	rec := Recommendation{Scheme: rules.Scheme, Sets: defaultSets, Reps: startReps(rules)}
+
+	history := []Session{}
+	for _, session := range sessions {
+		if len(workingSets(session)) > 0 {
+			history = append(history, session)
+		}
+	}
+	if len(history) == 0 {
+		rec.Action = ActionStart
+		rec.Reason = "no sessions with weighted sets to progress from"
+		return rec
+	}
+
+	last := workingSets(history[len(history)-1])
+	top := last[0].Weight
+	rec.Sets = len(last)
+	rec.FailedSessions = failedSessions(rules, history)
+
+	if rec.FailedSessions >= rules.DeloadAfter {
+		rec.Action = ActionDeload
+		rec.Weight = round(top*(1-rules.DeloadPercent/100), step)
+		rec.Reps = startReps(rules)
+		rec.Reason = fmt.Sprintf("%d failed sessions in a row at %g kg", rec.FailedSessions, top)
+		return rec
+	}
+
+	switch rules.Scheme {
+	case Double:
+		if minReps(last) >= rules.RepMax {
+			rec.Action = ActionIncrease
+			rec.Weight = round(top+rules.Increment, step)
+			rec.Reps = rules.RepMin
+			rec.Reason = fmt.Sprintf("every set reached %d reps", rules.RepMax)
+		} else {
+			rec.Action = ActionRepeat
+			rec.Weight = top
+			rec.Reps = max(rules.RepMin, min(rules.RepMax, minReps(last)+1))
+			rec.Reason = fmt.Sprintf("add reps until every set reaches %d", rules.RepMax)
+		}
+	case Linear:
+		rec.Reps = rules.RepMax
+		if minReps(last) >= rules.RepMax {
+			rec.Action = ActionIncrease
+			rec.Weight = round(top+rules.Increment, step)
+			rec.Reason = fmt.Sprintf("every set reached %d reps", rules.RepMax)
+		} else {
+			rec.Action = ActionRepeat
+			rec.Weight = top
+			rec.Reason = fmt.Sprintf("repeat until every set reaches %d reps", rules.RepMax)
+		}
+	case RPE:
+		set := topSet(last)
+		rpe := rules.TargetRPE
+		if set.RPE != nil {
+			rpe = *set.RPE
+		}
+		oneRepMax := fitness.EstimateOneRepMax(set.Weight, set.Reps+repsInReserve(rpe))
+		rec.Reps = rules.RepMax
+		rec.Weight = round(fitness.WeightForReps(oneRepMax, rules.RepMax+repsInReserve(rules.TargetRPE)), step)
+		switch {
+		case rec.Weight > top:
+			rec.Action = ActionIncrease
+		case rec.Weight < top:
+			rec.Action = ActionDecrease
+		default:
+			rec.Action = ActionRepeat
+		}
+		rec.Reason = fmt.Sprintf("%d reps at RPE %g from an estimated 1RM of %.1f kg", rules.RepMax, rules.TargetRPE, oneRepMax)
+	}
+	return rec
+}
+
+// startReps is the reps a new weight is first attempted for.
+func startReps(rules Rules) int {
+	if rules.Scheme == Double {
+		return rules.RepMin
+	}
+	return rules.RepMax
+}
+
+// workingSets returns the session's sets at its heaviest weight; lighter
+// sets are warm-ups or back-off sets.
+func workingSets(session Session) []Set {
+	top := 0.0
+	for _, set := range session.Sets {
+		if set.Reps > 0 {
+			top = math.Max(top, set.Weight)
+		}
+	}
+	sets := []Set{}
+	if top <= 0 {
+		return sets
+	}
+	for _, set := range session.Sets {
+		if set.Reps > 0 && set.Weight >= top-1e-9 {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// failed reports whether the session's working sets fell short of what the
+// scheme asked for: reps below the range, or a top set well above the
+// target RPE.
+func failed(rules Rules, sets []Set) bool {
+	switch rules.Scheme {
+	case Double:
+		return minReps(sets) < rules.RepMin
+	case RPE:
+		set := topSet(sets)
+		return set.Reps < rules.RepMax || (set.RPE != nil && *set.RPE > rules.TargetRPE+1)
+	default:
+		return minReps(sets) < rules.RepMax
+	}
+}
+
+// failedSessions counts the failed sessions at the last session's weight
+// ending with the last one. A session at another weight, such as the one
+// before a deload, ends the count so a deload is not recommended twice in a
+// row.
+func failedSessions(rules Rules, history []Session) int {
+	weight := workingSets(history[len(history)-1])[0].Weight
+	count := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		sets := workingSets(history[i])
+		if math.Abs(sets[0].Weight-weight) > 1e-9 || !failed(rules, sets) {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+func minReps(sets []Set) int {
+	reps := sets[0].Reps
+	for _, set := range sets[1:] {
+		reps = min(reps, set.Reps)
+	}
+	return reps
+}
+
+// topSet returns the working set with the most reps.
+func topSet(sets []Set) Set {
+	top := sets[0]
+	for _, set := range sets[1:] {
+		if set.Reps > top.Reps {
+			top = set
+		}
+	}
+	return top
+}
+
+// repsInReserve converts an RPE to the reps left in the tank: RPE 8 leaves
+// two.
+func repsInReserve(rpe float64) int {
+	return int(math.Round(10 - math.Min(10, rpe)))
+}
+
+// round rounds weight to the nearest multiple of step; a step of 0 leaves it
+// unrounded.
+func round(weight, step float64) float64 {
+	if step <= 0 {
+		return weight
+	}
+	return math.Round(weight/step) * step
+}
@@ -0,0 +1,156 @@
+package progression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// session builds a session of sets at weight with the given reps.
+func session(weight float64, reps ...int) Session {
+	s := Session{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	for _, r := range reps {
+		s.Sets = append(s.Sets, Set{Weight: weight, Reps: r})
+	}
+	return s
+}
+
+func rpe(s Session, value float64) Session {
+	for i := range s.Sets {
+		s.Sets[i].RPE = &value
+	}
+	return s
+}
+
+func TestRecommend(t *testing.T) {
+	double := DefaultRules()
+	linear := Rules{Scheme: Linear, RepMin: 5, RepMax: 5, Increment: 2.5, TargetRPE: 8, DeloadAfter: 3, DeloadPercent: 10}
+	autoregulated := Rules{Scheme: RPE, RepMin: 5, RepMax: 5, Increment: 2.5, TargetRPE: 8, DeloadAfter: 2, DeloadPercent: 10}
+
+	tests := []struct {
+		name     string
+		rules    Rules
+		sessions []Session
+		want     Recommendation
+	}{
+		{
+			name:  "no history",
+			rules: double,
+			want:  Recommendation{Scheme: Double, Action: ActionStart, Reps: 8, Sets: 3},
+		},
+		{
+			name:     "double adds reps within the range",
+			rules:    double,
+			sessions: []Session{session(60, 12, 11, 10)},
+			want:     Recommendation{Scheme: Double, Action: ActionRepeat, Weight: 60, Reps: 11, Sets: 3},
+		},
+		{
+			name:     "double adds weight at the top of the range",
+			rules:    double,
+			sessions: []Session{session(60, 12, 11, 10), session(60, 12, 12, 12)},
+			want:     Recommendation{Scheme: Double, Action: ActionIncrease, Weight: 62.5, Reps: 8, Sets: 3},
+		},
+		{
+			name:     "warm-up sets are ignored",
+			rules:    double,
+			sessions: []Session{{Sets: []Set{{Weight: 20, Reps: 10}, {Weight: 40, Reps: 5}, {Weight: 60, Reps: 12}, {Weight: 60, Reps: 12}}}},
+			want:     Recommendation{Scheme: Double, Action: ActionIncrease, Weight: 62.5, Reps: 8, Sets: 2},
+		},
+		{
+			name:     "double deloads after repeated failures",
+			rules:    double,
+			sessions: []Session{session(60, 12, 12, 12), session(62.5, 7, 6, 6), session(62.5, 7, 7, 6), session(62.5, 7, 6, 5)},
+			want:     Recommendation{Scheme: Double, Action: ActionDeload, Weight: 57.5, Reps: 8, Sets: 3, FailedSessions: 3},
+		},
+		{
+			name:     "a failed deload session does not deload again",
+			rules:    double,
+			sessions: []Session{session(62.5, 7, 6, 6), session(62.5, 7, 7, 6), session(62.5, 7, 6, 5), session(57.5, 7, 7, 7)},
+			want:     Recommendation{Scheme: Double, Action: ActionRepeat, Weight: 57.5, Reps: 8, Sets: 3, FailedSessions: 1},
+		},
+		{
+			name:     "linear adds weight after a full session",
+			rules:    linear,
+			sessions: []Session{session(100, 5, 5, 5, 5, 5)},
+			want:     Recommendation{Scheme: Linear, Action: ActionIncrease, Weight: 102.5, Reps: 5, Sets: 5},
+		},
+		{
+			name:     "linear repeats a missed session",
+			rules:    linear,
+			sessions: []Session{session(100, 5, 5, 5, 5, 5), session(102.5, 5, 5, 4, 4, 3)},
+			want:     Recommendation{Scheme: Linear, Action: ActionRepeat, Weight: 102.5, Reps: 5, Sets: 5, FailedSessions: 1},
+		},
+		{
+			name:     "rpe adds weight after an easy session",
+			rules:    autoregulated,
+			sessions: []Session{rpe(session(100, 5, 5, 5), 7)},
+			want:     Recommendation{Scheme: RPE, Action: ActionIncrease, Weight: 102.5, Reps: 5, Sets: 3},
+		},
+		{
+			name:     "rpe holds at the target",
+			rules:    autoregulated,
+			sessions: []Session{rpe(session(100, 5, 5, 5), 8)},
+			want:     Recommendation{Scheme: RPE, Action: ActionRepeat, Weight: 100, Reps: 5, Sets: 3},
+		},
+		{
+			name:     "rpe assumes the target when it was not recorded",
+			rules:    autoregulated,
+			sessions: []Session{session(100, 5, 5, 5)},
+			want:     Recommendation{Scheme: RPE, Action: ActionRepeat, Weight: 100, Reps: 5, Sets: 3},
+		},
+		{
+			name:     "rpe removes weight after a grind",
+			rules:    autoregulated,
+			sessions: []Session{rpe(session(100, 5, 5, 5), 9.5)},
+			want:     Recommendation{Scheme: RPE, Action: ActionDecrease, Weight: 97.5, Reps: 5, Sets: 3, FailedSessions: 1},
+		},
+		{
+			name:     "rpe deloads after repeated grinds",
+			rules:    autoregulated,
+			sessions: []Session{rpe(session(100, 5, 5, 5), 10), rpe(session(100, 5, 4, 4), 10)},
+			want:     Recommendation{Scheme: RPE, Action: ActionDeload, Weight: 90, Reps: 5, Sets: 3, FailedSessions: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Recommend(tt.rules, tt.sessions, 2.5)
+			assert.NotEmpty(t, got.Reason)
+			got.Reason = ""
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecommendRoundsToStep(t *testing.T) {
+	// 5 lb plates in kg
+	step := 2.26796
+	rules := Rules{Scheme: Linear, RepMin: 5, RepMax: 5, Increment: step, TargetRPE: 8, DeloadAfter: 3, DeloadPercent: 10}
+
+	got := Recommend(rules, []Session{session(99.79, 5, 5, 5)}, step)
+	assert.InDelta(t, 102.06, got.Weight, 0.01, "225 lb after 220 lb")
+	assert.InDelta(t, 0, got.Weight/step-float64(int(got.Weight/step+0.5)), 1e-9)
+
+	got = Recommend(rules, []Session{session(99.79, 5, 5, 5)}, 0)
+	assert.InDelta(t, 102.06, got.Weight, 0.01, "a zero step does not round")
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultRules().Validate())
+
+	invalid := []func(*Rules){
+		func(r *Rules) { r.Scheme = "5/3/1" },
+		func(r *Rules) { r.RepMin = 0 },
+		func(r *Rules) { r.RepMax = r.RepMin - 1 },
+		func(r *Rules) { r.Increment = 0 },
+		func(r *Rules) { r.TargetRPE = 11 },
+		func(r *Rules) { r.DeloadAfter = 0 },
+		func(r *Rules) { r.DeloadPercent = 60 },
+	}
+	for i, change := range invalid {
+		rules := DefaultRules()
+		change(&rules)
+		assert.ErrorIs(t, rules.Validate(), ErrInvalidRules, "case %d", i)
+	}
+}
@@ -16,11 +16,106 @@ func InitializeRoutes(app *app.Application) *chi.Mux {
 	r.Group(func(r chi.Router) {
 		r.Use(app.Middleware.Authenticate)
 
+		r.Post("/workouts/start", app.Middleware.RequireUser(app.WorkoutHandler.HandleStartWorkout))
+		r.Get("/workouts/active", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetActiveWorkout))
+		r.Get("/workouts/trash", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetTrash))
+		r.Post("/workouts/{id}/restore", app.Middleware.RequireUser(app.WorkoutHandler.HandleRestoreWorkout))
+		r.Get("/workouts/{id}/revisions", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetRevisions))
+		r.Post("/workouts/{id}/revisions/{rev}/revert", app.Middleware.RequireUser(app.WorkoutHandler.HandleRevertWorkout))
+		r.Get("/workouts/search", app.Middleware.RequireUser(app.WorkoutHandler.HandleSearchWorkouts))
+		r.Get("/workouts/export", app.Middleware.RequireUser(app.ExportHandler.HandleExportWorkouts))
+		r.Post("/workouts/import", app.Middleware.RequireUser(app.ImportHandler.HandleImportWorkouts))
+		r.Post("/workouts/import/track", app.Middleware.RequireUser(app.ImportHandler.HandleImportTrack))
+		r.Get("/workouts/{id}/track", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetTrack))
+		r.Post("/workouts/{id}/samples", app.Middleware.RequireUser(app.WorkoutHandler.HandleAddSamples))
+		r.Get("/workouts/{id}/samples", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetSamples))
+		r.Post("/workouts/{id}/sets", app.Middleware.RequireUser(app.WorkoutHandler.HandleLogSet))
+		r.Post("/workouts/{id}/entries/{entryId}/warmup", app.Middleware.RequireUser(app.PlateHandler.HandleSetWarmupSets))
+		r.Post("/workouts/{id}/finish", app.Middleware.RequireUser(app.WorkoutHandler.HandleFinishWorkout))
 		r.Get("/workouts/{id}", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetWorkoutByID))
 		r.Post("/workouts", app.Middleware.RequireUser(app.WorkoutHandler.HandleCreateWorkout))
 		r.Put("/workouts/{id}", app.Middleware.RequireUser(app.WorkoutHandler.HandleUpdateWorkout))
 		r.Delete("/workouts/{id}", app.Middleware.RequireUser(app.WorkoutHandler.HandleDeleteWorkout))
 		r.Get("/workouts", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetAllWorkouts))
+		r.Get("/tags", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetTags))
+		r.Post("/workouts/{id}/template", app.Middleware.RequireUser(app.TemplateHandler.HandleSaveWorkoutAsTemplate))
+
+		r.Get("/body-measurements/series", app.Middleware.RequireUser(app.BodyHandler.HandleGetMeasurementSeries))
+		r.Get("/body-measurements/{id}", app.Middleware.RequireUser(app.BodyHandler.HandleGetMeasurementByID))
+		r.Put("/body-measurements/{id}", app.Middleware.RequireUser(app.BodyHandler.HandleUpdateMeasurement))
+		r.Delete("/body-measurements/{id}", app.Middleware.RequireUser(app.BodyHandler.HandleDeleteMeasurement))
+		r.Post("/body-measurements", app.Middleware.RequireUser(app.BodyHandler.HandleCreateMeasurement))
+		r.Get("/body-measurements", app.Middleware.RequireUser(app.BodyHandler.HandleGetMeasurements))
+
+		r.Get("/goals/{id}", app.Middleware.RequireUser(app.GoalHandler.HandleGetGoalByID))
+		r.Delete("/goals/{id}", app.Middleware.RequireUser(app.GoalHandler.HandleDeleteGoal))
+		r.Post("/goals", app.Middleware.RequireUser(app.GoalHandler.HandleCreateGoal))
+		r.Get("/goals", app.Middleware.RequireUser(app.GoalHandler.HandleGetGoals))
+
+		r.Get("/templates/{id}", app.Middleware.RequireUser(app.TemplateHandler.HandleGetTemplateByID))
+		r.Post("/templates", app.Middleware.RequireUser(app.TemplateHandler.HandleCreateTemplate))
+		r.Put("/templates/{id}", app.Middleware.RequireUser(app.TemplateHandler.HandleUpdateTemplate))
+		r.Delete("/templates/{id}", app.Middleware.RequireUser(app.TemplateHandler.HandleDeleteTemplate))
+		r.Get("/templates", app.Middleware.RequireUser(app.TemplateHandler.HandleGetTemplates))
+		r.Post("/templates/{id}/start", app.Middleware.RequireUser(app.TemplateHandler.HandleStartTemplate))
+
+		r.Get("/programs/{id}", app.Middleware.RequireUser(app.ProgramHandler.HandleGetProgramByID))
+		r.Post("/programs", app.Middleware.RequireUser(app.ProgramHandler.HandleCreateProgram))
+		r.Delete("/programs/{id}", app.Middleware.RequireUser(app.ProgramHandler.HandleDeleteProgram))
+		r.Get("/programs", app.Middleware.RequireUser(app.ProgramHandler.HandleGetPrograms))
+		r.Post("/programs/{id}/enroll", app.Middleware.RequireUser(app.ProgramHandler.HandleEnroll))
+		r.Get("/enrollments/{id}/adherence", app.Middleware.RequireUser(app.ProgramHandler.HandleGetAdherence))
+		r.Get("/schedule", app.Middleware.RequireUser(app.ProgramHandler.HandleGetSchedule))
+		r.Post("/schedule/{id}/start", app.Middleware.RequireUser(app.ProgramHandler.HandleStartPlannedSession))
+		r.Post("/schedule/{id}/complete", app.Middleware.RequireUser(app.ProgramHandler.HandleCompletePlannedSession))
+
+		r.Post("/coaching/invitations", app.Middleware.RequireUser(app.CoachingHandler.HandleCreateInvitation))
+		r.Get("/coaching/relationships", app.Middleware.RequireUser(app.CoachingHandler.HandleGetRelationships))
+		r.Post("/coaching/relationships/{id}/accept", app.Middleware.RequireUser(app.CoachingHandler.HandleAcceptInvitation))
+		r.Put("/coaching/relationships/{id}", app.Middleware.RequireUser(app.CoachingHandler.HandleUpdatePermission))
+		r.Delete("/coaching/relationships/{id}", app.Middleware.RequireUser(app.CoachingHandler.HandleDeleteRelationship))
+		r.Get("/athletes/{id}/workouts", app.Middleware.RequireUser(app.WorkoutHandler.HandleGetAthleteWorkouts))
+		r.Post("/athletes/{id}/workouts", app.Middleware.RequireUser(app.WorkoutHandler.HandleCreateAthleteWorkout))
+
+		r.Get("/feed", app.Middleware.RequireUser(app.SocialHandler.HandleGetFeed))
+		r.Post("/users/{id}/follow", app.Middleware.RequireUser(app.SocialHandler.HandleFollow))
+		r.Delete("/users/{id}/follow", app.Middleware.RequireUser(app.SocialHandler.HandleUnfollow))
+		r.Get("/users/{id}/followers", app.Middleware.RequireUser(app.SocialHandler.HandleGetFollowers))
+		r.Get("/users/{id}/following", app.Middleware.RequireUser(app.SocialHandler.HandleGetFollowing))
+		r.Post("/users/{id}/block", app.Middleware.RequireUser(app.SocialHandler.HandleBlock))
+		r.Delete("/users/{id}/block", app.Middleware.RequireUser(app.SocialHandler.HandleUnblock))
+		r.Get("/users/me/blocks", app.Middleware.RequireUser(app.SocialHandler.HandleGetBlockedUsers))
+		r.Post("/workouts/{id}/likes", app.Middleware.RequireUser(app.SocialHandler.HandleLikeWorkout))
+		r.Delete("/workouts/{id}/likes", app.Middleware.RequireUser(app.SocialHandler.HandleUnlikeWorkout))
+		r.Get("/workouts/{id}/likes", app.Middleware.RequireUser(app.SocialHandler.HandleGetLikes))
+		r.Get("/workouts/{id}/comments", app.Middleware.RequireUser(app.SocialHandler.HandleGetComments))
+		r.Post("/workouts/{id}/comments", app.Middleware.RequireUser(app.SocialHandler.HandleCreateComment))
+		r.Put("/comments/{id}", app.Middleware.RequireUser(app.SocialHandler.HandleUpdateComment))
+		r.Delete("/comments/{id}", app.Middleware.RequireUser(app.SocialHandler.HandleDeleteComment))
+
+		r.Post("/workouts/{id}/share", app.Middleware.RequireUser(app.ShareHandler.HandleCreateShare))
+		r.Get("/workouts/{id}/shares", app.Middleware.RequireUser(app.ShareHandler.HandleGetShares))
+		r.Delete("/shares/{id}", app.Middleware.RequireUser(app.ShareHandler.HandleRevokeShare))
+
+		r.Get("/events", app.Middleware.RequireUser(app.EventHandler.HandleEvents))
+
+		r.Get("/users/me/records", app.Middleware.RequireUser(app.RecordHandler.HandleGetMyRecords))
+
+		r.Get("/exercises", app.Middleware.RequireUser(app.ExerciseHandler.HandleGetAllExercises))
+		r.Get("/exercises/{id}/recommendation", app.Middleware.RequireUser(app.ProgressionHandler.HandleGetRecommendation))
+		r.Get("/exercises/{id}/progression", app.Middleware.RequireUser(app.ProgressionHandler.HandleGetProgressionRules))
+		r.Put("/exercises/{id}/progression", app.Middleware.RequireUser(app.ProgressionHandler.HandleUpdateProgressionRules))
+		r.Delete("/exercises/{id}/progression", app.Middleware.RequireUser(app.ProgressionHandler.HandleDeleteProgressionRules))
+		r.Get("/exercises/{id}/rest", app.Middleware.RequireUser(app.RestHandler.HandleGetRestTarget))
+		r.Put("/exercises/{id}/rest", app.Middleware.RequireUser(app.RestHandler.HandleUpdateRestTarget))
+		r.Delete("/exercises/{id}/rest", app.Middleware.RequireUser(app.RestHandler.HandleDeleteRestTarget))
+
+		r.Get("/plates", app.Middleware.RequireUser(app.PlateHandler.HandleGetPlateInventory))
+		r.Put("/plates", app.Middleware.RequireUser(app.PlateHandler.HandleUpdatePlateInventory))
+		r.Get("/plates/calculate", app.Middleware.RequireUser(app.PlateHandler.HandleCalculatePlates))
+
+		r.Get("/analytics/summary", app.Middleware.RequireUser(app.AnalyticsHandler.HandleGetSummary))
+		r.Get("/analytics/exercises/{id}/progress", app.Middleware.RequireUser(app.AnalyticsHandler.HandleGetExerciseProgress))
 
 		r.Get("/user", app.Middleware.RequireUser(app.UserHandler.HandleGetUserByUsername))
 		r.Put("/users/{id}", app.Middleware.RequireUser(app.UserHandler.HandleUpdateUser))
@@ -31,6 +126,7 @@ func InitializeRoutes(app *app.Application) *chi.Mux {
 	r.Get("/health", app.HealthCheckHandler)
 	r.Post("/register", app.UserHandler.HandleCreateUser)
 	r.Post("/tokens/auth", app.TokenHandler.HandleCreateToken)
+	r.Get("/shared/{token}", app.ShareHandler.HandleGetSharedWorkout)
 
 	// API Documentation with Scalar
 	r.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,279 @@
+// Package sensor handles time series recorded by heart-rate monitors, power
+// meters and cadence sensors during a workout.
+package sensor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+)
+
+// ErrInvalidSeries is wrapped by errors for series that cannot be stored.
+var ErrInvalidSeries = errors.New("invalid sample series")
+
+const (
+	// MaxSamples bounds a single upload: a day at one sample per second.
+	MaxSamples = 86400
+
+	// maxGap is the longest interval between samples counted as recorded
+	// time; longer gaps are pauses or dropouts.
+	maxGap = 30 * time.Second
+)
+
+// Sample is one reading. Any metric may be missing.
+type Sample struct {
+	Time      time.Time
+	HeartRate *int // bpm
+	Power     *int // watts
+	Cadence   *int // rpm or steps per minute
+}
+
+// Series is the compact wire format for samples: parallel arrays with times
+// given as whole-second offsets from Start. When Offsets is omitted, samples
+// are IntervalSeconds apart (default 1). Metric arrays may be omitted or
+// contain nulls, but must otherwise have one value per sample.
+type Series struct {
+	Start           time.Time `json:"start"`
+	IntervalSeconds int       `json:"interval_seconds,omitempty"`
+	Offsets         []int     `json:"offsets"`
+	HeartRate       []*int    `json:"heart_rate"`
+	Power           []*int    `json:"power"`
+	Cadence         []*int    `json:"cadence"`
+}
+
+// metricRanges are the accepted values of each metric.
+var metricRanges = []struct {
+	name     string
+	min, max int
+}{
+	{"heart_rate", 20, 250},
+	{"power", 0, 3000},
+	{"cadence", 0, 300},
+}
+
+// Samples expands the series into samples ordered by time. It rejects
+// series whose arrays disagree in length, whose offsets are not increasing,
+// or whose values are out of range.
+func (s Series) Samples() ([]Sample, error) {
+	if s.Start.IsZero() {
+		return nil, fmt.Errorf("%w: start is required", ErrInvalidSeries)
+	}
+
+	count := len(s.Offsets)
+	metrics := [][]*int{s.HeartRate, s.Power, s.Cadence}
+	if count == 0 {
+		for _, values := range metrics {
+			count = max(count, len(values))
+		}
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: no samples", ErrInvalidSeries)
+	}
+	if count > MaxSamples {
+		return nil, fmt.Errorf("%w: at most %d samples per upload", ErrInvalidSeries, MaxSamples)
+	}
+	if s.Offsets == nil && s.IntervalSeconds < 0 {
+		return nil, fmt.Errorf("%w: interval_seconds must be positive", ErrInvalidSeries)
+	}
+	for i, values := range metrics {
+		if values != nil && len(values) != count {
+			return nil, fmt.Errorf("%w: %s has %d values, expected %d", ErrInvalidSeries, metricRanges[i].name, len(values), count)
+		}
+		for _, value := range values {
+			if value != nil && (*value < metricRanges[i].min || *value > metricRanges[i].max) {
+				return nil, fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidSeries, metricRanges[i].name, metricRanges[i].min, metricRanges[i].max)
+			}
+		}
+	}
+
+	interval := s.IntervalSeconds
+	if interval == 0 {
+		interval = 1
+	}
+	samples := make([]Sample, count)
+	for i := range samples {
+		offset := i * interval
+		if s.Offsets != nil {
+			offset = s.Offsets[i]
+			if offset < 0 || (i > 0 && offset <= s.Offsets[i-1]) {
+				return nil, fmt.Errorf("%w: offsets must be increasing and not negative", ErrInvalidSeries)
+			}
+		}
+		samples[i] = Sample{Time: s.Start.Add(time.Duration(offset) * time.Second)}
+		if s.HeartRate != nil {
+			samples[i].HeartRate = s.HeartRate[i]
+		}
+		if s.Power != nil {
+			samples[i].Power = s.Power[i]
+		}
+		if s.Cadence != nil {
+			samples[i].Cadence = s.Cadence[i]
+		}
+	}
+	return samples, nil
+}
+
+// Compact returns samples in the wire format, with offsets and with metric
+// arrays left out when no sample has that metric.
+func Compact(samples []Sample) Series {
+	series := Series{Offsets: make([]int, len(samples))}
+	if len(samples) == 0 {
+		return series
+	}
+	series.Start = samples[0].Time
+	heartRate := make([]*int, len(samples))
+	power := make([]*int, len(samples))
+	cadence := make([]*int, len(samples))
+	for i, sample := range samples {
+		series.Offsets[i] = int(sample.Time.Sub(series.Start) / time.Second)
+		heartRate[i], power[i], cadence[i] = sample.HeartRate, sample.Power, sample.Cadence
+		if sample.HeartRate != nil {
+			series.HeartRate = heartRate
+		}
+		if sample.Power != nil {
+			series.Power = power
+		}
+		if sample.Cadence != nil {
+			series.Cadence = cadence
+		}
+	}
+	return series
+}
+
+// Downsample reduces samples to at most maxPoints by splitting the recording
+// into equal time buckets and averaging each metric within a bucket. Each
+// bucket is reported at the time of its first sample.
+func Downsample(samples []Sample, maxPoints int) []Sample {
+	if maxPoints <= 0 || len(samples) <= maxPoints {
+		return samples
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+
+	start := samples[0].Time
+	span := samples[len(samples)-1].Time.Sub(start)
+	bucketSize := span/time.Duration(maxPoints) + 1
+
+	downsampled := make([]Sample, 0, maxPoints)
+	for i := 0; i < len(samples); {
+		bucket := samples[i].Time.Sub(start) / bucketSize
+		j := i
+		for j < len(samples) && samples[j].Time.Sub(start)/bucketSize == bucket {
+			j++
+		}
+		downsampled = append(downsampled, Sample{
+			Time:      samples[i].Time,
+			HeartRate: mean(samples[i:j], func(s Sample) *int { return s.HeartRate }),
+			Power:     mean(samples[i:j], func(s Sample) *int { return s.Power }),
+			Cadence:   mean(samples[i:j], func(s Sample) *int { return s.Cadence }),
+		})
+		i = j
+	}
+	return downsampled
+}
+
+func mean(samples []Sample, metric func(Sample) *int) *int {
+	total, count := 0, 0
+	for _, sample := range samples {
+		if value := metric(sample); value != nil {
+			total += *value
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	average := int(math.Round(float64(total) / float64(count)))
+	return &average
+}
+
+// MetricSummary is the average and maximum of one metric.
+type MetricSummary struct {
+	Average int `json:"average"`
+	Max     int `json:"max"`
+}
+
+// ZoneTime is the time spent in one heart-rate zone.
+type ZoneTime struct {
+	fitness.HeartRateZone
+	Seconds int     `json:"seconds"`
+	Percent float64 `json:"percent"` // of recorded heart-rate time; time below zone 1 is in no zone
+}
+
+// HeartRateSummary describes the heart-rate samples of a workout.
+// RecordedSeconds excludes gaps of more than 30 seconds; Zones is nil when
+// no maximum heart rate is known.
+type HeartRateSummary struct {
+	MetricSummary
+	RecordedSeconds int        `json:"recorded_seconds"`
+	Zones           []ZoneTime `json:"zones"`
+}
+
+// Summary describes each metric present in a workout's samples.
+type Summary struct {
+	HeartRate *HeartRateSummary `json:"heart_rate"`
+	Power     *MetricSummary    `json:"power"`
+	Cadence   *MetricSummary    `json:"cadence"`
+}
+
+// Summarize averages each metric over its samples, which must be ordered by
+// time. Each heart-rate sample counts from its time until the next
+// heart-rate sample, so time in zone stays accurate when samples are
+// irregular.
+func Summarize(samples []Sample, zones []fitness.HeartRateZone) Summary {
+	summary := Summary{
+		Power:   summarizeMetric(samples, func(s Sample) *int { return s.Power }),
+		Cadence: summarizeMetric(samples, func(s Sample) *int { return s.Cadence }),
+	}
+	metric := summarizeMetric(samples, func(s Sample) *int { return s.HeartRate })
+	if metric == nil {
+		return summary
+	}
+
+	heartRate := &HeartRateSummary{MetricSummary: *metric}
+	seconds := make([]time.Duration, len(zones)+1) // index 0 is below zone 1
+	var recorded time.Duration
+	var previous *Sample
+	for i := range samples {
+		if samples[i].HeartRate == nil {
+			continue
+		}
+		if previous != nil {
+			if gap := samples[i].Time.Sub(previous.Time); gap <= maxGap {
+				seconds[fitness.ZoneFor(zones, *previous.HeartRate)] += gap
+				recorded += gap
+			}
+		}
+		previous = &samples[i]
+	}
+	heartRate.RecordedSeconds = int(recorded / time.Second)
+
+	if len(zones) > 0 {
+		heartRate.Zones = make([]ZoneTime, len(zones))
+		for i, zone := range zones {
+			heartRate.Zones[i] = ZoneTime{HeartRateZone: zone, Seconds: int(seconds[zone.Zone] / time.Second)}
+			if recorded > 0 {
+				heartRate.Zones[i].Percent = math.Round(float64(seconds[zone.Zone])/float64(recorded)*1000) / 10
+			}
+		}
+	}
+	summary.HeartRate = heartRate
+	return summary
+}
+
+func summarizeMetric(samples []Sample, metric func(Sample) *int) *MetricSummary {
+	average := mean(samples, metric)
+	if average == nil {
+		return nil
+	}
+	summary := &MetricSummary{Average: *average}
+	for _, sample := range samples {
+		if value := metric(sample); value != nil && *value > summary.Max {
+			summary.Max = *value
+		}
+	}
+	return summary
+}
@@ -0,0 +1,109 @@
+package sensor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+)
+
+func ints(values ...int) []*int {
+	pointers := make([]*int, len(values))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	return pointers
+}
+
+var start = time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+
+func TestSeriesSamples(t *testing.T) {
+	samples, err := Series{Start: start, HeartRate: ints(120, 125, 130), Power: []*int{nil, ints(200)[0], nil}}.Samples()
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	assert.Equal(t, start.Add(2*time.Second), samples[2].Time, "samples default to one per second")
+	assert.Equal(t, 125, *samples[1].HeartRate)
+	assert.Nil(t, samples[0].Power)
+	assert.Nil(t, samples[0].Cadence)
+
+	samples, err = Series{Start: start, Offsets: []int{0, 5, 12}, Cadence: ints(80, 82, 85)}.Samples()
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(12*time.Second), samples[2].Time)
+
+	samples, err = Series{Start: start, IntervalSeconds: 4, HeartRate: ints(120, 121)}.Samples()
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(4*time.Second), samples[1].Time)
+}
+
+func TestSeriesSamplesInvalid(t *testing.T) {
+	cases := map[string]Series{
+		"missing start":      {HeartRate: ints(120)},
+		"empty":              {Start: start},
+		"length mismatch":    {Start: start, HeartRate: ints(120, 121), Power: ints(200)},
+		"offsets mismatch":   {Start: start, Offsets: []int{0, 1}, HeartRate: ints(120)},
+		"decreasing offsets": {Start: start, Offsets: []int{0, 5, 5}, HeartRate: ints(120, 121, 122)},
+		"out of range":       {Start: start, HeartRate: ints(120, 300)},
+	}
+	for name, series := range cases {
+		_, err := series.Samples()
+		assert.ErrorIs(t, err, ErrInvalidSeries, name)
+	}
+}
+
+func TestCompactRoundTrip(t *testing.T) {
+	series := Series{Start: start, Offsets: []int{0, 2, 3}, HeartRate: []*int{ints(120)[0], nil, ints(124)[0]}}
+	samples, err := series.Samples()
+	require.NoError(t, err)
+
+	compact := Compact(samples)
+	assert.Equal(t, series.Start, compact.Start)
+	assert.Equal(t, series.Offsets, compact.Offsets)
+	assert.Equal(t, series.HeartRate, compact.HeartRate)
+	assert.Nil(t, compact.Power, "metrics without values are left out")
+}
+
+func TestDownsample(t *testing.T) {
+	heartRates := make([]int, 600)
+	for i := range heartRates {
+		heartRates[i] = 100 + i/60
+	}
+	samples, err := Series{Start: start, HeartRate: ints(heartRates...)}.Samples()
+	require.NoError(t, err)
+
+	downsampled := Downsample(samples, 10)
+	require.Len(t, downsampled, 10)
+	assert.Equal(t, start, downsampled[0].Time)
+	assert.Equal(t, 100, *downsampled[0].HeartRate, "each bucket averages its samples")
+	assert.Equal(t, 109, *downsampled[9].HeartRate)
+	assert.Nil(t, downsampled[0].Power)
+
+	assert.Len(t, Downsample(samples, 1000), 600, "short series are returned as is")
+}
+
+func TestSummarize(t *testing.T) {
+	zones := fitness.HeartRateZones(200, 0)
+	// 30 s at 110 (zone 1), 30 s at 150 (zone 3), a 2 minute dropout, then
+	// 30 s at 185 (zone 5) and a final reading
+	series := Series{Start: start, Offsets: []int{0, 30, 60, 180, 210}, HeartRate: ints(110, 150, 150, 185, 90), Power: ints(100, 200, 300, 0, 0)}
+	samples, err := series.Samples()
+	require.NoError(t, err)
+
+	summary := Summarize(samples, zones)
+	require.NotNil(t, summary.HeartRate)
+	assert.Equal(t, 185, summary.HeartRate.Max)
+	assert.Equal(t, 90, summary.HeartRate.RecordedSeconds, "the gap is not recorded time")
+	require.Len(t, summary.HeartRate.Zones, 5)
+	assert.Equal(t, 30, summary.HeartRate.Zones[0].Seconds)
+	assert.Equal(t, 30, summary.HeartRate.Zones[2].Seconds)
+	assert.Equal(t, 30, summary.HeartRate.Zones[4].Seconds)
+	assert.Equal(t, 33.3, summary.HeartRate.Zones[0].Percent)
+	assert.Equal(t, 120, summary.Power.Average)
+	assert.Nil(t, summary.Cadence)
+
+	withoutZones := Summarize(samples, nil)
+	assert.Nil(t, withoutZones.HeartRate.Zones)
+	assert.Equal(t, 90, withoutZones.HeartRate.RecordedSeconds)
+}
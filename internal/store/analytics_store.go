@@ -0,0 +1,272 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// AnalyticsQuery selects a user's workouts in [From, To) and buckets them by
+// Period in the given IANA Timezone.
+type AnalyticsQuery struct {
+	UserID   int64
+	Period   string
+	Timezone string
+	From     time.Time
+	To       time.Time
+}
+
+// PeriodStart returns the start of the period bucket containing t, in t's
+// location, matching Postgres' date_trunc: weeks start on Monday.
+func PeriodStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case PeriodWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+type MuscleGroupVolume struct {
+	MuscleGroup string  `json:"muscle_group"`
+	Volume      float64 `json:"volume"` // sets x reps x weight, in kg, with body weight for bodyweight exercises
+}
+
+type AnalyticsBucket struct {
+	PeriodStart          string              `json:"period_start"` // YYYY-MM-DD in the user's timezone
+	Sessions             int                 `json:"sessions"`
+	TotalVolume          float64             `json:"total_volume"` // sets x reps x weight, in kg, with body weight for bodyweight exercises
+	TotalDurationMinutes int                 `json:"total_duration_minutes"`
+	TotalCalories        int                 `json:"total_calories"`
+	TotalDistanceMeters  float64             `json:"total_distance_meters"` // entry distances, or repeats x work distance for intervals
+	TotalDistance        float64             `json:"total_distance"`        // TotalDistanceMeters in km or mi
+	MuscleGroups         []MuscleGroupVolume `json:"muscle_groups"`
+	RestCompliance       RestCompliance      `json:"rest_compliance"`
+}
+
+type AnalyticsSummary struct {
+	Totals  AnalyticsBucket    `json:"totals"`
+	Buckets []*AnalyticsBucket `json:"buckets"`
+}
+
+type ExerciseProgressPoint struct {
+	PeriodStart    string  `json:"period_start"` // YYYY-MM-DD in the user's timezone
+	Sessions       int     `json:"sessions"`
+	MaxWeight      float64 `json:"max_weight"`
+	EstimatedOneRM float64 `json:"estimated_1rm"`
+	TotalVolume    float64 `json:"total_volume"`
+	TotalReps      int     `json:"total_reps"`
+
+	TotalDistanceMeters  float64 `json:"total_distance_meters"`
+	BestPaceSecondsPerKm *int    `json:"best_pace_seconds_per_km"` // fastest entry pace, nil without paced entries
+	TotalDistance        float64 `json:"total_distance"`           // TotalDistanceMeters in km or mi
+	BestPaceSeconds      *int    `json:"best_pace_seconds"`        // BestPaceSecondsPerKm per km or mi
+}
+
+// entryVolume is the SQL volume of entry e in workout w, joined to its
+// exercise as x. Bodyweight exercises add the owner's body weight when the
+// workout started (their latest measurement by then, else their profile
+// weight) to any added weight.
+const entryVolume = `e.sets * COALESCE(e.reps, 0) * (COALESCE(e.weight, 0) + CASE WHEN x.bodyweight THEN COALESCE(
+		(SELECT m.weight_kg FROM body_measurements m
+			WHERE m.user_id = w.user_id AND m.weight_kg IS NOT NULL AND m.measured_at <= COALESCE(w.started_at, w.created_at)
+			ORDER BY m.measured_at DESC LIMIT 1),
+		(SELECT u.weight_kg FROM users u WHERE u.id = w.user_id), 0) ELSE 0 END)`
+
+// entryDistance is the SQL distance covered by entry e: its distance, or the
+// work distance of all repeats for interval entries.
+const entryDistance = `COALESCE(e.distance_meters, e.interval_repeats * e.interval_work_meters, 0)`
+
+type PostgresAnalyticsStore struct {
+	db *sql.DB
+}
+
+func NewPostgresAnalyticsStore(db *sql.DB) *PostgresAnalyticsStore {
+	return &PostgresAnalyticsStore{db: db}
+}
+
+type AnalyticsStore interface {
+	GetSummary(q AnalyticsQuery) (*AnalyticsSummary, error)
+	GetExerciseProgress(q AnalyticsQuery, exerciseName string) ([]*ExerciseProgressPoint, error)
+}
+
+func (s *PostgresAnalyticsStore) GetSummary(q AnalyticsQuery) (*AnalyticsSummary, error) {
+	query := `WITH user_workouts AS (
+			SELECT id, duration_minutes, calories_burned, date_trunc($2, created_at AT TIME ZONE $3) AS bucket
+			FROM workouts
+			WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= $4 AND created_at < $5
+		),
+		workout_volume AS (
+			SELECT e.workout_id, SUM(` + entryVolume + `) AS volume,
+				SUM(` + entryDistance + `) AS distance
+			FROM workout_entries e
+			INNER JOIN user_workouts uw ON uw.id = e.workout_id
+			INNER JOIN workouts w ON w.id = e.workout_id
+			LEFT JOIN exercises x ON LOWER(x.name) = LOWER(e.exercise_name)
+			GROUP BY e.workout_id
+		)
+		SELECT to_char(uw.bucket, 'YYYY-MM-DD'), COUNT(*), COALESCE(SUM(wv.volume), 0),
+			COALESCE(SUM(uw.duration_minutes), 0), COALESCE(SUM(uw.calories_burned), 0), COALESCE(SUM(wv.distance), 0)
+		FROM user_workouts uw
+		LEFT JOIN workout_volume wv ON wv.workout_id = uw.id
+		GROUP BY uw.bucket
+		ORDER BY uw.bucket`
+	rows, err := s.db.Query(query, q.UserID, q.Period, q.Timezone, q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &AnalyticsSummary{
+		Totals:  AnalyticsBucket{MuscleGroups: []MuscleGroupVolume{}},
+		Buckets: []*AnalyticsBucket{},
+	}
+	buckets := map[string]*AnalyticsBucket{}
+	for rows.Next() {
+		bucket := &AnalyticsBucket{MuscleGroups: []MuscleGroupVolume{}}
+		err := rows.Scan(&bucket.PeriodStart, &bucket.Sessions, &bucket.TotalVolume, &bucket.TotalDurationMinutes, &bucket.TotalCalories, &bucket.TotalDistanceMeters)
+		if err != nil {
+			return nil, err
+		}
+		summary.Buckets = append(summary.Buckets, bucket)
+		buckets[bucket.PeriodStart] = bucket
+
+		summary.Totals.Sessions += bucket.Sessions
+		summary.Totals.TotalVolume += bucket.TotalVolume
+		summary.Totals.TotalDurationMinutes += bucket.TotalDurationMinutes
+		summary.Totals.TotalCalories += bucket.TotalCalories
+		summary.Totals.TotalDistanceMeters += bucket.TotalDistanceMeters
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT to_char(date_trunc($2, w.created_at AT TIME ZONE $3), 'YYYY-MM-DD'),
+			COALESCE(x.muscle_group, 'other'),
+			SUM(` + entryVolume + `)
+		FROM workout_entries e
+		INNER JOIN workouts w ON w.id = e.workout_id
+		LEFT JOIN exercises x ON LOWER(x.name) = LOWER(e.exercise_name)
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND w.created_at >= $4 AND w.created_at < $5
+		GROUP BY 1, 2
+		ORDER BY 1, 3 DESC`
+	groupRows, err := s.db.Query(query, q.UserID, q.Period, q.Timezone, q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	defer groupRows.Close()
+
+	totals := map[string]float64{}
+	order := []string{}
+	for groupRows.Next() {
+		var periodStart string
+		group := MuscleGroupVolume{}
+		if err := groupRows.Scan(&periodStart, &group.MuscleGroup, &group.Volume); err != nil {
+			return nil, err
+		}
+		if bucket, ok := buckets[periodStart]; ok {
+			bucket.MuscleGroups = append(bucket.MuscleGroups, group)
+		}
+		if _, ok := totals[group.MuscleGroup]; !ok {
+			order = append(order, group.MuscleGroup)
+		}
+		totals[group.MuscleGroup] += group.Volume
+	}
+	if err := groupRows.Err(); err != nil {
+		return nil, err
+	}
+	for _, muscleGroup := range order {
+		summary.Totals.MuscleGroups = append(summary.Totals.MuscleGroups, MuscleGroupVolume{MuscleGroup: muscleGroup, Volume: totals[muscleGroup]})
+	}
+	groupRows.Close()
+
+	// rests are timed like setRests, between working sets only, so warm-ups
+	// are filtered out before LAG sees them. Each is held to the entry's
+	// target, else the exercise's, else the default.
+	query = `WITH rests AS (
+			SELECT date_trunc($2, w.created_at AT TIME ZONE $3) AS bucket,
+				EXTRACT(EPOCH FROM s.started_at - LAG(s.completed_at) OVER (PARTITION BY s.entry_id ORDER BY s.set_number)) AS rest,
+				COALESCE(e.target_rest_seconds, rt.rest_seconds, $6) AS target
+			FROM workout_sets s
+			INNER JOIN workout_entries e ON e.id = s.entry_id
+			INNER JOIN workouts w ON w.id = e.workout_id
+			LEFT JOIN exercise_rest_targets rt ON rt.user_id = w.user_id AND LOWER(rt.exercise_name) = LOWER(e.exercise_name)
+			WHERE w.user_id = $1 AND w.deleted_at IS NULL AND w.created_at >= $4 AND w.created_at < $5 AND NOT s.warmup
+		)
+		SELECT to_char(bucket, 'YYYY-MM-DD'), ROUND(rest)::int, target
+		FROM rests
+		WHERE rest >= 0`
+	restRows, err := s.db.Query(query, q.UserID, q.Period, q.Timezone, q.From, q.To, DefaultRestSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer restRows.Close()
+
+	for restRows.Next() {
+		var periodStart string
+		var rest, target int
+		if err := restRows.Scan(&periodStart, &rest, &target); err != nil {
+			return nil, err
+		}
+		if bucket, ok := buckets[periodStart]; ok {
+			bucket.RestCompliance.add(rest, target)
+		}
+		summary.Totals.RestCompliance.add(rest, target)
+	}
+	if err := restRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// GetExerciseProgress returns per-period bests for one exercise. The estimated
+// 1RM expression mirrors fitness.EstimateOneRepMax.
+func (s *PostgresAnalyticsStore) GetExerciseProgress(q AnalyticsQuery, exerciseName string) ([]*ExerciseProgressPoint, error) {
+	query := `SELECT to_char(date_trunc($2, w.created_at AT TIME ZONE $3), 'YYYY-MM-DD'),
+			COUNT(DISTINCT w.id),
+			COALESCE(MAX(e.weight), 0),
+			COALESCE(ROUND(MAX(CASE
+				WHEN e.weight IS NULL OR COALESCE(e.reps, 0) <= 0 THEN NULL
+				WHEN e.reps = 1 THEN e.weight
+				WHEN e.reps <= 10 THEN e.weight * 36 / (37 - e.reps)
+				ELSE e.weight * (1 + e.reps / 30.0)
+			END), 2), 0),
+			COALESCE(SUM(` + entryVolume + `), 0),
+			COALESCE(SUM(e.sets * COALESCE(e.reps, 0)), 0),
+			SUM(` + entryDistance + `),
+			MIN(e.pace_seconds_per_km)
+		FROM workout_entries e
+		INNER JOIN workouts w ON w.id = e.workout_id
+		LEFT JOIN exercises x ON LOWER(x.name) = LOWER(e.exercise_name)
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND w.created_at >= $4 AND w.created_at < $5 AND LOWER(e.exercise_name) = LOWER($6)
+		GROUP BY 1
+		ORDER BY 1`
+	rows, err := s.db.Query(query, q.UserID, q.Period, q.Timezone, q.From, q.To, exerciseName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []*ExerciseProgressPoint{}
+	for rows.Next() {
+		point := &ExerciseProgressPoint{}
+		err := rows.Scan(&point.PeriodStart, &point.Sessions, &point.MaxWeight, &point.EstimatedOneRM, &point.TotalVolume, &point.TotalReps,
+			&point.TotalDistanceMeters, &point.BestPaceSecondsPerKm)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodStart(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		period string
+		want   time.Time
+	}{
+		{name: "day", at: time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC), period: PeriodDay, want: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "week from wednesday", at: time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC), period: PeriodWeek, want: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "week from monday", at: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), period: PeriodWeek, want: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "week from sunday", at: time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC), period: PeriodWeek, want: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "week across a year", at: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), period: PeriodWeek, want: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "month", at: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), period: PeriodMonth, want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "keeps the location", at: time.Date(2024, 3, 1, 2, 0, 0, 0, kolkata), period: PeriodMonth, want: time.Date(2024, 3, 1, 0, 0, 0, 0, kolkata)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(PeriodStart(tt.at, tt.period)), "got %s", PeriodStart(tt.at, tt.period))
+		})
+	}
+}
+
+// createWorkoutAt adds a workout with one squat entry created at the given
+// time.
+func createWorkoutAt(t *testing.T, db *sql.DB, userID int64, createdAt time.Time) {
+	t.Helper()
+	workout, err := NewPostgresWorkoutStore(db).CreateWorkout(&Workout{
+		UserID:  userID,
+		Title:   "Legs",
+		Entries: []WorkoutEntry{{ExerciseName: "Squat", Sets: 3, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100)}},
+	}, userID)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE workouts SET created_at = $2 WHERE id = $1`, workout.ID, createdAt)
+	require.NoError(t, err)
+}
+
+func bucketStarts(summary *AnalyticsSummary) map[string]int {
+	sessions := map[string]int{}
+	for _, bucket := range summary.Buckets {
+		sessions[bucket.PeriodStart] = bucket.Sessions
+	}
+	return sessions
+}
+
+func TestGetSummaryBuckets(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	analytics := NewPostgresAnalyticsStore(db)
+	userID := createTestUser(t, db, "bucketer")
+
+	createWorkoutAt(t, db, userID, time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC))   // Monday
+	createWorkoutAt(t, db, userID, time.Date(2024, 1, 14, 18, 0, 0, 0, time.UTC)) // Sunday of the same week
+	createWorkoutAt(t, db, userID, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC))  // next Monday
+	createWorkoutAt(t, db, userID, time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC))
+	createWorkoutAt(t, db, userID, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)) // outside the range
+
+	q := AnalyticsQuery{UserID: userID, Timezone: "UTC", From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		period string
+		want   map[string]int
+	}{
+		{period: PeriodDay, want: map[string]int{"2024-01-08": 1, "2024-01-14": 1, "2024-01-15": 1, "2024-02-01": 1}},
+		{period: PeriodWeek, want: map[string]int{"2024-01-08": 2, "2024-01-15": 1, "2024-01-29": 1}},
+		{period: PeriodMonth, want: map[string]int{"2024-01-01": 3, "2024-02-01": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			q.Period = tt.period
+			summary, err := analytics.GetSummary(q)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, bucketStarts(summary))
+			assert.Equal(t, 4, summary.Totals.Sessions)
+			assert.InDelta(t, 4*1500.0, summary.Totals.TotalVolume, 0.001)
+		})
+	}
+}
+
+func TestGetSummaryTimezone(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	analytics := NewPostgresAnalyticsStore(db)
+	userID := createTestUser(t, db, "traveller")
+
+	// Sunday evening in UTC is already Monday morning in Kolkata, and the
+	// last evening of January is February in Tokyo
+	createWorkoutAt(t, db, userID, time.Date(2024, 1, 7, 20, 0, 0, 0, time.UTC))
+	createWorkoutAt(t, db, userID, time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC))
+
+	q := AnalyticsQuery{UserID: userID, From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		timezone string
+		period   string
+		want     map[string]int
+	}{
+		{timezone: "UTC", period: PeriodWeek, want: map[string]int{"2024-01-01": 1, "2024-01-29": 1}},
+		{timezone: "Asia/Kolkata", period: PeriodWeek, want: map[string]int{"2024-01-08": 1, "2024-01-29": 1}},
+		{timezone: "UTC", period: PeriodMonth, want: map[string]int{"2024-01-01": 2}},
+		{timezone: "Asia/Tokyo", period: PeriodMonth, want: map[string]int{"2024-01-01": 1, "2024-02-01": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone+" "+tt.period, func(t *testing.T) {
+			q.Timezone = tt.timezone
+			q.Period = tt.period
+			summary, err := analytics.GetSummary(q)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, bucketStarts(summary))
+		})
+	}
+}
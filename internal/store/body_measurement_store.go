@@ -0,0 +1,276 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidMeasurement is wrapped by measurement validation errors.
+var ErrInvalidMeasurement = errors.New("invalid body measurement")
+
+// BodyMeasurement is a dated record of a user's body weight, body fat and
+// circumferences. Every value is optional, but at least one must be set.
+type BodyMeasurement struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	MeasuredAt     time.Time `json:"measured_at"`
+	WeightKg       *float64  `json:"weight_kg"`
+	BodyFatPercent *float64  `json:"body_fat_percent"`
+	NeckCm         *float64  `json:"neck_cm"`
+	ChestCm        *float64  `json:"chest_cm"`
+	WaistCm        *float64  `json:"waist_cm"`
+	HipsCm         *float64  `json:"hips_cm"`
+	ArmCm          *float64  `json:"arm_cm"`
+	ThighCm        *float64  `json:"thigh_cm"`
+	CalfCm         *float64  `json:"calf_cm"`
+	Notes          string    `json:"notes"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// MeasurementMetrics maps the metrics a series can be requested for to
+// their columns.
+var MeasurementMetrics = map[string]string{
+	"weight":           "weight_kg",
+	"body_fat_percent": "body_fat_percent",
+	"neck":             "neck_cm",
+	"chest":            "chest_cm",
+	"waist":            "waist_cm",
+	"hips":             "hips_cm",
+	"arm":              "arm_cm",
+	"thigh":            "thigh_cm",
+	"calf":             "calf_cm",
+}
+
+// MeasurementPoint is one measurement of a metric with the mean of the
+// measurements in the window ending at it.
+type MeasurementPoint struct {
+	MeasuredAt    time.Time `json:"measured_at"`
+	Value         float64   `json:"value"`
+	MovingAverage float64   `json:"moving_average"`
+}
+
+// MeasurementSeriesQuery selects a user's measurements of Metric in
+// [From, To), averaged over trailing windows of Window.
+type MeasurementSeriesQuery struct {
+	UserID int64
+	Metric string
+	From   time.Time
+	To     time.Time
+	Window time.Duration
+}
+
+type PostgresBodyMeasurementStore struct {
+	db *sql.DB
+}
+
+func NewPostgresBodyMeasurementStore(db *sql.DB) *PostgresBodyMeasurementStore {
+	return &PostgresBodyMeasurementStore{db: db}
+}
+
+type BodyMeasurementStore interface {
+	CreateMeasurement(measurement *BodyMeasurement) (*BodyMeasurement, error)
+	GetMeasurementByID(id int64) (*BodyMeasurement, error)
+	GetMeasurementsForUser(userID int64, from, to *time.Time) ([]*BodyMeasurement, error)
+	UpdateMeasurement(measurement *BodyMeasurement) error
+	DeleteMeasurement(id int64) error
+	GetMeasurementSeries(q MeasurementSeriesQuery) ([]MeasurementPoint, error)
+}
+
+const measurementColumns = `id, user_id, measured_at, weight_kg, body_fat_percent, neck_cm, chest_cm, waist_cm, hips_cm, arm_cm, thigh_cm, calf_cm, notes, created_at, updated_at`
+
+// measurementDest returns scan destinations matching measurementColumns.
+func measurementDest(m *BodyMeasurement) []any {
+	return []any{&m.ID, &m.UserID, &m.MeasuredAt, &m.WeightKg, &m.BodyFatPercent, &m.NeckCm, &m.ChestCm, &m.WaistCm, &m.HipsCm, &m.ArmCm, &m.ThighCm, &m.CalfCm,
+		&m.Notes, &m.CreatedAt, &m.UpdatedAt}
+}
+
+// validateMeasurement checks every value is in a plausible range, defaulting
+// MeasuredAt to now.
+func validateMeasurement(m *BodyMeasurement) error {
+	if m.MeasuredAt.IsZero() {
+		m.MeasuredAt = time.Now()
+	}
+	if m.MeasuredAt.After(time.Now().Add(24 * time.Hour)) {
+		return fmt.Errorf("%w: measured_at is in the future", ErrInvalidMeasurement)
+	}
+
+	ranges := []struct {
+		name     string
+		value    *float64
+		min, max float64
+	}{
+		{"weight_kg", m.WeightKg, 20, 400},
+		{"body_fat_percent", m.BodyFatPercent, 2, 75},
+		{"neck_cm", m.NeckCm, 10, 300},
+		{"chest_cm", m.ChestCm, 10, 300},
+		{"waist_cm", m.WaistCm, 10, 300},
+		{"hips_cm", m.HipsCm, 10, 300},
+		{"arm_cm", m.ArmCm, 10, 300},
+		{"thigh_cm", m.ThighCm, 10, 300},
+		{"calf_cm", m.CalfCm, 10, 300},
+	}
+	set := false
+	for _, r := range ranges {
+		if r.value == nil {
+			continue
+		}
+		if *r.value < r.min || *r.value > r.max {
+			return fmt.Errorf("%w: %s must be between %g and %g", ErrInvalidMeasurement, r.name, r.min, r.max)
+		}
+		set = true
+	}
+	if !set {
+		return fmt.Errorf("%w: at least one measurement is required", ErrInvalidMeasurement)
+	}
+	return nil
+}
+
+func (s *PostgresBodyMeasurementStore) CreateMeasurement(m *BodyMeasurement) (*BodyMeasurement, error) {
+	if err := validateMeasurement(m); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO body_measurements (user_id, measured_at, weight_kg, body_fat_percent, neck_cm, chest_cm, waist_cm, hips_cm, arm_cm, thigh_cm, calf_cm, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at`
+	err := s.db.QueryRow(query, m.UserID, m.MeasuredAt, m.WeightKg, m.BodyFatPercent, m.NeckCm, m.ChestCm, m.WaistCm, m.HipsCm, m.ArmCm, m.ThighCm, m.CalfCm, m.Notes).Scan(
+		&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *PostgresBodyMeasurementStore) GetMeasurementByID(id int64) (*BodyMeasurement, error) {
+	query := `SELECT ` + measurementColumns + ` FROM body_measurements WHERE id = $1`
+	m := &BodyMeasurement{}
+	err := s.db.QueryRow(query, id).Scan(measurementDest(m)...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetMeasurementsForUser returns the user's measurements in [from, to),
+// newest first. Nil bounds are open.
+func (s *PostgresBodyMeasurementStore) GetMeasurementsForUser(userID int64, from, to *time.Time) ([]*BodyMeasurement, error) {
+	query := `SELECT ` + measurementColumns + ` FROM body_measurements
+		WHERE user_id = $1 AND ($2::TIMESTAMPTZ IS NULL OR measured_at >= $2) AND ($3::TIMESTAMPTZ IS NULL OR measured_at < $3)
+		ORDER BY measured_at DESC, id DESC`
+	rows, err := s.db.Query(query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	measurements := []*BodyMeasurement{}
+	for rows.Next() {
+		m := &BodyMeasurement{}
+		if err := rows.Scan(measurementDest(m)...); err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, rows.Err()
+}
+
+// UpdateMeasurement replaces every value of the measurement.
+func (s *PostgresBodyMeasurementStore) UpdateMeasurement(m *BodyMeasurement) error {
+	if err := validateMeasurement(m); err != nil {
+		return err
+	}
+
+	query := `UPDATE body_measurements SET measured_at = $1, weight_kg = $2, body_fat_percent = $3, neck_cm = $4, chest_cm = $5, waist_cm = $6,
+			hips_cm = $7, arm_cm = $8, thigh_cm = $9, calf_cm = $10, notes = $11, updated_at = NOW()
+		WHERE id = $12 RETURNING user_id, created_at, updated_at`
+	return s.db.QueryRow(query, m.MeasuredAt, m.WeightKg, m.BodyFatPercent, m.NeckCm, m.ChestCm, m.WaistCm, m.HipsCm, m.ArmCm, m.ThighCm, m.CalfCm, m.Notes, m.ID).Scan(
+		&m.UserID, &m.CreatedAt, &m.UpdatedAt)
+}
+
+func (s *PostgresBodyMeasurementStore) DeleteMeasurement(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM body_measurements WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetMeasurementSeries returns the user's measurements of one metric, oldest
+// first. Measurements from the window before q.From are read too so the
+// first moving averages are complete.
+func (s *PostgresBodyMeasurementStore) GetMeasurementSeries(q MeasurementSeriesQuery) ([]MeasurementPoint, error) {
+	column, ok := MeasurementMetrics[q.Metric]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidMeasurement, q.Metric)
+	}
+
+	query := `SELECT measured_at, ` + column + ` FROM body_measurements
+		WHERE user_id = $1 AND ` + column + ` IS NOT NULL AND measured_at > $2 AND measured_at < $3
+		ORDER BY measured_at, id`
+	rows, err := s.db.Query(query, q.UserID, q.From.Add(-q.Window), q.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []MeasurementPoint{}
+	for rows.Next() {
+		point := MeasurementPoint{}
+		if err := rows.Scan(&point.MeasuredAt, &point.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	points = movingAverages(points, q.Window)
+	for len(points) > 0 && points[0].MeasuredAt.Before(q.From) {
+		points = points[1:]
+	}
+	return points, nil
+}
+
+// movingAverages sets each point's moving average to the mean of the points,
+// which must be sorted by time, measured less than window before it.
+func movingAverages(points []MeasurementPoint, window time.Duration) []MeasurementPoint {
+	start := 0
+	sum := 0.0
+	for i := range points {
+		sum += points[i].Value
+		for start < i && points[start].MeasuredAt.Add(window).Compare(points[i].MeasuredAt) <= 0 {
+			sum -= points[start].Value
+			start++
+		}
+		points[i].MovingAverage = sum / float64(i-start+1)
+	}
+	return points
+}
+
+// bodyWeightAt returns the user's weight from their latest measurement at or
+// before at, falling back to their profile weight. It is nil when neither is
+// known.
+func bodyWeightAt(q querier, userID int64, at time.Time) (*float64, error) {
+	var weightKg *float64
+	query := `SELECT COALESCE(
+			(SELECT weight_kg FROM body_measurements
+				WHERE user_id = $1 AND weight_kg IS NOT NULL AND measured_at <= $2
+				ORDER BY measured_at DESC LIMIT 1),
+			(SELECT weight_kg FROM users WHERE id = $1))`
+	err := q.QueryRow(query, userID, at).Scan(&weightKg)
+	return weightKg, err
+}
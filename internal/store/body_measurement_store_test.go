@@ -0,0 +1,55 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMeasurement(t *testing.T) {
+	tests := []struct {
+		name        string
+		measurement BodyMeasurement
+		wantErr     bool
+	}{
+		{name: "weight only", measurement: BodyMeasurement{WeightKg: utils.Float64Ptr(82.4)}},
+		{name: "circumferences", measurement: BodyMeasurement{WaistCm: utils.Float64Ptr(84), ArmCm: utils.Float64Ptr(38.5)}},
+		{name: "nothing measured", measurement: BodyMeasurement{Notes: "forgot the tape"}, wantErr: true},
+		{name: "weight out of range", measurement: BodyMeasurement{WeightKg: utils.Float64Ptr(8)}, wantErr: true},
+		{name: "body fat out of range", measurement: BodyMeasurement{BodyFatPercent: utils.Float64Ptr(80)}, wantErr: true},
+		{name: "in the future", measurement: BodyMeasurement{WeightKg: utils.Float64Ptr(80), MeasuredAt: time.Now().Add(72 * time.Hour)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMeasurement(&tt.measurement)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidMeasurement)
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, tt.measurement.MeasuredAt.IsZero(), "measured_at defaults to now")
+		})
+	}
+}
+
+func TestMovingAverages(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 8, 0, 0, 0, time.UTC) }
+	points := []MeasurementPoint{
+		{MeasuredAt: day(1), Value: 80},
+		{MeasuredAt: day(2), Value: 82},
+		{MeasuredAt: day(3), Value: 81},
+		{MeasuredAt: day(4), Value: 79},
+		{MeasuredAt: day(10), Value: 78},
+	}
+
+	averages := []float64{}
+	for _, point := range movingAverages(points, 3*24*time.Hour) {
+		averages = append(averages, point.MovingAverage)
+	}
+	assert.InDeltaSlice(t, []float64{80, 81, 81, 80.67, 78}, averages, 0.01, "each average covers the three days ending at its point")
+	assert.Empty(t, movingAverages([]MeasurementPoint{}, 24*time.Hour))
+}
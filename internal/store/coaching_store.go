@@ -0,0 +1,167 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	PermissionRead      = "read"
+	PermissionReadWrite = "read_write"
+
+	RelationshipPending  = "pending"
+	RelationshipAccepted = "accepted"
+)
+
+var ErrRelationshipExists = errors.New("a coaching relationship already exists between these users")
+
+// CoachRelationship grants a coach access to an athlete's workouts once the
+// invited party accepts. Either side may send the invitation.
+type CoachRelationship struct {
+	ID              int64      `json:"id"`
+	CoachID         int64      `json:"coach_id"`
+	CoachUsername   string     `json:"coach_username"`
+	AthleteID       int64      `json:"athlete_id"`
+	AthleteUsername string     `json:"athlete_username"`
+	InvitedBy       int64      `json:"invited_by"`
+	Permission      string     `json:"permission"` // read or read_write
+	Status          string     `json:"status"`     // pending or accepted
+	CreatedAt       time.Time  `json:"created_at"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+}
+
+// Invitee returns the user who has to accept the invitation.
+func (rel *CoachRelationship) Invitee() int64 {
+	if rel.InvitedBy == rel.CoachID {
+		return rel.AthleteID
+	}
+	return rel.CoachID
+}
+
+// Involves reports whether userID is the coach or the athlete.
+func (rel *CoachRelationship) Involves(userID int64) bool {
+	return rel.CoachID == userID || rel.AthleteID == userID
+}
+
+type PostgresCoachingStore struct {
+	db *sql.DB
+}
+
+func NewPostgresCoachingStore(db *sql.DB) *PostgresCoachingStore {
+	return &PostgresCoachingStore{db: db}
+}
+
+type CoachingStore interface {
+	CreateInvitation(rel *CoachRelationship) (*CoachRelationship, error)
+	GetRelationshipByID(id int64) (*CoachRelationship, error)
+	GetRelationshipsForUser(userID int64) ([]*CoachRelationship, error)
+	AcceptInvitation(id int64) error
+	UpdatePermission(id int64, permission string) error
+	DeleteRelationship(id int64) error
+	GetCoachPermission(coachID, athleteID int64) (string, error)
+}
+
+const relationshipQuery = `SELECT r.id, r.coach_id, c.username, r.athlete_id, a.username, r.invited_by,
+		r.permission, r.status, r.created_at, r.accepted_at
+	FROM coach_relationships r
+	JOIN users c ON c.id = r.coach_id
+	JOIN users a ON a.id = r.athlete_id`
+
+func scanRelationship(row interface{ Scan(dest ...any) error }) (*CoachRelationship, error) {
+	rel := &CoachRelationship{}
+	err := row.Scan(&rel.ID, &rel.CoachID, &rel.CoachUsername, &rel.AthleteID, &rel.AthleteUsername, &rel.InvitedBy,
+		&rel.Permission, &rel.Status, &rel.CreatedAt, &rel.AcceptedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+func (s *PostgresCoachingStore) CreateInvitation(rel *CoachRelationship) (*CoachRelationship, error) {
+	query := `INSERT INTO coach_relationships (coach_id, athlete_id, invited_by, permission)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+	var id int64
+	err := s.db.QueryRow(query, rel.CoachID, rel.AthleteID, rel.InvitedBy, rel.Permission).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrRelationshipExists
+		}
+		return nil, err
+	}
+	return s.GetRelationshipByID(id)
+}
+
+func (s *PostgresCoachingStore) GetRelationshipByID(id int64) (*CoachRelationship, error) {
+	rel, err := scanRelationship(s.db.QueryRow(relationshipQuery+` WHERE r.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// GetRelationshipsForUser returns every relationship, pending or accepted, in
+// which the user is either the coach or the athlete.
+func (s *PostgresCoachingStore) GetRelationshipsForUser(userID int64) ([]*CoachRelationship, error) {
+	query := relationshipQuery + ` WHERE r.coach_id = $1 OR r.athlete_id = $1 ORDER BY r.created_at DESC, r.id DESC`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relationships := []*CoachRelationship{}
+	for rows.Next() {
+		rel, err := scanRelationship(rows)
+		if err != nil {
+			return nil, err
+		}
+		relationships = append(relationships, rel)
+	}
+	return relationships, rows.Err()
+}
+
+func (s *PostgresCoachingStore) AcceptInvitation(id int64) error {
+	query := `UPDATE coach_relationships SET status = 'accepted', accepted_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+func (s *PostgresCoachingStore) UpdatePermission(id int64, permission string) error {
+	result, err := s.db.Exec(`UPDATE coach_relationships SET permission = $2 WHERE id = $1`, id, permission)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+// DeleteRelationship declines a pending invitation or ends an accepted
+// relationship.
+func (s *PostgresCoachingStore) DeleteRelationship(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM coach_relationships WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+// GetCoachPermission returns the permission coachID holds over athleteID's
+// workouts, or "" when there is no accepted relationship.
+func (s *PostgresCoachingStore) GetCoachPermission(coachID, athleteID int64) (string, error) {
+	query := `SELECT permission FROM coach_relationships
+		WHERE coach_id = $1 AND athlete_id = $2 AND status = 'accepted'`
+	var permission string
+	err := s.db.QueryRow(query, coachID, athleteID).Scan(&permission)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return permission, err
+}
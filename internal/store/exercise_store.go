@@ -0,0 +1,66 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Exercise struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	MuscleGroup string    `json:"muscle_group"`
+	Category    string    `json:"category"`   // strength or cardio
+	Bodyweight  bool      `json:"bodyweight"` // the lifter's body weight counts towards volume
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PostgresExerciseStore struct {
+	db *sql.DB
+}
+
+func NewPostgresExerciseStore(db *sql.DB) *PostgresExerciseStore {
+	return &PostgresExerciseStore{db: db}
+}
+
+type ExerciseStore interface {
+	GetExerciseByID(id int64) (*Exercise, error)
+	GetAllExercises() ([]*Exercise, error)
+}
+
+func (s *PostgresExerciseStore) GetExerciseByID(id int64) (*Exercise, error) {
+	query := `SELECT id, name, muscle_group, category, bodyweight, created_at
+		FROM exercises WHERE id = $1`
+	exercise := &Exercise{}
+	err := s.db.QueryRow(query, id).Scan(&exercise.ID, &exercise.Name, &exercise.MuscleGroup, &exercise.Category, &exercise.Bodyweight, &exercise.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return exercise, nil
+}
+
+func (s *PostgresExerciseStore) GetAllExercises() ([]*Exercise, error) {
+	query := `SELECT id, name, muscle_group, category, bodyweight, created_at
+		FROM exercises ORDER BY muscle_group, name`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []*Exercise{}
+	for rows.Next() {
+		exercise := &Exercise{}
+		err := rows.Scan(&exercise.ID, &exercise.Name, &exercise.MuscleGroup, &exercise.Category, &exercise.Bodyweight, &exercise.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
@@ -0,0 +1,560 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrInvalidGoal is wrapped by goal validation errors.
+var ErrInvalidGoal = errors.New("invalid goal")
+
+const (
+	GoalWorkouts = "workouts" // completed workouts
+	GoalDistance = "distance" // meters covered, optionally in one exercise
+	GoalLift     = "lift"     // heaviest weight lifted in one exercise
+)
+
+// liftTrendDays is how far back lift goals look for max weight records to
+// project their completion from.
+const liftTrendDays = 90
+
+// maxProjection bounds projected completion dates; a rate slow enough to
+// need longer is reported as no projection.
+const maxProjection = 10 * 365 * 24 * time.Hour
+
+// Goal is a target a user works towards. Workout and distance goals either
+// recur every Period (week or month) or run from their creation until
+// Deadline; lift goals have an optional Deadline. Progress is computed in the
+// user's time zone from their completed workouts.
+type Goal struct {
+	ID           int64   `json:"id"`
+	UserID       int64   `json:"user_id"`
+	Type         string  `json:"type"`
+	ExerciseName *string `json:"exercise_name"` // required for lift goals, optional for distance goals
+	Target       float64 `json:"target"`        // workouts, meters, or kg; distances and weights are in the caller's unit through the API
+	Period       *string `json:"period"`        // week or month for recurring goals
+	Deadline     *string `json:"deadline"`      // YYYY-MM-DD, inclusive
+	Unit         string  `json:"unit"`          // unit of Target and Current in the response
+
+	Current             float64    `json:"current"`
+	PercentComplete     float64    `json:"percent_complete"`
+	WindowStart         *string    `json:"window_start"`         // first day counted, YYYY-MM-DD in the user's timezone
+	WindowEnd           *string    `json:"window_end"`           // last day counted
+	ProjectedCompletion *string    `json:"projected_completion"` // day the target is reached at the current rate, nil without progress
+	AchievedAt          *time.Time `json:"achieved_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Streaks counts consecutive training days and weeks in the user's time
+// zone. A streak is current while it includes today or yesterday (this week
+// or last week for weekly streaks), so it is not broken before the day is
+// over. Weeks start on Monday.
+type Streaks struct {
+	CurrentDays     int     `json:"current_days"`
+	LongestDays     int     `json:"longest_days"`
+	CurrentWeeks    int     `json:"current_weeks"`
+	LongestWeeks    int     `json:"longest_weeks"`
+	LastWorkoutDate *string `json:"last_workout_date"` // YYYY-MM-DD, nil without workouts
+}
+
+type PostgresGoalStore struct {
+	db *sql.DB
+}
+
+func NewPostgresGoalStore(db *sql.DB) *PostgresGoalStore {
+	return &PostgresGoalStore{db: db}
+}
+
+type GoalStore interface {
+	CreateGoal(goal *Goal) (*Goal, error)
+	GetGoalByID(id int64) (*Goal, error)
+	GetGoalsForUser(userID int64) ([]*Goal, error)
+	DeleteGoal(id int64) error
+	GetStreaks(userID int64) (*Streaks, error)
+}
+
+const goalColumns = `id, user_id, goal_type, exercise_name, target, period, to_char(deadline, 'YYYY-MM-DD'), current_value, achieved_at, created_at, updated_at`
+
+// goalDest returns scan destinations matching goalColumns.
+func goalDest(g *Goal) []any {
+	return []any{&g.ID, &g.UserID, &g.Type, &g.ExerciseName, &g.Target, &g.Period, &g.Deadline, &g.Current, &g.AchievedAt, &g.CreatedAt, &g.UpdatedAt}
+}
+
+// validateGoal checks the goal's type, target and time frame, trimming its
+// exercise name.
+func validateGoal(g *Goal) error {
+	if g.ExerciseName != nil {
+		name := strings.TrimSpace(*g.ExerciseName)
+		g.ExerciseName = &name
+		if name == "" {
+			g.ExerciseName = nil
+		}
+	}
+
+	switch g.Type {
+	case GoalWorkouts:
+		if g.ExerciseName != nil {
+			return fmt.Errorf("%w: workout goals cannot name an exercise", ErrInvalidGoal)
+		}
+		if g.Target != math.Trunc(g.Target) {
+			return fmt.Errorf("%w: workout goals need a whole number of workouts", ErrInvalidGoal)
+		}
+	case GoalDistance:
+	case GoalLift:
+		if g.ExerciseName == nil {
+			return fmt.Errorf("%w: lift goals need an exercise_name", ErrInvalidGoal)
+		}
+		if g.Period != nil {
+			return fmt.Errorf("%w: lift goals cannot recur", ErrInvalidGoal)
+		}
+	default:
+		return fmt.Errorf("%w: type must be workouts, distance or lift", ErrInvalidGoal)
+	}
+
+	if g.Target <= 0 {
+		return fmt.Errorf("%w: target must be positive", ErrInvalidGoal)
+	}
+	if g.Period != nil && *g.Period != PeriodWeek && *g.Period != PeriodMonth {
+		return fmt.Errorf("%w: period must be week or month", ErrInvalidGoal)
+	}
+	if g.Deadline != nil {
+		if _, err := time.Parse(dateLayout, *g.Deadline); err != nil {
+			return fmt.Errorf("%w: deadline must be a YYYY-MM-DD date", ErrInvalidGoal)
+		}
+		if g.Period != nil {
+			return fmt.Errorf("%w: a goal has either a period or a deadline", ErrInvalidGoal)
+		}
+	}
+	if g.Type != GoalLift && g.Period == nil && g.Deadline == nil {
+		return fmt.Errorf("%w: %s goals need a period or a deadline", ErrInvalidGoal, g.Type)
+	}
+	return nil
+}
+
+// CreateGoal saves the goal with its progress so far.
+func (s *PostgresGoalStore) CreateGoal(g *Goal) (*Goal, error) {
+	if err := validateGoal(g); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO goals (user_id, goal_type, exercise_name, target, period, deadline)
+		VALUES ($1, $2, $3, $4, $5, $6::date) RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(query, g.UserID, g.Type, g.ExerciseName, g.Target, g.Period, g.Deadline).Scan(&g.ID, &g.CreatedAt, &g.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := userTimezone(tx, g.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := trackGoal(tx, g, loc, time.Now()); err != nil {
+		return nil, err
+	}
+	return g, tx.Commit()
+}
+
+// GetGoalByID returns the goal with its current progress.
+func (s *PostgresGoalStore) GetGoalByID(id int64) (*Goal, error) {
+	g := &Goal{}
+	err := s.db.QueryRow(`SELECT `+goalColumns+` FROM goals WHERE id = $1`, id).Scan(goalDest(g)...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := userTimezone(s.db, g.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := goalProgress(s.db, g, loc, time.Now()); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// GetGoalsForUser returns the user's goals, oldest first, with their current
+// progress.
+func (s *PostgresGoalStore) GetGoalsForUser(userID int64) ([]*Goal, error) {
+	goals, err := goalsForUser(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := userTimezone(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, g := range goals {
+		if err := goalProgress(s.db, g, loc, now); err != nil {
+			return nil, err
+		}
+	}
+	return goals, nil
+}
+
+func (s *PostgresGoalStore) DeleteGoal(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM goals WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetStreaks returns the user's training streaks as of today in their time
+// zone. Only completed workouts count.
+func (s *PostgresGoalStore) GetStreaks(userID int64) (*Streaks, error) {
+	loc, err := userTimezone(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT DISTINCT to_char(created_at AT TIME ZONE $2, 'YYYY-MM-DD')
+		FROM workouts
+		WHERE user_id = $1 AND status = 'completed' AND deleted_at IS NULL
+		ORDER BY 1`
+	rows, err := s.db.Query(query, userID, loc.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []time.Time{}
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		day, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	today, _ := time.Parse(dateLayout, time.Now().In(loc).Format(dateLayout))
+	streaks := computeStreaks(days, today)
+	return &streaks, nil
+}
+
+// refreshGoals recomputes and stores the progress of the user's goals, so
+// achievements are stamped as workouts are saved, changed or deleted.
+func refreshGoals(q querier, userID int64) error {
+	goals, err := goalsForUser(q, userID)
+	if err != nil || len(goals) == 0 {
+		return err
+	}
+
+	loc, err := userTimezone(q, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, g := range goals {
+		if err := trackGoal(q, g, loc, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trackGoal computes the goal's progress and stores it.
+func trackGoal(q querier, g *Goal, loc *time.Location, now time.Time) error {
+	if err := goalProgress(q, g, loc, now); err != nil {
+		return err
+	}
+	_, err := q.Exec(`UPDATE goals SET current_value = $1, achieved_at = $2 WHERE id = $3`, g.Current, g.AchievedAt, g.ID)
+	return err
+}
+
+func goalsForUser(q querier, userID int64) ([]*Goal, error) {
+	rows, err := q.Query(`SELECT `+goalColumns+` FROM goals WHERE user_id = $1 ORDER BY created_at, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []*Goal{}
+	for rows.Next() {
+		g := &Goal{}
+		if err := rows.Scan(goalDest(g)...); err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+// userTimezone returns the user's time zone, falling back to UTC.
+func userTimezone(q querier, userID int64) (*time.Location, error) {
+	var timezone string
+	err := q.QueryRow(`SELECT timezone FROM users WHERE id = $1`, userID).Scan(&timezone)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// goalProgress sets the goal's current value, percentage, window, achievement
+// and projected completion as of now in loc.
+func goalProgress(q querier, g *Goal, loc *time.Location, now time.Time) error {
+	start, end := goalWindow(g, loc, now)
+
+	var err error
+	switch g.Type {
+	case GoalWorkouts:
+		query := `SELECT COUNT(*) FROM workouts
+			WHERE user_id = $1 AND status = 'completed' AND deleted_at IS NULL AND created_at >= $2 AND created_at < $3`
+		err = q.QueryRow(query, g.UserID, start, end).Scan(&g.Current)
+	case GoalDistance:
+		query := `SELECT COALESCE(SUM(` + entryDistance + `), 0)
+			FROM workout_entries e
+			INNER JOIN workouts w ON w.id = e.workout_id
+			WHERE w.user_id = $1 AND w.status = 'completed' AND w.deleted_at IS NULL AND w.created_at >= $2 AND w.created_at < $3
+				AND ($4::TEXT IS NULL OR LOWER(e.exercise_name) = LOWER($4))`
+		err = q.QueryRow(query, g.UserID, start, end, g.ExerciseName).Scan(&g.Current)
+	case GoalLift:
+		query := `SELECT COALESCE(MAX(value), 0) FROM personal_records
+			WHERE user_id = $1 AND record_type = $2 AND LOWER(exercise_name) = LOWER($3)`
+		err = q.QueryRow(query, g.UserID, RecordMaxWeight, g.ExerciseName).Scan(&g.Current)
+	}
+	if err != nil {
+		return err
+	}
+
+	g.PercentComplete = math.Min(100, math.Round(g.Current/g.Target*1000)/10)
+	if g.Type != GoalLift {
+		first := start.Format(dateLayout)
+		last := end.AddDate(0, 0, -1).Format(dateLayout)
+		g.WindowStart, g.WindowEnd = &first, &last
+	} else if g.Deadline != nil {
+		g.WindowEnd = g.Deadline
+	}
+
+	// an achievement from an earlier period does not carry over
+	if g.Current < g.Target {
+		g.AchievedAt = nil
+	} else if g.AchievedAt == nil || g.AchievedAt.Before(start) {
+		achieved := now
+		g.AchievedAt = &achieved
+	}
+
+	var projected *time.Time
+	switch {
+	case g.AchievedAt != nil:
+		projected = g.AchievedAt
+	case g.Deadline != nil && !now.Before(end):
+		// the deadline has passed
+	case g.Type == GoalLift:
+		points, err := liftTrend(q, g, now)
+		if err != nil {
+			return err
+		}
+		projected = projectTrend(points, g.Target)
+	default:
+		projected = projectCumulative(g.Current, g.Target, start, now)
+	}
+	g.ProjectedCompletion = nil
+	if projected != nil {
+		date := projected.In(loc).Format(dateLayout)
+		g.ProjectedCompletion = &date
+	}
+	return nil
+}
+
+// goalWindow returns the instants progress is counted between: the current
+// week or month in loc for recurring goals, or the day the goal was created
+// through its deadline. Lift goals count every workout, so their window only
+// bounds when an achievement is still current.
+func goalWindow(g *Goal, loc *time.Location, now time.Time) (time.Time, time.Time) {
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	created := g.CreatedAt.In(loc)
+	start := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, loc)
+
+	if g.Period != nil {
+		switch *g.Period {
+		case PeriodWeek:
+			start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+			return start, start.AddDate(0, 0, 7)
+		case PeriodMonth:
+			start = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+			return start, start.AddDate(0, 1, 0)
+		}
+	}
+	end := today.AddDate(0, 0, 1)
+	if g.Deadline != nil {
+		if deadline, err := time.ParseInLocation(dateLayout, *g.Deadline, loc); err == nil {
+			end = deadline.AddDate(0, 0, 1)
+		}
+	}
+	if g.Type == GoalLift {
+		return g.CreatedAt, end
+	}
+	return start, end
+}
+
+// trendPoint is a value reached at a time.
+type trendPoint struct {
+	At    time.Time
+	Value float64
+}
+
+// liftTrend returns the lift goal's recent max weight records followed by
+// its current best now, so a stalled lift flattens the trend.
+func liftTrend(q querier, g *Goal, now time.Time) ([]trendPoint, error) {
+	query := `SELECT achieved_at, value FROM personal_records
+		WHERE user_id = $1 AND record_type = $2 AND LOWER(exercise_name) = LOWER($3) AND achieved_at >= $4
+		ORDER BY achieved_at`
+	rows, err := q.Query(query, g.UserID, RecordMaxWeight, g.ExerciseName, now.AddDate(0, 0, -liftTrendDays))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []trendPoint{}
+	for rows.Next() {
+		point := trendPoint{}
+		if err := rows.Scan(&point.At, &point.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return append(points, trendPoint{At: now, Value: g.Current}), nil
+}
+
+// projectCumulative extrapolates the rate current was reached at since start
+// to when target is reached. Less than a day counts as a day so an early
+// workout does not project an absurd rate. It is nil without progress.
+func projectCumulative(current, target float64, start, now time.Time) *time.Time {
+	if current <= 0 {
+		return nil
+	}
+	elapsed := now.Sub(start)
+	if elapsed < 24*time.Hour {
+		elapsed = 24 * time.Hour
+	}
+	remaining := (target - current) / current * float64(elapsed)
+	if remaining > float64(maxProjection) {
+		return nil
+	}
+	projected := now.Add(time.Duration(remaining))
+	return &projected
+}
+
+// projectTrend fits a least squares line through the points and returns when
+// it reaches target. It is nil for fewer than two points or a trend that is
+// not rising.
+func projectTrend(points []trendPoint, target float64) *time.Time {
+	if len(points) < 2 {
+		return nil
+	}
+	origin := points[0].At
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range points {
+		x := point.At.Sub(origin).Hours()
+		sumX += x
+		sumY += point.Value
+		sumXY += x * point.Value
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope <= 0 {
+		return nil
+	}
+	intercept := (sumY - slope*sumX) / n
+
+	last := points[len(points)-1].At
+	hours := (target - intercept) / slope
+	if hours-last.Sub(origin).Hours() > maxProjection.Hours() {
+		return nil
+	}
+	at := origin.Add(time.Duration(hours * float64(time.Hour)))
+	if at.Before(last) {
+		at = last
+	}
+	return &at
+}
+
+// computeStreaks counts streaks from the distinct days, sorted ascending and
+// at midnight UTC, that had a workout.
+func computeStreaks(days []time.Time, today time.Time) Streaks {
+	streaks := Streaks{}
+	if len(days) == 0 {
+		return streaks
+	}
+	last := days[len(days)-1].Format(dateLayout)
+	streaks.LastWorkoutDate = &last
+
+	nextDay := func(day time.Time) time.Time { return day.AddDate(0, 0, 1) }
+	streaks.CurrentDays, streaks.LongestDays = runs(days, nextDay, today)
+
+	weekOf := func(day time.Time) time.Time { return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) }
+	weeks := []time.Time{}
+	for _, day := range days {
+		week := weekOf(day)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].Equal(week) {
+			weeks = append(weeks, week)
+		}
+	}
+	nextWeek := func(week time.Time) time.Time { return week.AddDate(0, 0, 7) }
+	streaks.CurrentWeeks, streaks.LongestWeeks = runs(weeks, nextWeek, weekOf(today))
+	return streaks
+}
+
+// runs returns the length of the run of consecutive steps ending at, or one
+// step before, current and the length of the longest run in steps.
+func runs(steps []time.Time, next func(time.Time) time.Time, current time.Time) (int, int) {
+	run, longest := 0, 0
+	for i, step := range steps {
+		if i > 0 && next(steps[i-1]).Equal(step) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	last := steps[len(steps)-1]
+	if last.Equal(current) || next(last).Equal(current) {
+		return run, longest
+	}
+	return 0, longest
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGoal(t *testing.T) {
+	week := PeriodWeek
+	year := "year"
+	deadline := "2024-06-30"
+	bench := "Bench Press"
+	blank := "  "
+
+	tests := []struct {
+		name    string
+		goal    Goal
+		wantErr bool
+	}{
+		{name: "workouts per week", goal: Goal{Type: GoalWorkouts, Target: 4, Period: &week}},
+		{name: "distance by deadline", goal: Goal{Type: GoalDistance, Target: 50000, Deadline: &deadline}},
+		{name: "lift by deadline", goal: Goal{Type: GoalLift, Target: 100, ExerciseName: &bench, Deadline: &deadline}},
+		{name: "lift without deadline", goal: Goal{Type: GoalLift, Target: 100, ExerciseName: &bench}},
+		{name: "unknown type", goal: Goal{Type: "steps", Target: 10000, Period: &week}, wantErr: true},
+		{name: "zero target", goal: Goal{Type: GoalWorkouts, Period: &week}, wantErr: true},
+		{name: "fractional workouts", goal: Goal{Type: GoalWorkouts, Target: 2.5, Period: &week}, wantErr: true},
+		{name: "workouts for an exercise", goal: Goal{Type: GoalWorkouts, Target: 4, Period: &week, ExerciseName: &bench}, wantErr: true},
+		{name: "lift without exercise", goal: Goal{Type: GoalLift, Target: 100, ExerciseName: &blank}, wantErr: true},
+		{name: "recurring lift", goal: Goal{Type: GoalLift, Target: 100, ExerciseName: &bench, Period: &week}, wantErr: true},
+		{name: "unknown period", goal: Goal{Type: GoalWorkouts, Target: 4, Period: &year}, wantErr: true},
+		{name: "period and deadline", goal: Goal{Type: GoalWorkouts, Target: 4, Period: &week, Deadline: &deadline}, wantErr: true},
+		{name: "no time frame", goal: Goal{Type: GoalDistance, Target: 50000}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGoal(&tt.goal)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidGoal)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGoalWindow(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// Monday 2024-03-04 04:00 UTC is still Sunday the 3rd in New York
+	now := time.Date(2024, 3, 4, 4, 0, 0, 0, time.UTC)
+	week, month := PeriodWeek, PeriodMonth
+	deadline := "2024-03-31"
+	created := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		goal       Goal
+		start, end time.Time
+	}{
+		{"week", Goal{Type: GoalWorkouts, Period: &week}, time.Date(2024, 2, 26, 0, 0, 0, 0, loc), time.Date(2024, 3, 4, 0, 0, 0, 0, loc)},
+		{"month", Goal{Type: GoalDistance, Period: &month}, time.Date(2024, 3, 1, 0, 0, 0, 0, loc), time.Date(2024, 4, 1, 0, 0, 0, 0, loc)},
+		{"deadline", Goal{Type: GoalDistance, Deadline: &deadline, CreatedAt: created}, time.Date(2024, 3, 1, 0, 0, 0, 0, loc), time.Date(2024, 4, 1, 0, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := goalWindow(&tt.goal, loc, now)
+			assert.True(t, tt.start.Equal(start), "start %v, want %v", start, tt.start)
+			assert.True(t, tt.end.Equal(end), "end %v, want %v", end, tt.end)
+		})
+	}
+}
+
+func TestProjectCumulative(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 10)
+
+	projected := projectCumulative(20000, 50000, start, now)
+	require.NotNil(t, projected)
+	assert.Equal(t, start.AddDate(0, 0, 25), *projected, "2 km a day reaches 50 km on day 25")
+
+	assert.Nil(t, projectCumulative(0, 50000, start, now), "no progress, no projection")
+
+	early := projectCumulative(1, 4, start, start.Add(time.Hour))
+	require.NotNil(t, early)
+	assert.Equal(t, start.Add(time.Hour).AddDate(0, 0, 3), *early, "less than a day counts as a day")
+}
+
+func TestProjectTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d) }
+
+	rising := []trendPoint{{day(0), 90}, {day(10), 92.5}, {day(20), 95}}
+	projected := projectTrend(rising, 100)
+	require.NotNil(t, projected)
+	assert.WithinDuration(t, day(40), *projected, time.Minute, "0.25 kg a day reaches 100 kg on day 40")
+
+	assert.Nil(t, projectTrend([]trendPoint{{day(0), 90}}, 100), "one point has no trend")
+	assert.Nil(t, projectTrend([]trendPoint{{day(0), 90}, {day(30), 90}}, 100), "a flat trend never gets there")
+	assert.Nil(t, projectTrend([]trendPoint{{day(0), 90}, {day(1), 90.001}}, 500), "too far out")
+}
+
+func TestComputeStreaks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d) }
+	// Friday 1st to Sunday 3rd, then Tuesday 12th to Thursday 14th
+	days := []time.Time{day(0), day(1), day(2), day(11), day(12), day(13)}
+
+	streaks := computeStreaks(days, day(14))
+	assert.Equal(t, 3, streaks.CurrentDays, "a streak survives until the day is over")
+	assert.Equal(t, 3, streaks.LongestDays)
+	assert.Equal(t, 1, streaks.CurrentWeeks, "the week of the 4th had no workouts")
+	assert.Equal(t, 1, streaks.LongestWeeks)
+	assert.Equal(t, "2024-03-14", *streaks.LastWorkoutDate)
+
+	streaks = computeStreaks(days, day(15))
+	assert.Equal(t, 0, streaks.CurrentDays)
+	assert.Equal(t, 3, streaks.LongestDays)
+	assert.Equal(t, 1, streaks.CurrentWeeks, "the current week still counts")
+
+	weekly := []time.Time{day(0), day(4), day(11), day(18)}
+	streaks = computeStreaks(weekly, day(25))
+	assert.Equal(t, 0, streaks.CurrentDays)
+	assert.Equal(t, 1, streaks.LongestDays)
+	assert.Equal(t, 4, streaks.CurrentWeeks, "last week's workout keeps the weekly streak")
+	assert.Equal(t, 4, streaks.LongestWeeks)
+
+	assert.Equal(t, Streaks{}, computeStreaks([]time.Time{}, day(0)))
+}
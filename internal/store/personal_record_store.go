@@ -0,0 +1,247 @@
+package store
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+)
+
+const (
+	RecordMaxWeight    = "max_weight"
+	RecordEstimated1RM = "estimated_1rm"
+	RecordMaxReps      = "max_reps"
+	RecordMaxDuration  = "max_duration"
+	RecordMaxVolume    = "max_volume"
+)
+
+type PersonalRecord struct {
+	ID           int64     `json:"id"`
+	UserID       int64     `json:"user_id"`
+	WorkoutID    int       `json:"workout_id"`
+	ExerciseName string    `json:"exercise_name"`
+	RecordType   string    `json:"record_type"`
+	Value        float64   `json:"value"`  // kg, seconds or kg volume depending on record_type
+	Weight       *float64  `json:"weight"` // weight the record was set at, nil for bodyweight
+	Reps         *int      `json:"reps"`
+	AchievedAt   time.Time `json:"achieved_at"`
+}
+
+type PostgresPersonalRecordStore struct {
+	db *sql.DB
+}
+
+func NewPostgresPersonalRecordStore(db *sql.DB) *PostgresPersonalRecordStore {
+	return &PostgresPersonalRecordStore{db: db}
+}
+
+type PersonalRecordStore interface {
+	GetPersonalRecords(userID int64) ([]*PersonalRecord, error)
+	GetPersonalRecordHistory(userID int64, exerciseName string) ([]*PersonalRecord, error)
+}
+
+// GetPersonalRecords returns the current best for every exercise and record
+// type. Records are only ever inserted when they improve on the previous best,
+// so the most recent row per key is the standing record.
+func (s *PostgresPersonalRecordStore) GetPersonalRecords(userID int64) ([]*PersonalRecord, error) {
+	query := `SELECT DISTINCT ON (LOWER(exercise_name), record_type, CASE WHEN record_type = 'max_reps' THEN weight END)
+			id, user_id, workout_id, exercise_name, record_type, value, weight, reps, achieved_at
+		FROM personal_records
+		WHERE user_id = $1
+		ORDER BY LOWER(exercise_name), record_type, CASE WHEN record_type = 'max_reps' THEN weight END, achieved_at DESC, id DESC`
+
+	return s.queryRecords(query, userID)
+}
+
+// GetPersonalRecordHistory returns every record the user has set, oldest
+// first. An empty exerciseName returns the history for all exercises.
+func (s *PostgresPersonalRecordStore) GetPersonalRecordHistory(userID int64, exerciseName string) ([]*PersonalRecord, error) {
+	query := `SELECT id, user_id, workout_id, exercise_name, record_type, value, weight, reps, achieved_at
+		FROM personal_records
+		WHERE user_id = $1 AND ($2 = '' OR LOWER(exercise_name) = LOWER($2))
+		ORDER BY achieved_at, id`
+
+	return s.queryRecords(query, userID, exerciseName)
+}
+
+func (s *PostgresPersonalRecordStore) queryRecords(query string, args ...any) ([]*PersonalRecord, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*PersonalRecord{}
+	for rows.Next() {
+		record := &PersonalRecord{}
+		err := rows.Scan(&record.ID, &record.UserID, &record.WorkoutID, &record.ExerciseName, &record.RecordType, &record.Value, &record.Weight, &record.Reps, &record.AchievedAt)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// recordCandidate is a single logged entry considered for personal records.
+type recordCandidate struct {
+	WorkoutID       int
+	AchievedAt      time.Time
+	ExerciseName    string
+	Sets            int
+	Reps            *int
+	DurationSeconds *int
+	Weight          *float64
+}
+
+// recomputePersonalRecords rebuilds the record history for the given
+// exercises from the user's workouts. It runs inside the caller's transaction
+// so records never drift from the workouts they were derived from.
+func recomputePersonalRecords(q querier, userID int64, exerciseNames []string) error {
+	names := normalizeExerciseNames(exerciseNames)
+	if len(names) == 0 {
+		return nil
+	}
+
+	_, err := q.Exec(`DELETE FROM personal_records WHERE user_id = $1 AND LOWER(exercise_name) = ANY($2)`, userID, names)
+	if err != nil {
+		return err
+	}
+
+	query := `SELECT w.id, w.created_at, e.exercise_name, e.sets, e.reps, e.duration_seconds, e.weight
+		FROM workout_entries e
+		INNER JOIN workouts w ON w.id = e.workout_id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND LOWER(e.exercise_name) = ANY($2)
+		ORDER BY w.created_at, w.id, e.order_index`
+	rows, err := q.Query(query, userID, names)
+	if err != nil {
+		return err
+	}
+
+	candidates := []recordCandidate{}
+	for rows.Next() {
+		c := recordCandidate{}
+		err := rows.Scan(&c.WorkoutID, &c.AchievedAt, &c.ExerciseName, &c.Sets, &c.Reps, &c.DurationSeconds, &c.Weight)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, record := range detectPersonalRecords(candidates) {
+		query := `INSERT INTO personal_records (user_id, workout_id, exercise_name, record_type, value, weight, reps, achieved_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		_, err := q.Exec(query, userID, record.WorkoutID, record.ExerciseName, record.RecordType, record.Value, record.Weight, record.Reps, record.AchievedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// detectPersonalRecords walks candidates in chronological order and emits a
+// record every time one of them beats the previous best for its exercise.
+func detectPersonalRecords(candidates []recordCandidate) []PersonalRecord {
+	type key struct {
+		exercise   string
+		recordType string
+		weight     float64
+	}
+	best := map[key]float64{}
+	records := []PersonalRecord{}
+
+	consider := func(c recordCandidate, recordType string, weightKey float64, value float64) {
+		if value <= 0 {
+			return
+		}
+		k := key{exercise: strings.ToLower(c.ExerciseName), recordType: recordType, weight: weightKey}
+		if current, ok := best[k]; ok && value <= current {
+			return
+		}
+		best[k] = value
+		records = append(records, PersonalRecord{
+			WorkoutID:    c.WorkoutID,
+			ExerciseName: c.ExerciseName,
+			RecordType:   recordType,
+			Value:        math.Round(value*100) / 100,
+			Weight:       c.Weight,
+			Reps:         c.Reps,
+			AchievedAt:   c.AchievedAt,
+		})
+	}
+
+	for _, c := range candidates {
+		reps := 0
+		if c.Reps != nil {
+			reps = *c.Reps
+		}
+
+		if c.Weight != nil {
+			weight := *c.Weight
+			consider(c, RecordMaxWeight, 0, weight)
+			consider(c, RecordEstimated1RM, 0, fitness.EstimateOneRepMax(weight, reps))
+			consider(c, RecordMaxVolume, 0, float64(c.Sets*reps)*weight)
+			consider(c, RecordMaxReps, weight, float64(reps))
+		} else {
+			// bodyweight reps are tracked under their own key
+			consider(c, RecordMaxReps, -1, float64(reps))
+		}
+
+		if c.DurationSeconds != nil {
+			consider(c, RecordMaxDuration, 0, float64(*c.DurationSeconds))
+		}
+	}
+
+	return records
+}
+
+func normalizeExerciseNames(exerciseNames []string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, name := range exerciseNames {
+		name = strings.ToLower(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func workoutExerciseNames(q querier, workoutID int64) ([]string, error) {
+	rows, err := q.Query(`SELECT exercise_name FROM workout_entries WHERE workout_id = $1`, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func entryExerciseNames(entries []WorkoutEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.ExerciseName)
+	}
+	return names
+}
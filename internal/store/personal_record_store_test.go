@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPersonalRecords(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	candidates := []recordCandidate{
+		{WorkoutID: 1, AchievedAt: day, ExerciseName: "Bench Press", Sets: 3, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(80)},
+		{WorkoutID: 2, AchievedAt: day.AddDate(0, 0, 7), ExerciseName: "bench press", Sets: 3, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(80)},
+		{WorkoutID: 3, AchievedAt: day.AddDate(0, 0, 14), ExerciseName: "Bench Press", Sets: 1, Reps: utils.IntPtr(3), Weight: utils.Float64Ptr(85)},
+	}
+
+	records := detectPersonalRecords(candidates)
+
+	byWorkout := map[int][]string{}
+	for _, record := range records {
+		byWorkout[record.WorkoutID] = append(byWorkout[record.WorkoutID], record.RecordType)
+	}
+
+	assert.ElementsMatch(t, []string{RecordMaxWeight, RecordEstimated1RM, RecordMaxVolume, RecordMaxReps}, byWorkout[1])
+	assert.Empty(t, byWorkout[2], "repeating a session should not set new records")
+	assert.ElementsMatch(t, []string{RecordMaxWeight, RecordMaxReps}, byWorkout[3])
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/plates"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+)
+
+// PlateInventory is the bar and plates a user loads, in the unit the plates
+// are marked in. Unlike other weights it is stored in that unit, so a 45 lb
+// plate stays a round number.
+type PlateInventory struct {
+	Unit      string         `json:"unit"` // kg or lb
+	BarWeight float64        `json:"bar_weight"`
+	Plates    []plates.Plate `json:"plates"`  // heaviest first; count is every plate owned
+	Default   bool           `json:"default"` // nothing saved yet; a standard gym set in the user's units
+	UpdatedAt *time.Time     `json:"updated_at"`
+}
+
+// System is the unit system the inventory's weights are in.
+func (i *PlateInventory) System() units.System {
+	if i.Unit == "lb" {
+		return units.Imperial
+	}
+	return units.Metric
+}
+
+// DefaultPlateInventory is a standard gym set: an Olympic bar with four
+// pairs of the heaviest plate and a pair of each lighter one.
+func DefaultPlateInventory(system units.System) *PlateInventory {
+	if system == units.Imperial {
+		return &PlateInventory{Unit: "lb", BarWeight: 45, Default: true,
+			Plates: []plates.Plate{{Weight: 45, Count: 8}, {Weight: 35, Count: 2}, {Weight: 25, Count: 2}, {Weight: 10, Count: 4}, {Weight: 5, Count: 2}, {Weight: 2.5, Count: 2}}}
+	}
+	return &PlateInventory{Unit: "kg", BarWeight: 20, Default: true,
+		Plates: []plates.Plate{{Weight: 25, Count: 8}, {Weight: 20, Count: 2}, {Weight: 15, Count: 2}, {Weight: 10, Count: 2}, {Weight: 5, Count: 2}, {Weight: 2.5, Count: 2}, {Weight: 1.25, Count: 2}}}
+}
+
+type PostgresPlateStore struct {
+	db *sql.DB
+}
+
+func NewPostgresPlateStore(db *sql.DB) *PostgresPlateStore {
+	return &PostgresPlateStore{db: db}
+}
+
+type PlateStore interface {
+	GetPlateInventory(userID int64) (*PlateInventory, error)
+	SavePlateInventory(userID int64, inventory *PlateInventory) error
+}
+
+// GetPlateInventory returns the user's inventory, or the default one for
+// their units when none has been saved.
+func (s *PostgresPlateStore) GetPlateInventory(userID int64) (*PlateInventory, error) {
+	inventory := &PlateInventory{Plates: []plates.Plate{}}
+	var system string
+	query := `SELECT u.units, p.unit, p.bar_weight, p.updated_at
+		FROM users u
+		LEFT JOIN plate_inventories p ON p.user_id = u.id
+		WHERE u.id = $1`
+	var unit sql.NullString
+	var barWeight sql.NullFloat64
+	err := s.db.QueryRow(query, userID).Scan(&system, &unit, &barWeight, &inventory.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !unit.Valid {
+		return DefaultPlateInventory(units.System(system)), nil
+	}
+	inventory.Unit = unit.String
+	inventory.BarWeight = barWeight.Float64
+
+	rows, err := s.db.Query(`SELECT weight, count FROM plate_inventory_plates WHERE user_id = $1 ORDER BY weight DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		plate := plates.Plate{}
+		if err := rows.Scan(&plate.Weight, &plate.Count); err != nil {
+			return nil, err
+		}
+		inventory.Plates = append(inventory.Plates, plate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// SavePlateInventory validates the inventory and replaces the user's.
+func (s *PostgresPlateStore) SavePlateInventory(userID int64, inventory *PlateInventory) error {
+	if inventory.Unit != "kg" && inventory.Unit != "lb" {
+		return fmt.Errorf("%w: unit must be kg or lb", plates.ErrInvalidInventory)
+	}
+	if inventory.BarWeight < 0 || inventory.BarWeight > 100 {
+		return fmt.Errorf("%w: bar_weight must be between 0 and 100", plates.ErrInvalidInventory)
+	}
+	if inventory.Plates == nil {
+		inventory.Plates = []plates.Plate{}
+	}
+	if err := plates.Validate(inventory.Plates); err != nil {
+		return err
+	}
+	sort.Slice(inventory.Plates, func(i, j int) bool { return inventory.Plates[i].Weight > inventory.Plates[j].Weight })
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO plate_inventories (user_id, unit, bar_weight)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET unit = EXCLUDED.unit, bar_weight = EXCLUDED.bar_weight, updated_at = NOW()
+		RETURNING updated_at`
+	err = tx.QueryRow(query, userID, inventory.Unit, inventory.BarWeight).Scan(&inventory.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM plate_inventory_plates WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	for _, plate := range inventory.Plates {
+		_, err = tx.Exec(`INSERT INTO plate_inventory_plates (user_id, weight, count) VALUES ($1, $2, $3)`, userID, plate.Weight, plate.Count)
+		if err != nil {
+			return err
+		}
+	}
+
+	inventory.Default = false
+	return tx.Commit()
+}
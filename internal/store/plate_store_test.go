@@ -0,0 +1,28 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/mounis-bhat/rest-api-go/internal/plates"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPlateInventory(t *testing.T) {
+	for _, system := range []units.System{units.Metric, units.Imperial} {
+		inventory := DefaultPlateInventory(system)
+		assert.True(t, inventory.Default)
+		assert.Equal(t, system, inventory.System())
+		assert.Equal(t, system.WeightUnit(), inventory.Unit)
+		require.NoError(t, plates.Validate(inventory.Plates))
+
+		// the smallest pair is the plate step the rest of the API rounds to
+		smallest := inventory.Plates[len(inventory.Plates)-1]
+		assert.Equal(t, system.PlateStep(), 2*smallest.Weight)
+	}
+
+	loadout, err := plates.Load(315, 45, DefaultPlateInventory(units.Imperial).Plates)
+	require.NoError(t, err)
+	assert.Equal(t, []plates.Plate{{Weight: 45, Count: 3}}, loadout.PerSide)
+}
@@ -0,0 +1,486 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrSessionTaken is returned when a planned session already has a workout
+// linked to it, finished or still in progress.
+var ErrSessionTaken = errors.New("planned session already has a workout")
+
+const (
+	EnrollmentActive    = "active"
+	EnrollmentCompleted = "completed"
+	EnrollmentCancelled = "cancelled"
+)
+
+type Program struct {
+	ID           int               `json:"id"`
+	UserID       int64             `json:"user_id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Weeks        int               `json:"weeks"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	Days         []ProgramDay      `json:"days"`
+	Progressions []ProgressionRule `json:"progressions"`
+}
+
+type ProgramDay struct {
+	ID         int  `json:"id"`
+	WeekNumber *int `json:"week_number"` // 1-based; nil repeats the day every week
+	DayOfWeek  int  `json:"day_of_week"` // ISO weekday, 1 = Monday
+	TemplateID int  `json:"template_id"`
+}
+
+type ProgressionRule struct {
+	ID              int     `json:"id"`
+	ExerciseName    string  `json:"exercise_name"`
+	WeeklyIncrement float64 `json:"weekly_increment"` // kg added each week after the first
+}
+
+type ProgramEnrollment struct {
+	ID        int       `json:"id"`
+	ProgramID int       `json:"program_id"`
+	UserID    int64     `json:"user_id"`
+	StartDate string    `json:"start_date"` // YYYY-MM-DD
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PlannedSession struct {
+	ID            int        `json:"id"`
+	EnrollmentID  int        `json:"enrollment_id"`
+	UserID        int64      `json:"user_id"`
+	ProgramID     int        `json:"program_id"`
+	ProgramName   string     `json:"program_name"`
+	TemplateID    int        `json:"template_id"`
+	TemplateTitle string     `json:"template_title"`
+	ScheduledDate string     `json:"scheduled_date"` // YYYY-MM-DD in the user's timezone
+	WeekNumber    int        `json:"week_number"`
+	WorkoutID     *int       `json:"workout_id"`
+	CompletedAt   *time.Time `json:"completed_at"` // nil while the workout is still in progress
+}
+
+type Adherence struct {
+	EnrollmentID int     `json:"enrollment_id"`
+	Total        int     `json:"total"`     // every session in the program
+	Due          int     `json:"due"`       // sessions scheduled up to and including today
+	Completed    int     `json:"completed"` // due sessions with a linked workout
+	Missed       int     `json:"missed"`    // past sessions without a linked workout
+	Rate         float64 `json:"rate"`      // completed / due
+}
+
+// ApplyProgression adds the program's weekly increments to matching entries of
+// a workout built for the given 1-based week.
+func (p *Program) ApplyProgression(workout *Workout, week int) {
+	if week <= 1 {
+		return
+	}
+	for i := range workout.Entries {
+		entry := &workout.Entries[i]
+		if entry.Weight == nil {
+			continue
+		}
+		for _, rule := range p.Progressions {
+			if strings.EqualFold(rule.ExerciseName, entry.ExerciseName) {
+				weight := math.Round((*entry.Weight+rule.WeeklyIncrement*float64(week-1))*100) / 100
+				entry.Weight = &weight
+			}
+		}
+	}
+}
+
+// planSessions lays out every session of the program for an enrollment
+// starting on start. Week one begins on the start date and each program day
+// lands on the next matching weekday within its week.
+func planSessions(program *Program, start time.Time) []PlannedSession {
+	startWeekday := int(start.Weekday())
+	if startWeekday == 0 {
+		startWeekday = 7
+	}
+
+	sessions := []PlannedSession{}
+	for week := 1; week <= program.Weeks; week++ {
+		weekStart := start.AddDate(0, 0, (week-1)*7)
+		for _, day := range program.Days {
+			if day.WeekNumber != nil && *day.WeekNumber != week {
+				continue
+			}
+			offset := (day.DayOfWeek - startWeekday + 7) % 7
+			sessions = append(sessions, PlannedSession{
+				TemplateID:    day.TemplateID,
+				ScheduledDate: weekStart.AddDate(0, 0, offset).Format(dateLayout),
+				WeekNumber:    week,
+			})
+		}
+	}
+	return sessions
+}
+
+type PostgresProgramStore struct {
+	db *sql.DB
+}
+
+func NewPostgresProgramStore(db *sql.DB) *PostgresProgramStore {
+	return &PostgresProgramStore{db: db}
+}
+
+type ProgramStore interface {
+	CreateProgram(program *Program) (*Program, error)
+	GetProgramByID(id int64) (*Program, error)
+	GetProgramsForUser(userID int64) ([]*Program, error)
+	DeleteProgram(id int64) error
+	Enroll(program *Program, userID int64, startDate string) (*ProgramEnrollment, error)
+	GetEnrollmentByID(id int64) (*ProgramEnrollment, error)
+	GetSchedule(userID int64, from, to string) ([]*PlannedSession, error)
+	GetPlannedSessionByID(id int64) (*PlannedSession, error)
+	CompletePlannedSession(sessionID int64, workoutID int) error
+	GetAdherence(enrollmentID int64, today string) (*Adherence, error)
+}
+
+func (s *PostgresProgramStore) CreateProgram(program *Program) (*Program, error) {
+	if program.Name == "" {
+		return nil, fmt.Errorf("program name is required")
+	}
+	if program.Weeks <= 0 {
+		return nil, fmt.Errorf("program must span at least one week")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO programs (user_id, name, description, weeks)
+		VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(query, program.UserID, program.Name, program.Description, program.Weeks).Scan(&program.ID, &program.CreatedAt, &program.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range program.Days {
+		day := &program.Days[i]
+		query = `INSERT INTO program_days (program_id, week_number, day_of_week, template_id)
+			VALUES ($1, $2, $3, $4) RETURNING id`
+		err = tx.QueryRow(query, program.ID, day.WeekNumber, day.DayOfWeek, day.TemplateID).Scan(&day.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range program.Progressions {
+		rule := &program.Progressions[i]
+		query = `INSERT INTO program_progressions (program_id, exercise_name, weekly_increment)
+			VALUES ($1, $2, $3) RETURNING id`
+		err = tx.QueryRow(query, program.ID, rule.ExerciseName, rule.WeeklyIncrement).Scan(&rule.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return program, tx.Commit()
+}
+
+func (s *PostgresProgramStore) GetProgramByID(id int64) (*Program, error) {
+	query := `SELECT id, user_id, name, description, weeks, created_at, updated_at
+		FROM programs WHERE id = $1`
+	program := &Program{}
+	err := s.db.QueryRow(query, id).Scan(&program.ID, &program.UserID, &program.Name, &program.Description, &program.Weeks, &program.CreatedAt, &program.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadProgramDetails(program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+func (s *PostgresProgramStore) GetProgramsForUser(userID int64) ([]*Program, error) {
+	query := `SELECT id, user_id, name, description, weeks, created_at, updated_at
+		FROM programs WHERE user_id = $1 ORDER BY name, id`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	programs := []*Program{}
+	for rows.Next() {
+		program := &Program{}
+		err := rows.Scan(&program.ID, &program.UserID, &program.Name, &program.Description, &program.Weeks, &program.CreatedAt, &program.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, program := range programs {
+		if err := s.loadProgramDetails(program); err != nil {
+			return nil, err
+		}
+	}
+	return programs, nil
+}
+
+func (s *PostgresProgramStore) loadProgramDetails(program *Program) error {
+	rows, err := s.db.Query(`SELECT id, week_number, day_of_week, template_id
+		FROM program_days WHERE program_id = $1 ORDER BY week_number NULLS FIRST, day_of_week, id`, program.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	program.Days = []ProgramDay{}
+	for rows.Next() {
+		day := ProgramDay{}
+		if err := rows.Scan(&day.ID, &day.WeekNumber, &day.DayOfWeek, &day.TemplateID); err != nil {
+			return err
+		}
+		program.Days = append(program.Days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	ruleRows, err := s.db.Query(`SELECT id, exercise_name, weekly_increment
+		FROM program_progressions WHERE program_id = $1 ORDER BY id`, program.ID)
+	if err != nil {
+		return err
+	}
+	defer ruleRows.Close()
+
+	program.Progressions = []ProgressionRule{}
+	for ruleRows.Next() {
+		rule := ProgressionRule{}
+		if err := ruleRows.Scan(&rule.ID, &rule.ExerciseName, &rule.WeeklyIncrement); err != nil {
+			return err
+		}
+		program.Progressions = append(program.Progressions, rule)
+	}
+	return ruleRows.Err()
+}
+
+func (s *PostgresProgramStore) DeleteProgram(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM programs WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Enroll starts the program for userID on startDate (YYYY-MM-DD) and
+// materializes every planned session up front so the schedule is stable even
+// if the program's days change later.
+func (s *PostgresProgramStore) Enroll(program *Program, userID int64, startDate string) (*ProgramEnrollment, error) {
+	start, err := time.Parse(dateLayout, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	enrollment := &ProgramEnrollment{
+		ProgramID: program.ID,
+		UserID:    userID,
+		StartDate: startDate,
+		Status:    EnrollmentActive,
+	}
+	query := `INSERT INTO program_enrollments (program_id, user_id, start_date, status)
+		VALUES ($1, $2, $3::date, $4) RETURNING id, created_at`
+	err = tx.QueryRow(query, enrollment.ProgramID, enrollment.UserID, enrollment.StartDate, enrollment.Status).Scan(&enrollment.ID, &enrollment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range planSessions(program, start) {
+		query = `INSERT INTO planned_sessions (enrollment_id, template_id, scheduled_date, week_number)
+			VALUES ($1, $2, $3::date, $4)`
+		_, err = tx.Exec(query, enrollment.ID, session.TemplateID, session.ScheduledDate, session.WeekNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return enrollment, tx.Commit()
+}
+
+func (s *PostgresProgramStore) GetEnrollmentByID(id int64) (*ProgramEnrollment, error) {
+	query := `SELECT id, program_id, user_id, to_char(start_date, 'YYYY-MM-DD'), status, created_at
+		FROM program_enrollments WHERE id = $1`
+	enrollment := &ProgramEnrollment{}
+	err := s.db.QueryRow(query, id).Scan(&enrollment.ID, &enrollment.ProgramID, &enrollment.UserID, &enrollment.StartDate, &enrollment.Status, &enrollment.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+const plannedSessionColumns = `ps.id, ps.enrollment_id, pe.user_id, pe.program_id, p.name, ps.template_id, t.title,
+	to_char(ps.scheduled_date, 'YYYY-MM-DD'), ps.week_number, sw.id, CASE WHEN sw.status = 'completed' THEN ps.completed_at END`
+
+const plannedSessionJoins = `FROM planned_sessions ps
+	INNER JOIN program_enrollments pe ON pe.id = ps.enrollment_id
+	INNER JOIN programs p ON p.id = pe.program_id
+	INNER JOIN workout_templates t ON t.id = ps.template_id
+	LEFT JOIN workouts sw ON sw.id = ps.workout_id AND sw.deleted_at IS NULL`
+
+func scanPlannedSession(row interface{ Scan(dest ...any) error }) (*PlannedSession, error) {
+	session := &PlannedSession{}
+	err := row.Scan(&session.ID, &session.EnrollmentID, &session.UserID, &session.ProgramID, &session.ProgramName, &session.TemplateID, &session.TemplateTitle,
+		&session.ScheduledDate, &session.WeekNumber, &session.WorkoutID, &session.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSchedule returns the user's planned sessions from active and completed
+// enrollments between from and to (inclusive, YYYY-MM-DD).
+func (s *PostgresProgramStore) GetSchedule(userID int64, from, to string) ([]*PlannedSession, error) {
+	query := `SELECT ` + plannedSessionColumns + ` ` + plannedSessionJoins + `
+		WHERE pe.user_id = $1 AND pe.status IN ('active', 'completed') AND ps.scheduled_date BETWEEN $2::date AND $3::date
+		ORDER BY ps.scheduled_date, ps.id`
+	rows, err := s.db.Query(query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*PlannedSession{}
+	for rows.Next() {
+		session, err := scanPlannedSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *PostgresProgramStore) GetPlannedSessionByID(id int64) (*PlannedSession, error) {
+	query := `SELECT ` + plannedSessionColumns + ` ` + plannedSessionJoins + `
+		WHERE ps.id = $1`
+	session, err := scanPlannedSession(s.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CompletePlannedSession links workoutID to a session that has no workout
+// yet. It returns ErrSessionTaken when the session is already taken.
+func (s *PostgresProgramStore) CompletePlannedSession(sessionID int64, workoutID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = lockOpenPlannedSession(tx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	err = linkPlannedSession(tx, sessionID, workoutID)
+	if err != nil {
+		return err
+	}
+
+	err = linkCompletedWorkout(tx, int64(workoutID))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockOpenPlannedSession locks a planned session until the end of the
+// caller's transaction. A session whose workout is in the trash is open
+// again. It returns ErrSessionTaken when the session already has a
+// workout and sql.ErrNoRows when it does not exist.
+func lockOpenPlannedSession(q querier, id int64) error {
+	query := `SELECT NOT EXISTS (SELECT 1 FROM workouts w WHERE w.id = ps.workout_id AND w.deleted_at IS NULL)
+		FROM planned_sessions ps WHERE ps.id = $1
+		FOR UPDATE`
+	var open bool
+	err := q.QueryRow(query, id).Scan(&open)
+	if err != nil {
+		return err
+	}
+	if !open {
+		return ErrSessionTaken
+	}
+	return nil
+}
+
+// linkPlannedSession links a workout to a session. completed_at is only
+// stamped for a completed workout; a live session stamps it when finished.
+func linkPlannedSession(q querier, sessionID int64, workoutID int) error {
+	query := `UPDATE planned_sessions SET workout_id = $1,
+			completed_at = CASE WHEN (SELECT status FROM workouts WHERE id = $1) = 'completed' THEN NOW() END
+		WHERE id = $2`
+	result, err := q.Exec(query, workoutID, sessionID)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+// GetAdherence reports how many sessions due by today (YYYY-MM-DD) were
+// completed. Sessions whose workout is in the trash or still in progress
+// count as not done.
+func (s *PostgresProgramStore) GetAdherence(enrollmentID int64, today string) (*Adherence, error) {
+	query := `SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE ps.scheduled_date <= $2::date),
+			COUNT(*) FILTER (WHERE ps.scheduled_date <= $2::date AND sw.id IS NOT NULL),
+			COUNT(*) FILTER (WHERE ps.scheduled_date < $2::date AND sw.id IS NULL)
+		FROM planned_sessions ps
+		LEFT JOIN workouts sw ON sw.id = ps.workout_id AND sw.deleted_at IS NULL AND sw.status = 'completed'
+		WHERE ps.enrollment_id = $1`
+	adherence := &Adherence{EnrollmentID: int(enrollmentID)}
+	err := s.db.QueryRow(query, enrollmentID, today).Scan(&adherence.Total, &adherence.Due, &adherence.Completed, &adherence.Missed)
+	if err != nil {
+		return nil, err
+	}
+	if adherence.Due > 0 {
+		adherence.Rate = math.Round(float64(adherence.Completed)/float64(adherence.Due)*1000) / 1000
+	}
+	return adherence, nil
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSessions(t *testing.T) {
+	program := &Program{
+		Weeks: 2,
+		Days: []ProgramDay{
+			{DayOfWeek: 1, TemplateID: 1},                              // every Monday
+			{DayOfWeek: 3, TemplateID: 2, WeekNumber: utils.IntPtr(2)}, // Wednesday of week two only
+		},
+	}
+
+	// Enrolling on a Wednesday puts the first Monday at the end of week one.
+	start := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	sessions := planSessions(program, start)
+
+	require.Len(t, sessions, 3)
+	assert.Equal(t, "2025-01-13", sessions[0].ScheduledDate)
+	assert.Equal(t, 1, sessions[0].WeekNumber)
+	assert.Equal(t, "2025-01-20", sessions[1].ScheduledDate)
+	assert.Equal(t, "2025-01-15", sessions[2].ScheduledDate)
+	assert.Equal(t, 2, sessions[2].WeekNumber)
+}
+
+func TestApplyProgression(t *testing.T) {
+	program := &Program{Progressions: []ProgressionRule{{ExerciseName: "Squat", WeeklyIncrement: 2.5}}}
+	workout := &Workout{Entries: []WorkoutEntry{
+		{ExerciseName: "squat", Weight: utils.Float64Ptr(100)},
+		{ExerciseName: "Bench Press", Weight: utils.Float64Ptr(60)},
+	}}
+
+	program.ApplyProgression(workout, 3)
+
+	assert.Equal(t, 105.0, *workout.Entries[0].Weight)
+	assert.Equal(t, 60.0, *workout.Entries[1].Weight)
+}
+
+func TestStartPlannedWorkout(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	workouts := NewPostgresWorkoutStore(db)
+	programs := NewPostgresProgramStore(db)
+	templates := NewPostgresTemplateStore(db)
+	userID := createTestUser(t, db, "planner")
+
+	template, err := templates.CreateTemplate(&WorkoutTemplate{
+		UserID:  userID,
+		Title:   "Leg day",
+		Entries: []TemplateEntry{{ExerciseName: "Squat", TargetSets: 5, TargetReps: utils.IntPtr(5), TargetWeight: utils.Float64Ptr(100)}},
+	})
+	require.NoError(t, err)
+	program, err := programs.CreateProgram(&Program{UserID: userID, Name: "Squat cycle", Weeks: 1, Days: []ProgramDay{{DayOfWeek: 1, TemplateID: template.ID}}})
+	require.NoError(t, err)
+	_, err = programs.Enroll(program, userID, "2025-01-06")
+	require.NoError(t, err)
+
+	sessions, err := programs.GetSchedule(userID, "2025-01-06", "2025-01-12")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	sessionID := int64(sessions[0].ID)
+
+	workout, err := workouts.StartPlannedWorkout(sessionID, template.ToWorkout(userID))
+	require.NoError(t, err)
+	assert.Equal(t, WorkoutActive, workout.Status)
+
+	// the session is taken but only done once the workout is finished
+	session, err := programs.GetPlannedSessionByID(sessionID)
+	require.NoError(t, err)
+	require.NotNil(t, session.WorkoutID)
+	assert.Equal(t, workout.ID, *session.WorkoutID)
+	assert.Nil(t, session.CompletedAt)
+
+	records, err := NewPostgresPersonalRecordStore(db).GetPersonalRecords(userID)
+	require.NoError(t, err)
+	assert.Empty(t, records, "targets are not lifted until the sets are logged")
+
+	// a taken session can neither be started again nor relinked
+	_, err = workouts.StartPlannedWorkout(sessionID, template.ToWorkout(userID))
+	assert.ErrorIs(t, err, ErrSessionTaken)
+	other, err := workouts.CreateWorkout(&Workout{UserID: userID, Title: "Squats"}, userID)
+	require.NoError(t, err)
+	assert.ErrorIs(t, programs.CompletePlannedSession(sessionID, other.ID), ErrSessionTaken)
+
+	all, err := workouts.GetWorkoutsForUser(userID)
+	require.NoError(t, err)
+	assert.Len(t, all, 2, "the rejected start must not leave a workout behind")
+
+	// logging the first set keeps the planned five
+	entry, err := workouts.LogSet(int64(workout.ID), &LoggedSet{ExerciseName: "Squat", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100)}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, entry.Sets)
+	require.Len(t, entry.SetLog, 1)
+
+	_, err = workouts.FinishWorkout(int64(workout.ID), userID)
+	require.NoError(t, err)
+	_, err = workouts.FinishWorkout(int64(workout.ID), userID)
+	assert.ErrorIs(t, err, ErrWorkoutNotActive)
+	session, err = programs.GetPlannedSessionByID(sessionID)
+	require.NoError(t, err)
+	assert.NotNil(t, session.CompletedAt)
+
+	// trashing the linked workout opens the session again
+	require.NoError(t, workouts.DeleteWorkout(int64(workout.ID), userID))
+	require.NoError(t, programs.CompletePlannedSession(sessionID, other.ID))
+
+	_, err = workouts.StartPlannedWorkout(sessionID+1000, template.ToWorkout(userID))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDeleteTemplateUsedByProgram(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	programs := NewPostgresProgramStore(db)
+	templates := NewPostgresTemplateStore(db)
+	userID := createTestUser(t, db, "planner")
+
+	template, err := templates.CreateTemplate(&WorkoutTemplate{UserID: userID, Title: "Leg day"})
+	require.NoError(t, err)
+	program, err := programs.CreateProgram(&Program{UserID: userID, Name: "Squat cycle", Weeks: 2, Days: []ProgramDay{{DayOfWeek: 1, TemplateID: template.ID}}})
+	require.NoError(t, err)
+	_, err = programs.Enroll(program, userID, "2025-01-06")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, templates.DeleteTemplate(int64(template.ID)), ErrTemplateInUse)
+	sessions, err := programs.GetSchedule(userID, "2025-01-06", "2025-01-19")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	require.NoError(t, programs.DeleteProgram(int64(program.ID)))
+	assert.NoError(t, templates.DeleteTemplate(int64(template.ID)))
+
+	// deleting the user still cascades through programs and templates
+	template, err = templates.CreateTemplate(&WorkoutTemplate{UserID: userID, Title: "Leg day"})
+	require.NoError(t, err)
+	_, err = programs.CreateProgram(&Program{UserID: userID, Name: "Squat cycle", Weeks: 1, Days: []ProgramDay{{DayOfWeek: 1, TemplateID: template.ID}}})
+	require.NoError(t, err)
+	_, err = db.Exec(`DELETE FROM users WHERE id = $1`, userID)
+	assert.NoError(t, err)
+}
+
+func TestLinkCompletedWorkout(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	workouts := NewPostgresWorkoutStore(db)
+	programs := NewPostgresProgramStore(db)
+	templates := NewPostgresTemplateStore(db)
+	userID := createTestUser(t, db, "planner")
+
+	template, err := templates.CreateTemplate(&WorkoutTemplate{
+		UserID:  userID,
+		Title:   "Leg day",
+		Entries: []TemplateEntry{{ExerciseName: "Squat", TargetSets: 5, TargetReps: utils.IntPtr(5), TargetWeight: utils.Float64Ptr(100)}},
+	})
+	require.NoError(t, err)
+
+	// two weeks of leg day on today's weekday, starting today
+	today := time.Now().UTC()
+	weekday := int(today.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	program, err := programs.CreateProgram(&Program{UserID: userID, Name: "Squat cycle", Weeks: 2, Days: []ProgramDay{{DayOfWeek: weekday, TemplateID: template.ID}}})
+	require.NoError(t, err)
+	enrollment, err := programs.Enroll(program, userID, today.Format(dateLayout))
+	require.NoError(t, err)
+
+	schedule := func() []*PlannedSession {
+		t.Helper()
+		sessions, err := programs.GetSchedule(userID, today.Format(dateLayout), today.AddDate(0, 0, 13).Format(dateLayout))
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		return sessions
+	}
+
+	// a workout that does not follow the template is not linked
+	_, err = workouts.CreateWorkout(&Workout{UserID: userID, Title: "Run", DurationMinutes: 30}, userID)
+	require.NoError(t, err)
+	assert.Nil(t, schedule()[0].WorkoutID)
+
+	// finishing a live session from the template fulfils today's session
+	started, err := workouts.StartWorkout(template.ToWorkout(userID))
+	require.NoError(t, err)
+	assert.Nil(t, schedule()[0].WorkoutID, "active sessions are not linked yet")
+	_, err = workouts.FinishWorkout(int64(started.ID), userID)
+	require.NoError(t, err)
+
+	sessions := schedule()
+	require.NotNil(t, sessions[0].WorkoutID)
+	assert.Equal(t, started.ID, *sessions[0].WorkoutID)
+	assert.Nil(t, sessions[1].WorkoutID)
+	current, err := programs.GetEnrollmentByID(int64(enrollment.ID))
+	require.NoError(t, err)
+	assert.Equal(t, EnrollmentActive, current.Status)
+
+	// another one today has no open session left to fulfil
+	extra, err := workouts.CreateWorkout(template.ToWorkout(userID), userID)
+	require.NoError(t, err)
+	sessions = schedule()
+	assert.Equal(t, started.ID, *sessions[0].WorkoutID)
+	assert.Nil(t, sessions[1].WorkoutID)
+
+	// completing the last session completes the enrollment
+	require.NoError(t, programs.CompletePlannedSession(int64(sessions[1].ID), extra.ID))
+	current, err = programs.GetEnrollmentByID(int64(enrollment.ID))
+	require.NoError(t, err)
+	assert.Equal(t, EnrollmentCompleted, current.Status)
+	assert.Len(t, schedule(), 2, "completed programs stay on the schedule")
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/progression"
+)
+
+// recommendationSessions is how many recent sessions of an exercise a
+// recommendation is derived from; enough to see the longest deload streak.
+const recommendationSessions = 10
+
+// ProgressionRules are a user's progression rules for one exercise. Exercises
+// without rules of their own use progression.DefaultRules.
+type ProgressionRules struct {
+	ExerciseName string `json:"exercise_name"`
+	progression.Rules
+	Default   bool       `json:"default"` // no rules have been saved for the exercise
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// ExerciseRecommendation is the target for the next session of an exercise
+// with the rules it was derived from.
+type ExerciseRecommendation struct {
+	ExerciseName string `json:"exercise_name"`
+	progression.Recommendation
+	LastSession *time.Time       `json:"last_session"` // nil without history
+	Rules       ProgressionRules `json:"rules"`
+}
+
+type PostgresProgressionStore struct {
+	db *sql.DB
+}
+
+func NewPostgresProgressionStore(db *sql.DB) *PostgresProgressionStore {
+	return &PostgresProgressionStore{db: db}
+}
+
+type ProgressionStore interface {
+	GetRules(userID int64, exerciseName string) (*ProgressionRules, error)
+	SaveRules(userID int64, rules *ProgressionRules) error
+	DeleteRules(userID int64, exerciseName string) error
+	GetRecommendation(userID int64, exerciseName string, step float64) (*ExerciseRecommendation, error)
+}
+
+// GetRules returns the user's rules for the exercise, or the defaults when
+// none have been saved.
+func (s *PostgresProgressionStore) GetRules(userID int64, exerciseName string) (*ProgressionRules, error) {
+	rules := &ProgressionRules{ExerciseName: exerciseName}
+	query := `SELECT scheme, rep_min, rep_max, increment, target_rpe, deload_after, deload_percent, updated_at
+		FROM progression_rules WHERE user_id = $1 AND LOWER(exercise_name) = LOWER($2)`
+	err := s.db.QueryRow(query, userID, exerciseName).Scan(&rules.Scheme, &rules.RepMin, &rules.RepMax, &rules.Increment, &rules.TargetRPE,
+		&rules.DeloadAfter, &rules.DeloadPercent, &rules.UpdatedAt)
+	if err == sql.ErrNoRows {
+		rules.Rules = progression.DefaultRules()
+		rules.Default = true
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRules validates and stores the rules, replacing any the user had for
+// the exercise.
+func (s *PostgresProgressionStore) SaveRules(userID int64, rules *ProgressionRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO progression_rules (user_id, exercise_name, scheme, rep_min, rep_max, increment, target_rpe, deload_after, deload_percent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, LOWER(exercise_name)) DO UPDATE SET
+			scheme = EXCLUDED.scheme, rep_min = EXCLUDED.rep_min, rep_max = EXCLUDED.rep_max, increment = EXCLUDED.increment,
+			target_rpe = EXCLUDED.target_rpe, deload_after = EXCLUDED.deload_after, deload_percent = EXCLUDED.deload_percent,
+			updated_at = NOW()
+		RETURNING updated_at`
+	rules.Default = false
+	return s.db.QueryRow(query, userID, rules.ExerciseName, rules.Scheme, rules.RepMin, rules.RepMax, rules.Increment, rules.TargetRPE,
+		rules.DeloadAfter, rules.DeloadPercent).Scan(&rules.UpdatedAt)
+}
+
+// DeleteRules returns the exercise to the default rules.
+func (s *PostgresProgressionStore) DeleteRules(userID int64, exerciseName string) error {
+	result, err := s.db.Exec(`DELETE FROM progression_rules WHERE user_id = $1 AND LOWER(exercise_name) = LOWER($2)`, userID, exerciseName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetRecommendation recommends the next session of the exercise from the
+// user's recent completed workouts. New weights are rounded to step kg, which
+// is also the increment of the default rules.
+func (s *PostgresProgressionStore) GetRecommendation(userID int64, exerciseName string, step float64) (*ExerciseRecommendation, error) {
+	rules, err := s.GetRules(userID, exerciseName)
+	if err != nil {
+		return nil, err
+	}
+	if rules.Default && step > 0 {
+		rules.Increment = step
+	}
+
+	sessions, err := recentSessions(s.db, userID, exerciseName, recommendationSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &ExerciseRecommendation{
+		ExerciseName:   exerciseName,
+		Recommendation: progression.Recommend(rules.Rules, sessions, step),
+		Rules:          *rules,
+	}
+	if len(sessions) > 0 {
+		rec.LastSession = &sessions[len(sessions)-1].Date
+	}
+	return rec, nil
+}
+
+// recentSessions returns the sets of the exercise in the user's last limit
+// completed workouts with it, oldest first. Logged sets are used when the
+// entry has them; otherwise each of the entry's sets is taken to match its
+// summary.
+func recentSessions(q querier, userID int64, exerciseName string, limit int) ([]progression.Session, error) {
+	query := `WITH recent AS (
+			SELECT DISTINCT w.id, w.created_at
+			FROM workouts w
+			INNER JOIN workout_entries e ON e.workout_id = w.id
+			WHERE w.user_id = $1 AND w.status = 'completed' AND w.deleted_at IS NULL AND LOWER(e.exercise_name) = LOWER($2)
+			ORDER BY w.created_at DESC, w.id DESC
+			LIMIT $3
+		)
+		SELECT r.id, r.created_at, e.id, e.sets, COALESCE(e.reps, 0), COALESCE(e.weight, 0), e.rpe
+		FROM recent r
+		INNER JOIN workout_entries e ON e.workout_id = r.id AND LOWER(e.exercise_name) = LOWER($2)
+		ORDER BY r.created_at, r.id, e.order_index, e.id`
+	rows, err := q.Query(query, userID, exerciseName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type summary struct {
+		session int
+		sets    int
+		set     progression.Set
+	}
+	sessions := []progression.Session{}
+	entries := map[int64]summary{}
+	entryIDs := []int64{}
+	lastWorkout := int64(0)
+	for rows.Next() {
+		var workoutID, entryID int64
+		var date time.Time
+		e := summary{}
+		err := rows.Scan(&workoutID, &date, &entryID, &e.sets, &e.set.Reps, &e.set.Weight, &e.set.RPE)
+		if err != nil {
+			return nil, err
+		}
+		if workoutID != lastWorkout {
+			sessions = append(sessions, progression.Session{Date: date, Sets: []progression.Set{}})
+			lastWorkout = workoutID
+		}
+		e.session = len(sessions) - 1
+		entries[entryID] = e
+		entryIDs = append(entryIDs, entryID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if len(entryIDs) == 0 {
+		return sessions, nil
+	}
+
+	query = `SELECT entry_id, COALESCE(reps, 0), COALESCE(weight, 0), rpe
+		FROM workout_sets WHERE entry_id = ANY($1) AND NOT warmup
+		ORDER BY entry_id, set_number`
+	setRows, err := q.Query(query, entryIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer setRows.Close()
+
+	logged := map[int64][]progression.Set{}
+	for setRows.Next() {
+		var entryID int64
+		set := progression.Set{}
+		if err := setRows.Scan(&entryID, &set.Reps, &set.Weight, &set.RPE); err != nil {
+			return nil, err
+		}
+		logged[entryID] = append(logged[entryID], set)
+	}
+	if err := setRows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, entryID := range entryIDs {
+		e := entries[entryID]
+		session := &sessions[e.session]
+		if sets, ok := logged[entryID]; ok {
+			session.Sets = append(session.Sets, sets...)
+			continue
+		}
+		for range e.sets {
+			session.Sets = append(session.Sets, e.set)
+		}
+	}
+	return sessions, nil
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidRestTarget is wrapped by rest target validation errors.
+var ErrInvalidRestTarget = errors.New("invalid rest target")
+
+const (
+	// DefaultRestSeconds is the rest target of exercises without one.
+	DefaultRestSeconds = 120
+	maxRestSeconds     = 3600
+	// restToleranceSeconds is the least a rest may miss its target by and
+	// still comply; longer targets allow a fifth of the target.
+	restToleranceSeconds = 15
+)
+
+// RestTarget is a user's rest between sets of one exercise. Entries with a
+// target_rest_seconds of their own override it.
+type RestTarget struct {
+	ExerciseName string     `json:"exercise_name"`
+	RestSeconds  int        `json:"rest_seconds"`
+	Default      bool       `json:"default"` // no target has been saved for the exercise
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+// RestCompliance compares timed rests with their targets. A rest complies
+// when it is within a fifth of its target, or 15 seconds for short targets.
+type RestCompliance struct {
+	Rests              int      `json:"rests"` // rests between sets with start and completion times
+	Compliant          int      `json:"compliant"`
+	TooShort           int      `json:"too_short"`
+	TooLong            int      `json:"too_long"`
+	CompliancePercent  *float64 `json:"compliance_percent"`   // nil without timed rests
+	AverageRestSeconds *int     `json:"average_rest_seconds"` // nil without timed rests
+
+	totalSeconds int
+}
+
+type PostgresRestStore struct {
+	db *sql.DB
+}
+
+func NewPostgresRestStore(db *sql.DB) *PostgresRestStore {
+	return &PostgresRestStore{db: db}
+}
+
+type RestStore interface {
+	GetRestTarget(userID int64, exerciseName string) (*RestTarget, error)
+	SaveRestTarget(userID int64, target *RestTarget) error
+	DeleteRestTarget(userID int64, exerciseName string) error
+}
+
+// GetRestTarget returns the user's rest target for the exercise, or the
+// default when none has been saved.
+func (s *PostgresRestStore) GetRestTarget(userID int64, exerciseName string) (*RestTarget, error) {
+	target := &RestTarget{ExerciseName: exerciseName}
+	query := `SELECT rest_seconds, updated_at FROM exercise_rest_targets
+		WHERE user_id = $1 AND LOWER(exercise_name) = LOWER($2)`
+	err := s.db.QueryRow(query, userID, exerciseName).Scan(&target.RestSeconds, &target.UpdatedAt)
+	if err == sql.ErrNoRows {
+		target.RestSeconds = DefaultRestSeconds
+		target.Default = true
+		return target, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// SaveRestTarget validates and stores the target, replacing any the user
+// had for the exercise.
+func (s *PostgresRestStore) SaveRestTarget(userID int64, target *RestTarget) error {
+	if !validRest(&target.RestSeconds) {
+		return fmt.Errorf("%w: rest_seconds must be between 0 and %d", ErrInvalidRestTarget, maxRestSeconds)
+	}
+
+	query := `INSERT INTO exercise_rest_targets (user_id, exercise_name, rest_seconds)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, LOWER(exercise_name)) DO UPDATE SET rest_seconds = EXCLUDED.rest_seconds, updated_at = NOW()
+		RETURNING updated_at`
+	target.Default = false
+	return s.db.QueryRow(query, userID, target.ExerciseName, target.RestSeconds).Scan(&target.UpdatedAt)
+}
+
+// DeleteRestTarget returns the exercise to the default rest target.
+func (s *PostgresRestStore) DeleteRestTarget(userID int64, exerciseName string) error {
+	result, err := s.db.Exec(`DELETE FROM exercise_rest_targets WHERE user_id = $1 AND LOWER(exercise_name) = LOWER($2)`, userID, exerciseName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// validRest reports whether an optional rest is between 0 and an hour.
+func validRest(seconds *int) bool {
+	return seconds == nil || (*seconds >= 0 && *seconds <= maxRestSeconds)
+}
+
+// setRests sets the rest before each of an entry's working sets, ordered by
+// set number: from the previous working set completing to this one starting.
+// Warm-up sets, sets that were not timed and the first working set have none.
+func setRests(sets []WorkoutSet) {
+	var previous *WorkoutSet
+	for i := range sets {
+		set := &sets[i]
+		set.RestSeconds = nil
+		if set.Warmup {
+			continue
+		}
+		if previous != nil && previous.CompletedAt != nil && set.StartedAt != nil {
+			rest := int(math.Round(set.StartedAt.Sub(*previous.CompletedAt).Seconds()))
+			if rest >= 0 {
+				set.RestSeconds = &rest
+			}
+		}
+		previous = set
+	}
+}
+
+// add counts a rest of restSeconds against a target of targetSeconds.
+func (c *RestCompliance) add(restSeconds, targetSeconds int) {
+	tolerance := max(restToleranceSeconds, targetSeconds/5)
+	switch {
+	case restSeconds < targetSeconds-tolerance:
+		c.TooShort++
+	case restSeconds > targetSeconds+tolerance:
+		c.TooLong++
+	default:
+		c.Compliant++
+	}
+	c.Rests++
+	c.totalSeconds += restSeconds
+
+	percent := math.Round(float64(c.Compliant)/float64(c.Rests)*1000) / 10
+	average := int(math.Round(float64(c.totalSeconds) / float64(c.Rests)))
+	c.CompliancePercent = &percent
+	c.AverageRestSeconds = &average
+}
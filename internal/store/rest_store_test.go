@@ -0,0 +1,109 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRests(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	at := func(seconds int) *time.Time {
+		ts := start.Add(time.Duration(seconds) * time.Second)
+		return &ts
+	}
+
+	sets := []WorkoutSet{
+		{SetNumber: 1, Warmup: true}, // generated
+		{SetNumber: 2, Warmup: true, StartedAt: at(-300), CompletedAt: at(-240)},
+		{SetNumber: 3, StartedAt: at(0), CompletedAt: at(30)},
+		{SetNumber: 4, StartedAt: at(120), CompletedAt: at(150)},
+		{SetNumber: 5, CompletedAt: at(400)}, // not timed
+		{SetNumber: 6, StartedAt: at(580), CompletedAt: at(600)},
+	}
+	setRests(sets)
+
+	assert.Nil(t, sets[0].RestSeconds, "warm-ups have no rest")
+	assert.Nil(t, sets[1].RestSeconds, "warm-ups have no rest")
+	assert.Nil(t, sets[2].RestSeconds, "the first working set has no rest, even after a timed warm-up")
+	require.NotNil(t, sets[3].RestSeconds)
+	assert.Equal(t, 90, *sets[3].RestSeconds)
+	assert.Nil(t, sets[4].RestSeconds, "untimed sets have no rest")
+	require.NotNil(t, sets[5].RestSeconds)
+	assert.Equal(t, 180, *sets[5].RestSeconds)
+}
+
+func TestRestCompliance(t *testing.T) {
+	var compliance RestCompliance
+	assert.Nil(t, compliance.CompliancePercent)
+	assert.Nil(t, compliance.AverageRestSeconds)
+
+	compliance.add(60, 90)   // 15s tolerance: too short
+	compliance.add(100, 90)  // within 15s
+	compliance.add(330, 300) // within a fifth of 300
+	compliance.add(400, 300) // too long
+
+	assert.Equal(t, 4, compliance.Rests)
+	assert.Equal(t, 2, compliance.Compliant)
+	assert.Equal(t, 1, compliance.TooShort)
+	assert.Equal(t, 1, compliance.TooLong)
+	require.NotNil(t, compliance.CompliancePercent)
+	assert.Equal(t, 50.0, *compliance.CompliancePercent)
+	require.NotNil(t, compliance.AverageRestSeconds)
+	assert.Equal(t, 223, *compliance.AverageRestSeconds)
+
+	t.Run("warm-ups are left out of the summary", func(t *testing.T) {
+		db := setupTestDb(t)
+		defer db.Close()
+
+		store := NewPostgresWorkoutStore(db)
+		userID := createTestUser(t, db, "rester")
+
+		workout, err := store.StartWorkout(&Workout{UserID: userID, Title: "Bench"})
+		require.NoError(t, err)
+		workoutID := int64(workout.ID)
+		start := time.Now().Add(-time.Hour).Truncate(time.Second)
+		at := func(seconds int) *time.Time {
+			ts := start.Add(time.Duration(seconds) * time.Second)
+			return &ts
+		}
+		entry, err := store.LogSet(workoutID, &LoggedSet{ExerciseName: "Bench Press", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(80), StartedAt: at(0), CompletedAt: at(30)}, userID)
+		require.NoError(t, err)
+		_, err = store.LogSet(workoutID, &LoggedSet{EntryID: &entry.ID, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(80), StartedAt: at(150), CompletedAt: at(180)}, userID)
+		require.NoError(t, err)
+		_, err = store.SetWarmupSets(workoutID, int64(entry.ID), []WorkoutSet{{Reps: utils.IntPtr(10), Weight: utils.Float64Ptr(20)}}, userID)
+		require.NoError(t, err)
+		// warm-ups added before untimed warm-ups were stored kept the time
+		// they were generated at
+		_, err = db.Exec(`UPDATE workout_sets SET completed_at = $1 WHERE entry_id = $2 AND warmup`, at(-20), entry.ID)
+		require.NoError(t, err)
+
+		summary, err := NewPostgresAnalyticsStore(db).GetSummary(AnalyticsQuery{
+			UserID:   userID,
+			Period:   "day",
+			Timezone: "UTC",
+			From:     start.Add(-24 * time.Hour),
+			To:       time.Now().Add(24 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		// only the 120s rest between the working sets counts
+		rests := summary.Totals.RestCompliance
+		assert.Equal(t, 1, rests.Rests)
+		assert.Equal(t, 1, rests.Compliant)
+		require.NotNil(t, rests.AverageRestSeconds)
+		assert.Equal(t, 120, *rests.AverageRestSeconds)
+
+		reloaded, err := store.GetWorkoutById(workoutID)
+		require.NoError(t, err)
+		sets := reloaded.Entries[0].SetLog
+		require.Len(t, sets, 3)
+		assert.Nil(t, sets[0].RestSeconds)
+		assert.Nil(t, sets[1].RestSeconds)
+		require.NotNil(t, sets[2].RestSeconds)
+		assert.Equal(t, 120, *sets[2].RestSeconds)
+	})
+}
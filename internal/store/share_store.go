@@ -0,0 +1,131 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/tokens"
+)
+
+// WorkoutShare is a revocable public link to a workout. Only the token's hash
+// is stored, so Token is set only on the share returned by CreateShare.
+type WorkoutShare struct {
+	ID        int64      `json:"id"`
+	WorkoutID int64      `json:"workout_id"`
+	Token     string     `json:"token,omitempty"`
+	CreatedBy int64      `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type PostgresShareStore struct {
+	db *sql.DB
+}
+
+func NewPostgresShareStore(db *sql.DB) *PostgresShareStore {
+	return &PostgresShareStore{db: db}
+}
+
+type ShareStore interface {
+	CreateShare(workoutID, createdBy int64, expiresAt *time.Time) (*WorkoutShare, error)
+	GetShareByID(id int64) (*WorkoutShare, error)
+	GetSharesForWorkout(workoutID int64) ([]*WorkoutShare, error)
+	RevokeShare(id int64) error
+	GetSharedWorkout(token string) (*Workout, error)
+}
+
+const shareColumns = `id, workout_id, created_by, expires_at, revoked_at, created_at`
+
+func scanShare(row interface{ Scan(dest ...any) error }) (*WorkoutShare, error) {
+	share := &WorkoutShare{}
+	err := row.Scan(&share.ID, &share.WorkoutID, &share.CreatedBy, &share.ExpiresAt, &share.RevokedAt, &share.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (s *PostgresShareStore) CreateShare(workoutID, createdBy int64, expiresAt *time.Time) (*WorkoutShare, error) {
+	plainText, hash, err := tokens.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO workout_shares (workout_id, token_hash, created_by, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + shareColumns
+	share, err := scanShare(s.db.QueryRow(query, workoutID, hash, createdBy, expiresAt))
+	if err != nil {
+		return nil, err
+	}
+	share.Token = plainText
+	return share, nil
+}
+
+func (s *PostgresShareStore) GetShareByID(id int64) (*WorkoutShare, error) {
+	share, err := scanShare(s.db.QueryRow(`SELECT `+shareColumns+` FROM workout_shares WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (s *PostgresShareStore) GetSharesForWorkout(workoutID int64) ([]*WorkoutShare, error) {
+	query := `SELECT ` + shareColumns + ` FROM workout_shares
+		WHERE workout_id = $1
+		ORDER BY created_at DESC, id DESC`
+	rows, err := s.db.Query(query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []*WorkoutShare{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+// RevokeShare disables a share link. Revoking twice keeps the first
+// revocation time.
+func (s *PostgresShareStore) RevokeShare(id int64) error {
+	query := `UPDATE workout_shares SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1`
+	result, err := s.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+// GetSharedWorkout returns the workout behind an active share token, or nil
+// when the token is unknown, revoked or expired.
+func (s *PostgresShareStore) GetSharedWorkout(token string) (*Workout, error) {
+	query := `SELECT ` + qualifiedWorkoutColumns() + `
+		FROM workout_shares s
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE s.token_hash = $1
+		  AND w.deleted_at IS NULL
+		  AND s.revoked_at IS NULL
+		  AND (s.expires_at IS NULL OR s.expires_at > NOW())`
+	workout, err := scanWorkout(s.db.QueryRow(query, tokens.Hash(token)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadWorkoutDetails(s.db, workout); err != nil {
+		return nil, err
+	}
+	return workout, nil
+}
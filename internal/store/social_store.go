@@ -0,0 +1,446 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxCommentLength = 2000
+
+var (
+	ErrBlocked        = errors.New("user is blocked")
+	ErrInvalidComment = errors.New("invalid comment")
+	ErrInvalidCursor  = errors.New("invalid cursor")
+)
+
+type UserSummary struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type FeedItem struct {
+	Workout      *Workout `json:"workout"`
+	Username     string   `json:"username"`
+	LikeCount    int      `json:"like_count"`
+	CommentCount int      `json:"comment_count"`
+	LikedByMe    bool     `json:"liked_by_me"`
+}
+
+// FeedCursor marks the last item of a feed page. The feed is ordered by
+// created_at then id, both descending, so the pair is a stable position.
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+func (c FeedCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixMicro(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeFeedCursor(cursor string) (*FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	workoutID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &FeedCursor{CreatedAt: time.UnixMicro(createdAt).UTC(), ID: workoutID}, nil
+}
+
+type Comment struct {
+	ID        int64     `json:"id"`
+	WorkoutID int64     `json:"workout_id"`
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+	if len([]rune(body)) > maxCommentLength {
+		return "", fmt.Errorf("%w: body must be at most %d characters", ErrInvalidComment, maxCommentLength)
+	}
+	return body, nil
+}
+
+// blockedBetween is a SQL predicate that holds when either user has blocked
+// the other. a and b are SQL expressions, usually placeholders or columns.
+func blockedBetween(a, b string) string {
+	return `EXISTS (SELECT 1 FROM user_blocks ub
+		WHERE (ub.blocker_id = ` + a + ` AND ub.blocked_id = ` + b + `)
+		   OR (ub.blocker_id = ` + b + ` AND ub.blocked_id = ` + a + `))`
+}
+
+// workoutVisibleTo is a SQL predicate over workouts aliased w that holds when
+// the viewer may see the workout: they own it, coach its owner, or it is
+// public or followers-only and they follow the owner. Blocking in either
+// direction hides everything.
+func workoutVisibleTo(viewer string) string {
+	return `(w.user_id = ` + viewer + ` OR (
+		NOT ` + blockedBetween("w.user_id", viewer) + `
+		AND (
+			w.visibility = 'public'
+			OR (w.visibility = 'followers' AND EXISTS (
+				SELECT 1 FROM follows f WHERE f.follower_id = ` + viewer + ` AND f.followee_id = w.user_id))
+			OR EXISTS (
+				SELECT 1 FROM coach_relationships cr
+				WHERE cr.coach_id = ` + viewer + ` AND cr.athlete_id = w.user_id AND cr.status = 'accepted')
+		)
+	))`
+}
+
+// qualifiedWorkoutColumns prefixes workoutColumns with the w alias for
+// queries that join other tables.
+func qualifiedWorkoutColumns() string {
+	columns := strings.Split(workoutColumns, ", ")
+	for i, column := range columns {
+		columns[i] = "w." + column
+	}
+	return strings.Join(columns, ", ")
+}
+
+type PostgresSocialStore struct {
+	db *sql.DB
+}
+
+func NewPostgresSocialStore(db *sql.DB) *PostgresSocialStore {
+	return &PostgresSocialStore{db: db}
+}
+
+type SocialStore interface {
+	Follow(followerID, followeeID int64) error
+	Unfollow(followerID, followeeID int64) error
+	GetFollowers(userID, viewerID int64) ([]UserSummary, error)
+	GetFollowing(userID, viewerID int64) ([]UserSummary, error)
+	Block(blockerID, blockedID int64) error
+	Unblock(blockerID, blockedID int64) error
+	GetBlockedUsers(userID int64) ([]UserSummary, error)
+	IsBlocked(userID, otherID int64) (bool, error)
+	GetFeed(viewerID int64, cursor *FeedCursor, limit int) ([]*FeedItem, error)
+	LikeWorkout(workoutID, userID int64) error
+	UnlikeWorkout(workoutID, userID int64) error
+	GetLikes(workoutID, viewerID int64) ([]UserSummary, error)
+	CreateComment(comment *Comment) (*Comment, error)
+	GetCommentByID(id int64) (*Comment, error)
+	GetComments(workoutID, viewerID int64) ([]*Comment, error)
+	UpdateComment(id int64, body string) (*Comment, error)
+	DeleteComment(id int64) error
+}
+
+func userExists(q querier, userID int64) (bool, error) {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists)
+	return exists, err
+}
+
+func isBlocked(q querier, userID, otherID int64) (bool, error) {
+	var blocked bool
+	err := q.QueryRow(`SELECT `+blockedBetween("$1", "$2"), userID, otherID).Scan(&blocked)
+	return blocked, err
+}
+
+// Follow makes followerID follow followeeID. Following twice is a no-op. It
+// returns sql.ErrNoRows when the followee does not exist and ErrBlocked when
+// either user has blocked the other.
+func (s *PostgresSocialStore) Follow(followerID, followeeID int64) error {
+	exists, err := userExists(s.db, followeeID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	blocked, err := isBlocked(s.db, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+
+	query := `INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+	_, err = s.db.Exec(query, followerID, followeeID)
+	return err
+}
+
+func (s *PostgresSocialStore) Unfollow(followerID, followeeID int64) error {
+	_, err := s.db.Exec(`DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	return err
+}
+
+func (s *PostgresSocialStore) queryUsers(query string, args ...any) ([]UserSummary, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []UserSummary{}
+	for rows.Next() {
+		user := UserSummary{}
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// GetFollowers lists who follows userID, hiding users blocked with viewerID.
+func (s *PostgresSocialStore) GetFollowers(userID, viewerID int64) ([]UserSummary, error) {
+	query := `SELECT u.id, u.username FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1 AND NOT ` + blockedBetween("u.id", "$2") + `
+		ORDER BY f.created_at DESC`
+	return s.queryUsers(query, userID, viewerID)
+}
+
+// GetFollowing lists who userID follows, hiding users blocked with viewerID.
+func (s *PostgresSocialStore) GetFollowing(userID, viewerID int64) ([]UserSummary, error) {
+	query := `SELECT u.id, u.username FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1 AND NOT ` + blockedBetween("u.id", "$2") + `
+		ORDER BY f.created_at DESC`
+	return s.queryUsers(query, userID, viewerID)
+}
+
+// Block records the block and severs follows and coaching relationships
+// between the two users in both directions.
+func (s *PostgresSocialStore) Block(blockerID, blockedID int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists, err := userExists(tx, blockedID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM follows
+		WHERE (follower_id = $1 AND followee_id = $2) OR (follower_id = $2 AND followee_id = $1)`
+	_, err = tx.Exec(query, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM coach_relationships
+		WHERE (coach_id = $1 AND athlete_id = $2) OR (coach_id = $2 AND athlete_id = $1)`
+	_, err = tx.Exec(query, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresSocialStore) Unblock(blockerID, blockedID int64) error {
+	_, err := s.db.Exec(`DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`, blockerID, blockedID)
+	return err
+}
+
+func (s *PostgresSocialStore) GetBlockedUsers(userID int64) ([]UserSummary, error) {
+	query := `SELECT u.id, u.username FROM user_blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC`
+	return s.queryUsers(query, userID)
+}
+
+// IsBlocked reports whether either user has blocked the other.
+func (s *PostgresSocialStore) IsBlocked(userID, otherID int64) (bool, error) {
+	return isBlocked(s.db, userID, otherID)
+}
+
+// GetFeed returns completed public and followers-only workouts from users the
+// viewer follows, newest first, starting after cursor when it is set.
+func (s *PostgresSocialStore) GetFeed(viewerID int64, cursor *FeedCursor, limit int) ([]*FeedItem, error) {
+	var cursorTime *time.Time
+	var cursorID int
+	if cursor != nil {
+		cursorTime = &cursor.CreatedAt
+		cursorID = cursor.ID
+	}
+
+	query := `SELECT ` + qualifiedWorkoutColumns() + `, u.username,
+			(SELECT COUNT(*) FROM workout_likes l
+				WHERE l.workout_id = w.id AND NOT ` + blockedBetween("l.user_id", "$1") + `),
+			(SELECT COUNT(*) FROM workout_comments c
+				WHERE c.workout_id = w.id AND NOT ` + blockedBetween("c.user_id", "$1") + `),
+			EXISTS (SELECT 1 FROM workout_likes l WHERE l.workout_id = w.id AND l.user_id = $1)
+		FROM workouts w
+		JOIN follows f ON f.followee_id = w.user_id AND f.follower_id = $1
+		JOIN users u ON u.id = w.user_id
+		WHERE w.status = 'completed'
+		  AND w.deleted_at IS NULL
+		  AND w.visibility IN ('public', 'followers')
+		  AND NOT ` + blockedBetween("w.user_id", "$1") + `
+		  AND ($2::TIMESTAMPTZ IS NULL OR (w.created_at, w.id) < ($2::TIMESTAMPTZ, $3))
+		ORDER BY w.created_at DESC, w.id DESC
+		LIMIT $4`
+	rows, err := s.db.Query(query, viewerID, cursorTime, cursorID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*FeedItem{}
+	for rows.Next() {
+		workout := &Workout{}
+		item := &FeedItem{Workout: workout}
+		dest := append(workoutDest(workout), &item.Username, &item.LikeCount, &item.CommentCount, &item.LikedByMe)
+		err := rows.Scan(dest...)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if err := loadWorkoutDetails(s.db, item.Workout); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// LikeWorkout records a like. Liking twice is a no-op.
+func (s *PostgresSocialStore) LikeWorkout(workoutID, userID int64) error {
+	_, err := s.db.Exec(`INSERT INTO workout_likes (workout_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, workoutID, userID)
+	return err
+}
+
+func (s *PostgresSocialStore) UnlikeWorkout(workoutID, userID int64) error {
+	_, err := s.db.Exec(`DELETE FROM workout_likes WHERE workout_id = $1 AND user_id = $2`, workoutID, userID)
+	return err
+}
+
+// GetLikes lists who liked the workout, hiding users blocked with viewerID.
+func (s *PostgresSocialStore) GetLikes(workoutID, viewerID int64) ([]UserSummary, error) {
+	query := `SELECT u.id, u.username FROM workout_likes l
+		JOIN users u ON u.id = l.user_id
+		WHERE l.workout_id = $1 AND NOT ` + blockedBetween("u.id", "$2") + `
+		ORDER BY l.created_at DESC`
+	return s.queryUsers(query, workoutID, viewerID)
+}
+
+const commentQuery = `SELECT c.id, c.workout_id, c.user_id, u.username, c.body, c.created_at, c.updated_at
+	FROM workout_comments c
+	JOIN users u ON u.id = c.user_id`
+
+func scanComment(row interface{ Scan(dest ...any) error }) (*Comment, error) {
+	comment := &Comment{}
+	err := row.Scan(&comment.ID, &comment.WorkoutID, &comment.UserID, &comment.Username, &comment.Body, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *PostgresSocialStore) CreateComment(comment *Comment) (*Comment, error) {
+	body, err := validateCommentBody(comment.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO workout_comments (workout_id, user_id, body) VALUES ($1, $2, $3) RETURNING id`
+	var id int64
+	err = s.db.QueryRow(query, comment.WorkoutID, comment.UserID, body).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCommentByID(id)
+}
+
+func (s *PostgresSocialStore) GetCommentByID(id int64) (*Comment, error) {
+	comment, err := scanComment(s.db.QueryRow(commentQuery+` WHERE c.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetComments lists a workout's comments oldest first, hiding comments from
+// users blocked with viewerID.
+func (s *PostgresSocialStore) GetComments(workoutID, viewerID int64) ([]*Comment, error) {
+	query := commentQuery + ` WHERE c.workout_id = $1 AND NOT ` + blockedBetween("c.user_id", "$2") + `
+		ORDER BY c.created_at, c.id`
+	rows, err := s.db.Query(query, workoutID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+func (s *PostgresSocialStore) UpdateComment(id int64, body string) (*Comment, error) {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`UPDATE workout_comments SET body = $2, updated_at = NOW() WHERE id = $1`, id, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireRowsAffected(result); err != nil {
+		return nil, err
+	}
+	return s.GetCommentByID(id)
+}
+
+func (s *PostgresSocialStore) DeleteComment(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM workout_comments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
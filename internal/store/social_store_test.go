@@ -0,0 +1,38 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedCursorRoundTrip(t *testing.T) {
+	cursor := FeedCursor{CreatedAt: time.Date(2025, 3, 14, 9, 26, 53, 589793000, time.UTC), ID: 42}
+
+	decoded, err := DecodeFeedCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeFeedCursorRejectsGarbage(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm9jb2xvbg", "YWJjOjE"} {
+		_, err := DecodeFeedCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestValidateCommentBody(t *testing.T) {
+	body, err := validateCommentBody("  Nice PR!  ")
+	require.NoError(t, err)
+	assert.Equal(t, "Nice PR!", body)
+
+	_, err = validateCommentBody("   ")
+	assert.ErrorIs(t, err, ErrInvalidComment)
+
+	_, err = validateCommentBody(strings.Repeat("a", maxCommentLength+1))
+	assert.ErrorIs(t, err, ErrInvalidComment)
+}
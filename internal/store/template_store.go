@@ -0,0 +1,263 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTemplateInUse is returned when deleting a template that a program or
+// planned session still refers to.
+var ErrTemplateInUse = errors.New("template is used by a program")
+
+type WorkoutTemplate struct {
+	ID          int             `json:"id"`
+	UserID      int64           `json:"user_id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Entries     []TemplateEntry `json:"entries"`
+}
+
+type TemplateEntry struct {
+	ID                    int      `json:"id"`
+	ExerciseName          string   `json:"exercise_name"`
+	TargetSets            int      `json:"target_sets"`
+	TargetReps            *int     `json:"target_reps"`
+	TargetDurationSeconds *int     `json:"target_duration_seconds"`
+	TargetWeight          *float64 `json:"target_weight"` // in kg
+	Notes                 string   `json:"notes"`
+	OrderIndex            int      `json:"order_index"`
+}
+
+// ToWorkout builds an unsaved workout for userID pre-filled with the
+// template's targets.
+func (t *WorkoutTemplate) ToWorkout(userID int64) *Workout {
+	templateID := t.ID
+	workout := &Workout{
+		UserID:      userID,
+		Title:       t.Title,
+		Description: t.Description,
+		TemplateID:  &templateID,
+		Entries:     make([]WorkoutEntry, 0, len(t.Entries)),
+	}
+	for _, entry := range t.Entries {
+		workout.Entries = append(workout.Entries, WorkoutEntry{
+			ExerciseName:    entry.ExerciseName,
+			Sets:            entry.TargetSets,
+			Reps:            entry.TargetReps,
+			DurationSeconds: entry.TargetDurationSeconds,
+			Weight:          entry.TargetWeight,
+			Notes:           entry.Notes,
+			OrderIndex:      entry.OrderIndex,
+		})
+	}
+	return workout
+}
+
+// TemplateFromWorkout builds an unsaved template that repeats workout.
+func TemplateFromWorkout(workout *Workout) *WorkoutTemplate {
+	template := &WorkoutTemplate{
+		UserID:      workout.UserID,
+		Title:       workout.Title,
+		Description: workout.Description,
+		Entries:     make([]TemplateEntry, 0, len(workout.Entries)),
+	}
+	for _, entry := range workout.Entries {
+		template.Entries = append(template.Entries, TemplateEntry{
+			ExerciseName:          entry.ExerciseName,
+			TargetSets:            entry.Sets,
+			TargetReps:            entry.Reps,
+			TargetDurationSeconds: entry.DurationSeconds,
+			TargetWeight:          entry.Weight,
+			Notes:                 entry.Notes,
+			OrderIndex:            entry.OrderIndex,
+		})
+	}
+	return template
+}
+
+type PostgresTemplateStore struct {
+	db *sql.DB
+}
+
+func NewPostgresTemplateStore(db *sql.DB) *PostgresTemplateStore {
+	return &PostgresTemplateStore{db: db}
+}
+
+type TemplateStore interface {
+	CreateTemplate(template *WorkoutTemplate) (*WorkoutTemplate, error)
+	GetTemplateByID(id int64) (*WorkoutTemplate, error)
+	GetTemplatesForUser(userID int64) ([]*WorkoutTemplate, error)
+	UpdateTemplate(template *WorkoutTemplate) error
+	DeleteTemplate(id int64) error
+}
+
+func (s *PostgresTemplateStore) CreateTemplate(template *WorkoutTemplate) (*WorkoutTemplate, error) {
+	if template.Title == "" {
+		return nil, fmt.Errorf("template title is required")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO workout_templates (user_id, title, description)
+		VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(query, template.UserID, template.Title, template.Description).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Entries, err = insertTemplateEntries(tx, template.ID, template.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, tx.Commit()
+}
+
+func (s *PostgresTemplateStore) GetTemplateByID(id int64) (*WorkoutTemplate, error) {
+	query := `SELECT id, user_id, title, description, created_at, updated_at
+		FROM workout_templates WHERE id = $1`
+	template := &WorkoutTemplate{}
+	err := s.db.QueryRow(query, id).Scan(&template.ID, &template.UserID, &template.Title, &template.Description, &template.CreatedAt, &template.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	template.Entries, err = s.getTemplateEntries(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *PostgresTemplateStore) GetTemplatesForUser(userID int64) ([]*WorkoutTemplate, error) {
+	query := `SELECT id, user_id, title, description, created_at, updated_at
+		FROM workout_templates WHERE user_id = $1 ORDER BY title, id`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []*WorkoutTemplate{}
+	for rows.Next() {
+		template := &WorkoutTemplate{}
+		err := rows.Scan(&template.ID, &template.UserID, &template.Title, &template.Description, &template.CreatedAt, &template.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates {
+		template.Entries, err = s.getTemplateEntries(template.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// UpdateTemplate replaces the template's fields and its full list of entries.
+func (s *PostgresTemplateStore) UpdateTemplate(template *WorkoutTemplate) error {
+	if template.Title == "" {
+		return fmt.Errorf("template title is required")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE workout_templates SET title = $1, description = $2, updated_at = NOW()
+		WHERE id = $3 RETURNING user_id, created_at, updated_at`
+	err = tx.QueryRow(query, template.Title, template.Description, template.ID).Scan(&template.UserID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM workout_template_entries WHERE template_id = $1`, template.ID)
+	if err != nil {
+		return err
+	}
+
+	template.Entries, err = insertTemplateEntries(tx, template.ID, template.Entries)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteTemplate deletes a template. Templates used by a program are kept so
+// its planned sessions survive; it returns ErrTemplateInUse for those.
+func (s *PostgresTemplateStore) DeleteTemplate(id int64) error {
+	result, err := s.db.Exec(`DELETE FROM workout_templates WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrTemplateInUse
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *PostgresTemplateStore) getTemplateEntries(templateID int) ([]TemplateEntry, error) {
+	query := `SELECT id, exercise_name, target_sets, target_reps, target_duration_seconds, target_weight, notes, order_index
+		FROM workout_template_entries WHERE template_id = $1 ORDER BY order_index, id`
+	rows, err := s.db.Query(query, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []TemplateEntry{}
+	for rows.Next() {
+		entry := TemplateEntry{}
+		err := rows.Scan(&entry.ID, &entry.ExerciseName, &entry.TargetSets, &entry.TargetReps, &entry.TargetDurationSeconds, &entry.TargetWeight, &entry.Notes, &entry.OrderIndex)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func insertTemplateEntries(q querier, templateID int, entries []TemplateEntry) ([]TemplateEntry, error) {
+	inserted := make([]TemplateEntry, 0, len(entries))
+	for _, entry := range entries {
+		query := `INSERT INTO workout_template_entries (template_id, exercise_name, target_sets, target_reps, target_duration_seconds, target_weight, notes, order_index)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+		err := q.QueryRow(query, templateID, entry.ExerciseName, entry.TargetSets, entry.TargetReps, entry.TargetDurationSeconds, entry.TargetWeight, entry.Notes, entry.OrderIndex).Scan(&entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, entry)
+	}
+	return inserted, nil
+}
@@ -0,0 +1,251 @@
+package store
+
+import "github.com/mounis-bhat/rest-api-go/internal/units"
+
+// Values are stored in metric. Fields without a unit in their name (weight,
+// target_weight, weekly_increment, volumes) are read and written in the
+// caller's unit system by the conversions below; fields that name their
+// unit (weight_kg, distance_meters, pace_seconds_per_km) are always metric.
+// Entries also carry distance and pace_seconds in km or mi so clients need
+// not convert those themselves.
+
+// convertWeight returns a new pointer to value converted by convert.
+func convertWeight(value *float64, convert func(float64) float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	converted := convert(*value)
+	return &converted
+}
+
+// InUnits returns a copy of the workout with its weights, distances and
+// paces in system's units. Weights keep the precision they are stored with,
+// so a workout sent back unchanged keeps its weights. The workout itself is
+// left unchanged since it may be shared, e.g. with a pending event.
+func (w *Workout) InUnits(system units.System) *Workout {
+	if w == nil {
+		return nil
+	}
+	converted := *w
+	converted.Entries = entriesInUnits(w.Entries, system)
+	if w.Groups != nil {
+		converted.Groups = make([]EntryGroup, len(w.Groups))
+		for i, group := range w.Groups {
+			group.Entries = entriesInUnits(group.Entries, system)
+			converted.Groups[i] = group
+		}
+	}
+	return &converted
+}
+
+// InUnits returns a copy of the entry in system's units.
+func (e *WorkoutEntry) InUnits(system units.System) *WorkoutEntry {
+	return &entriesInUnits([]WorkoutEntry{*e}, system)[0]
+}
+
+func entriesInUnits(entries []WorkoutEntry, system units.System) []WorkoutEntry {
+	if entries == nil {
+		return nil
+	}
+	converted := make([]WorkoutEntry, len(entries))
+	for i, entry := range entries {
+		entry.Weight = convertWeight(entry.Weight, system.StoredWeight)
+		if entry.DistanceMeters != nil {
+			distance := units.Round(system.Distance(*entry.DistanceMeters), 2)
+			entry.Distance = &distance
+		}
+		if entry.PaceSecondsPerKm != nil {
+			pace := system.Pace(*entry.PaceSecondsPerKm)
+			entry.PaceSeconds = &pace
+		}
+		if entry.SetLog != nil {
+			sets := make([]WorkoutSet, len(entry.SetLog))
+			for j, set := range entry.SetLog {
+				set.Weight = convertWeight(set.Weight, system.StoredWeight)
+				sets[j] = set
+			}
+			entry.SetLog = sets
+		}
+		converted[i] = entry
+	}
+	return converted
+}
+
+// FromUnits converts a workout written in system's units to metric in
+// place. An entry's distance and pace_seconds fill in distance_meters and
+// pace_seconds_per_km when those are not given.
+func (w *Workout) FromUnits(system units.System) {
+	for i := range w.Entries {
+		entry := &w.Entries[i]
+		entry.Weight = convertWeight(entry.Weight, system.Kilograms)
+		if entry.Distance != nil && entry.DistanceMeters == nil {
+			meters := system.Meters(*entry.Distance)
+			entry.DistanceMeters = &meters
+		}
+		if entry.PaceSeconds != nil && entry.PaceSecondsPerKm == nil {
+			pace := system.SecondsPerKm(*entry.PaceSeconds)
+			entry.PaceSecondsPerKm = &pace
+		}
+		entry.Distance = nil
+		entry.PaceSeconds = nil
+		for j := range entry.SetLog {
+			entry.SetLog[j].Weight = convertWeight(entry.SetLog[j].Weight, system.Kilograms)
+		}
+	}
+}
+
+// FromUnits converts the set's weight from system's unit to kg.
+func (set *LoggedSet) FromUnits(system units.System) {
+	set.Weight = convertWeight(set.Weight, system.Kilograms)
+}
+
+// InUnits returns a copy of the template with target weights in system's
+// unit, rounded to plate increments.
+func (t *WorkoutTemplate) InUnits(system units.System) *WorkoutTemplate {
+	converted := *t
+	if t.Entries != nil {
+		converted.Entries = make([]TemplateEntry, len(t.Entries))
+		for i, entry := range t.Entries {
+			entry.TargetWeight = convertWeight(entry.TargetWeight, system.Plates)
+			converted.Entries[i] = entry
+		}
+	}
+	return &converted
+}
+
+// FromUnits converts the template's target weights to kg in place.
+func (t *WorkoutTemplate) FromUnits(system units.System) {
+	for i := range t.Entries {
+		t.Entries[i].TargetWeight = convertWeight(t.Entries[i].TargetWeight, system.Kilograms)
+	}
+}
+
+// InUnits returns a copy of the program with weekly increments in system's
+// unit.
+func (p *Program) InUnits(system units.System) *Program {
+	converted := *p
+	if p.Progressions != nil {
+		converted.Progressions = make([]ProgressionRule, len(p.Progressions))
+		for i, rule := range p.Progressions {
+			rule.WeeklyIncrement = system.Plates(rule.WeeklyIncrement)
+			converted.Progressions[i] = rule
+		}
+	}
+	return &converted
+}
+
+// FromUnits converts the program's weekly increments to kg in place.
+func (p *Program) FromUnits(system units.System) {
+	for i := range p.Progressions {
+		p.Progressions[i].WeeklyIncrement = system.Kilograms(p.Progressions[i].WeeklyIncrement)
+	}
+}
+
+// InUnits returns a copy of the record in system's units. Weight, estimated
+// one-rep max and volume records are converted; rep and duration records
+// only have their weight converted.
+func (r *PersonalRecord) InUnits(system units.System) *PersonalRecord {
+	converted := *r
+	converted.Weight = convertWeight(r.Weight, system.Plates)
+	switch r.RecordType {
+	case RecordMaxWeight:
+		converted.Value = system.Plates(r.Value)
+	case RecordEstimated1RM, RecordMaxVolume:
+		converted.Value = units.Round(system.Weight(r.Value), 1)
+	}
+	return &converted
+}
+
+// InUnits returns a copy of the bucket with volumes in system's weight unit
+// and distance in km or mi.
+func (b AnalyticsBucket) InUnits(system units.System) AnalyticsBucket {
+	b.TotalVolume = units.Round(system.Weight(b.TotalVolume), 1)
+	b.TotalDistance = units.Round(system.Distance(b.TotalDistanceMeters), 2)
+	groups := make([]MuscleGroupVolume, len(b.MuscleGroups))
+	for i, group := range b.MuscleGroups {
+		group.Volume = units.Round(system.Weight(group.Volume), 1)
+		groups[i] = group
+	}
+	b.MuscleGroups = groups
+	return b
+}
+
+// InUnits returns a copy of the summary in system's units.
+func (s *AnalyticsSummary) InUnits(system units.System) *AnalyticsSummary {
+	converted := &AnalyticsSummary{
+		Totals:  s.Totals.InUnits(system),
+		Buckets: make([]*AnalyticsBucket, len(s.Buckets)),
+	}
+	for i, bucket := range s.Buckets {
+		b := bucket.InUnits(system)
+		converted.Buckets[i] = &b
+	}
+	return converted
+}
+
+// InUnits returns a copy of the point with weights and volume in system's
+// weight unit and distance and pace in km or mi.
+func (point *ExerciseProgressPoint) InUnits(system units.System) *ExerciseProgressPoint {
+	p := *point
+	p.MaxWeight = system.Plates(p.MaxWeight)
+	p.EstimatedOneRM = units.Round(system.Weight(p.EstimatedOneRM), 1)
+	p.TotalVolume = units.Round(system.Weight(p.TotalVolume), 1)
+	p.TotalDistance = units.Round(system.Distance(p.TotalDistanceMeters), 2)
+	if p.BestPaceSecondsPerKm != nil {
+		pace := system.Pace(*p.BestPaceSecondsPerKm)
+		p.BestPaceSeconds = &pace
+	}
+	return &p
+}
+
+// InUnits returns a copy of the goal with distance targets in km or mi and
+// lift targets in system's weight unit, naming the unit used.
+func (g *Goal) InUnits(system units.System) *Goal {
+	converted := *g
+	switch g.Type {
+	case GoalWorkouts:
+		converted.Unit = GoalWorkouts
+	case GoalDistance:
+		converted.Unit = system.DistanceUnit()
+		converted.Target = units.Round(system.Distance(g.Target), 2)
+		converted.Current = units.Round(system.Distance(g.Current), 2)
+	case GoalLift:
+		converted.Unit = system.WeightUnit()
+		converted.Target = system.Plates(g.Target)
+		converted.Current = system.Plates(g.Current)
+	}
+	return &converted
+}
+
+// FromUnits converts the goal's target from system's units to meters or kg
+// in place.
+func (g *Goal) FromUnits(system units.System) {
+	switch g.Type {
+	case GoalDistance:
+		g.Target = system.Meters(g.Target)
+	case GoalLift:
+		g.Target = system.Kilograms(g.Target)
+	}
+}
+
+// InUnits returns a copy of the rules with the increment in system's weight
+// unit.
+func (r *ProgressionRules) InUnits(system units.System) *ProgressionRules {
+	converted := *r
+	converted.Increment = units.Round(system.Weight(r.Increment), 2)
+	return &converted
+}
+
+// FromUnits converts the rules' increment to kg in place.
+func (r *ProgressionRules) FromUnits(system units.System) {
+	r.Increment = system.Kilograms(r.Increment)
+}
+
+// InUnits returns a copy of the recommendation with its weight in system's
+// unit, rounded to plate increments.
+func (r *ExerciseRecommendation) InUnits(system units.System) *ExerciseRecommendation {
+	converted := *r
+	converted.Weight = system.Plates(r.Weight)
+	converted.Rules = *r.Rules.InUnits(system)
+	return &converted
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/mounis-bhat/rest-api-go/internal/progression"
+	"github.com/mounis-bhat/rest-api-go/internal/units"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkoutInUnits(t *testing.T) {
+	group := 0
+	entry := WorkoutEntry{
+		ExerciseName:     "Squat",
+		Weight:           utils.Float64Ptr(100),
+		GroupIndex:       &group,
+		SetLog:           []WorkoutSet{{SetNumber: 1, Weight: utils.Float64Ptr(102.5)}},
+		DistanceMeters:   utils.Float64Ptr(5000),
+		PaceSecondsPerKm: utils.IntPtr(300),
+	}
+	workout := &Workout{
+		Entries: []WorkoutEntry{entry},
+		Groups:  []EntryGroup{{GroupType: GroupSuperset, Entries: []WorkoutEntry{entry}}},
+	}
+
+	converted := workout.InUnits(units.Imperial)
+	require.Len(t, converted.Entries, 1)
+	got := converted.Entries[0]
+	assert.Equal(t, 220.46, *got.Weight)
+	assert.Equal(t, 225.97, *got.SetLog[0].Weight)
+	assert.Equal(t, 3.11, *got.Distance)
+	assert.Equal(t, 483, *got.PaceSeconds)
+	assert.Equal(t, 5000.0, *got.DistanceMeters, "metric fields are kept")
+	assert.Equal(t, 220.46, *converted.Groups[0].Entries[0].Weight)
+
+	assert.Equal(t, 100.0, *workout.Entries[0].Weight, "the original is not modified")
+	assert.Equal(t, 102.5, *workout.Entries[0].SetLog[0].Weight)
+	assert.Nil(t, workout.Entries[0].Distance)
+	assert.Equal(t, 100.0, *workout.Groups[0].Entries[0].Weight)
+
+	assert.Equal(t, 5.0, *workout.InUnits(units.Metric).Entries[0].Distance)
+}
+
+func TestWorkoutFromUnits(t *testing.T) {
+	workout := &Workout{Entries: []WorkoutEntry{
+		{ExerciseName: "Squat", Weight: utils.Float64Ptr(225), SetLog: []WorkoutSet{{Weight: utils.Float64Ptr(135)}}},
+		{ExerciseName: "Running", Distance: utils.Float64Ptr(3.1), PaceSeconds: utils.IntPtr(480)},
+		{ExerciseName: "Rowing", Distance: utils.Float64Ptr(1), DistanceMeters: utils.Float64Ptr(2000)},
+	}}
+	workout.FromUnits(units.Imperial)
+
+	assert.InDelta(t, 102.06, *workout.Entries[0].Weight, 0.01)
+	assert.InDelta(t, 61.23, *workout.Entries[0].SetLog[0].Weight, 0.01)
+	assert.InDelta(t, 4989, *workout.Entries[1].DistanceMeters, 1)
+	assert.Equal(t, 298, *workout.Entries[1].PaceSecondsPerKm)
+	assert.Nil(t, workout.Entries[1].Distance, "derived fields are cleared")
+	assert.Nil(t, workout.Entries[1].PaceSeconds)
+	assert.Equal(t, 2000.0, *workout.Entries[2].DistanceMeters, "distance_meters wins over distance")
+
+	assert.Equal(t, 225.0, *workout.InUnits(units.Imperial).Entries[0].Weight, "entered weights survive the round trip")
+}
+
+func TestWorkoutUnitsRoundTrip(t *testing.T) {
+	stored := []float64{100, 102.3, 102.06, 61.23, 0.01}
+	for _, system := range []units.System{units.Metric, units.Imperial} {
+		t.Run(string(system), func(t *testing.T) {
+			workout := &Workout{}
+			for _, weight := range stored {
+				workout.Entries = append(workout.Entries, WorkoutEntry{
+					Weight: utils.Float64Ptr(weight),
+					SetLog: []WorkoutSet{{Weight: utils.Float64Ptr(weight)}},
+				})
+			}
+
+			// a GET sent straight back stores the same weights
+			sent := workout.InUnits(system)
+			sent.FromUnits(system)
+			for i, weight := range stored {
+				assert.Equal(t, weight, units.Round(*sent.Entries[i].Weight, 2))
+				assert.Equal(t, weight, units.Round(*sent.Entries[i].SetLog[0].Weight, 2))
+			}
+		})
+	}
+}
+
+func TestPersonalRecordInUnits(t *testing.T) {
+	tests := []struct {
+		record PersonalRecord
+		want   float64
+	}{
+		{PersonalRecord{RecordType: RecordMaxWeight, Value: 100, Weight: utils.Float64Ptr(100)}, 220.5},
+		{PersonalRecord{RecordType: RecordMaxVolume, Value: 1000, Weight: utils.Float64Ptr(100)}, 2204.6},
+		{PersonalRecord{RecordType: RecordMaxReps, Value: 12, Weight: utils.Float64Ptr(100)}, 12},
+		{PersonalRecord{RecordType: RecordMaxDuration, Value: 90}, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.record.RecordType, func(t *testing.T) {
+			converted := tt.record.InUnits(units.Imperial)
+			assert.Equal(t, tt.want, converted.Value)
+			if tt.record.Weight != nil {
+				assert.Equal(t, 220.5, *converted.Weight)
+			}
+		})
+	}
+}
+
+func TestGoalInUnits(t *testing.T) {
+	bench := "Bench Press"
+	lift := &Goal{Type: GoalLift, ExerciseName: &bench, Target: 100, Current: 92.5}
+	converted := lift.InUnits(units.Imperial)
+	assert.Equal(t, "lb", converted.Unit)
+	assert.Equal(t, 220.5, converted.Target)
+	assert.Equal(t, 204.0, converted.Current)
+	assert.Equal(t, 100.0, lift.Target, "the original is not modified")
+
+	run := &Goal{Type: GoalDistance, Target: 50000, Current: 12345}
+	converted = run.InUnits(units.Metric)
+	assert.Equal(t, "km", converted.Unit)
+	assert.Equal(t, 50.0, converted.Target)
+	assert.Equal(t, 12.35, converted.Current)
+
+	assert.Equal(t, GoalWorkouts, (&Goal{Type: GoalWorkouts, Target: 4}).InUnits(units.Imperial).Unit)
+
+	run = &Goal{Type: GoalDistance, Target: 10}
+	run.FromUnits(units.Imperial)
+	assert.InDelta(t, 16093.44, run.Target, 0.01)
+}
+
+func TestExerciseRecommendationInUnits(t *testing.T) {
+	rec := &ExerciseRecommendation{ExerciseName: "Squat", Rules: ProgressionRules{Rules: progression.DefaultRules()}}
+	rec.Weight = units.Imperial.Kilograms(225)
+	rec.Rules.Increment = units.Imperial.Kilograms(5)
+
+	converted := rec.InUnits(units.Imperial)
+	assert.Equal(t, 225.0, converted.Weight)
+	assert.Equal(t, 5.0, converted.Rules.Increment)
+	assert.InDelta(t, 102.06, rec.Weight, 0.01, "the original is not modified")
+	assert.InDelta(t, 2.27, rec.Rules.Increment, 0.01)
+}
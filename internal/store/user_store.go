@@ -36,8 +36,35 @@ type User struct {
 	Username     string    `json:"username"`
 	Email        string    `json:"email"`
 	PasswordHash password  `json:"-"`
+	Timezone     string    `json:"timezone"` // IANA time zone name, e.g. Europe/Berlin
+	Units        string    `json:"units"`    // metric or imperial; values are stored metric either way
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	UserProfile
+}
+
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+// UserProfile holds the optional physiological details used for heart-rate
+// zones and calorie estimates. On update, nil keeps the current value.
+type UserProfile struct {
+	BirthYear        *int     `json:"birth_year"`
+	Sex              *string  `json:"sex"`       // male or female
+	WeightKg         *float64 `json:"weight_kg"` // body weight in kg
+	RestingHeartRate *int     `json:"resting_heart_rate"`
+	MaxHeartRate     *int     `json:"max_heart_rate"` // when nil, estimated from age
+}
+
+// Age returns the user's age in the year of at, or false when the birth
+// year is unknown.
+func (p UserProfile) Age(at time.Time) (int, bool) {
+	if p.BirthYear == nil {
+		return 0, false
+	}
+	return at.Year() - *p.BirthYear, true
 }
 
 var AnonymousUser = &User{}
@@ -46,6 +73,17 @@ func (u *User) IsAnonymous() bool {
 	return u == AnonymousUser
 }
 
+// userColumns are the users columns scanned by userDest; they are
+// qualified so they can be selected from joins.
+const userColumns = `u.id, u.username, u.email, u.password_hash, u.timezone, u.units,
+	u.birth_year, u.sex, u.weight_kg, u.resting_heart_rate, u.max_heart_rate, u.created_at, u.updated_at`
+
+// userDest returns scan destinations matching userColumns.
+func userDest(user *User) []any {
+	return []any{&user.ID, &user.Username, &user.Email, &user.PasswordHash.hash, &user.Timezone, &user.Units,
+		&user.BirthYear, &user.Sex, &user.WeightKg, &user.RestingHeartRate, &user.MaxHeartRate, &user.CreatedAt, &user.UpdatedAt}
+}
+
 type PostgresUserStore struct {
 	db *sql.DB
 }
@@ -66,7 +104,7 @@ type UserStore interface {
 func (s *PostgresUserStore) GetUserToken(scope, tokenPlaintext string) (*User, error) {
 	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
 
-	query := `SELECT u.id, u.username, u.email, u.password_hash, u.created_at, u.updated_at
+	query := `SELECT ` + userColumns + `
 		FROM users u
 		INNER JOIN tokens t ON u.id = t.user_id
 		WHERE t.scope = $1 AND t.hash = $2 AND t.expiry > $3`
@@ -75,14 +113,7 @@ func (s *PostgresUserStore) GetUserToken(scope, tokenPlaintext string) (*User, e
 		PasswordHash: password{},
 	}
 
-	err := s.db.QueryRow(query, scope, tokenHash[:], time.Now()).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.PasswordHash.hash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	err := s.db.QueryRow(query, scope, tokenHash[:], time.Now()).Scan(userDest(user)...)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -101,10 +132,18 @@ func (s *PostgresUserStore) CreateUser(user *User) (*User, error) {
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO users (username, email, password_hash)
-		VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
+	if user.Timezone == "" {
+		user.Timezone = "UTC"
+	}
+	if user.Units == "" {
+		user.Units = "metric"
+	}
+
+	query := `INSERT INTO users (username, email, password_hash, timezone, birth_year, sex, weight_kg, resting_heart_rate, max_heart_rate, units)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
 
-	err = tx.QueryRow(query, user.Username, user.Email, user.PasswordHash.hash).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	err = tx.QueryRow(query, user.Username, user.Email, user.PasswordHash.hash, user.Timezone,
+		user.BirthYear, user.Sex, user.WeightKg, user.RestingHeartRate, user.MaxHeartRate, user.Units).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -118,19 +157,12 @@ func (s *PostgresUserStore) GetUserByUsername(username string) (*User, error) {
 	}
 
 	query := `
-  		SELECT id, username, email, password_hash, created_at, updated_at
-  		FROM users
+  		SELECT ` + userColumns + `
+  		FROM users u
   		WHERE username = $1
   	`
 
-	err := s.db.QueryRow(query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.PasswordHash.hash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	err := s.db.QueryRow(query, username).Scan(userDest(user)...)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -161,8 +193,15 @@ func (s *PostgresUserStore) UpdateUser(user *User) error {
 	defer tx.Rollback()
 
 	query := `UPDATE users SET username = $1, email = $2, password_hash = $3,
-		updated_at = NOW() WHERE id = $4`
-	_, err = tx.Exec(query, user.Username, user.Email, user.PasswordHash.hash, user.ID)
+			timezone = COALESCE(NULLIF($4, ''), timezone), units = COALESCE(NULLIF($11, ''), units),
+			birth_year = COALESCE($6, birth_year), sex = COALESCE($7, sex), weight_kg = COALESCE($8, weight_kg),
+			resting_heart_rate = COALESCE($9, resting_heart_rate), max_heart_rate = COALESCE($10, max_heart_rate),
+			updated_at = NOW()
+		WHERE id = $5
+		RETURNING timezone, units, birth_year, sex, weight_kg, resting_heart_rate, max_heart_rate`
+	err = tx.QueryRow(query, user.Username, user.Email, user.PasswordHash.hash, user.Timezone, user.ID,
+		user.BirthYear, user.Sex, user.WeightKg, user.RestingHeartRate, user.MaxHeartRate, user.Units).Scan(
+		&user.Timezone, &user.Units, &user.BirthYear, &user.Sex, &user.WeightKg, &user.RestingHeartRate, &user.MaxHeartRate)
 	if err != nil {
 		return err
 	}
@@ -190,8 +229,8 @@ func (s *PostgresUserStore) DeleteUser(id int64) error {
 	return tx.Commit()
 }
 func (s *PostgresUserStore) GetAllUsers() ([]*User, error) {
-	query := `SELECT id, username, email, password_hash, created_at, updated_at
-		FROM users`
+	query := `SELECT ` + userColumns + `
+		FROM users u`
 	rows, err := s.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -202,7 +241,7 @@ func (s *PostgresUserStore) GetAllUsers() ([]*User, error) {
 		user := &User{
 			PasswordHash: password{},
 		}
-		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash.hash, &user.CreatedAt, &user.UpdatedAt)
+		err := rows.Scan(userDest(user)...)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"math"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+	"github.com/mounis-bhat/rest-api-go/internal/sensor"
+)
+
+const (
+	// CaloriesSourceHeartRate marks calories estimated from heart-rate
+	// samples and the owner's profile.
+	CaloriesSourceHeartRate = "heart_rate"
+	// CaloriesSourceMET marks calories estimated from the entries' MET
+	// values and durations and the owner's weight.
+	CaloriesSourceMET = "met"
+)
+
+// SetMETTable replaces the table used to estimate calories from entries.
+func (s *PostgresWorkoutStore) SetMETTable(table *fitness.METTable) {
+	s.mets = table
+}
+
+// reestimateCalories replaces the workout's calories with an estimate unless
+// the client supplied them, updating both the row and workout. Heart-rate
+// samples give the better estimate and are preferred; otherwise the entries
+// are costed with MET values. Both use the owner's body weight when the
+// workout started. It reports whether the calories changed.
+func (s *PostgresWorkoutStore) reestimateCalories(q querier, workout *Workout) (bool, error) {
+	if workout.CaloriesBurned > 0 && workout.CaloriesSource == nil {
+		return false, nil
+	}
+
+	profile, err := loadUserProfile(q, workout.UserID)
+	if err != nil {
+		return false, err
+	}
+	at := workout.CreatedAt
+	if workout.StartedAt != nil {
+		at = *workout.StartedAt
+	}
+	// the latest measured weight is more current than the profile's
+	profile.WeightKg, err = bodyWeightAt(q, workout.UserID, at)
+	if err != nil {
+		return false, err
+	}
+
+	samples, err := loadSamples(q, int64(workout.ID))
+	if err != nil {
+		return false, err
+	}
+	source := CaloriesSourceHeartRate
+	calories := heartRateCalories(profile, at, samples)
+	if calories == 0 {
+		entries, err := loadMETEntries(q, int64(workout.ID))
+		if err != nil {
+			return false, err
+		}
+		weightKg := float64(fitness.ReferenceWeightKg)
+		if profile.WeightKg != nil {
+			weightKg = *profile.WeightKg
+		}
+		source = CaloriesSourceMET
+		calories = metCalories(s.mets, entries, workout.DurationMinutes, weightKg)
+	}
+	if calories == 0 || (calories == workout.CaloriesBurned && workout.CaloriesSource != nil && *workout.CaloriesSource == source) {
+		return false, nil
+	}
+
+	query := `UPDATE workouts SET calories_burned = $1, calories_source = $2, updated_at = NOW() WHERE id = $3`
+	_, err = q.Exec(query, calories, source, workout.ID)
+	if err != nil {
+		return false, err
+	}
+	workout.CaloriesBurned = calories
+	workout.CaloriesSource = &source
+	return true, nil
+}
+
+// heartRateCalories estimates the calories burned over the heart-rate
+// samples from the user's weight, age and sex. It returns 0 when there are
+// no heart-rate samples or the profile lacks a weight or birth year.
+func heartRateCalories(profile UserProfile, at time.Time, samples []sensor.Sample) int {
+	summary := sensor.Summarize(samples, nil)
+	if summary.HeartRate == nil || summary.HeartRate.RecordedSeconds == 0 {
+		return 0
+	}
+	age, ok := profile.Age(at)
+	if !ok || profile.WeightKg == nil {
+		return 0
+	}
+	sex := ""
+	if profile.Sex != nil {
+		sex = *profile.Sex
+	}
+	minutes := float64(summary.HeartRate.RecordedSeconds) / 60
+	return fitness.CaloriesFromHeartRate(summary.HeartRate.Average, minutes, *profile.WeightKg, age, sex)
+}
+
+// metEntry is an entry with the category of its exercise, empty when the
+// exercise is not in the library, and the number of sets logged for it.
+type metEntry struct {
+	WorkoutEntry
+	Category   string
+	LoggedSets int
+}
+
+func loadMETEntries(q querier, workoutID int64) ([]metEntry, error) {
+	query := `SELECT e.exercise_name, COALESCE(x.category, ''), e.sets, e.duration_seconds, e.distance_meters, e.pace_seconds_per_km,
+			e.interval_repeats, e.interval_work_seconds, e.interval_work_meters, e.interval_rest_seconds,
+			(SELECT COUNT(*) FROM workout_sets s WHERE s.entry_id = e.id)
+		FROM workout_entries e
+		LEFT JOIN exercises x ON LOWER(x.name) = LOWER(e.exercise_name)
+		WHERE e.workout_id = $1`
+	rows, err := q.Query(query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []metEntry{}
+	for rows.Next() {
+		entry := metEntry{}
+		var repeats, workSeconds, restSeconds *int
+		var workMeters *float64
+		err := rows.Scan(&entry.ExerciseName, &entry.Category, &entry.Sets, &entry.DurationSeconds, &entry.DistanceMeters, &entry.PaceSecondsPerKm,
+			&repeats, &workSeconds, &workMeters, &restSeconds, &entry.LoggedSets)
+		if err != nil {
+			return nil, err
+		}
+		entry.Intervals = entryIntervals(repeats, workSeconds, workMeters, restSeconds)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// metCalories costs each entry at its MET for the time it took. When no
+// entry has a known or inferable duration, the whole workout is costed at
+// the table's default MET for its duration.
+func metCalories(table *fitness.METTable, entries []metEntry, durationMinutes int, weightKg float64) int {
+	total := 0.0
+	seconds := 0
+	for _, entry := range entries {
+		entrySeconds, speedKmh := entryActivity(table, entry)
+		met := table.Lookup(entry.ExerciseName, entry.Category, speedKmh)
+		total += fitness.CaloriesFromMET(met, weightKg, entrySeconds)
+		seconds += entrySeconds
+	}
+	if seconds == 0 {
+		total = fitness.CaloriesFromMET(table.Default, weightKg, durationMinutes*60)
+	}
+	return int(math.Round(total))
+}
+
+// entryActivity returns how long an entry took and, when it can be told, the
+// speed in km/h. Intervals count only their work bouts. Strength sets
+// without a duration are assumed to take the table's set time, which
+// includes the rest after them.
+func entryActivity(table *fitness.METTable, entry metEntry) (int, float64) {
+	sets := max(entry.Sets, entry.LoggedSets, 1)
+	seconds := 0
+	meters := 0.0
+	switch {
+	case entry.Intervals != nil:
+		work := entry.Intervals
+		if work.WorkSeconds != nil {
+			seconds = work.Repeats * *work.WorkSeconds
+		} else if work.WorkMeters != nil && entry.PaceSecondsPerKm != nil {
+			seconds = int(float64(work.Repeats) * *work.WorkMeters * float64(*entry.PaceSecondsPerKm) / 1000)
+		}
+		if work.WorkMeters != nil {
+			meters = float64(work.Repeats) * *work.WorkMeters
+		}
+	case entry.DurationSeconds != nil:
+		seconds = *entry.DurationSeconds * sets
+	case entry.DistanceMeters != nil && entry.PaceSecondsPerKm != nil:
+		seconds = int(*entry.DistanceMeters * float64(*entry.PaceSecondsPerKm) / 1000)
+	case entry.Category != "cardio" && entry.DistanceMeters == nil:
+		seconds = table.SetSeconds * sets
+	}
+	if entry.Intervals == nil && entry.DistanceMeters != nil {
+		meters = *entry.DistanceMeters
+	}
+
+	speedKmh := 0.0
+	if entry.PaceSecondsPerKm != nil && *entry.PaceSecondsPerKm > 0 {
+		speedKmh = 3600 / float64(*entry.PaceSecondsPerKm)
+	} else if meters > 0 && seconds > 0 {
+		speedKmh = meters / 1000 / (float64(seconds) / 3600)
+	}
+	return seconds, speedKmh
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMETCalories(t *testing.T) {
+	table := fitness.DefaultMETTable()
+	strength := func(name string, sets int) metEntry {
+		return metEntry{WorkoutEntry: WorkoutEntry{ExerciseName: name, Sets: sets, Reps: utils.IntPtr(5)}, Category: "strength"}
+	}
+
+	tests := []struct {
+		name     string
+		entries  []metEntry
+		duration int
+		want     int
+	}{
+		{name: "strength sets take the set time", entries: []metEntry{strength("Squat", 5)}, want: 80},
+		{name: "logged sets beyond the plan count", entries: []metEntry{{WorkoutEntry: strength("Bench Press", 3).WorkoutEntry, Category: "strength", LoggedSets: 5}}, want: 67},
+		{
+			name:    "timed sets",
+			entries: []metEntry{{WorkoutEntry: WorkoutEntry{ExerciseName: "Plank", Sets: 3, DurationSeconds: utils.IntPtr(60)}, Category: "strength"}},
+			want:    15,
+		},
+		{
+			name: "running pace picks the MET",
+			entries: []metEntry{{WorkoutEntry: WorkoutEntry{ExerciseName: "Running", Sets: 1, DistanceMeters: utils.Float64Ptr(5000),
+				DurationSeconds: utils.IntPtr(1500), PaceSecondsPerKm: utils.IntPtr(300)}, Category: "cardio"}},
+			want: 381,
+		},
+		{
+			name: "intervals count their work",
+			entries: []metEntry{{WorkoutEntry: WorkoutEntry{ExerciseName: "Rowing",
+				Intervals: &Intervals{Repeats: 8, WorkSeconds: utils.IntPtr(120), WorkMeters: utils.Float64Ptr(500), RestSeconds: 90}}, Category: "cardio"}},
+			want: 149,
+		},
+		{name: "mixed", entries: []metEntry{strength("Squat", 5), strength("Deadlift", 5)}, want: 160},
+		{name: "no entries falls back to the workout duration", duration: 45, want: 300},
+		{
+			name:     "cardio without a duration falls back too",
+			entries:  []metEntry{{WorkoutEntry: WorkoutEntry{ExerciseName: "Running", DistanceMeters: utils.Float64Ptr(5000)}, Category: "cardio"}},
+			duration: 30,
+			want:     200,
+		},
+		{name: "nothing to go on", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, metCalories(table, tt.entries, tt.duration, 80))
+		})
+	}
+}
+
+func TestUpdateWorkoutKeepsEstimatedCalories(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	store := NewPostgresWorkoutStore(db)
+	userID := createTestUser(t, db, "runner")
+
+	created, err := store.CreateWorkout(&Workout{
+		UserID:          userID,
+		Title:           "Easy run",
+		DurationMinutes: 30,
+		Entries:         []WorkoutEntry{{ExerciseName: "Running", Sets: 1, DurationSeconds: utils.IntPtr(1800)}},
+	}, userID)
+	require.NoError(t, err)
+
+	reload := func() *Workout {
+		t.Helper()
+		workout, err := store.GetWorkoutById(int64(created.ID))
+		require.NoError(t, err)
+		return workout
+	}
+	estimated := reload()
+	require.NotNil(t, estimated.CaloriesSource)
+	assert.Equal(t, CaloriesSourceMET, *estimated.CaloriesSource)
+	require.Positive(t, estimated.CaloriesBurned)
+
+	// a GET sent straight back stays estimated, with or without its source
+	require.NoError(t, store.UpdateWorkout(reload(), userID))
+	workout := reload()
+	assert.Equal(t, estimated.CaloriesBurned, workout.CaloriesBurned)
+	require.NotNil(t, workout.CaloriesSource)
+
+	workout.CaloriesSource = nil
+	require.NoError(t, store.UpdateWorkout(workout, userID))
+	workout = reload()
+	require.NotNil(t, workout.CaloriesSource)
+	assert.Equal(t, CaloriesSourceMET, *workout.CaloriesSource)
+
+	// so longer entries are estimated again
+	workout.Entries[0].DurationSeconds = utils.IntPtr(3600)
+	require.NoError(t, store.UpdateWorkout(workout, userID))
+	workout = reload()
+	require.NotNil(t, workout.CaloriesSource)
+	assert.Greater(t, workout.CaloriesBurned, estimated.CaloriesBurned)
+
+	// a new value is the client's and is kept from then on, even when the
+	// edited GET still carries the old calories_source
+	require.NotNil(t, workout.CaloriesSource)
+	workout.CaloriesBurned = 999
+	require.NoError(t, store.UpdateWorkout(workout, userID))
+	workout = reload()
+	assert.Nil(t, workout.CaloriesSource)
+	assert.Equal(t, 999, workout.CaloriesBurned)
+
+	workout.Entries[0].DurationSeconds = utils.IntPtr(1200)
+	require.NoError(t, store.UpdateWorkout(workout, userID))
+	workout = reload()
+	assert.Nil(t, workout.CaloriesSource)
+	assert.Equal(t, 999, workout.CaloriesBurned)
+}
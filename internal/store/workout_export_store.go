@@ -0,0 +1,44 @@
+package store
+
+import (
+	"time"
+)
+
+// exportBatchSize bounds how many workouts an export holds in memory at once.
+const exportBatchSize = 100
+
+// ExportWorkouts passes each of the user's workouts created in [from, to),
+// oldest first and with full details, to emit. Either bound may be nil.
+// Workouts are read in keyset-paginated batches so memory use does not grow
+// with the size of the training log. An error from emit stops the export.
+func (s *PostgresWorkoutStore) ExportWorkouts(userID int64, from, to *time.Time, emit func(*Workout) error) error {
+	query := `SELECT ` + workoutColumns + `
+		FROM workouts
+		WHERE user_id = $1 AND deleted_at IS NULL
+		  AND ($2::TIMESTAMPTZ IS NULL OR created_at >= $2)
+		  AND ($3::TIMESTAMPTZ IS NULL OR created_at < $3)
+		  AND ($4::TIMESTAMPTZ IS NULL OR (created_at, id) > ($4::TIMESTAMPTZ, $5))
+		ORDER BY created_at, id
+		LIMIT $6`
+
+	var afterTime *time.Time
+	var afterID int
+	for {
+		batch, err := s.queryWorkouts(query, userID, from, to, afterTime, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, workout := range batch {
+			if err := emit(workout); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		afterTime, afterID = &last.CreatedAt, last.ID
+	}
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/mounis-bhat/rest-api-go/internal/sensor"
+)
+
+func validateImportedWorkout(workout *Workout) error {
+	if workout.Title == "" {
+		return fmt.Errorf("%w: workout title is required", ErrInvalidWorkout)
+	}
+	if workout.StartedAt == nil {
+		return fmt.Errorf("%w: workout %q has no start time", ErrInvalidWorkout, workout.Title)
+	}
+	if err := validateEntries(workout); err != nil {
+		return err
+	}
+	if err := validateEntryGroups(workout); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(workout.Tags)
+	if err != nil {
+		return err
+	}
+	workout.Tags = tags
+	return nil
+}
+
+// importedWorkoutExists reports whether the user already has a workout with
+// the same title and start time, which means it came from an earlier import
+// of the same data.
+func importedWorkoutExists(q querier, workout *Workout) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM workouts
+		WHERE user_id = $1 AND title = $2 AND started_at = $3 AND deleted_at IS NULL)`
+	err := q.QueryRow(query, workout.UserID, workout.Title, workout.StartedAt).Scan(&exists)
+	return exists, err
+}
+
+// insertImportedWorkout stores a completed workout with its original start
+// and end times, entries, logged sets and sensor samples, estimating its
+// calories when they were not recorded. Personal records are left for the
+// caller to recompute.
+func (s *PostgresWorkoutStore) insertImportedWorkout(q querier, workout *Workout, samples []sensor.Sample, actorID int64) error {
+	workout.Status = WorkoutCompleted
+	workout.CaloriesSource = nil
+	if workout.Visibility == "" {
+		workout.Visibility = VisibilityPrivate
+	}
+	query := `INSERT INTO workouts (user_id, title, description, duration_minutes, calories_burned, calories_source, visibility, status, started_at, ended_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $9) RETURNING id, created_at, updated_at`
+	err := q.QueryRow(query, workout.UserID, workout.Title, workout.Description, workout.DurationMinutes, workout.CaloriesBurned, workout.CaloriesSource,
+		workout.Visibility, workout.Status, workout.StartedAt, workout.EndedAt).Scan(&workout.ID, &workout.CreatedAt, &workout.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	groupIDs, err := insertEntryGroups(q, workout)
+	if err != nil {
+		return err
+	}
+	err = insertEntries(q, workout, groupIDs)
+	if err != nil {
+		return err
+	}
+	for _, entry := range workout.Entries {
+		for _, set := range entry.SetLog {
+			query := `INSERT INTO workout_sets (entry_id, set_number, reps, weight, duration_seconds, rpe, warmup, started_at, completed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+			_, err := q.Exec(query, entry.ID, set.SetNumber, set.Reps, set.Weight, set.DurationSeconds, set.RPE, set.Warmup, set.StartedAt, set.CompletedAt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(samples) > 0 {
+		err = insertSamples(q, int64(workout.ID), samples)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = s.reestimateCalories(q, workout)
+	if err != nil {
+		return err
+	}
+
+	err = setWorkoutTags(q, workout)
+	if err != nil {
+		return err
+	}
+	err = refreshSearchVector(q, workout.ID)
+	if err != nil {
+		return err
+	}
+	return recordRevision(q, int64(workout.ID), RevisionCreate, &actorID)
+}
+
+// ImportWorkouts stores completed workouts brought in from another app, with
+// their original start and end times and logged sets, in a single
+// transaction: either every workout is stored or none is. A workout with the
+// same title and start time as one the user already has is assumed to come
+// from an earlier import of the same file and is skipped; the second result
+// counts those.
+func (s *PostgresWorkoutStore) ImportWorkouts(userID int64, workouts []*Workout, actorID int64) ([]*Workout, int, error) {
+	for _, workout := range workouts {
+		workout.UserID = userID
+		if err := validateImportedWorkout(workout); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	imported := []*Workout{}
+	skipped := 0
+	names := []string{}
+	for _, workout := range workouts {
+		exists, err := importedWorkoutExists(tx, workout)
+		if err != nil {
+			return nil, 0, err
+		}
+		if exists {
+			skipped++
+			continue
+		}
+
+		workout.Visibility = VisibilityPrivate
+		if err := s.insertImportedWorkout(tx, workout, nil, actorID); err != nil {
+			return nil, 0, err
+		}
+		names = append(names, entryExerciseNames(workout.Entries)...)
+		imported = append(imported, workout)
+	}
+
+	// records are recomputed once for the whole import rather than per workout
+	err = recomputePersonalRecords(tx, userID, names)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = refreshGoals(tx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+	return imported, skipped, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportWorkouts(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	store := NewPostgresWorkoutStore(db)
+	userID := createTestUser(t, db, "importer")
+
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	imported := func(title string, day int, weight float64) *Workout {
+		startedAt := start.AddDate(0, 0, day)
+		endedAt := startedAt.Add(time.Hour)
+		sets := []WorkoutSet{}
+		for i := 1; i <= 3; i++ {
+			completedAt := startedAt.Add(time.Duration(i) * 5 * time.Minute)
+			sets = append(sets, WorkoutSet{SetNumber: i, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(weight), CompletedAt: &completedAt})
+		}
+		return &Workout{
+			Title:           title,
+			DurationMinutes: 60,
+			StartedAt:       &startedAt,
+			EndedAt:         &endedAt,
+			Entries:         []WorkoutEntry{{ExerciseName: "Deadlift", Sets: 3, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(weight), SetLog: sets}},
+		}
+	}
+	stored := func() []*Workout {
+		t.Helper()
+		workouts, err := store.GetWorkoutsForUser(userID)
+		require.NoError(t, err)
+		return workouts
+	}
+
+	// a row failing validation stops the import before anything is written
+	missingTitle := imported("", 2, 100)
+	_, _, err := store.ImportWorkouts(userID, []*Workout{imported("Pull A", 0, 140), imported("Pull B", 1, 145), missingTitle}, userID)
+	assert.ErrorIs(t, err, ErrInvalidWorkout)
+	assert.Empty(t, stored())
+
+	// a row the database rejects rolls back the rows inserted before it
+	tooHeavy := imported("Pull C", 2, 5000)
+	_, _, err = store.ImportWorkouts(userID, []*Workout{imported("Pull A", 0, 140), imported("Pull B", 1, 145), tooHeavy}, userID)
+	require.Error(t, err)
+	assert.Empty(t, stored())
+	var revisions int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM workout_revisions`).Scan(&revisions))
+	assert.Zero(t, revisions)
+
+	workouts, skipped, err := store.ImportWorkouts(userID, []*Workout{imported("Pull A", 0, 140), imported("Pull B", 1, 145)}, userID)
+	require.NoError(t, err)
+	assert.Len(t, workouts, 2)
+	assert.Zero(t, skipped)
+
+	all := stored()
+	require.Len(t, all, 2)
+	for _, workout := range all {
+		assert.Equal(t, WorkoutCompleted, workout.Status)
+		assert.Equal(t, VisibilityPrivate, workout.Visibility)
+		full, err := store.GetWorkoutById(int64(workout.ID))
+		require.NoError(t, err)
+		require.Len(t, full.Entries, 1)
+		assert.Len(t, full.Entries[0].SetLog, 3)
+	}
+
+	// importing the same file again only counts duplicates
+	workouts, skipped, err = store.ImportWorkouts(userID, []*Workout{imported("Pull A", 0, 140), imported("Pull B", 1, 145)}, userID)
+	require.NoError(t, err)
+	assert.Empty(t, workouts)
+	assert.Equal(t, 2, skipped)
+	assert.Len(t, stored(), 2)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// StartPlannedWorkout starts workout as the live session for a planned
+// session and links the two in the same transaction, so concurrent starts
+// cannot both succeed. The session counts as done once the workout is
+// finished. It returns ErrSessionTaken when the session already has a
+// workout and sql.ErrNoRows when the session does not exist.
+func (s *PostgresWorkoutStore) StartPlannedWorkout(sessionID int64, workout *Workout) (*Workout, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = lockOpenPlannedSession(tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = startWorkout(tx, workout)
+	if err != nil {
+		return nil, err
+	}
+
+	err = linkPlannedSession(tx, sessionID, workout.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return workout, tx.Commit()
+}
+
+// validateWorkoutTemplate rejects a template_id that is not one of the
+// workout owner's templates.
+func validateWorkoutTemplate(q querier, workout *Workout) error {
+	if workout.TemplateID == nil {
+		return nil
+	}
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM workout_templates WHERE id = $1 AND user_id = $2)`
+	err := q.QueryRow(query, *workout.TemplateID, workout.UserID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: template %d not found", ErrInvalidWorkout, *workout.TemplateID)
+	}
+	return nil
+}
+
+// linkCompletedWorkout links a completed workout that follows a template to
+// the user's open planned session for that template on the day it was done,
+// in the user's time zone, unless the workout already fulfils a session. The
+// session's enrollment is then completed once none of its sessions from that
+// day on are left open; earlier ones stay missed.
+func linkCompletedWorkout(q querier, workoutID int64) error {
+	// sessions started from the schedule were linked when the workout started
+	query := `UPDATE planned_sessions SET completed_at = NOW()
+		WHERE workout_id = $1 AND completed_at IS NULL
+			AND EXISTS (SELECT 1 FROM workouts WHERE id = $1 AND status = 'completed')`
+	_, err := q.Exec(query, workoutID)
+	if err != nil {
+		return err
+	}
+
+	query = `SELECT ps.id FROM workouts w
+		INNER JOIN users u ON u.id = w.user_id
+		INNER JOIN program_enrollments pe ON pe.user_id = w.user_id AND pe.status = 'active'
+		INNER JOIN planned_sessions ps ON ps.enrollment_id = pe.id AND ps.template_id = w.template_id
+		WHERE w.id = $1 AND w.status = 'completed' AND w.deleted_at IS NULL
+			AND ps.scheduled_date = (COALESCE(w.started_at, w.created_at) AT TIME ZONE u.timezone)::date
+			AND NOT EXISTS (SELECT 1 FROM workouts sw WHERE sw.id = ps.workout_id AND sw.deleted_at IS NULL)
+			AND NOT EXISTS (SELECT 1 FROM planned_sessions linked WHERE linked.workout_id = w.id)
+		ORDER BY ps.id
+		LIMIT 1
+		FOR UPDATE OF ps`
+	var sessionID int64
+	err = q.QueryRow(query, workoutID).Scan(&sessionID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		if err := linkPlannedSession(q, sessionID, int(workoutID)); err != nil {
+			return err
+		}
+	}
+
+	query = `UPDATE program_enrollments pe SET status = 'completed'
+		FROM planned_sessions done
+		INNER JOIN workouts w ON w.id = done.workout_id
+		WHERE done.workout_id = $1 AND w.status = 'completed' AND pe.id = done.enrollment_id AND pe.status = 'active'
+			AND NOT EXISTS (
+				SELECT 1 FROM planned_sessions ps
+				WHERE ps.enrollment_id = pe.id AND ps.scheduled_date >= done.scheduled_date
+					AND NOT EXISTS (SELECT 1 FROM workouts sw WHERE sw.id = ps.workout_id AND sw.deleted_at IS NULL AND sw.status = 'completed')
+			)`
+	_, err = q.Exec(query, workoutID)
+	return err
+}
@@ -0,0 +1,393 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+const (
+	// RevisionBaseline captures a workout created before revisions were
+	// recorded, just before its first tracked change.
+	RevisionBaseline = "baseline"
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionDelete   = "delete"
+	RevisionRestore  = "restore"
+	RevisionRevert   = "revert"
+)
+
+// WorkoutRevision is an immutable record of one change to a workout. Diff
+// compares the workout with the previous revision; ActorID is nil for
+// changes made by background jobs.
+type WorkoutRevision struct {
+	ID            int64           `json:"id"`
+	WorkoutID     int64           `json:"workout_id"`
+	Revision      int             `json:"revision"`
+	Action        string          `json:"action"`
+	ActorID       *int64          `json:"actor_id"`
+	ActorUsername *string         `json:"actor_username"`
+	Diff          json.RawMessage `json:"diff" swaggertype:"object"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// revisionIgnoredFields change on every write or never change, so they are
+// left out of diffs.
+var revisionIgnoredFields = map[string]bool{"id": true, "user_id": true, "created_at": true, "updated_at": true}
+
+// revisionSnapshot loads the workout as stored, trashed or not. Groups are
+// stored without their nested entries since entries are kept in the flat
+// list.
+func revisionSnapshot(q querier, workoutID int64) (*Workout, error) {
+	query := `SELECT ` + workoutColumns + ` FROM workouts WHERE id = $1`
+	workout, err := scanWorkout(q.QueryRow(query, workoutID))
+	if err != nil {
+		return nil, err
+	}
+	if err := loadWorkoutDetails(q, workout); err != nil {
+		return nil, err
+	}
+	for i := range workout.Groups {
+		workout.Groups[i].Entries = nil
+	}
+	return workout, nil
+}
+
+// recordRevision stores the workout's current state as its next revision,
+// along with a diff against the previous one. It must run in the transaction
+// that made the change so the two can never disagree.
+func recordRevision(q querier, workoutID int64, action string, actorID *int64) error {
+	current, err := revisionSnapshot(q, workoutID)
+	if err != nil {
+		return err
+	}
+
+	var previous *Workout
+	var raw []byte
+	query := `SELECT snapshot FROM workout_revisions WHERE workout_id = $1 ORDER BY revision DESC LIMIT 1`
+	err = q.QueryRow(query, workoutID).Scan(&raw)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		previous = &Workout{}
+		if err := json.Unmarshal(raw, previous); err != nil {
+			return err
+		}
+	}
+
+	diff, err := diffSnapshots(previous, current)
+	if err != nil {
+		return err
+	}
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO workout_revisions (workout_id, user_id, revision, action, actor_id, snapshot, diff)
+		VALUES ($1, (SELECT user_id FROM workouts WHERE id = $1),
+			(SELECT COALESCE(MAX(revision), 0) + 1 FROM workout_revisions WHERE workout_id = $1), $2, $3, $4, $5)`
+	_, err = q.Exec(query, workoutID, action, actorID, snapshotJSON, diffJSON)
+	return err
+}
+
+// ensureBaselineRevision locks the workout row so revisions are numbered in
+// commit order and, for workouts that predate revision tracking, records
+// their state before the first tracked change. A missing workout is left for
+// the caller's own query to report.
+func ensureBaselineRevision(q querier, workoutID int64) error {
+	var hasRevisions bool
+	query := `SELECT EXISTS (SELECT 1 FROM workout_revisions r WHERE r.workout_id = w.id)
+		FROM workouts w WHERE w.id = $1 FOR UPDATE`
+	err := q.QueryRow(query, workoutID).Scan(&hasRevisions)
+	if err == sql.ErrNoRows || hasRevisions {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return recordRevision(q, workoutID, RevisionBaseline, nil)
+}
+
+// diffSnapshots compares two workout states field by field. Entries and
+// groups are matched by ID and reported as added, removed or changed. A nil
+// before diffs against an empty workout.
+func diffSnapshots(before, after *Workout) (map[string]any, error) {
+	beforeFields, err := snapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := snapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]any{}
+	for _, key := range unionKeys(beforeFields, afterFields) {
+		if revisionIgnoredFields[key] {
+			continue
+		}
+		if key == "entries" || key == "groups" {
+			if change := diffByID(beforeFields[key], afterFields[key]); change != nil {
+				diff[key] = change
+			}
+			continue
+		}
+		if !reflect.DeepEqual(beforeFields[key], afterFields[key]) {
+			diff[key] = FieldChange{From: beforeFields[key], To: afterFields[key]}
+		}
+	}
+	return diff, nil
+}
+
+func snapshotFields(workout *Workout) (map[string]any, error) {
+	fields := map[string]any{}
+	if workout == nil {
+		return fields, nil
+	}
+	raw, err := json.Marshal(workout)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffByID compares two JSON arrays of objects keyed by "id", returning nil
+// when they are the same.
+func diffByID(before, after any) map[string]any {
+	beforeItems := itemsByID(before)
+	afterItems := itemsByID(after)
+
+	added := []any{}
+	removed := []any{}
+	changed := []map[string]any{}
+	for _, item := range listItems(after) {
+		id := item["id"]
+		previous, ok := beforeItems[id]
+		if !ok {
+			added = append(added, item)
+			continue
+		}
+		changes := map[string]FieldChange{}
+		for _, key := range unionKeys(previous, item) {
+			if key == "id" || key == "entries" {
+				continue
+			}
+			if !reflect.DeepEqual(previous[key], item[key]) {
+				changes[key] = FieldChange{From: previous[key], To: item[key]}
+			}
+		}
+		if len(changes) > 0 {
+			changed = append(changed, map[string]any{"id": id, "changes": changes})
+		}
+	}
+	for _, item := range listItems(before) {
+		if _, ok := afterItems[item["id"]]; !ok {
+			removed = append(removed, item)
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 && len(changed) == 0 {
+		return nil
+	}
+	diff := map[string]any{}
+	if len(added) > 0 {
+		diff["added"] = added
+	}
+	if len(removed) > 0 {
+		diff["removed"] = removed
+	}
+	if len(changed) > 0 {
+		diff["changed"] = changed
+	}
+	return diff
+}
+
+func listItems(value any) []map[string]any {
+	list, _ := value.([]any)
+	items := make([]map[string]any, 0, len(list))
+	for _, element := range list {
+		if item, ok := element.(map[string]any); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func itemsByID(value any) map[any]map[string]any {
+	items := map[any]map[string]any{}
+	for _, item := range listItems(value) {
+		items[item["id"]] = item
+	}
+	return items
+}
+
+// GetRevisions returns the workout's revisions, newest first.
+func (s *PostgresWorkoutStore) GetRevisions(workoutID int64) ([]*WorkoutRevision, error) {
+	query := `SELECT r.id, r.workout_id, r.revision, r.action, r.actor_id, u.username, r.diff, r.created_at
+		FROM workout_revisions r
+		LEFT JOIN users u ON u.id = r.actor_id
+		WHERE r.workout_id = $1
+		ORDER BY r.revision DESC`
+	rows, err := s.db.Query(query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []*WorkoutRevision{}
+	for rows.Next() {
+		revision := &WorkoutRevision{}
+		var diff []byte
+		err := rows.Scan(&revision.ID, &revision.WorkoutID, &revision.Revision, &revision.Action, &revision.ActorID, &revision.ActorUsername, &diff, &revision.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		revision.Diff = diff
+		revisions = append(revisions, revision)
+	}
+	return revisions, rows.Err()
+}
+
+// RevertWorkout restores the title, description, duration, calories,
+// visibility, tags, groups and entries (with their logged sets) captured in
+// a revision. Session status and timestamps are left alone. The revert is
+// itself recorded as a new revision, so it can be undone too. It returns
+// sql.ErrNoRows when the workout does not exist or is trashed and
+// ErrRevisionNotFound for an unknown revision.
+func (s *PostgresWorkoutStore) RevertWorkout(id int64, revision int, actorID int64) (*Workout, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRow(`SELECT user_id FROM workouts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	err = tx.QueryRow(`SELECT snapshot FROM workout_revisions WHERE workout_id = $1 AND revision = $2`, id, revision).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	target := &Workout{}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return nil, err
+	}
+	target.ID = int(id)
+	target.UserID = userID
+
+	previousNames, err := workoutExerciseNames(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE workouts SET title = $1, description = $2, duration_minutes = $3, calories_burned = $4, calories_source = $5, visibility = $6, updated_at = NOW()
+		WHERE id = $7`
+	_, err = tx.Exec(query, target.Title, target.Description, target.DurationMinutes, target.CaloriesBurned, target.CaloriesSource, target.Visibility, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// entries are recreated, which also removes their logged sets
+	_, err = tx.Exec(`DELETE FROM workout_entries WHERE workout_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`DELETE FROM workout_entry_groups WHERE workout_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs, err := insertEntryGroups(tx, target)
+	if err != nil {
+		return nil, err
+	}
+	err = insertEntries(tx, target, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range target.Entries {
+		for _, set := range entry.SetLog {
+			query := `INSERT INTO workout_sets (entry_id, set_number, reps, weight, duration_seconds, rpe, warmup, started_at, completed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+			_, err := tx.Exec(query, entry.ID, set.SetNumber, set.Reps, set.Weight, set.DurationSeconds, set.RPE, set.Warmup, set.StartedAt, set.CompletedAt)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if target.Tags == nil {
+		target.Tags = []string{}
+	}
+	err = setWorkoutTags(tx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	err = refreshSearchVector(tx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recomputePersonalRecords(tx, userID, append(previousNames, entryExerciseNames(target.Entries)...))
+	if err != nil {
+		return nil, err
+	}
+
+	err = refreshGoals(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordRevision(tx, id, RevisionRevert, &actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetWorkoutById(id)
+}
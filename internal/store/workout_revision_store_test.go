@@ -0,0 +1,129 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	before := &Workout{
+		ID:         1,
+		Title:      "Leg Day",
+		Visibility: VisibilityPrivate,
+		Tags:       []string{"legs"},
+		Entries: []WorkoutEntry{
+			{ID: 10, ExerciseName: "Squat", Sets: 5, Reps: utils.IntPtr(5)},
+			{ID: 11, ExerciseName: "Lunge", Sets: 3, Reps: utils.IntPtr(10)},
+		},
+	}
+	after := &Workout{
+		ID:         1,
+		Title:      "Heavy Leg Day",
+		Visibility: VisibilityPrivate,
+		Tags:       []string{"legs"},
+		Entries: []WorkoutEntry{
+			{ID: 10, ExerciseName: "Squat", Sets: 5, Reps: utils.IntPtr(3)},
+			{ID: 12, ExerciseName: "Leg Press", Sets: 3, Reps: utils.IntPtr(12)},
+		},
+	}
+
+	diff, err := diffSnapshots(before, after)
+	require.NoError(t, err)
+
+	assert.Equal(t, FieldChange{From: "Leg Day", To: "Heavy Leg Day"}, diff["title"])
+	assert.NotContains(t, diff, "visibility")
+	assert.NotContains(t, diff, "tags")
+	assert.NotContains(t, diff, "updated_at")
+
+	entries := diff["entries"].(map[string]any)
+	require.Len(t, entries["added"], 1)
+	require.Len(t, entries["removed"], 1)
+	changed := entries["changed"].([]map[string]any)
+	require.Len(t, changed, 1)
+	assert.Equal(t, float64(10), changed[0]["id"])
+	assert.Equal(t, map[string]FieldChange{"reps": {From: float64(5), To: float64(3)}}, changed[0]["changes"])
+}
+
+func TestDiffSnapshotsUnchanged(t *testing.T) {
+	workout := &Workout{Title: "Rest", Entries: []WorkoutEntry{{ID: 1, ExerciseName: "Walk"}}}
+
+	diff, err := diffSnapshots(workout, workout)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffSnapshotsCreate(t *testing.T) {
+	diff, err := diffSnapshots(nil, &Workout{Title: "Run", Entries: []WorkoutEntry{{ID: 1, ExerciseName: "Run"}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, FieldChange{From: nil, To: "Run"}, diff["title"])
+	entries := diff["entries"].(map[string]any)
+	assert.Len(t, entries["added"], 1)
+	assert.NotContains(t, entries, "removed")
+}
+
+func TestRevertWorkout(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	store := NewPostgresWorkoutStore(db)
+	ownerID := createTestUser(t, db, "athlete")
+	coachID := createTestUser(t, db, "coach")
+
+	workout, err := store.StartWorkout(&Workout{UserID: ownerID, Title: "Leg day", Tags: []string{"legs"}})
+	require.NoError(t, err)
+	id := int64(workout.ID)
+	_, err = store.LogSet(id, &LoggedSet{ExerciseName: "Squat", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100)}, ownerID)
+	require.NoError(t, err)
+	_, err = store.LogSet(id, &LoggedSet{ExerciseName: "Squat", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(105)}, ownerID)
+	require.NoError(t, err)
+
+	edited, err := store.GetWorkoutById(id)
+	require.NoError(t, err)
+	edited.Title = "Leg day (edited)"
+	edited.Tags = []string{"edited"}
+	edited.Entries[0].Weight = utils.Float64Ptr(90)
+	edited.Entries[0].Notes = "Too heavy"
+	require.NoError(t, store.UpdateWorkout(edited, coachID))
+
+	// back to the workout as it was after the first set
+	reverted, err := store.RevertWorkout(id, 2, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, "Leg day", reverted.Title)
+	assert.Equal(t, []string{"legs"}, reverted.Tags)
+	require.Len(t, reverted.Entries, 1)
+	entry := reverted.Entries[0]
+	assert.Equal(t, "Squat", entry.ExerciseName)
+	assert.Empty(t, entry.Notes)
+	require.NotNil(t, entry.Weight)
+	assert.Equal(t, 100.0, *entry.Weight)
+	require.Len(t, entry.SetLog, 1)
+	assert.Equal(t, 1, entry.SetLog[0].SetNumber)
+	assert.Equal(t, 100.0, *entry.SetLog[0].Weight)
+
+	revisions, err := store.GetRevisions(id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 5)
+	want := []struct {
+		action  string
+		actorID int64
+	}{
+		{RevisionRevert, ownerID},
+		{RevisionUpdate, coachID},
+		{RevisionUpdate, ownerID},
+		{RevisionUpdate, ownerID},
+		{RevisionCreate, ownerID},
+	}
+	for i, revision := range revisions {
+		assert.Equal(t, len(revisions)-i, revision.Revision)
+		assert.Equal(t, want[i].action, revision.Action)
+		require.NotNil(t, revision.ActorID)
+		assert.Equal(t, want[i].actorID, *revision.ActorID)
+	}
+
+	_, err = store.RevertWorkout(id, 9, ownerID)
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
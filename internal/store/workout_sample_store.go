@@ -0,0 +1,147 @@
+package store
+
+import (
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+	"github.com/mounis-bhat/rest-api-go/internal/sensor"
+)
+
+// WorkoutSamples is a workout's sensor samples, ordered by time, with the
+// heart-rate zones of its owner. Zones is nil when the owner has neither a
+// maximum heart rate nor a birth year in their profile.
+type WorkoutSamples struct {
+	Samples []sensor.Sample
+	Zones   []fitness.HeartRateZone
+}
+
+// AddSamples stores sensor samples for a workout, replacing earlier samples
+// recorded at the same time so uploads can be retried. Unless the client
+// supplied the workout's calories, they are re-estimated from the heart-rate
+// samples, and a revision is recorded when the estimate changes. It returns
+// the number of samples stored.
+func (s *PostgresWorkoutStore) AddSamples(workoutID int64, samples []sensor.Sample, actorID int64) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	err = ensureBaselineRevision(tx, workoutID)
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT ` + workoutColumns + ` FROM workouts WHERE id = $1 AND deleted_at IS NULL`
+	workout, err := scanWorkout(tx.QueryRow(query, workoutID))
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertSamples(tx, workoutID, samples)
+	if err != nil {
+		return 0, err
+	}
+
+	changed, err := s.reestimateCalories(tx, workout)
+	if err != nil {
+		return 0, err
+	}
+	if changed {
+		err = recordRevision(tx, workoutID, RevisionUpdate, &actorID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(samples), tx.Commit()
+}
+
+// insertSamples upserts samples, which must have distinct times, in a single
+// statement.
+func insertSamples(q querier, workoutID int64, samples []sensor.Sample) error {
+	times := make([]time.Time, len(samples))
+	heartRates := make([]*int, len(samples))
+	powers := make([]*int, len(samples))
+	cadences := make([]*int, len(samples))
+	for i, sample := range samples {
+		times[i] = sample.Time
+		heartRates[i] = sample.HeartRate
+		powers[i] = sample.Power
+		cadences[i] = sample.Cadence
+	}
+	query := `INSERT INTO workout_samples (workout_id, recorded_at, heart_rate, power, cadence)
+		SELECT $1, * FROM unnest($2::TIMESTAMPTZ[], $3::SMALLINT[], $4::SMALLINT[], $5::SMALLINT[])
+		ON CONFLICT (workout_id, recorded_at) DO UPDATE
+		SET heart_rate = EXCLUDED.heart_rate, power = EXCLUDED.power, cadence = EXCLUDED.cadence`
+	_, err := q.Exec(query, workoutID, times, heartRates, powers, cadences)
+	return err
+}
+
+func loadSamples(q querier, workoutID int64) ([]sensor.Sample, error) {
+	query := `SELECT recorded_at, heart_rate, power, cadence
+		FROM workout_samples WHERE workout_id = $1 ORDER BY recorded_at`
+	rows, err := q.Query(query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := []sensor.Sample{}
+	for rows.Next() {
+		sample := sensor.Sample{}
+		err := rows.Scan(&sample.Time, &sample.HeartRate, &sample.Power, &sample.Cadence)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+// GetSamples returns the workout's samples at full resolution along with its
+// owner's heart-rate zones.
+func (s *PostgresWorkoutStore) GetSamples(workoutID int64) (*WorkoutSamples, error) {
+	var userID int64
+	var at time.Time
+	query := `SELECT user_id, COALESCE(started_at, created_at) FROM workouts WHERE id = $1 AND deleted_at IS NULL`
+	err := s.db.QueryRow(query, workoutID).Scan(&userID, &at)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := loadUserProfile(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := loadSamples(s.db, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkoutSamples{Samples: samples, Zones: heartRateZones(profile, at)}, nil
+}
+
+func loadUserProfile(q querier, userID int64) (UserProfile, error) {
+	profile := UserProfile{}
+	query := `SELECT birth_year, sex, weight_kg, resting_heart_rate, max_heart_rate FROM users WHERE id = $1`
+	err := q.QueryRow(query, userID).Scan(&profile.BirthYear, &profile.Sex, &profile.WeightKg, &profile.RestingHeartRate, &profile.MaxHeartRate)
+	return profile, err
+}
+
+// heartRateZones uses the profile's maximum heart rate, or an estimate from
+// the user's age at the time of the workout.
+func heartRateZones(profile UserProfile, at time.Time) []fitness.HeartRateZone {
+	maxHR := 0
+	if profile.MaxHeartRate != nil {
+		maxHR = *profile.MaxHeartRate
+	} else if age, ok := profile.Age(at); ok {
+		maxHR = fitness.EstimateMaxHeartRate(age)
+	}
+	if maxHR == 0 {
+		return nil
+	}
+	restingHR := 0
+	if profile.RestingHeartRate != nil {
+		restingHR = *profile.RestingHeartRate
+	}
+	return fitness.HeartRateZones(maxHR, restingHR)
+}
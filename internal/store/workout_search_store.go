@@ -0,0 +1,197 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxWorkoutTags = 20
+	maxTagLength   = 50
+)
+
+// Tag is one of a user's workout tags and the number of workouts carrying it.
+type Tag struct {
+	Name         string `json:"name"`
+	WorkoutCount int    `json:"workout_count"`
+}
+
+// WorkoutFilter narrows GetAllWorkouts. A workout matches when it carries
+// every tag in Tags.
+type WorkoutFilter struct {
+	Tags []string
+}
+
+type WorkoutSearchResult struct {
+	Workout *Workout `json:"workout"`
+	Rank    float64  `json:"rank"`
+}
+
+// searchVectorExpr builds a workout's search document over alias w. Titles
+// weigh most, then exercise names, the description and finally entry notes.
+const searchVectorExpr = `setweight(to_tsvector('english', w.title), 'A') ||
+	setweight(to_tsvector('english', COALESCE((SELECT string_agg(e.exercise_name, ' ') FROM workout_entries e WHERE e.workout_id = w.id), '')), 'B') ||
+	setweight(to_tsvector('english', COALESCE(w.description, '')), 'C') ||
+	setweight(to_tsvector('english', COALESCE((SELECT string_agg(e.notes, ' ') FROM workout_entries e WHERE e.workout_id = w.id), '')), 'D')`
+
+// normalizeTag trims and lowercases a tag so "Leg Day" and "leg day " are the
+// same tag.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags normalizes and de-duplicates tags, keeping their order. It
+// never returns nil so workouts always serialize an empty tag list.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags cannot be empty", ErrInvalidWorkout)
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidWorkout, tag, maxTagLength)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxWorkoutTags {
+		return nil, fmt.Errorf("%w: a workout can have at most %d tags", ErrInvalidWorkout, maxWorkoutTags)
+	}
+	return normalized, nil
+}
+
+// setWorkoutTags replaces the workout's tags, creating tags in the owner's
+// namespace as needed and dropping tags no longer used by any workout.
+func setWorkoutTags(q querier, workout *Workout) error {
+	_, err := q.Exec(`DELETE FROM workout_tags WHERE workout_id = $1`, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range workout.Tags {
+		var tagID int64
+		query := `INSERT INTO tags (user_id, name) VALUES ($1, $2)
+			ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id`
+		err := q.QueryRow(query, workout.UserID, name).Scan(&tagID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.Exec(`INSERT INTO workout_tags (workout_id, tag_id) VALUES ($1, $2)`, workout.ID, tagID)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `DELETE FROM tags t
+		WHERE t.user_id = $1 AND NOT EXISTS (SELECT 1 FROM workout_tags wt WHERE wt.tag_id = t.id)`
+	_, err = q.Exec(query, workout.UserID)
+	return err
+}
+
+func loadWorkoutTags(q querier, workout *Workout) error {
+	query := `SELECT t.name FROM workout_tags wt
+		INNER JOIN tags t ON t.id = wt.tag_id
+		WHERE wt.workout_id = $1
+		ORDER BY t.name`
+	rows, err := q.Query(query, workout.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	workout.Tags = []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		workout.Tags = append(workout.Tags, name)
+	}
+	return rows.Err()
+}
+
+// refreshSearchVector rebuilds the workout's search document. It must run
+// after every change to the title, description or entries.
+func refreshSearchVector(q querier, workoutID int) error {
+	_, err := q.Exec(`UPDATE workouts w SET search_vector = `+searchVectorExpr+` WHERE w.id = $1`, workoutID)
+	return err
+}
+
+// tagFilterClause returns a condition over alias w requiring every tag in
+// the array parameter, whose length is passed as the count parameter.
+func tagFilterClause(tagsParam, countParam string) string {
+	return `(SELECT COUNT(*) FROM workout_tags wt
+		INNER JOIN tags t ON t.id = wt.tag_id
+		WHERE wt.workout_id = w.id AND t.name = ANY(` + tagsParam + `)) = ` + countParam
+}
+
+// SearchWorkouts runs a web-style full-text query (quoted phrases, OR, -term)
+// over the workouts the viewer may see, best matches first.
+func (s *PostgresWorkoutStore) SearchWorkouts(viewerID int64, search string, limit int) ([]*WorkoutSearchResult, error) {
+	query := `SELECT ` + qualifiedWorkoutColumns() + `, ts_rank_cd(w.search_vector, q) AS rank
+		FROM workouts w, websearch_to_tsquery('english', $2) q
+		WHERE w.search_vector @@ q AND w.deleted_at IS NULL AND ` + workoutVisibleTo("$1") + `
+		ORDER BY rank DESC, w.created_at DESC, w.id DESC
+		LIMIT $3`
+	rows, err := s.db.Query(query, viewerID, search, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*WorkoutSearchResult{}
+	for rows.Next() {
+		result := &WorkoutSearchResult{Workout: &Workout{}}
+		err := rows.Scan(append(workoutDest(result.Workout), &result.Rank)...)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, result := range results {
+		if err := loadWorkoutDetails(s.db, result.Workout); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// GetTags returns the user's tags with their usage counts, most used first.
+// Tags only found on trashed workouts are left out.
+func (s *PostgresWorkoutStore) GetTags(userID int64) ([]Tag, error) {
+	query := `SELECT t.name, COUNT(*)
+		FROM tags t
+		INNER JOIN workout_tags wt ON wt.tag_id = t.id
+		INNER JOIN workouts w ON w.id = wt.workout_id AND w.deleted_at IS NULL
+		WHERE t.user_id = $1
+		GROUP BY t.id, t.name
+		ORDER BY COUNT(*) DESC, t.name`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.Name, &tag.WorkoutCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,490 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrActiveWorkoutExists = errors.New("an active workout is already in progress")
+	ErrWorkoutNotActive    = errors.New("workout is not active")
+)
+
+// LoggedSet is a single set performed during a live session. It is added to
+// EntryID when given, otherwise to the workout's entry for ExerciseName,
+// which is created on the first set.
+type LoggedSet struct {
+	EntryID         *int     `json:"entry_id"`
+	ExerciseName    string   `json:"exercise_name"`
+	Reps            *int     `json:"reps"`
+	Weight          *float64 `json:"weight"` // in kg
+	DurationSeconds *int     `json:"duration_seconds"`
+	RPE             *float64 `json:"rpe"` // rate of perceived exertion, 1 to 10
+
+	StartedAt   *time.Time `json:"started_at"`   // when the set began, which times the rest before it
+	CompletedAt *time.Time `json:"completed_at"` // defaults to now
+}
+
+func (set *LoggedSet) validate() error {
+	if set.EntryID == nil && set.ExerciseName == "" {
+		return fmt.Errorf("%w: entry_id or exercise_name is required", ErrInvalidWorkout)
+	}
+	if set.Reps == nil && set.Weight == nil && set.DurationSeconds == nil {
+		return fmt.Errorf("%w: a set needs reps, weight or duration_seconds", ErrInvalidWorkout)
+	}
+	if (set.Reps != nil && *set.Reps < 0) || (set.Weight != nil && *set.Weight < 0) || (set.DurationSeconds != nil && *set.DurationSeconds < 0) {
+		return fmt.Errorf("%w: set values must not be negative", ErrInvalidWorkout)
+	}
+	if !validRPE(set.RPE) {
+		return fmt.Errorf("%w: rpe must be between 1 and 10", ErrInvalidWorkout)
+	}
+	completedAt := time.Now()
+	if set.CompletedAt != nil {
+		completedAt = *set.CompletedAt
+	}
+	if set.StartedAt != nil && set.StartedAt.After(completedAt) {
+		return fmt.Errorf("%w: started_at must not be after completed_at", ErrInvalidWorkout)
+	}
+	return nil
+}
+
+// StartWorkout creates an active workout stamped with started_at. A user can
+// only have one active workout at a time.
+func (s *PostgresWorkoutStore) StartWorkout(workout *Workout) (*Workout, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = startWorkout(tx, workout)
+	if err != nil {
+		return nil, err
+	}
+
+	return workout, tx.Commit()
+}
+
+// startWorkout validates and inserts an active workout as part of the
+// caller's transaction.
+func startWorkout(q querier, workout *Workout) error {
+	if workout.Title == "" {
+		return fmt.Errorf("%w: workout title is required", ErrInvalidWorkout)
+	}
+	if err := validateVisibility(workout, false); err != nil {
+		return err
+	}
+	if err := validateEntries(workout); err != nil {
+		return err
+	}
+	if err := validateEntryGroups(workout); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(workout.Tags)
+	if err != nil {
+		return err
+	}
+	workout.Tags = tags
+
+	err = validateWorkoutTemplate(q, workout)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO workouts (user_id, title, description, duration_minutes, calories_burned, visibility, status, started_at, template_id)
+		VALUES ($1, $2, $3, 0, $4, $5, 'active', NOW(), $6) RETURNING id, status, started_at, created_at, updated_at`
+	err = q.QueryRow(query, workout.UserID, workout.Title, workout.Description, workout.CaloriesBurned, workout.Visibility, workout.TemplateID).Scan(&workout.ID, &workout.Status, &workout.StartedAt, &workout.CreatedAt, &workout.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrActiveWorkoutExists
+		}
+		return err
+	}
+
+	groupIDs, err := insertEntryGroups(q, workout)
+	if err != nil {
+		return err
+	}
+
+	err = insertEntries(q, workout, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	err = setWorkoutTags(q, workout)
+	if err != nil {
+		return err
+	}
+
+	err = refreshSearchVector(q, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	return recordRevision(q, int64(workout.ID), RevisionCreate, &workout.UserID)
+}
+
+// GetActiveWorkout returns the user's in-progress workout, or nil if there is
+// none.
+func (s *PostgresWorkoutStore) GetActiveWorkout(userID int64) (*Workout, error) {
+	query := `SELECT ` + workoutColumns + `
+		FROM workouts WHERE user_id = $1 AND status = 'active' AND deleted_at IS NULL`
+	workout, err := scanWorkout(s.db.QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadWorkoutDetails(s.db, workout); err != nil {
+		return nil, err
+	}
+	return workout, nil
+}
+
+// LogSet appends a set to an active workout and refreshes the entry's summary
+// columns: sets grows to the number of logged working sets but never drops
+// below the planned count, reps/weight/duration reflect the top set and rpe
+// the hardest set. Warm-up sets are left out.
+func (s *PostgresWorkoutStore) LogSet(workoutID int64, set *LoggedSet, actorID int64) (*WorkoutEntry, error) {
+	if err := set.validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM workouts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, workoutID).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+	if status != WorkoutActive {
+		return nil, ErrWorkoutNotActive
+	}
+
+	err = ensureBaselineRevision(tx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entryID int
+	if set.EntryID != nil {
+		err = tx.QueryRow(`SELECT id FROM workout_entries WHERE id = $1 AND workout_id = $2`, *set.EntryID, workoutID).Scan(&entryID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: entry %d is not part of this workout", ErrInvalidWorkout, *set.EntryID)
+		}
+	} else {
+		query := `SELECT id FROM workout_entries
+			WHERE workout_id = $1 AND LOWER(exercise_name) = LOWER($2)
+			ORDER BY order_index DESC, id DESC LIMIT 1`
+		err = tx.QueryRow(query, workoutID, set.ExerciseName).Scan(&entryID)
+		if err == sql.ErrNoRows {
+			query = `INSERT INTO workout_entries (workout_id, exercise_name, sets, reps, duration_seconds, weight, notes, order_index)
+				VALUES ($1, $2, 1, $3, $4, $5, '', (SELECT COALESCE(MAX(order_index) + 1, 0) FROM workout_entries WHERE workout_id = $1))
+				RETURNING id`
+			err = tx.QueryRow(query, workoutID, set.ExerciseName, set.Reps, set.DurationSeconds, set.Weight).Scan(&entryID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO workout_sets (entry_id, set_number, reps, weight, duration_seconds, rpe, started_at, completed_at)
+		VALUES ($1, (SELECT COALESCE(MAX(set_number), 0) + 1 FROM workout_sets WHERE entry_id = $1), $2, $3, $4, $5, $6, COALESCE($7, NOW()))`
+	_, err = tx.Exec(query, entryID, set.Reps, set.Weight, set.DurationSeconds, set.RPE, set.StartedAt, set.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `UPDATE workout_entries SET
+			sets = GREATEST(sets, (SELECT COUNT(*) FROM workout_sets WHERE entry_id = $1 AND NOT warmup)),
+			(reps, weight, duration_seconds) = (
+				SELECT reps, weight, duration_seconds FROM workout_sets WHERE entry_id = $1 AND NOT warmup
+				ORDER BY weight DESC NULLS LAST, reps DESC NULLS LAST, duration_seconds DESC NULLS LAST
+				LIMIT 1
+			),
+			rpe = (SELECT MAX(rpe) FROM workout_sets WHERE entry_id = $1 AND NOT warmup),
+			updated_at = NOW()
+		WHERE id = $1`
+	_, err = tx.Exec(query, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE workouts SET updated_at = NOW() WHERE id = $1`, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = refreshSearchVector(tx, int(workoutID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordRevision(tx, workoutID, RevisionUpdate, &actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	workout := &Workout{ID: int(workoutID)}
+	if err := loadWorkoutDetails(tx, workout); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for i := range workout.Entries {
+		if workout.Entries[i].ID == entryID {
+			return &workout.Entries[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// SetWarmupSets replaces the entry's warm-up sets with sets, numbered ahead
+// of its working sets. The entry's summary is unchanged since warm-ups do not
+// count towards it. It returns sql.ErrNoRows when the entry is not part of
+// the workout.
+func (s *PostgresWorkoutStore) SetWarmupSets(workoutID, entryID int64, sets []WorkoutSet, actorID int64) (*WorkoutEntry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	query := `SELECT e.id FROM workout_entries e
+		INNER JOIN workouts w ON w.id = e.workout_id
+		WHERE e.id = $1 AND e.workout_id = $2 AND w.deleted_at IS NULL
+		FOR UPDATE OF w`
+	err = tx.QueryRow(query, entryID, workoutID).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ensureBaselineRevision(tx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM workout_sets WHERE entry_id = $1 AND warmup`, entryID)
+	if err != nil {
+		return nil, err
+	}
+	query = `UPDATE workout_sets s SET set_number = r.position + $2
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY set_number, id) AS position FROM workout_sets WHERE entry_id = $1) r
+		WHERE s.id = r.id`
+	_, err = tx.Exec(query, entryID, len(sets))
+	if err != nil {
+		return nil, err
+	}
+	// warm-ups are generated ahead of being performed, so they are untimed
+	for i, set := range sets {
+		query := `INSERT INTO workout_sets (entry_id, set_number, reps, weight, duration_seconds, warmup, started_at, completed_at)
+			VALUES ($1, $2, $3, $4, $5, TRUE, NULL, NULL)`
+		_, err = tx.Exec(query, entryID, i+1, set.Reps, set.Weight, set.DurationSeconds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE workouts SET updated_at = NOW() WHERE id = $1`, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordRevision(tx, workoutID, RevisionUpdate, &actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	workout := &Workout{ID: int(workoutID)}
+	if err := loadWorkoutDetails(tx, workout); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for i := range workout.Entries {
+		if workout.Entries[i].ID == id {
+			return &workout.Entries[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// FinishWorkout completes an active workout, stamping ended_at and deriving
+// duration_minutes from the session length. A workout that follows a
+// template fulfils the day's planned session for it.
+func (s *PostgresWorkoutStore) FinishWorkout(id, actorID int64) (*Workout, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = ensureBaselineRevision(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	workout, err := finishWorkout(tx, id, nil)
+	if err == sql.ErrNoRows {
+		var exists bool
+		err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM workouts WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrWorkoutNotActive
+		}
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.reestimateCalories(tx, workout)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := workoutExerciseNames(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = recomputePersonalRecords(tx, workout.UserID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	err = refreshGoals(tx, workout.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = linkCompletedWorkout(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordRevision(tx, id, RevisionUpdate, &actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetWorkoutById(id)
+}
+
+// finishWorkout marks an active workout completed at endedAt, or now when
+// endedAt is nil, and returns the updated row.
+func finishWorkout(q querier, id int64, endedAt *time.Time) (*Workout, error) {
+	query := `UPDATE workouts SET
+			status = 'completed',
+			ended_at = COALESCE($2, NOW()),
+			duration_minutes = GREATEST(1, CEIL(EXTRACT(EPOCH FROM (COALESCE($2, NOW()) - started_at)) / 60))::INTEGER,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'active' AND deleted_at IS NULL
+		RETURNING ` + workoutColumns
+	return scanWorkout(q.QueryRow(query, id, endedAt))
+}
+
+// CleanupAbandonedWorkouts closes active workouts started more than olderThan
+// ago. Sessions with logged sets are finished at their last set; empty ones
+// are moved to the trash. It returns the number of workouts cleaned up.
+func (s *PostgresWorkoutStore) CleanupAbandonedWorkouts(olderThan time.Duration) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE cannot be combined with GROUP BY, so the last set is looked
+	// up per workout
+	query := `SELECT w.id, (
+			SELECT MAX(ws.completed_at)
+			FROM workout_entries e
+			INNER JOIN workout_sets ws ON ws.entry_id = e.id
+			WHERE e.workout_id = w.id
+		)
+		FROM workouts w
+		WHERE w.status = 'active' AND w.deleted_at IS NULL AND w.started_at < $1
+		FOR UPDATE`
+	rows, err := tx.Query(query, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+
+	type abandoned struct {
+		id      int64
+		lastSet *time.Time
+	}
+	workouts := []abandoned{}
+	for rows.Next() {
+		a := abandoned{}
+		if err := rows.Scan(&a.id, &a.lastSet); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		workouts = append(workouts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, a := range workouts {
+		if a.lastSet == nil {
+			// trashed by the cleanup job, so there is no actor
+			if err := trashWorkout(tx, a.id, nil); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := ensureBaselineRevision(tx, a.id); err != nil {
+			return 0, err
+		}
+		workout, err := finishWorkout(tx, a.id, a.lastSet)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := s.reestimateCalories(tx, workout); err != nil {
+			return 0, err
+		}
+		names, err := workoutExerciseNames(tx, a.id)
+		if err != nil {
+			return 0, err
+		}
+		if err := recomputePersonalRecords(tx, workout.UserID, names); err != nil {
+			return 0, err
+		}
+		if err := refreshGoals(tx, workout.UserID); err != nil {
+			return 0, err
+		}
+		if err := linkCompletedWorkout(tx, a.id); err != nil {
+			return 0, err
+		}
+		// closed by the cleanup job, so there is no actor
+		if err := recordRevision(tx, a.id, RevisionUpdate, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(workouts), tx.Commit()
+}
@@ -2,8 +2,35 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/fitness"
+	"github.com/mounis-bhat/rest-api-go/internal/sensor"
+	"github.com/mounis-bhat/rest-api-go/internal/track"
+)
+
+// ErrInvalidWorkout is wrapped by validation errors so handlers can tell bad
+// input apart from database failures.
+var ErrInvalidWorkout = errors.New("invalid workout")
+
+const (
+	GroupSuperset = "superset"
+	GroupCircuit  = "circuit"
+	GroupGiantSet = "giant_set"
+)
+
+const (
+	WorkoutActive    = "active"
+	WorkoutCompleted = "completed"
+)
+
+const (
+	VisibilityPrivate   = "private"
+	VisibilityFollowers = "followers"
+	VisibilityPublic    = "public"
 )
 
 type Workout struct {
@@ -13,172 +40,594 @@ type Workout struct {
 	Description     string         `json:"description"`
 	DurationMinutes int            `json:"duration_minutes"`
 	CaloriesBurned  int            `json:"calories_burned"` // in kcal
+	CaloriesSource  *string        `json:"calories_source"` // how calories were estimated, nil when supplied by the client
+	Status          string         `json:"status"`          // active while the session is in progress, then completed
+	Visibility      string         `json:"visibility"`      // private, followers or public
+	StartedAt       *time.Time     `json:"started_at"`
+	EndedAt         *time.Time     `json:"ended_at"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       *time.Time     `json:"deleted_at,omitempty"` // set while the workout is in the trash
+	TemplateID      *int           `json:"template_id"`          // template the workout follows, which links it to planned sessions
+	Tags            []string       `json:"tags"`                 // on update, nil keeps the current tags
 	Entries         []WorkoutEntry `json:"entries"`
+	Groups          []EntryGroup   `json:"groups"`
+	Track           *track.Summary `json:"track,omitempty"` // set for workouts imported from a GPS recording
 }
 
 type WorkoutEntry struct {
-	ID              int      `json:"id"`
-	ExerciseName    string   `json:"exercise_name"`
-	Sets            int      `json:"sets"`
-	Reps            *int     `json:"reps"`
-	DurationSeconds *int     `json:"duration_seconds"`
-	Weight          *float64 `json:"weight"` // in kg
-	Notes           string   `json:"notes"`
-	OrderIndex      int      `json:"order_index"`
+	ID              int          `json:"id"`
+	ExerciseName    string       `json:"exercise_name"`
+	Sets            int          `json:"sets"`
+	Reps            *int         `json:"reps"`
+	DurationSeconds *int         `json:"duration_seconds"`
+	Weight          *float64     `json:"weight"` // in kg, or the caller's unit through the API
+	Notes           string       `json:"notes"`
+	OrderIndex      int          `json:"order_index"`
+	GroupIndex      *int         `json:"group_index"` // index into Workout.Groups, nil when ungrouped
+	SetLog          []WorkoutSet `json:"set_log"`     // individual sets logged during a live session
+	RPE             *float64     `json:"rpe"`         // rate of perceived exertion of the hardest set, 1 to 10
+
+	TargetRestSeconds *int `json:"target_rest_seconds"` // rest between sets; nil uses the exercise's rest target
+
+	DistanceMeters   *float64   `json:"distance_meters"`
+	PaceSecondsPerKm *int       `json:"pace_seconds_per_km"` // derived from distance and duration when omitted
+	InclinePercent   *float64   `json:"incline_percent"`     // treadmill incline, negative for a decline
+	ResistanceLevel  *int       `json:"resistance_level"`    // machine resistance setting
+	Intervals        *Intervals `json:"intervals"`           // work/rest repeats, nil for a steady effort
+
+	// Distance and PaceSeconds are DistanceMeters and PaceSecondsPerKm in
+	// the caller's units (km or mi); they are never stored.
+	Distance    *float64 `json:"distance,omitempty"`
+	PaceSeconds *int     `json:"pace_seconds,omitempty"`
+}
+
+// Intervals are Repeats bouts of work, each lasting WorkSeconds or covering
+// WorkMeters (or both), separated by RestSeconds of recovery.
+type Intervals struct {
+	Repeats     int      `json:"repeats"`
+	WorkSeconds *int     `json:"work_seconds"`
+	WorkMeters  *float64 `json:"work_meters"`
+	RestSeconds int      `json:"rest_seconds"`
+}
+
+type WorkoutSet struct {
+	ID              int        `json:"id"`
+	SetNumber       int        `json:"set_number"`
+	Reps            *int       `json:"reps"`
+	Weight          *float64   `json:"weight"` // in kg, or the caller's unit through the API
+	DurationSeconds *int       `json:"duration_seconds"`
+	RPE             *float64   `json:"rpe"`
+	Warmup          bool       `json:"warmup"`       // left out of the entry's summary
+	StartedAt       *time.Time `json:"started_at"`   // nil when the client did not time the set
+	CompletedAt     *time.Time `json:"completed_at"` // nil for generated warm-up sets
+	RestSeconds     *int       `json:"rest_seconds"` // from the entry's previous working set completing to this one starting; set on read
+}
+
+// EntryGroup performs its entries back to back for the given number of
+// rounds. Entries is filled in on read; writes link entries through
+// WorkoutEntry.GroupIndex.
+type EntryGroup struct {
+	ID                          int            `json:"id"`
+	GroupType                   string         `json:"type"` // superset, circuit or giant_set
+	Rounds                      int            `json:"rounds"`
+	RestBetweenExercisesSeconds *int           `json:"rest_between_exercises_seconds"`
+	RestBetweenRoundsSeconds    *int           `json:"rest_between_rounds_seconds"`
+	OrderIndex                  int            `json:"order_index"`
+	Entries                     []WorkoutEntry `json:"entries"`
 }
 
 type PostgresWorkoutStore struct {
-	db *sql.DB
+	db   *sql.DB
+	mets *fitness.METTable
 }
 
 func NewPostgresWorkoutStore(db *sql.DB) *PostgresWorkoutStore {
-	return &PostgresWorkoutStore{db: db}
+	return &PostgresWorkoutStore{db: db, mets: fitness.DefaultMETTable()}
 }
 
 type WorkoutStore interface {
-	CreateWorkout(workout *Workout) (*Workout, error)
+	CreateWorkout(workout *Workout, actorID int64) (*Workout, error)
 	GetWorkoutById(id int64) (*Workout, error)
-	UpdateWorkout(workout *Workout) error
-	DeleteWorkout(id int64) error
-	GetAllWorkouts() ([]*Workout, error)
+	UpdateWorkout(workout *Workout, actorID int64) error
+	DeleteWorkout(id, actorID int64) error
+	GetAllWorkouts(viewerID int64, filter WorkoutFilter) ([]*Workout, error)
+	GetWorkoutsForUser(userID int64) ([]*Workout, error)
+	GetTrashedWorkouts(userID int64) ([]*Workout, error)
+	RestoreWorkout(id, userID int64) (*Workout, error)
+	PurgeTrashedWorkouts(olderThan time.Duration) (int, error)
 	GetWorkoutOwner(id int64) (int, error)
+	CanViewWorkout(id, viewerID int64) (bool, error)
+	SearchWorkouts(viewerID int64, search string, limit int) ([]*WorkoutSearchResult, error)
+	GetTags(userID int64) ([]Tag, error)
+	StartWorkout(workout *Workout) (*Workout, error)
+	StartPlannedWorkout(sessionID int64, workout *Workout) (*Workout, error)
+	GetActiveWorkout(userID int64) (*Workout, error)
+	LogSet(workoutID int64, set *LoggedSet, actorID int64) (*WorkoutEntry, error)
+	SetWarmupSets(workoutID, entryID int64, sets []WorkoutSet, actorID int64) (*WorkoutEntry, error)
+	FinishWorkout(id, actorID int64) (*Workout, error)
+	CleanupAbandonedWorkouts(olderThan time.Duration) (int, error)
+	GetRevisions(workoutID int64) ([]*WorkoutRevision, error)
+	RevertWorkout(id int64, revision int, actorID int64) (*Workout, error)
+	ExportWorkouts(userID int64, from, to *time.Time, emit func(*Workout) error) error
+	ImportWorkouts(userID int64, workouts []*Workout, actorID int64) ([]*Workout, int, error)
+	ImportTrackWorkout(workout *Workout, summary *track.Summary, points []track.Point, actorID int64) (*Workout, error)
+	GetTrackPoints(workoutID int64) ([]track.Point, error)
+	AddSamples(workoutID int64, samples []sensor.Sample, actorID int64) (int, error)
+	GetSamples(workoutID int64) (*WorkoutSamples, error)
 }
 
-func (s *PostgresWorkoutStore) CreateWorkout(workout *Workout) (*Workout, error) {
-	if workout.Title == "" {
-		return nil, fmt.Errorf("workout title is required")
+const workoutColumns = `id, user_id, title, description, duration_minutes, calories_burned, calories_source, status, visibility, started_at, ended_at, created_at, updated_at, deleted_at, template_id`
+
+// workoutDest returns scan destinations matching workoutColumns.
+func workoutDest(workout *Workout) []any {
+	return []any{&workout.ID, &workout.UserID, &workout.Title, &workout.Description, &workout.DurationMinutes, &workout.CaloriesBurned, &workout.CaloriesSource,
+		&workout.Status, &workout.Visibility, &workout.StartedAt, &workout.EndedAt, &workout.CreatedAt, &workout.UpdatedAt, &workout.DeletedAt, &workout.TemplateID}
+}
+
+func scanWorkout(row interface{ Scan(dest ...any) error }) (*Workout, error) {
+	workout := &Workout{}
+	err := row.Scan(workoutDest(workout)...)
+	if err != nil {
+		return nil, err
+	}
+	return workout, nil
+}
+
+// validateVisibility defaults an empty visibility to private when required
+// is false and rejects unknown values.
+func validateVisibility(workout *Workout, required bool) error {
+	switch workout.Visibility {
+	case VisibilityPrivate, VisibilityFollowers, VisibilityPublic:
+		return nil
+	case "":
+		if !required {
+			workout.Visibility = VisibilityPrivate
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: visibility must be private, followers or public", ErrInvalidWorkout)
 	}
+}
 
+// validateEntries mirrors the valid_workout_entry and cardio CHECK
+// constraints so bad entries are reported as validation errors, and derives
+// pace from distance and duration when it is omitted.
+func validateEntries(workout *Workout) error {
+	for i := range workout.Entries {
+		entry := &workout.Entries[i]
+		if entry.Reps == nil && entry.DurationSeconds == nil && entry.Weight == nil && entry.DistanceMeters == nil && entry.Intervals == nil {
+			return fmt.Errorf("%w: entry %q needs reps, a duration, a weight, a distance or intervals", ErrInvalidWorkout, entry.ExerciseName)
+		}
+		if entry.DistanceMeters != nil && (*entry.DistanceMeters <= 0 || *entry.DistanceMeters > maxDistanceMeters) {
+			return fmt.Errorf("%w: entry %q distance must be between 0 and %d km", ErrInvalidWorkout, entry.ExerciseName, maxDistanceMeters/1000)
+		}
+		if entry.PaceSecondsPerKm != nil && *entry.PaceSecondsPerKm <= 0 {
+			return fmt.Errorf("%w: entry %q pace must be positive", ErrInvalidWorkout, entry.ExerciseName)
+		}
+		if entry.InclinePercent != nil && (*entry.InclinePercent < -20 || *entry.InclinePercent > 40) {
+			return fmt.Errorf("%w: entry %q incline must be between -20 and 40 percent", ErrInvalidWorkout, entry.ExerciseName)
+		}
+		if entry.ResistanceLevel != nil && (*entry.ResistanceLevel < 0 || *entry.ResistanceLevel > 100) {
+			return fmt.Errorf("%w: entry %q resistance level must be between 0 and 100", ErrInvalidWorkout, entry.ExerciseName)
+		}
+		if !validRPE(entry.RPE) {
+			return fmt.Errorf("%w: entry %q rpe must be between 1 and 10", ErrInvalidWorkout, entry.ExerciseName)
+		}
+		if !validRest(entry.TargetRestSeconds) {
+			return fmt.Errorf("%w: entry %q target rest must be between 0 and %d seconds", ErrInvalidWorkout, entry.ExerciseName, maxRestSeconds)
+		}
+		for _, set := range entry.SetLog {
+			if !validRPE(set.RPE) {
+				return fmt.Errorf("%w: entry %q set rpe must be between 1 and 10", ErrInvalidWorkout, entry.ExerciseName)
+			}
+			if set.StartedAt != nil && (set.CompletedAt == nil || set.StartedAt.After(*set.CompletedAt)) {
+				return fmt.Errorf("%w: entry %q sets must start before they complete", ErrInvalidWorkout, entry.ExerciseName)
+			}
+		}
+		if intervals := entry.Intervals; intervals != nil {
+			if intervals.Repeats <= 0 {
+				return fmt.Errorf("%w: entry %q intervals need at least one repeat", ErrInvalidWorkout, entry.ExerciseName)
+			}
+			if intervals.WorkSeconds == nil && intervals.WorkMeters == nil {
+				return fmt.Errorf("%w: entry %q intervals need work_seconds or work_meters", ErrInvalidWorkout, entry.ExerciseName)
+			}
+			if (intervals.WorkSeconds != nil && *intervals.WorkSeconds <= 0) || (intervals.WorkMeters != nil && *intervals.WorkMeters <= 0) || intervals.RestSeconds < 0 {
+				return fmt.Errorf("%w: entry %q interval work must be positive and rest not negative", ErrInvalidWorkout, entry.ExerciseName)
+			}
+		}
+
+		if entry.PaceSecondsPerKm == nil && entry.DistanceMeters != nil && entry.DurationSeconds != nil && *entry.DurationSeconds > 0 {
+			pace := int(math.Round(float64(*entry.DurationSeconds) / (*entry.DistanceMeters / 1000)))
+			entry.PaceSecondsPerKm = &pace
+		}
+	}
+	return nil
+}
+
+// validRPE reports whether an optional RPE is on the 1 to 10 scale.
+func validRPE(rpe *float64) bool {
+	return rpe == nil || (*rpe >= 1 && *rpe <= 10)
+}
+
+// maxDistanceMeters bounds a single entry's distance; ultra events are well
+// under it.
+const maxDistanceMeters = 1000000
+
+// intervalColumns returns the interval_* column values of an entry.
+func intervalColumns(entry WorkoutEntry) (repeats *int, workSeconds *int, workMeters *float64, restSeconds *int) {
+	if entry.Intervals == nil {
+		return nil, nil, nil, nil
+	}
+	return &entry.Intervals.Repeats, entry.Intervals.WorkSeconds, entry.Intervals.WorkMeters, &entry.Intervals.RestSeconds
+}
+
+// entryIntervals builds Intervals from scanned interval_* columns.
+func entryIntervals(repeats *int, workSeconds *int, workMeters *float64, restSeconds *int) *Intervals {
+	if repeats == nil {
+		return nil
+	}
+	intervals := &Intervals{Repeats: *repeats, WorkSeconds: workSeconds, WorkMeters: workMeters}
+	if restSeconds != nil {
+		intervals.RestSeconds = *restSeconds
+	}
+	return intervals
+}
+
+// validateEntryGroups checks group settings and membership. A superset pairs
+// exactly two exercises, a giant set needs at least three and a circuit at
+// least two.
+func validateEntryGroups(workout *Workout) error {
+	members := make([]int, len(workout.Groups))
+	for _, entry := range workout.Entries {
+		if entry.GroupIndex == nil {
+			continue
+		}
+		if *entry.GroupIndex < 0 || *entry.GroupIndex >= len(workout.Groups) {
+			return fmt.Errorf("%w: entry %q references unknown group %d", ErrInvalidWorkout, entry.ExerciseName, *entry.GroupIndex)
+		}
+		members[*entry.GroupIndex]++
+	}
+
+	for i := range workout.Groups {
+		group := &workout.Groups[i]
+		if group.Rounds == 0 {
+			group.Rounds = 1
+		}
+		if group.Rounds < 0 {
+			return fmt.Errorf("%w: group %d rounds must be positive", ErrInvalidWorkout, i)
+		}
+		if (group.RestBetweenExercisesSeconds != nil && *group.RestBetweenExercisesSeconds < 0) ||
+			(group.RestBetweenRoundsSeconds != nil && *group.RestBetweenRoundsSeconds < 0) {
+			return fmt.Errorf("%w: group %d rest intervals must not be negative", ErrInvalidWorkout, i)
+		}
+
+		switch group.GroupType {
+		case GroupSuperset:
+			if members[i] != 2 {
+				return fmt.Errorf("%w: superset %d must contain exactly 2 entries", ErrInvalidWorkout, i)
+			}
+		case GroupGiantSet:
+			if members[i] < 3 {
+				return fmt.Errorf("%w: giant set %d must contain at least 3 entries", ErrInvalidWorkout, i)
+			}
+		case GroupCircuit:
+			if members[i] < 2 {
+				return fmt.Errorf("%w: circuit %d must contain at least 2 entries", ErrInvalidWorkout, i)
+			}
+		default:
+			return fmt.Errorf("%w: group %d has unknown type %q", ErrInvalidWorkout, i, group.GroupType)
+		}
+	}
+	return nil
+}
+
+// CreateWorkout stores a new workout on behalf of actorID, who is the owner
+// or one of their coaches.
+func (s *PostgresWorkoutStore) CreateWorkout(workout *Workout, actorID int64) (*Workout, error) {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO workouts (user_id, title, description, duration_minutes, calories_burned)
-		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
-
-	err = tx.QueryRow(query, workout.UserID, workout.Title, workout.Description, workout.DurationMinutes, workout.CaloriesBurned).Scan(&workout.ID, &workout.CreatedAt, &workout.UpdatedAt)
+	err = s.createWorkout(tx, workout, actorID)
 	if err != nil {
 		return nil, err
 	}
 
-	insertedEntries := make([]WorkoutEntry, 0, len(workout.Entries))
-	for _, entry := range workout.Entries {
-		query = `INSERT INTO workout_entries (workout_id, exercise_name, sets, reps, duration_seconds, weight, notes, order_index)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
-		err = tx.QueryRow(query, workout.ID, entry.ExerciseName, entry.Sets, entry.Reps, entry.DurationSeconds, entry.Weight, entry.Notes, entry.OrderIndex).Scan(&entry.ID)
-		if err != nil {
-			return nil, err
-		}
-		insertedEntries = append(insertedEntries, entry)
+	err = linkCompletedWorkout(tx, int64(workout.ID))
+	if err != nil {
+		return nil, err
 	}
-	workout.Entries = insertedEntries
 
 	return workout, tx.Commit()
 }
 
-func (s *PostgresWorkoutStore) GetWorkoutById(id int64) (*Workout, error) {
-	query := `SELECT id, user_id, title, description, duration_minutes, calories_burned, created_at, updated_at
-		FROM workouts WHERE id = $1`
-	workout := &Workout{}
-	err := s.db.QueryRow(query, id).Scan(&workout.ID, &workout.UserID, &workout.Title, &workout.Description, &workout.DurationMinutes, &workout.CaloriesBurned, &workout.CreatedAt, &workout.UpdatedAt)
+// createWorkout validates and inserts a completed workout as part of the
+// caller's transaction.
+func (s *PostgresWorkoutStore) createWorkout(q querier, workout *Workout, actorID int64) error {
+	if workout.Title == "" {
+		return fmt.Errorf("%w: workout title is required", ErrInvalidWorkout)
+	}
+	if err := validateVisibility(workout, false); err != nil {
+		return err
+	}
+	if err := validateEntries(workout); err != nil {
+		return err
+	}
+	if err := validateEntryGroups(workout); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(workout.Tags)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	workout.Tags = tags
+	workout.CaloriesSource = nil
+	if err := validateWorkoutTemplate(q, workout); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO workouts (user_id, title, description, duration_minutes, calories_burned, visibility, template_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, status, created_at, updated_at`
+
+	err = q.QueryRow(query, workout.UserID, workout.Title, workout.Description, workout.DurationMinutes, workout.CaloriesBurned, workout.Visibility, workout.TemplateID).Scan(&workout.ID, &workout.Status, &workout.CreatedAt, &workout.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	groupIDs, err := insertEntryGroups(q, workout)
+	if err != nil {
+		return err
+	}
+
+	err = insertEntries(q, workout, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.reestimateCalories(q, workout)
+	if err != nil {
+		return err
+	}
+
+	err = setWorkoutTags(q, workout)
+	if err != nil {
+		return err
 	}
 
-	query = `SELECT id, exercise_name, sets, reps, duration_seconds, weight, notes, order_index
-		FROM workout_entries WHERE workout_id = $1`
-	rows, err := s.db.Query(query, id)
+	err = refreshSearchVector(q, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	err = recomputePersonalRecords(q, workout.UserID, entryExerciseNames(workout.Entries))
+	if err != nil {
+		return err
+	}
+
+	err = refreshGoals(q, workout.UserID)
+	if err != nil {
+		return err
+	}
+
+	return recordRevision(q, int64(workout.ID), RevisionCreate, &actorID)
+}
+
+func (s *PostgresWorkoutStore) GetWorkoutById(id int64) (*Workout, error) {
+	query := `SELECT ` + workoutColumns + `
+		FROM workouts WHERE id = $1 AND deleted_at IS NULL`
+	workout, err := scanWorkout(s.db.QueryRow(query, id))
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		entry := WorkoutEntry{}
-		err := rows.Scan(&entry.ID, &entry.ExerciseName, &entry.Sets, &entry.Reps, &entry.DurationSeconds, &entry.Weight, &entry.Notes, &entry.OrderIndex)
-		if err != nil {
-			return nil, err
-		}
-		workout.Entries = append(workout.Entries, entry)
+	if err := loadWorkoutDetails(s.db, workout); err != nil {
+		return nil, err
 	}
 
 	return workout, nil
 }
 
-func (s *PostgresWorkoutStore) UpdateWorkout(workout *Workout) error {
+func (s *PostgresWorkoutStore) UpdateWorkout(workout *Workout, actorID int64) error {
+	if err := validateVisibility(workout, true); err != nil {
+		return err
+	}
+	if err := validateEntries(workout); err != nil {
+		return err
+	}
+	if err := validateEntryGroups(workout); err != nil {
+		return err
+	}
+	if workout.Tags != nil {
+		tags, err := normalizeTags(workout.Tags)
+		if err != nil {
+			return err
+		}
+		workout.Tags = tags
+	}
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	query := `UPDATE workouts SET title = $1, description = $2, duration_minutes = $3, calories_burned = $4, updated_at = NOW()
-		WHERE id = $5`
+	err = ensureBaselineRevision(tx, int64(workout.ID))
+	if err != nil {
+		return err
+	}
 
-	result, err := tx.Exec(query, workout.Title, workout.Description, workout.DurationMinutes, workout.CaloriesBurned, workout.ID)
+	previousNames, err := workoutExerciseNames(tx, int64(workout.ID))
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	// calories sent back unchanged stay estimated; any other value was
+	// supplied by the client, whatever calories_source came with it
+	query := `UPDATE workouts SET title = $1, description = $2, duration_minutes = $3, calories_burned = $4,
+			calories_source = CASE WHEN $4 = calories_burned THEN calories_source END,
+			visibility = COALESCE(NULLIF($6, ''), visibility), updated_at = NOW()
+		WHERE id = $5 AND deleted_at IS NULL RETURNING user_id, visibility, calories_source, started_at, created_at`
+
+	err = tx.QueryRow(query, workout.Title, workout.Description, workout.DurationMinutes, workout.CaloriesBurned, workout.ID, workout.Visibility).Scan(
+		&workout.UserID, &workout.Visibility, &workout.CaloriesSource, &workout.StartedAt, &workout.CreatedAt)
 	if err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+
+	// groups are replaced wholesale; entries keep their IDs and are relinked below
+	_, err = tx.Exec(`DELETE FROM workout_entry_groups WHERE workout_id = $1`, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	groupIDs, err := insertEntryGroups(tx, workout)
+	if err != nil {
+		return err
 	}
 
 	for _, entry := range workout.Entries {
-		query = `UPDATE workout_entries SET exercise_name = $1, sets = $2, reps = $3, duration_seconds = $4, weight = $5, notes = $6, order_index = $7
-			WHERE id = $8`
-		_, err := tx.Exec(query, entry.ExerciseName, entry.Sets, entry.Reps, entry.DurationSeconds, entry.Weight, entry.Notes, entry.OrderIndex, entry.ID)
+		repeats, workSeconds, workMeters, restSeconds := intervalColumns(entry)
+		query = `UPDATE workout_entries SET exercise_name = $1, sets = $2, reps = $3, duration_seconds = $4, weight = $5, notes = $6, order_index = $7, group_id = $8,
+				distance_meters = $11, pace_seconds_per_km = $12, incline_percent = $13, resistance_level = $14,
+				interval_repeats = $15, interval_work_seconds = $16, interval_work_meters = $17, interval_rest_seconds = $18, rpe = $19,
+				target_rest_seconds = $20
+			WHERE id = $9 AND workout_id = $10`
+		_, err := tx.Exec(query, entry.ExerciseName, entry.Sets, entry.Reps, entry.DurationSeconds, entry.Weight, entry.Notes, entry.OrderIndex, entryGroupID(entry, groupIDs), entry.ID, workout.ID,
+			entry.DistanceMeters, entry.PaceSecondsPerKm, entry.InclinePercent, entry.ResistanceLevel, repeats, workSeconds, workMeters, restSeconds, entry.RPE,
+			entry.TargetRestSeconds)
 		if err != nil {
 			return err
 		}
 	}
+	nestGroupEntries(workout)
+
+	// calories follow the updated entries unless the client supplied them
+	_, err = s.reestimateCalories(tx, workout)
+	if err != nil {
+		return err
+	}
+
+	if workout.Tags != nil {
+		err = setWorkoutTags(tx, workout)
+	} else {
+		err = loadWorkoutTags(tx, workout)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = refreshSearchVector(tx, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	err = recomputePersonalRecords(tx, workout.UserID, append(previousNames, entryExerciseNames(workout.Entries)...))
+	if err != nil {
+		return err
+	}
+
+	err = refreshGoals(tx, workout.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = recordRevision(tx, int64(workout.ID), RevisionUpdate, &actorID)
+	if err != nil {
+		return err
+	}
 
 	return tx.Commit()
 }
 
-func (s *PostgresWorkoutStore) DeleteWorkout(id int64) error {
+// DeleteWorkout moves the workout to the trash. It keeps its entries so it
+// can be restored until PurgeTrashedWorkouts removes it for good.
+func (s *PostgresWorkoutStore) DeleteWorkout(id, actorID int64) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	query := `DELETE FROM workout_entries WHERE workout_id = $1`
-	_, err = tx.Exec(query, id)
+	err = trashWorkout(tx, id, &actorID)
 	if err != nil {
 		return err
 	}
 
-	query = `DELETE FROM workouts WHERE id = $1`
-	result, err := tx.Exec(query, id)
+	return tx.Commit()
+}
+
+// trashWorkout moves a workout to the trash and records the deletion by
+// actorID, which is nil for background jobs. Records and goals are
+// recomputed without it.
+func trashWorkout(q querier, id int64, actorID *int64) error {
+	err := ensureBaselineRevision(q, id)
 	if err != nil {
 		return err
 	}
 
-	// Check if any row was affected
-	rowsAffected, err := result.RowsAffected()
+	previousNames, err := workoutExerciseNames(q, id)
 	if err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+
+	var userID int64
+	query := `UPDATE workouts SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING user_id`
+	err = q.QueryRow(query, id).Scan(&userID)
+	if err != nil {
+		return err
 	}
 
-	return tx.Commit()
+	err = recomputePersonalRecords(q, userID, previousNames)
+	if err != nil {
+		return err
+	}
+
+	err = refreshGoals(q, userID)
+	if err != nil {
+		return err
+	}
+
+	return recordRevision(q, id, RevisionDelete, actorID)
 }
 
-func (s *PostgresWorkoutStore) GetAllWorkouts() ([]*Workout, error) {
-	query := `SELECT id, user_id, title, description, duration_minutes, calories_burned, created_at, updated_at
-		FROM workouts`
-	rows, err := s.db.Query(query)
+// GetAllWorkouts returns every workout the viewer may see that matches the
+// filter, newest first.
+func (s *PostgresWorkoutStore) GetAllWorkouts(viewerID int64, filter WorkoutFilter) ([]*Workout, error) {
+	query := `SELECT ` + workoutColumns + `
+		FROM workouts w
+		WHERE w.deleted_at IS NULL AND ` + workoutVisibleTo("$1")
+	args := []any{viewerID}
+
+	tags := []string{}
+	for _, tag := range filter.Tags {
+		if tag = normalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		query += ` AND ` + tagFilterClause("$2", "$3")
+		args = append(args, tags, len(tags))
+	}
+
+	query += ` ORDER BY created_at DESC, id DESC`
+	return s.queryWorkouts(query, args...)
+}
+
+// GetWorkoutsForUser returns the user's workouts, newest first.
+func (s *PostgresWorkoutStore) GetWorkoutsForUser(userID int64) ([]*Workout, error) {
+	query := `SELECT ` + workoutColumns + `
+		FROM workouts WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC`
+	return s.queryWorkouts(query, userID)
+}
+
+// queryWorkouts runs a query selecting workoutColumns and loads the details
+// of every workout it returns.
+func (s *PostgresWorkoutStore) queryWorkouts(query string, args ...any) ([]*Workout, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -186,35 +635,10 @@ func (s *PostgresWorkoutStore) GetAllWorkouts() ([]*Workout, error) {
 
 	workouts := []*Workout{}
 	for rows.Next() {
-		workout := &Workout{}
-		err := rows.Scan(&workout.ID, &workout.UserID, &workout.Title, &workout.Description, &workout.DurationMinutes, &workout.CaloriesBurned, &workout.CreatedAt, &workout.UpdatedAt)
+		workout, err := scanWorkout(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		// Load entries for this workout
-		entriesQuery := `SELECT id, exercise_name, sets, reps, duration_seconds, weight, notes, order_index
-			FROM workout_entries WHERE workout_id = $1`
-		entryRows, err := s.db.Query(entriesQuery, workout.ID)
-		if err != nil {
-			return nil, err
-		}
-		defer entryRows.Close()
-
-		workout.Entries = []WorkoutEntry{}
-		for entryRows.Next() {
-			entry := WorkoutEntry{}
-			err := entryRows.Scan(&entry.ID, &entry.ExerciseName, &entry.Sets, &entry.Reps, &entry.DurationSeconds, &entry.Weight, &entry.Notes, &entry.OrderIndex)
-			if err != nil {
-				return nil, err
-			}
-			workout.Entries = append(workout.Entries, entry)
-		}
-
-		if err = entryRows.Err(); err != nil {
-			return nil, err
-		}
-
 		workouts = append(workouts, workout)
 	}
 
@@ -222,11 +646,18 @@ func (s *PostgresWorkoutStore) GetAllWorkouts() ([]*Workout, error) {
 		return nil, err
 	}
 
+	// Load entries for each workout
+	for _, workout := range workouts {
+		if err := loadWorkoutDetails(s.db, workout); err != nil {
+			return nil, err
+		}
+	}
+
 	return workouts, nil
 }
 
 func (s *PostgresWorkoutStore) GetWorkoutOwner(id int64) (int, error) {
-	query := `SELECT user_id FROM workouts WHERE id = $1`
+	query := `SELECT user_id FROM workouts WHERE id = $1 AND deleted_at IS NULL`
 	var userID int
 	err := s.db.QueryRow(query, id).Scan(&userID)
 	if err != nil {
@@ -234,3 +665,177 @@ func (s *PostgresWorkoutStore) GetWorkoutOwner(id int64) (int, error) {
 	}
 	return userID, nil
 }
+
+// CanViewWorkout reports whether the viewer may see the workout under its
+// visibility, follows, coaching and blocks. It returns sql.ErrNoRows when the
+// workout does not exist or is in the trash.
+func (s *PostgresWorkoutStore) CanViewWorkout(id, viewerID int64) (bool, error) {
+	query := `SELECT ` + workoutVisibleTo("$2") + ` FROM workouts w WHERE w.id = $1 AND w.deleted_at IS NULL`
+	var visible bool
+	err := s.db.QueryRow(query, id, viewerID).Scan(&visible)
+	return visible, err
+}
+
+// loadWorkoutDetails fills in the workout's entries, groups and tags.
+func loadWorkoutDetails(q querier, workout *Workout) error {
+	query := `SELECT id, group_type, rounds, rest_between_exercises_seconds, rest_between_rounds_seconds, order_index
+		FROM workout_entry_groups WHERE workout_id = $1 ORDER BY order_index, id`
+	groupRows, err := q.Query(query, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	workout.Groups = []EntryGroup{}
+	groupIndexes := map[int]int{}
+	for groupRows.Next() {
+		group := EntryGroup{}
+		err := groupRows.Scan(&group.ID, &group.GroupType, &group.Rounds, &group.RestBetweenExercisesSeconds, &group.RestBetweenRoundsSeconds, &group.OrderIndex)
+		if err != nil {
+			groupRows.Close()
+			return err
+		}
+		groupIndexes[group.ID] = len(workout.Groups)
+		workout.Groups = append(workout.Groups, group)
+	}
+	groupRows.Close()
+	if err := groupRows.Err(); err != nil {
+		return err
+	}
+
+	query = `SELECT id, exercise_name, sets, reps, duration_seconds, weight, notes, order_index, group_id,
+			distance_meters, pace_seconds_per_km, incline_percent, resistance_level,
+			interval_repeats, interval_work_seconds, interval_work_meters, interval_rest_seconds, rpe, target_rest_seconds
+		FROM workout_entries WHERE workout_id = $1 ORDER BY order_index, id`
+	rows, err := q.Query(query, workout.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	workout.Entries = []WorkoutEntry{}
+	for rows.Next() {
+		entry := WorkoutEntry{}
+		var groupID, repeats, workSeconds, restSeconds *int
+		var workMeters *float64
+		err := rows.Scan(&entry.ID, &entry.ExerciseName, &entry.Sets, &entry.Reps, &entry.DurationSeconds, &entry.Weight, &entry.Notes, &entry.OrderIndex, &groupID,
+			&entry.DistanceMeters, &entry.PaceSecondsPerKm, &entry.InclinePercent, &entry.ResistanceLevel,
+			&repeats, &workSeconds, &workMeters, &restSeconds, &entry.RPE, &entry.TargetRestSeconds)
+		if err != nil {
+			return err
+		}
+		entry.Intervals = entryIntervals(repeats, workSeconds, workMeters, restSeconds)
+		if groupID != nil {
+			if index, ok := groupIndexes[*groupID]; ok {
+				entry.GroupIndex = &index
+			}
+		}
+		workout.Entries = append(workout.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	query = `SELECT s.entry_id, s.id, s.set_number, s.reps, s.weight, s.duration_seconds, s.rpe, s.warmup, s.started_at, s.completed_at
+		FROM workout_sets s
+		INNER JOIN workout_entries e ON e.id = s.entry_id
+		WHERE e.workout_id = $1
+		ORDER BY s.entry_id, s.set_number`
+	setRows, err := q.Query(query, workout.ID)
+	if err != nil {
+		return err
+	}
+	defer setRows.Close()
+
+	setLogs := map[int][]WorkoutSet{}
+	for setRows.Next() {
+		var entryID int
+		set := WorkoutSet{}
+		err := setRows.Scan(&entryID, &set.ID, &set.SetNumber, &set.Reps, &set.Weight, &set.DurationSeconds, &set.RPE, &set.Warmup, &set.StartedAt, &set.CompletedAt)
+		if err != nil {
+			return err
+		}
+		setLogs[entryID] = append(setLogs[entryID], set)
+	}
+	if err := setRows.Err(); err != nil {
+		return err
+	}
+	for i := range workout.Entries {
+		workout.Entries[i].SetLog = setLogs[workout.Entries[i].ID]
+		setRests(workout.Entries[i].SetLog)
+		if workout.Entries[i].SetLog == nil {
+			workout.Entries[i].SetLog = []WorkoutSet{}
+		}
+	}
+
+	nestGroupEntries(workout)
+	if err := loadWorkoutTrack(q, workout); err != nil {
+		return err
+	}
+	return loadWorkoutTags(q, workout)
+}
+
+func insertEntryGroups(q querier, workout *Workout) ([]int, error) {
+	groupIDs := make([]int, len(workout.Groups))
+	for i := range workout.Groups {
+		group := &workout.Groups[i]
+		query := `INSERT INTO workout_entry_groups (workout_id, group_type, rounds, rest_between_exercises_seconds, rest_between_rounds_seconds, order_index)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+		err := q.QueryRow(query, workout.ID, group.GroupType, group.Rounds, group.RestBetweenExercisesSeconds, group.RestBetweenRoundsSeconds, group.OrderIndex).Scan(&group.ID)
+		if err != nil {
+			return nil, err
+		}
+		groupIDs[i] = group.ID
+	}
+	return groupIDs, nil
+}
+
+// insertEntries stores the workout's entries, replacing workout.Entries with
+// the inserted rows.
+func insertEntries(q querier, workout *Workout, groupIDs []int) error {
+	insertedEntries := make([]WorkoutEntry, 0, len(workout.Entries))
+	for _, entry := range workout.Entries {
+		repeats, workSeconds, workMeters, restSeconds := intervalColumns(entry)
+		query := `INSERT INTO workout_entries (workout_id, exercise_name, sets, reps, duration_seconds, weight, notes, order_index, group_id,
+				distance_meters, pace_seconds_per_km, incline_percent, resistance_level,
+				interval_repeats, interval_work_seconds, interval_work_meters, interval_rest_seconds, rpe, target_rest_seconds)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19) RETURNING id`
+		err := q.QueryRow(query, workout.ID, entry.ExerciseName, entry.Sets, entry.Reps, entry.DurationSeconds, entry.Weight, entry.Notes, entry.OrderIndex, entryGroupID(entry, groupIDs),
+			entry.DistanceMeters, entry.PaceSecondsPerKm, entry.InclinePercent, entry.ResistanceLevel,
+			repeats, workSeconds, workMeters, restSeconds, entry.RPE, entry.TargetRestSeconds).Scan(&entry.ID)
+		if err != nil {
+			return err
+		}
+		if entry.SetLog == nil {
+			entry.SetLog = []WorkoutSet{}
+		}
+		insertedEntries = append(insertedEntries, entry)
+	}
+	workout.Entries = insertedEntries
+	nestGroupEntries(workout)
+	return nil
+}
+
+func entryGroupID(entry WorkoutEntry, groupIDs []int) *int {
+	if entry.GroupIndex == nil {
+		return nil
+	}
+	return &groupIDs[*entry.GroupIndex]
+}
+
+// nestGroupEntries copies grouped entries into their groups so clients can
+// read either the flat Entries list or the nested Groups view.
+func nestGroupEntries(workout *Workout) {
+	if workout.Groups == nil {
+		workout.Groups = []EntryGroup{}
+	}
+	for i := range workout.Groups {
+		workout.Groups[i].Entries = []WorkoutEntry{}
+	}
+	for _, entry := range workout.Entries {
+		if entry.GroupIndex != nil {
+			group := &workout.Groups[*entry.GroupIndex]
+			group.Entries = append(group.Entries, entry)
+		}
+	}
+}
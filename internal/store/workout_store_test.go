@@ -2,7 +2,9 @@ package store
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/mounis-bhat/rest-api-go/internal/utils"
@@ -22,13 +24,23 @@ func setupTestDb(t *testing.T) *sql.DB {
 		t.Fatalf("failed to run migrations: %v", err)
 	}
 
-	_, err = db.Exec("TRUNCATE workouts, workout_entries CASCADE")
+	_, err = db.Exec("TRUNCATE users, workouts, workout_entries CASCADE")
 	if err != nil {
 		t.Fatalf("failed to truncate tables: %v", err)
 	}
 	return db
 }
 
+// createTestUser inserts a user for workouts to belong to and returns its ID.
+func createTestUser(t *testing.T, db *sql.DB, username string) int64 {
+	t.Helper()
+	var id int64
+	query := `INSERT INTO users (username, email, password_hash) VALUES ($1, $2, 'hash') RETURNING id`
+	err := db.QueryRow(query, username, username+"@example.com").Scan(&id)
+	require.NoError(t, err)
+	return id
+}
+
 func TestCreateWorkout(t *testing.T) {
 	db := setupTestDb(t)
 	defer db.Close()
@@ -83,7 +95,7 @@ func TestCreateWorkout(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			createdWorkout, err := store.CreateWorkout(tt.workout)
+			createdWorkout, err := store.CreateWorkout(tt.workout, tt.workout.UserID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -113,3 +125,259 @@ func TestCreateWorkout(t *testing.T) {
 	}
 
 }
+
+func TestCleanupAbandonedWorkouts(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	store := NewPostgresWorkoutStore(db)
+	lifterID := createTestUser(t, db, "lifter")
+	idleID := createTestUser(t, db, "idle")
+	currentID := createTestUser(t, db, "current")
+
+	abandoned, err := store.StartWorkout(&Workout{UserID: lifterID, Title: "Push"})
+	require.NoError(t, err)
+	lastSet := time.Now().Add(-20 * time.Hour).Truncate(time.Second)
+	_, err = store.LogSet(int64(abandoned.ID), &LoggedSet{ExerciseName: "Bench Press", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100), CompletedAt: &lastSet}, lifterID)
+	require.NoError(t, err)
+
+	empty, err := store.StartWorkout(&Workout{UserID: idleID, Title: "Pull"})
+	require.NoError(t, err)
+	current, err := store.StartWorkout(&Workout{UserID: currentID, Title: "Legs"})
+	require.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE workouts SET started_at = NOW() - INTERVAL '1 day' WHERE id IN ($1, $2)`, abandoned.ID, empty.ID)
+	require.NoError(t, err)
+
+	count, err := store.CleanupAbandonedWorkouts(12 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// the session with sets is finished at its last set
+	finished, err := store.GetWorkoutById(int64(abandoned.ID))
+	require.NoError(t, err)
+	assert.Equal(t, WorkoutCompleted, finished.Status)
+	require.NotNil(t, finished.EndedAt)
+	assert.WithinDuration(t, lastSet, *finished.EndedAt, time.Second)
+
+	// the empty one goes to the trash with its history kept
+	trashed, err := store.GetTrashedWorkouts(idleID)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, empty.ID, trashed[0].ID)
+	revisions, err := store.GetRevisions(int64(empty.ID))
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, RevisionDelete, revisions[0].Action)
+	assert.Nil(t, revisions[0].ActorID)
+	assert.Equal(t, RevisionCreate, revisions[1].Action)
+
+	// and no longer blocks a new session
+	_, err = store.StartWorkout(&Workout{UserID: idleID, Title: "Pull again"})
+	require.NoError(t, err)
+
+	active, err := store.GetActiveWorkout(currentID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, current.ID, active.ID)
+}
+
+func TestSetWarmupSets(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	store := NewPostgresWorkoutStore(db)
+	userID := createTestUser(t, db, "squatter")
+
+	workout, err := store.StartWorkout(&Workout{UserID: userID, Title: "Legs"})
+	require.NoError(t, err)
+	workoutID := int64(workout.ID)
+	entry, err := store.LogSet(workoutID, &LoggedSet{ExerciseName: "Squat", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100)}, userID)
+	require.NoError(t, err)
+	entryID := int64(entry.ID)
+	_, err = store.LogSet(workoutID, &LoggedSet{EntryID: &entry.ID, Reps: utils.IntPtr(4), Weight: utils.Float64Ptr(100)}, userID)
+	require.NoError(t, err)
+
+	warmup := func(weights ...float64) []WorkoutSet {
+		sets := []WorkoutSet{}
+		for _, weight := range weights {
+			sets = append(sets, WorkoutSet{Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(weight), Warmup: true})
+		}
+		return sets
+	}
+	// assertSets checks the set log in order: warm-ups first, then the
+	// working sets with their reps
+	assertSets := func(entry *WorkoutEntry, warmupWeights []float64, workingReps []int) {
+		t.Helper()
+		require.Len(t, entry.SetLog, len(warmupWeights)+len(workingReps))
+		for i, set := range entry.SetLog {
+			assert.Equal(t, i+1, set.SetNumber)
+			if i < len(warmupWeights) {
+				assert.True(t, set.Warmup)
+				assert.Equal(t, warmupWeights[i], *set.Weight)
+				assert.Nil(t, set.StartedAt, "warm-ups are untimed")
+				assert.Nil(t, set.CompletedAt, "warm-ups are untimed")
+				continue
+			}
+			assert.False(t, set.Warmup)
+			assert.Equal(t, workingReps[i-len(warmupWeights)], *set.Reps)
+			assert.NotNil(t, set.CompletedAt)
+		}
+		// the summary only counts working sets
+		assert.Equal(t, len(workingReps), entry.Sets)
+		assert.Equal(t, 100.0, *entry.Weight)
+	}
+
+	entry, err = store.SetWarmupSets(workoutID, entryID, warmup(20, 60, 80), userID)
+	require.NoError(t, err)
+	assertSets(entry, []float64{20, 60, 80}, []int{5, 4})
+
+	// calling it again replaces the warm-ups and renumbers the working sets
+	entry, err = store.SetWarmupSets(workoutID, entryID, warmup(40), userID)
+	require.NoError(t, err)
+	assertSets(entry, []float64{40}, []int{5, 4})
+
+	reloaded, err := store.GetWorkoutById(workoutID)
+	require.NoError(t, err)
+	require.Len(t, reloaded.Entries, 1)
+	assertSets(&reloaded.Entries[0], []float64{40}, []int{5, 4})
+
+	_, err = store.SetWarmupSets(workoutID, entryID+1, warmup(40), userID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestValidateEntryGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		workout *Workout
+		wantErr bool
+	}{
+		{
+			name: "valid superset",
+			workout: &Workout{
+				Groups: []EntryGroup{{GroupType: GroupSuperset, Rounds: 3}},
+				Entries: []WorkoutEntry{
+					{ExerciseName: "Bench Press", GroupIndex: utils.IntPtr(0)},
+					{ExerciseName: "Barbell Row", GroupIndex: utils.IntPtr(0)},
+					{ExerciseName: "Plank"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "superset with three entries",
+			workout: &Workout{
+				Groups: []EntryGroup{{GroupType: GroupSuperset}},
+				Entries: []WorkoutEntry{
+					{ExerciseName: "Bench Press", GroupIndex: utils.IntPtr(0)},
+					{ExerciseName: "Barbell Row", GroupIndex: utils.IntPtr(0)},
+					{ExerciseName: "Dips", GroupIndex: utils.IntPtr(0)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "entry references missing group",
+			workout: &Workout{
+				Entries: []WorkoutEntry{{ExerciseName: "Squat", GroupIndex: utils.IntPtr(1)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown group type",
+			workout: &Workout{
+				Groups: []EntryGroup{{GroupType: "tabata"}},
+				Entries: []WorkoutEntry{
+					{ExerciseName: "Burpees", GroupIndex: utils.IntPtr(0)},
+					{ExerciseName: "Jump Rope", GroupIndex: utils.IntPtr(0)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEntryGroups(tt.workout)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWorkout)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   WorkoutEntry
+		wantErr bool
+	}{
+		{name: "strength", entry: WorkoutEntry{ExerciseName: "Squat", Sets: 3, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100)}},
+		{name: "distance only", entry: WorkoutEntry{ExerciseName: "Running", DistanceMeters: utils.Float64Ptr(5000)}},
+		{name: "intervals only", entry: WorkoutEntry{ExerciseName: "Rowing", Intervals: &Intervals{Repeats: 8, WorkMeters: utils.Float64Ptr(500), RestSeconds: 90}}},
+		{name: "no metrics", entry: WorkoutEntry{ExerciseName: "Running", Sets: 1, InclinePercent: utils.Float64Ptr(2)}, wantErr: true},
+		{name: "zero distance", entry: WorkoutEntry{ExerciseName: "Running", DistanceMeters: utils.Float64Ptr(0)}, wantErr: true},
+		{name: "incline too steep", entry: WorkoutEntry{ExerciseName: "Walking", DurationSeconds: utils.IntPtr(600), InclinePercent: utils.Float64Ptr(45)}, wantErr: true},
+		{name: "resistance out of range", entry: WorkoutEntry{ExerciseName: "Cycling", DurationSeconds: utils.IntPtr(600), ResistanceLevel: utils.IntPtr(101)}, wantErr: true},
+		{name: "intervals without work", entry: WorkoutEntry{ExerciseName: "Running", Intervals: &Intervals{Repeats: 4, RestSeconds: 60}}, wantErr: true},
+		{name: "intervals without repeats", entry: WorkoutEntry{ExerciseName: "Running", Intervals: &Intervals{WorkSeconds: utils.IntPtr(60)}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEntries(&Workout{Entries: []WorkoutEntry{tt.entry}})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWorkout)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	workout := &Workout{Entries: []WorkoutEntry{{ExerciseName: "Running", DistanceMeters: utils.Float64Ptr(5000), DurationSeconds: utils.IntPtr(1500)}}}
+	require.NoError(t, validateEntries(workout))
+	assert.Equal(t, 300, *workout.Entries[0].PaceSecondsPerKm, "pace is derived from distance and duration")
+}
+
+func TestLoggedSetValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     LoggedSet
+		wantErr bool
+	}{
+		{name: "by exercise name", set: LoggedSet{ExerciseName: "Squat", Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(100)}},
+		{name: "by entry id", set: LoggedSet{EntryID: utils.IntPtr(3), DurationSeconds: utils.IntPtr(60)}},
+		{name: "no target entry", set: LoggedSet{Reps: utils.IntPtr(5)}, wantErr: true},
+		{name: "no values", set: LoggedSet{ExerciseName: "Squat"}, wantErr: true},
+		{name: "negative reps", set: LoggedSet{ExerciseName: "Squat", Reps: utils.IntPtr(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.set.validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWorkout)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]string{" Leg  Day", "deload", "leg day"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leg day", "deload"}, tags)
+
+	tags, err = normalizeTags(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, tags)
+
+	_, err = normalizeTags([]string{"  "})
+	assert.ErrorIs(t, err, ErrInvalidWorkout)
+
+	_, err = normalizeTags([]string{strings.Repeat("a", maxTagLength+1)})
+	assert.ErrorIs(t, err, ErrInvalidWorkout)
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/sensor"
+	"github.com/mounis-bhat/rest-api-go/internal/track"
+)
+
+var ErrWorkoutAlreadyImported = errors.New("workout has already been imported")
+
+// ImportTrackWorkout stores a workout recorded as a GPS track together with
+// the track's summary and raw points. It returns ErrWorkoutAlreadyImported
+// when the user already has a workout with the same title and start time.
+func (s *PostgresWorkoutStore) ImportTrackWorkout(workout *Workout, summary *track.Summary, points []track.Point, actorID int64) (*Workout, error) {
+	if err := validateImportedWorkout(workout); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	exists, err := importedWorkoutExists(tx, workout)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrWorkoutAlreadyImported
+	}
+
+	// heart rates recorded with the track are kept as samples for zones, and
+	// estimate calories when the device did not report them
+	if err := s.insertImportedWorkout(tx, workout, heartRateSamples(points), actorID); err != nil {
+		return nil, err
+	}
+
+	splits, err := json.Marshal(summary.Splits)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO workout_tracks (workout_id, sport, distance_meters, elapsed_seconds, moving_seconds, elevation_gain_meters,
+			average_speed_kmh, average_pace_seconds_per_km, average_heart_rate, max_heart_rate, splits)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err = tx.Exec(query, workout.ID, summary.Sport, summary.DistanceMeters, summary.ElapsedSeconds, summary.MovingSeconds, summary.ElevationGainMeters,
+		summary.AverageSpeedKmh, summary.AveragePaceSecondsPerKm, summary.AverageHeartRate, summary.MaxHeartRate, splits)
+	if err != nil {
+		return nil, err
+	}
+
+	// points are sent as parallel arrays so a long recording is a single
+	// statement rather than one per point
+	seqs := make([]int, len(points))
+	times := make([]time.Time, len(points))
+	latitudes := make([]*float64, len(points))
+	longitudes := make([]*float64, len(points))
+	elevations := make([]*float64, len(points))
+	heartRates := make([]*int, len(points))
+	distances := make([]*float64, len(points))
+	for i, point := range points {
+		seqs[i] = i
+		times[i] = point.Time
+		latitudes[i] = point.Latitude
+		longitudes[i] = point.Longitude
+		elevations[i] = point.Elevation
+		heartRates[i] = point.HeartRate
+		distances[i] = point.Distance
+	}
+	query = `INSERT INTO workout_track_points (workout_id, seq, recorded_at, latitude, longitude, elevation, heart_rate, distance_meters)
+		SELECT $1, * FROM unnest($2::INTEGER[], $3::TIMESTAMPTZ[], $4::DOUBLE PRECISION[], $5::DOUBLE PRECISION[], $6::DOUBLE PRECISION[], $7::INTEGER[], $8::DOUBLE PRECISION[])`
+	_, err = tx.Exec(query, workout.ID, seqs, times, latitudes, longitudes, elevations, heartRates, distances)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recomputePersonalRecords(tx, workout.UserID, entryExerciseNames(workout.Entries))
+	if err != nil {
+		return nil, err
+	}
+
+	err = refreshGoals(tx, workout.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetWorkoutById(int64(workout.ID))
+}
+
+// heartRateSamples returns a sample for each point with a heart rate,
+// keeping the first of points recorded at the same time.
+func heartRateSamples(points []track.Point) []sensor.Sample {
+	samples := []sensor.Sample{}
+	for _, point := range points {
+		if point.HeartRate == nil {
+			continue
+		}
+		if len(samples) > 0 && !point.Time.After(samples[len(samples)-1].Time) {
+			continue
+		}
+		samples = append(samples, sensor.Sample{Time: point.Time, HeartRate: point.HeartRate})
+	}
+	return samples
+}
+
+// loadWorkoutTrack sets workout.Track when the workout was recorded as a GPS
+// track.
+func loadWorkoutTrack(q querier, workout *Workout) error {
+	query := `SELECT sport, distance_meters, elapsed_seconds, moving_seconds, elevation_gain_meters,
+			average_speed_kmh, average_pace_seconds_per_km, average_heart_rate, max_heart_rate, splits
+		FROM workout_tracks WHERE workout_id = $1`
+	summary := &track.Summary{}
+	var splits []byte
+	err := q.QueryRow(query, workout.ID).Scan(&summary.Sport, &summary.DistanceMeters, &summary.ElapsedSeconds, &summary.MovingSeconds, &summary.ElevationGainMeters,
+		&summary.AverageSpeedKmh, &summary.AveragePaceSecondsPerKm, &summary.AverageHeartRate, &summary.MaxHeartRate, &splits)
+	if err == sql.ErrNoRows {
+		workout.Track = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(splits, &summary.Splits); err != nil {
+		return err
+	}
+	workout.Track = summary
+	return nil
+}
+
+// GetTrackPoints returns the workout's recorded points in order, or nil when
+// it has no track.
+func (s *PostgresWorkoutStore) GetTrackPoints(workoutID int64) ([]track.Point, error) {
+	query := `SELECT recorded_at, latitude, longitude, elevation, heart_rate, distance_meters
+		FROM workout_track_points WHERE workout_id = $1 ORDER BY seq`
+	rows, err := s.db.Query(query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []track.Point
+	for rows.Next() {
+		point := track.Point{}
+		err := rows.Scan(&point.Time, &point.Latitude, &point.Longitude, &point.Elevation, &point.HeartRate, &point.Distance)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, rows.Err()
+}
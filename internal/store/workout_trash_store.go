@@ -0,0 +1,97 @@
+package store
+
+import (
+	"time"
+)
+
+// GetTrashedWorkouts returns the user's trashed workouts, most recently
+// deleted first.
+func (s *PostgresWorkoutStore) GetTrashedWorkouts(userID int64) ([]*Workout, error) {
+	query := `SELECT ` + workoutColumns + `
+		FROM workouts WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`
+	return s.queryWorkouts(query, userID)
+}
+
+// RestoreWorkout moves one of the user's workouts out of the trash. It
+// returns sql.ErrNoRows when the user has no such workout in the trash and
+// ErrActiveWorkoutExists when restoring a live session would make it the
+// user's second active workout.
+func (s *PostgresWorkoutStore) RestoreWorkout(id, userID int64) (*Workout, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = ensureBaselineRevision(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE workouts SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+	result, err := tx.Exec(query, id, userID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrActiveWorkoutExists
+		}
+		return nil, err
+	}
+	if err := requireRowsAffected(result); err != nil {
+		return nil, err
+	}
+
+	names, err := workoutExerciseNames(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = recomputePersonalRecords(tx, userID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	err = refreshGoals(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordRevision(tx, id, RevisionRestore, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetWorkoutById(id)
+}
+
+// PurgeTrashedWorkouts permanently deletes workouts that have been in the
+// trash for longer than olderThan, along with everything that references
+// them except their revisions, which stay as an audit trail until the owner
+// is deleted. It returns the number of workouts purged.
+func (s *PostgresWorkoutStore) PurgeTrashedWorkouts(olderThan time.Duration) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM workouts WHERE deleted_at < $1`, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM tags t WHERE NOT EXISTS (SELECT 1 FROM workout_tags wt WHERE wt.tag_id = t.id)`)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), tx.Commit()
+}
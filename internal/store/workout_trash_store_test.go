@@ -0,0 +1,139 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mounis-bhat/rest-api-go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkoutTrash(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	store := NewPostgresWorkoutStore(db)
+	social := NewPostgresSocialStore(db)
+	analytics := NewPostgresAnalyticsStore(db)
+	records := NewPostgresPersonalRecordStore(db)
+
+	ownerID := createTestUser(t, db, "owner")
+	followerID := createTestUser(t, db, "follower")
+	require.NoError(t, social.Follow(followerID, ownerID))
+
+	squats := func(title string, weight float64) *Workout {
+		workout, err := store.CreateWorkout(&Workout{
+			UserID:          ownerID,
+			Title:           title,
+			DurationMinutes: 45,
+			Visibility:      VisibilityPublic,
+			Entries:         []WorkoutEntry{{ExerciseName: "Squat", Sets: 3, Reps: utils.IntPtr(5), Weight: utils.Float64Ptr(weight)}},
+		}, ownerID)
+		require.NoError(t, err)
+		return workout
+	}
+	kept := squats("Easy squats", 100)
+	trashed := squats("Heavy squats", 140)
+
+	require.NoError(t, store.DeleteWorkout(int64(trashed.ID), ownerID))
+
+	// trashed workouts disappear from everything but the trash
+	assertVisible := func(want ...int) {
+		t.Helper()
+
+		workouts, err := store.GetAllWorkouts(ownerID, WorkoutFilter{})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, workoutIDs(workouts), "list")
+
+		results, err := store.SearchWorkouts(ownerID, "squats", 20)
+		require.NoError(t, err)
+		found := []int{}
+		for _, result := range results {
+			found = append(found, result.Workout.ID)
+		}
+		assert.ElementsMatch(t, want, found, "search")
+
+		feed, err := social.GetFeed(followerID, nil, 20)
+		require.NoError(t, err)
+		found = []int{}
+		for _, item := range feed {
+			found = append(found, item.Workout.ID)
+		}
+		assert.ElementsMatch(t, want, found, "feed")
+
+		summary, err := analytics.GetSummary(AnalyticsQuery{
+			UserID:   ownerID,
+			Period:   "week",
+			Timezone: "UTC",
+			From:     time.Now().Add(-24 * time.Hour),
+			To:       time.Now().Add(24 * time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, len(want), summary.Totals.Sessions, "analytics")
+	}
+	maxWeight := func() float64 {
+		t.Helper()
+		current, err := records.GetPersonalRecords(ownerID)
+		require.NoError(t, err)
+		for _, record := range current {
+			if record.RecordType == RecordMaxWeight {
+				return record.Value
+			}
+		}
+		return 0
+	}
+
+	assertVisible(kept.ID)
+	assert.Equal(t, 100.0, maxWeight())
+	_, err := store.GetWorkoutById(int64(trashed.ID))
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	trash, err := store.GetTrashedWorkouts(ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{trashed.ID}, workoutIDs(trash))
+
+	// restoring brings it and its records back
+	restored, err := store.RestoreWorkout(int64(trashed.ID), ownerID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assertVisible(kept.ID, trashed.ID)
+	assert.Equal(t, 140.0, maxWeight())
+
+	_, err = store.RestoreWorkout(int64(trashed.ID), ownerID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// only workouts trashed for longer than the retention are purged
+	require.NoError(t, store.DeleteWorkout(int64(kept.ID), ownerID))
+	require.NoError(t, store.DeleteWorkout(int64(trashed.ID), ownerID))
+	_, err = db.Exec(`UPDATE workouts SET deleted_at = NOW() - INTERVAL '40 days' WHERE id = $1`, kept.ID)
+	require.NoError(t, err)
+
+	count, err := store.PurgeTrashedWorkouts(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	trash, err = store.GetTrashedWorkouts(ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{trashed.ID}, workoutIDs(trash))
+
+	var remaining int
+	err = db.QueryRow(`SELECT COUNT(*) FROM workout_revisions WHERE workout_id = $1`, kept.ID).Scan(&remaining)
+	require.NoError(t, err)
+	assert.NotZero(t, remaining, "purged workouts keep their history")
+
+	_, err = db.Exec(`DELETE FROM users WHERE id = $1`, ownerID)
+	require.NoError(t, err)
+	err = db.QueryRow(`SELECT COUNT(*) FROM workout_revisions WHERE workout_id = $1`, kept.ID).Scan(&remaining)
+	require.NoError(t, err)
+	assert.Zero(t, remaining, "deleting the owner removes the history")
+}
+
+func workoutIDs(workouts []*Workout) []int {
+	ids := []int{}
+	for _, workout := range workouts {
+		ids = append(ids, workout.ID)
+	}
+	return ids
+}
@@ -28,16 +28,32 @@ func GenerateToken(userID int, ttl time.Duration, scope string) (*Token, error)
 		Scope:     scope,
 	}
 
-	emptyBytes := make([]byte, 32)
-	_, err := rand.Read(emptyBytes)
+	plainText, hash, err := NewSecret()
 	if err != nil {
 		return nil, err
 	}
 
-	token.PlainText = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(emptyBytes)
-
-	hash := sha256.Sum256([]byte(token.PlainText))
-	token.Hash = hash[:]
+	token.PlainText = plainText
+	token.Hash = hash
 
 	return token, nil
 }
+
+// NewSecret returns 256 random bits encoded as URL-safe text, along with the
+// hash that should be stored in place of the text.
+func NewSecret() (string, []byte, error) {
+	emptyBytes := make([]byte, 32)
+	_, err := rand.Read(emptyBytes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	plainText := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(emptyBytes)
+	return plainText, Hash(plainText), nil
+}
+
+// Hash returns the SHA-256 hash under which a secret is stored.
+func Hash(plainText string) []byte {
+	hash := sha256.Sum256([]byte(plainText))
+	return hash[:]
+}
@@ -0,0 +1,58 @@
+package track
+
+import "time"
+
+// Feature is a GeoJSON Feature (RFC 7946).
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   *LineString    `json:"geometry"` // null for tracks without positions
+	Properties map[string]any `json:"properties"`
+}
+
+type LineString struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"` // [longitude, latitude] or [longitude, latitude, elevation]
+}
+
+// GeoJSON returns the track as a LineString feature. Elevation is included
+// in the coordinates when every positioned point has one. Per-point times
+// and heart rates follow the coordinateProperties convention understood by
+// common mapping libraries, with null for missing heart rates. Points
+// without a position are left out.
+func GeoJSON(points []Point, properties map[string]any) Feature {
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	feature := Feature{Type: "Feature", Properties: properties}
+
+	withElevation := true
+	positioned := []Point{}
+	for _, point := range points {
+		if point.hasPosition() {
+			positioned = append(positioned, point)
+			withElevation = withElevation && point.Elevation != nil
+		}
+	}
+	if len(positioned) == 0 {
+		return feature
+	}
+
+	coordinates := make([][]float64, len(positioned))
+	times := make([]string, len(positioned))
+	heartRates := make([]*int, len(positioned))
+	for i, point := range positioned {
+		coordinates[i] = []float64{*point.Longitude, *point.Latitude}
+		if withElevation {
+			coordinates[i] = append(coordinates[i], *point.Elevation)
+		}
+		times[i] = point.Time.UTC().Format(time.RFC3339)
+		heartRates[i] = point.HeartRate
+	}
+
+	feature.Geometry = &LineString{Type: "LineString", Coordinates: coordinates}
+	feature.Properties["coordinateProperties"] = map[string]any{
+		"times": times,
+		"heart": heartRates,
+	}
+	return feature
+}
@@ -0,0 +1,68 @@
+package track
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+// GPX 1.1 with Garmin's TrackPointExtension for heart rate. Field tags
+// without a namespace match any namespace, so the hr element is found
+// whatever prefix the writer used.
+type gpxDocument struct {
+	Metadata struct {
+		Name string `xml:"name"`
+	} `xml:"metadata"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Latitude  float64  `xml:"lat,attr"`
+	Longitude float64  `xml:"lon,attr"`
+	Elevation *float64 `xml:"ele"`
+	Time      string   `xml:"time"`
+	HeartRate *int     `xml:"extensions>TrackPointExtension>hr"`
+}
+
+func decodeGPX(decoder *xml.Decoder, start xml.StartElement) (*Activity, error) {
+	var doc gpxDocument
+	if err := decoder.DecodeElement(&doc, &start); err != nil {
+		return nil, err
+	}
+
+	activity := &Activity{Name: doc.Metadata.Name, Sport: SportOther, Points: []Point{}}
+	for i, trk := range doc.Tracks {
+		if i == 0 {
+			if trk.Name != "" {
+				activity.Name = trk.Name
+			}
+			activity.Sport = normalizeSport(trk.Type)
+		}
+		for _, segment := range trk.Segments {
+			for _, p := range segment.Points {
+				t, ok := parseTimestamp(p.Time)
+				if !ok {
+					continue
+				}
+				lat, lon := p.Latitude, p.Longitude
+				activity.Points = append(activity.Points, Point{
+					Time:      t,
+					Latitude:  &lat,
+					Longitude: &lon,
+					Elevation: p.Elevation,
+					HeartRate: p.HeartRate,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(activity.Points, func(i, j int) bool {
+		return activity.Points[i].Time.Before(activity.Points[j].Time)
+	})
+	return activity, nil
+}
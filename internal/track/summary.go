@@ -0,0 +1,222 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// SplitMeters is the length of each split.
+	SplitMeters = 1000.0
+	// movingSpeed is the slowest speed, in m/s, at which the athlete counts
+	// as moving. Slower stretches are stops at lights, breaks and GPS drift.
+	movingSpeed = 0.5
+	// climbThreshold is how far, in meters, the elevation has to rise above
+	// the last low point before the climb counts. It filters out the noise
+	// in GPS altitude.
+	climbThreshold = 3.0
+	earthRadius    = 6371008.8
+)
+
+type Summary struct {
+	Sport                   string  `json:"sport"`
+	DistanceMeters          float64 `json:"distance_meters"`
+	ElapsedSeconds          int     `json:"elapsed_seconds"`
+	MovingSeconds           int     `json:"moving_seconds"`
+	ElevationGainMeters     float64 `json:"elevation_gain_meters"`
+	AverageSpeedKmh         float64 `json:"average_speed_kmh"`           // over moving time
+	AveragePaceSecondsPerKm int     `json:"average_pace_seconds_per_km"` // over moving time, 0 without distance
+	AverageHeartRate        *int    `json:"average_heart_rate"`          // mean of the recorded samples
+	MaxHeartRate            *int    `json:"max_heart_rate"`
+	Splits                  []Split `json:"splits"`
+}
+
+// Split covers SplitMeters of the track; the last one is usually shorter.
+type Split struct {
+	Number              int     `json:"number"`
+	DistanceMeters      float64 `json:"distance_meters"`
+	MovingSeconds       int     `json:"moving_seconds"`
+	PaceSecondsPerKm    int     `json:"pace_seconds_per_km"`
+	SpeedKmh            float64 `json:"speed_kmh"`
+	ElevationGainMeters float64 `json:"elevation_gain_meters"`
+	AverageHeartRate    *int    `json:"average_heart_rate"`
+}
+
+// splitTotals accumulates a split before its averages are computed.
+type splitTotals struct {
+	distance  float64
+	moving    time.Duration
+	gain      float64
+	heartRate heartRateTotals
+}
+
+type heartRateTotals struct {
+	sum, count, max int
+}
+
+func (h *heartRateTotals) add(value *int) {
+	if value == nil || *value <= 0 {
+		return
+	}
+	h.sum += *value
+	h.count++
+	h.max = max(h.max, *value)
+}
+
+func (h heartRateTotals) average() *int {
+	if h.count == 0 {
+		return nil
+	}
+	average := int(math.Round(float64(h.sum) / float64(h.count)))
+	return &average
+}
+
+// Summarize computes the activity's totals and splits. Points must be in
+// time order, as Parse returns them.
+func Summarize(activity *Activity) *Summary {
+	points := activity.Points
+	summary := &Summary{Sport: activity.Sport, Splits: []Split{}}
+	if len(points) == 0 {
+		return summary
+	}
+
+	var moving time.Duration
+	var heartRate heartRateTotals
+	climb := newClimbCounter(points[0].Elevation)
+	current := splitTotals{}
+	heartRate.add(points[0].HeartRate)
+	current.heartRate.add(points[0].HeartRate)
+
+	for i := 1; i < len(points); i++ {
+		previous, point := points[i-1], points[i]
+		distance := segmentDistance(previous, point)
+		elapsed := point.Time.Sub(previous.Time)
+		gain := climb.add(point.Elevation)
+
+		segmentMoving := time.Duration(0)
+		if elapsed > 0 && distance/elapsed.Seconds() >= movingSpeed {
+			segmentMoving = elapsed
+			moving += elapsed
+		}
+
+		// a segment that crosses a split boundary is divided between the two
+		// splits in proportion to its distance
+		remaining := distance
+		for current.distance+remaining > SplitMeters {
+			share := (SplitMeters - current.distance) / remaining
+			current.distance = SplitMeters
+			current.moving += time.Duration(float64(segmentMoving) * share)
+			segmentMoving -= time.Duration(float64(segmentMoving) * share)
+			remaining -= remaining * share
+			summary.Splits = append(summary.Splits, current.split(len(summary.Splits)+1))
+			current = splitTotals{}
+		}
+		current.distance += remaining
+		current.moving += segmentMoving
+		current.gain += gain
+		current.heartRate.add(point.HeartRate)
+		if current.distance >= SplitMeters {
+			summary.Splits = append(summary.Splits, current.split(len(summary.Splits)+1))
+			current = splitTotals{}
+		}
+
+		summary.DistanceMeters += distance
+		summary.ElevationGainMeters += gain
+		heartRate.add(point.HeartRate)
+	}
+	if current.distance >= 1 {
+		summary.Splits = append(summary.Splits, current.split(len(summary.Splits)+1))
+	}
+
+	summary.DistanceMeters = round(summary.DistanceMeters, 1)
+	summary.ElevationGainMeters = round(summary.ElevationGainMeters, 1)
+	summary.ElapsedSeconds = int(points[len(points)-1].Time.Sub(points[0].Time).Round(time.Second) / time.Second)
+	summary.MovingSeconds = int(moving.Round(time.Second) / time.Second)
+	summary.AverageSpeedKmh, summary.AveragePaceSecondsPerKm = speedAndPace(summary.DistanceMeters, moving)
+	summary.AverageHeartRate = heartRate.average()
+	if heartRate.max > 0 {
+		summary.MaxHeartRate = &heartRate.max
+	}
+	return summary
+}
+
+func (t splitTotals) split(number int) Split {
+	speed, pace := speedAndPace(t.distance, t.moving)
+	return Split{
+		Number:              number,
+		DistanceMeters:      round(t.distance, 1),
+		MovingSeconds:       int(t.moving.Round(time.Second) / time.Second),
+		PaceSecondsPerKm:    pace,
+		SpeedKmh:            speed,
+		ElevationGainMeters: round(t.gain, 1),
+		AverageHeartRate:    t.heartRate.average(),
+	}
+}
+
+func speedAndPace(meters float64, moving time.Duration) (float64, int) {
+	if meters <= 0 || moving <= 0 {
+		return 0, 0
+	}
+	speed := round(meters/moving.Seconds()*3.6, 2)
+	pace := int(math.Round(moving.Seconds() / (meters / 1000)))
+	return speed, pace
+}
+
+// segmentDistance measures between two points along the earth's surface,
+// falling back to the device's own distance counter for indoor recordings.
+func segmentDistance(a, b Point) float64 {
+	if a.hasPosition() && b.hasPosition() {
+		return haversine(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude)
+	}
+	if a.Distance != nil && b.Distance != nil && *b.Distance > *a.Distance {
+		return *b.Distance - *a.Distance
+	}
+	return 0
+}
+
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	rad1, rad2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad1)*math.Cos(rad2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// climbCounter adds up elevation gain with hysteresis: a rise only counts
+// once it reaches climbThreshold above the lowest point since the last
+// counted climb.
+type climbCounter struct {
+	low *float64
+}
+
+func newClimbCounter(elevation *float64) *climbCounter {
+	return &climbCounter{low: copyFloat(elevation)}
+}
+
+func (c *climbCounter) add(elevation *float64) float64 {
+	if elevation == nil {
+		return 0
+	}
+	if c.low == nil || *elevation < *c.low {
+		c.low = copyFloat(elevation)
+		return 0
+	}
+	if gain := *elevation - *c.low; gain >= climbThreshold {
+		c.low = copyFloat(elevation)
+		return gain
+	}
+	return 0
+}
+
+func copyFloat(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	v := *value
+	return &v
+}
+
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,69 @@
+package track
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+// Garmin Training Center XML v2. Only the first activity in a file is read.
+type tcxDocument struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		Notes string `xml:"Notes"`
+		Laps  []struct {
+			Calories int `xml:"Calories"`
+			Tracks   []struct {
+				Points []tcxPoint `xml:"Trackpoint"`
+			} `xml:"Track"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+type tcxPoint struct {
+	Time     string `xml:"Time"`
+	Position *struct {
+		Latitude  float64 `xml:"LatitudeDegrees"`
+		Longitude float64 `xml:"LongitudeDegrees"`
+	} `xml:"Position"`
+	Altitude  *float64 `xml:"AltitudeMeters"`
+	Distance  *float64 `xml:"DistanceMeters"`
+	HeartRate *int     `xml:"HeartRateBpm>Value"`
+}
+
+func decodeTCX(decoder *xml.Decoder, start xml.StartElement) (*Activity, error) {
+	var doc tcxDocument
+	if err := decoder.DecodeElement(&doc, &start); err != nil {
+		return nil, err
+	}
+
+	activity := &Activity{Sport: SportOther, Points: []Point{}}
+	if len(doc.Activities) == 0 {
+		return activity, nil
+	}
+
+	source := doc.Activities[0]
+	activity.Name = source.Notes
+	activity.Sport = normalizeSport(source.Sport)
+	for _, lap := range source.Laps {
+		activity.Calories += lap.Calories
+		for _, trk := range lap.Tracks {
+			for _, p := range trk.Points {
+				t, ok := parseTimestamp(p.Time)
+				if !ok {
+					continue
+				}
+				point := Point{Time: t, Elevation: p.Altitude, HeartRate: p.HeartRate, Distance: p.Distance}
+				if p.Position != nil {
+					lat, lon := p.Position.Latitude, p.Position.Longitude
+					point.Latitude, point.Longitude = &lat, &lon
+				}
+				activity.Points = append(activity.Points, point)
+			}
+		}
+	}
+
+	sort.SliceStable(activity.Points, func(i, j int) bool {
+		return activity.Points[i].Time.Before(activity.Points[j].Time)
+	})
+	return activity, nil
+}
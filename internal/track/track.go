@@ -0,0 +1,116 @@
+// Package track reads GPS recordings from GPX and TCX files and summarises
+// them: distance, moving time, elevation gain, heart rate and per-kilometre
+// splits. It only depends on the standard library.
+package track
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownFormat = errors.New("file is not GPX or TCX")
+	ErrTooFewPoints  = errors.New("track needs at least two timestamped points")
+)
+
+const (
+	SportRunning  = "running"
+	SportCycling  = "cycling"
+	SportWalking  = "walking"
+	SportHiking   = "hiking"
+	SportSwimming = "swimming"
+	SportRowing   = "rowing"
+	SportOther    = "other"
+)
+
+// Point is one recorded track point. Latitude and Longitude are nil for
+// indoor recordings, which only carry the device's cumulative Distance.
+type Point struct {
+	Time      time.Time `json:"time"`
+	Latitude  *float64  `json:"latitude"`
+	Longitude *float64  `json:"longitude"`
+	Elevation *float64  `json:"elevation"`  // in meters
+	HeartRate *int      `json:"heart_rate"` // in bpm
+	Distance  *float64  `json:"distance"`   // cumulative meters as reported by the device
+}
+
+func (p Point) hasPosition() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+// Activity is a parsed recording with its points in time order.
+type Activity struct {
+	Name     string
+	Sport    string // one of the Sport constants
+	Calories int    // as reported by the device, 0 when unknown
+	Points   []Point
+}
+
+// Parse reads a GPX or TCX document, telling them apart by the root element.
+// Points without a timestamp cannot be placed on the timeline and are
+// dropped.
+func Parse(r io.Reader) (*Activity, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, ErrUnknownFormat
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var activity *Activity
+		switch start.Name.Local {
+		case "gpx":
+			activity, err = decodeGPX(decoder, start)
+		case "TrainingCenterDatabase":
+			activity, err = decodeTCX(decoder, start)
+		default:
+			return nil, ErrUnknownFormat
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(activity.Points) < 2 {
+			return nil, ErrTooFewPoints
+		}
+		return activity, nil
+	}
+}
+
+// normalizeSport maps the activity types used by GPX and TCX writers onto
+// the Sport constants.
+func normalizeSport(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "running", "run", "trail_running", "treadmill_running", "9":
+		return SportRunning
+	case "cycling", "biking", "ride", "road_biking", "mountain_biking", "1":
+		return SportCycling
+	case "walking", "walk", "10":
+		return SportWalking
+	case "hiking", "hike", "17":
+		return SportHiking
+	case "swimming", "swim", "open_water_swimming", "5":
+		return SportSwimming
+	case "rowing", "row":
+		return SportRowing
+	default:
+		return SportOther
+	}
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,95 @@
+package track
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFixture(t *testing.T, name string) *Activity {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	activity, err := Parse(file)
+	require.NoError(t, err)
+	return activity
+}
+
+func TestParseGPX(t *testing.T) {
+	activity := parseFixture(t, "run.gpx")
+
+	assert.Equal(t, "Morning Run", activity.Name)
+	assert.Equal(t, SportRunning, activity.Sport)
+	require.Len(t, activity.Points, 24, "the point without a timestamp is dropped")
+	assert.Equal(t, 140, *activity.Points[0].HeartRate, "namespaced heart rate extension is read")
+	assert.Equal(t, 400.0, *activity.Points[0].Elevation)
+
+	summary := Summarize(activity)
+	assert.InDelta(t, 2201.7, summary.DistanceMeters, 0.2)
+	assert.Equal(t, 720, summary.ElapsedSeconds)
+	assert.Equal(t, 660, summary.MovingSeconds, "the one minute stop is not moving time")
+	assert.Equal(t, 21.0, summary.ElevationGainMeters)
+	assert.Equal(t, 151, *summary.AverageHeartRate)
+	assert.Equal(t, 162, *summary.MaxHeartRate)
+	assert.Equal(t, 300, summary.AveragePaceSecondsPerKm)
+	assert.InDelta(t, 12.0, summary.AverageSpeedKmh, 0.01)
+
+	require.Len(t, summary.Splits, 3)
+	assert.Equal(t, 1000.0, summary.Splits[0].DistanceMeters)
+	assert.Equal(t, 300, summary.Splits[0].PaceSecondsPerKm)
+	assert.InDelta(t, 201.7, summary.Splits[2].DistanceMeters, 0.2)
+	var gain float64
+	for _, split := range summary.Splits {
+		gain += split.ElevationGainMeters
+	}
+	assert.Equal(t, summary.ElevationGainMeters, gain)
+}
+
+func TestParseTCX(t *testing.T) {
+	activity := parseFixture(t, "treadmill.tcx")
+
+	assert.Equal(t, "Treadmill intervals", activity.Name)
+	assert.Equal(t, SportRunning, activity.Sport)
+	assert.Equal(t, 110, activity.Calories)
+	require.Len(t, activity.Points, 11)
+	assert.Nil(t, activity.Points[0].Latitude)
+
+	summary := Summarize(activity)
+	assert.Equal(t, 2000.0, summary.DistanceMeters, "indoor distance comes from the device")
+	assert.Equal(t, 600, summary.MovingSeconds)
+	assert.Equal(t, 0.0, summary.ElevationGainMeters)
+	assert.Equal(t, 155, *summary.AverageHeartRate)
+	require.Len(t, summary.Splits, 2)
+	assert.Equal(t, 300, summary.Splits[1].PaceSecondsPerKm)
+	assert.Equal(t, 158, *summary.Splits[1].AverageHeartRate)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<?xml version="1.0"?><kml></kml>`))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = Parse(strings.NewReader(`<gpx><trk><trkseg><trkpt lat="1" lon="2"><time>2024-01-01T00:00:00Z</time></trkpt></trkseg></trk></gpx>`))
+	assert.ErrorIs(t, err, ErrTooFewPoints)
+
+	_, err = Parse(strings.NewReader(`<gpx><trk>`))
+	assert.Error(t, err)
+}
+
+func TestGeoJSON(t *testing.T) {
+	activity := parseFixture(t, "run.gpx")
+
+	feature := GeoJSON(activity.Points, map[string]any{"sport": SportRunning})
+	require.NotNil(t, feature.Geometry)
+	assert.Equal(t, "LineString", feature.Geometry.Type)
+	require.Len(t, feature.Geometry.Coordinates, 24)
+	assert.Equal(t, []float64{8.0, 47.0, 400.0}, feature.Geometry.Coordinates[0], "coordinates are longitude first")
+	assert.Equal(t, SportRunning, feature.Properties["sport"])
+
+	indoor := GeoJSON(parseFixture(t, "treadmill.tcx").Points, nil)
+	assert.Nil(t, indoor.Geometry)
+}
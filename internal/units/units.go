@@ -0,0 +1,165 @@
+// Package units converts between the metric values the API stores and the
+// unit system a user reads and writes them in.
+package units
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+var ErrUnknownSystem = errors.New("unknown unit system")
+
+type System string
+
+const (
+	Metric   System = "metric"   // kg, km and cm
+	Imperial System = "imperial" // lb, mi and in
+)
+
+const (
+	poundsPerKg   = 2.2046226218
+	cmPerInch     = 2.54
+	metersPerMile = 1609.344
+)
+
+// Parse returns the system named by value, case-insensitively.
+func Parse(value string) (System, error) {
+	switch System(strings.ToLower(strings.TrimSpace(value))) {
+	case Metric:
+		return Metric, nil
+	case Imperial:
+		return Imperial, nil
+	}
+	return "", ErrUnknownSystem
+}
+
+// WeightUnit is the abbreviation weights are shown in.
+func (s System) WeightUnit() string {
+	if s == Imperial {
+		return "lb"
+	}
+	return "kg"
+}
+
+// LengthUnit is the abbreviation body lengths are shown in.
+func (s System) LengthUnit() string {
+	if s == Imperial {
+		return "in"
+	}
+	return "cm"
+}
+
+// DistanceUnit is the abbreviation distances are shown in.
+func (s System) DistanceUnit() string {
+	if s == Imperial {
+		return "mi"
+	}
+	return "km"
+}
+
+// WeightIncrement is the step lifting weights are rounded to: the smallest
+// change a pair of fractional plates makes.
+func (s System) WeightIncrement() float64 {
+	if s == Imperial {
+		return 0.5
+	}
+	return 0.25
+}
+
+// PlateStep is the smallest jump in bar weight with standard plates: a
+// pair of 1.25 kg or 2.5 lb plates.
+func (s System) PlateStep() float64 {
+	if s == Imperial {
+		return 5
+	}
+	return 2.5
+}
+
+// Weight converts kg to the system's weight unit.
+func (s System) Weight(kg float64) float64 {
+	if s == Imperial {
+		return kg * poundsPerKg
+	}
+	return kg
+}
+
+// Kilograms converts a weight in the system's unit to kg.
+func (s System) Kilograms(weight float64) float64 {
+	if s == Imperial {
+		return weight / poundsPerKg
+	}
+	return weight
+}
+
+// Plates converts kg to the system's weight unit rounded to the nearest
+// WeightIncrement, for weights that are loaded on a bar.
+func (s System) Plates(kg float64) float64 {
+	increment := s.WeightIncrement()
+	return math.Round(s.Weight(kg)/increment) * increment
+}
+
+// StoredWeight converts a weight stored in kg, to 0.01 kg, to the system's
+// unit without losing precision: sending the result back stores the same
+// kg. The plate-rounded weight is used when it maps back to the same value,
+// otherwise the weight is given to 0.01.
+func (s System) StoredWeight(kg float64) float64 {
+	if plates := s.Plates(kg); Round(s.Kilograms(plates), 2) == Round(kg, 2) {
+		return plates
+	}
+	return Round(s.Weight(kg), 2)
+}
+
+// Length converts cm to the system's length unit.
+func (s System) Length(cm float64) float64 {
+	if s == Imperial {
+		return cm / cmPerInch
+	}
+	return cm
+}
+
+// Centimeters converts a length in the system's unit to cm.
+func (s System) Centimeters(length float64) float64 {
+	if s == Imperial {
+		return length * cmPerInch
+	}
+	return length
+}
+
+// Distance converts meters to km or mi.
+func (s System) Distance(meters float64) float64 {
+	if s == Imperial {
+		return meters / metersPerMile
+	}
+	return meters / 1000
+}
+
+// Meters converts a distance in km or mi to meters.
+func (s System) Meters(distance float64) float64 {
+	if s == Imperial {
+		return distance * metersPerMile
+	}
+	return distance * 1000
+}
+
+// Pace converts seconds per km to whole seconds per km or mi.
+func (s System) Pace(secondsPerKm int) int {
+	if s == Imperial {
+		return int(math.Round(float64(secondsPerKm) * metersPerMile / 1000))
+	}
+	return secondsPerKm
+}
+
+// SecondsPerKm converts a pace in seconds per km or mi to seconds per km.
+func (s System) SecondsPerKm(pace int) int {
+	if s == Imperial {
+		return int(math.Round(float64(pace) * 1000 / metersPerMile))
+	}
+	return pace
+}
+
+// Round rounds value to the given number of decimal places.
+func Round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
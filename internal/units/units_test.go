@@ -0,0 +1,70 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	system, err := Parse(" Imperial ")
+	require.NoError(t, err)
+	assert.Equal(t, Imperial, system)
+
+	system, err = Parse("metric")
+	require.NoError(t, err)
+	assert.Equal(t, Metric, system)
+
+	_, err = Parse("stone")
+	assert.ErrorIs(t, err, ErrUnknownSystem)
+}
+
+func TestConversions(t *testing.T) {
+	assert.Equal(t, 80.0, Metric.Weight(80))
+	assert.Equal(t, 176.4, Round(Imperial.Weight(80), 1))
+	assert.Equal(t, 90.0, Metric.Length(90))
+	assert.Equal(t, 35.4, Round(Imperial.Length(90), 1))
+
+	assert.InDelta(t, 102.06, Imperial.Kilograms(225), 0.01)
+	assert.Equal(t, 90.0, Imperial.Centimeters(Imperial.Length(90)))
+	assert.Equal(t, 5.0, Metric.Distance(5000))
+	assert.Equal(t, 3.11, Round(Imperial.Distance(5000), 2))
+	assert.InDelta(t, 5000, Imperial.Meters(Imperial.Distance(5000)), 1e-9)
+	assert.Equal(t, 300, Metric.Pace(300))
+	assert.Equal(t, 483, Imperial.Pace(300), "5:00 per km is 8:03 per mile")
+	assert.Equal(t, 300, Imperial.SecondsPerKm(483))
+
+	assert.Equal(t, "kg", Metric.WeightUnit())
+	assert.Equal(t, "lb", Imperial.WeightUnit())
+	assert.Equal(t, "cm", Metric.LengthUnit())
+	assert.Equal(t, "in", Imperial.LengthUnit())
+	assert.Equal(t, "km", Metric.DistanceUnit())
+	assert.Equal(t, "mi", Imperial.DistanceUnit())
+}
+
+func TestPlates(t *testing.T) {
+	assert.Equal(t, 102.5, Metric.Plates(102.5))
+	assert.Equal(t, 62.25, Metric.Plates(62.369), "kg weights round to 0.25")
+	assert.Equal(t, 225.0, Imperial.Plates(Imperial.Kilograms(225)), "entered pounds survive the round trip")
+	assert.Equal(t, 220.5, Imperial.Plates(100))
+	assert.Equal(t, 0.0, Imperial.Plates(0))
+	assert.Equal(t, 5.0, Imperial.Plates(Imperial.Kilograms(Imperial.PlateStep())), "a plate step survives the round trip")
+}
+
+func TestStoredWeight(t *testing.T) {
+	assert.Equal(t, 225.0, Imperial.StoredWeight(102.06), "entered pounds read back as entered")
+	assert.Equal(t, 220.46, Imperial.StoredWeight(100), "220.5 lb would store 100.02 kg")
+	assert.Equal(t, 102.3, Metric.StoredWeight(102.3), "102.25 kg would change the weight")
+	assert.Equal(t, 102.5, Metric.StoredWeight(102.5))
+
+	// every stored weight survives the round trip
+	for hundredths := 0; hundredths <= 50000; hundredths++ {
+		kg := float64(hundredths) / 100
+		for _, system := range []System{Metric, Imperial} {
+			if got := Round(system.Kilograms(system.StoredWeight(kg)), 2); got != kg {
+				t.Fatalf("%v kg in %s reads back as %v kg", kg, system, got)
+			}
+		}
+	}
+}
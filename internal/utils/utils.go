@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -41,6 +42,36 @@ func ReadIdParam(r *http.Request) (int64, error) {
 	return id, nil
 }
 
+// ReadDateParam parses a YYYY-MM-DD query parameter as midnight in loc.
+// The boolean result reports whether the parameter was present.
+func ReadDateParam(r *http.Request, name string, loc *time.Location) (time.Time, bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("invalid %s date, expected YYYY-MM-DD: %w", name, err)
+	}
+	return date, true, nil
+}
+
+// ReadLimitParam parses the limit query parameter, falling back to
+// defaultLimit when it is absent and capping it at maxLimit.
+func ReadLimitParam(r *http.Request, defaultLimit, maxLimit int) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	return min(limit, maxLimit), nil
+}
+
 func IntPtr(i int) *int {
 	return &i
 }
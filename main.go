@@ -54,6 +54,8 @@ func main() {
 	}
 	defer app.DB.Close()
 
+	app.StartBackgroundJobs()
+
 	r := routes.InitializeRoutes(app)
 
 	c := cors.New(cors.Options{
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS personal_records (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    exercise_name VARCHAR(255) NOT NULL,
+    record_type VARCHAR(32) NOT NULL,
+    value DECIMAL(10, 2) NOT NULL,
+    weight DECIMAL(5, 2),
+    reps INTEGER,
+    achieved_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_record_type CHECK (
+        record_type IN ('max_weight', 'estimated_1rm', 'max_reps', 'max_duration', 'max_volume')
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_exercise
+    ON personal_records (user_id, LOWER(exercise_name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS personal_records;
+-- +goose StatementEnd
\ No newline at end of file
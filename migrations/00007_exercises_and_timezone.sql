@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+CREATE TABLE IF NOT EXISTS exercises (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    muscle_group VARCHAR(50) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_exercise_category CHECK (category IN ('strength', 'cardio'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_name ON exercises (LOWER(name));
+
+INSERT INTO exercises (name, muscle_group, category) VALUES
+    ('Bench Press', 'chest', 'strength'),
+    ('Incline Bench Press', 'chest', 'strength'),
+    ('Dumbbell Bench Press', 'chest', 'strength'),
+    ('Push Ups', 'chest', 'strength'),
+    ('Dips', 'chest', 'strength'),
+    ('Squat', 'legs', 'strength'),
+    ('Front Squat', 'legs', 'strength'),
+    ('Leg Press', 'legs', 'strength'),
+    ('Lunges', 'legs', 'strength'),
+    ('Romanian Deadlift', 'legs', 'strength'),
+    ('Leg Curl', 'legs', 'strength'),
+    ('Leg Extension', 'legs', 'strength'),
+    ('Calf Raise', 'legs', 'strength'),
+    ('Deadlift', 'back', 'strength'),
+    ('Barbell Row', 'back', 'strength'),
+    ('Pull Ups', 'back', 'strength'),
+    ('Chin Ups', 'back', 'strength'),
+    ('Lat Pulldown', 'back', 'strength'),
+    ('Seated Cable Row', 'back', 'strength'),
+    ('Overhead Press', 'shoulders', 'strength'),
+    ('Lateral Raise', 'shoulders', 'strength'),
+    ('Face Pull', 'shoulders', 'strength'),
+    ('Barbell Curl', 'arms', 'strength'),
+    ('Dumbbell Curl', 'arms', 'strength'),
+    ('Tricep Pushdown', 'arms', 'strength'),
+    ('Skull Crusher', 'arms', 'strength'),
+    ('Plank', 'core', 'strength'),
+    ('Crunches', 'core', 'strength'),
+    ('Hanging Leg Raise', 'core', 'strength'),
+    ('Running', 'cardio', 'cardio'),
+    ('Cycling', 'cardio', 'cardio'),
+    ('Rowing', 'cardio', 'cardio'),
+    ('Swimming', 'cardio', 'cardio'),
+    ('Walking', 'cardio', 'cardio'),
+    ('Jump Rope', 'cardio', 'cardio'),
+    ('Elliptical', 'cardio', 'cardio')
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exercises;
+
+ALTER TABLE users
+    DROP COLUMN timezone;
+-- +goose StatementEnd
\ No newline at end of file
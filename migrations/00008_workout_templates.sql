@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS workout_templates (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workout_template_entries (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES workout_templates(id) ON DELETE CASCADE,
+    exercise_name VARCHAR(255) NOT NULL,
+    target_sets INTEGER NOT NULL,
+    target_reps INTEGER,
+    target_duration_seconds INTEGER,
+    target_weight DECIMAL(5, 2),
+    notes TEXT,
+    order_index INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_template_entry CHECK (
+        (target_sets IS NOT NULL AND target_reps IS NOT NULL) OR
+        (target_duration_seconds IS NOT NULL) OR
+        (target_weight IS NOT NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_templates_user_id ON workout_templates (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_template_entries;
+DROP TABLE IF EXISTS workout_templates;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS programs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    weeks INTEGER NOT NULL CHECK (weeks > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS program_days (
+    id BIGSERIAL PRIMARY KEY,
+    program_id BIGINT NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    week_number INTEGER,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+    template_id BIGINT NOT NULL REFERENCES workout_templates(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS program_progressions (
+    id BIGSERIAL PRIMARY KEY,
+    program_id BIGINT NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    exercise_name VARCHAR(255) NOT NULL,
+    weekly_increment DECIMAL(5, 2) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS program_enrollments (
+    id BIGSERIAL PRIMARY KEY,
+    program_id BIGINT NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_enrollment_status CHECK (status IN ('active', 'completed', 'cancelled'))
+);
+
+CREATE TABLE IF NOT EXISTS planned_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    enrollment_id BIGINT NOT NULL REFERENCES program_enrollments(id) ON DELETE CASCADE,
+    template_id BIGINT NOT NULL REFERENCES workout_templates(id) ON DELETE CASCADE,
+    scheduled_date DATE NOT NULL,
+    week_number INTEGER NOT NULL,
+    workout_id BIGINT REFERENCES workouts(id) ON DELETE SET NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_program_enrollments_user_id ON program_enrollments (user_id);
+CREATE INDEX IF NOT EXISTS idx_planned_sessions_enrollment_date ON planned_sessions (enrollment_id, scheduled_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS planned_sessions;
+DROP TABLE IF EXISTS program_enrollments;
+DROP TABLE IF EXISTS program_progressions;
+DROP TABLE IF EXISTS program_days;
+DROP TABLE IF EXISTS programs;
+-- +goose StatementEnd
\ No newline at end of file
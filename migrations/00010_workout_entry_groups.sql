@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS workout_entry_groups (
+    id BIGSERIAL PRIMARY KEY,
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    group_type VARCHAR(20) NOT NULL,
+    rounds INTEGER NOT NULL DEFAULT 1 CHECK (rounds > 0),
+    rest_between_exercises_seconds INTEGER CHECK (rest_between_exercises_seconds >= 0),
+    rest_between_rounds_seconds INTEGER CHECK (rest_between_rounds_seconds >= 0),
+    order_index INTEGER NOT NULL,
+    CONSTRAINT valid_group_type CHECK (group_type IN ('superset', 'circuit', 'giant_set'))
+);
+
+ALTER TABLE workout_entries
+    ADD COLUMN group_id BIGINT REFERENCES workout_entry_groups(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workout_entries
+    DROP COLUMN group_id;
+
+DROP TABLE IF EXISTS workout_entry_groups;
+-- +goose StatementEnd
\ No newline at end of file
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workouts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'completed',
+    ADD COLUMN started_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN ended_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT valid_workout_status CHECK (status IN ('active', 'completed'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_one_active_per_user
+    ON workouts (user_id) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS workout_sets (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES workout_entries(id) ON DELETE CASCADE,
+    set_number INTEGER NOT NULL,
+    reps INTEGER,
+    weight DECIMAL(5, 2),
+    duration_seconds INTEGER,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_workout_set CHECK (
+        reps IS NOT NULL OR duration_seconds IS NOT NULL OR weight IS NOT NULL
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_sets_entry_id ON workout_sets (entry_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_sets;
+
+DROP INDEX IF EXISTS idx_workouts_one_active_per_user;
+
+ALTER TABLE workouts
+    DROP CONSTRAINT valid_workout_status,
+    DROP COLUMN ended_at,
+    DROP COLUMN started_at,
+    DROP COLUMN status;
+-- +goose StatementEnd
\ No newline at end of file
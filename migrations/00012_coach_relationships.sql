@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS coach_relationships (
+    id BIGSERIAL PRIMARY KEY,
+    coach_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    athlete_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invited_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT valid_coach_permission CHECK (permission IN ('read', 'read_write')),
+    CONSTRAINT valid_coach_status CHECK (status IN ('pending', 'accepted')),
+    CONSTRAINT coach_is_not_athlete CHECK (coach_id <> athlete_id),
+    CONSTRAINT unique_coach_athlete UNIQUE (coach_id, athlete_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coach_relationships_athlete_id ON coach_relationships (athlete_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS coach_relationships;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workouts
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'private',
+    ADD CONSTRAINT valid_workout_visibility CHECK (visibility IN ('private', 'followers', 'public'));
+
+CREATE INDEX IF NOT EXISTS idx_workouts_user_created ON workouts (user_id, created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT no_self_follow CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows (followee_id);
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT no_self_block CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_id ON user_blocks (blocked_id);
+
+CREATE TABLE IF NOT EXISTS workout_likes (
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workout_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS workout_comments (
+    id BIGSERIAL PRIMARY KEY,
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT comment_not_empty CHECK (LENGTH(TRIM(body)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_comments_workout_id ON workout_comments (workout_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_comments;
+DROP TABLE IF EXISTS workout_likes;
+DROP TABLE IF EXISTS user_blocks;
+DROP TABLE IF EXISTS follows;
+
+DROP INDEX IF EXISTS idx_workouts_user_created;
+
+ALTER TABLE workouts
+    DROP CONSTRAINT valid_workout_visibility,
+    DROP COLUMN visibility;
+-- +goose StatementEnd
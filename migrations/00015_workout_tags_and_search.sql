@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS workout_tags (
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (workout_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_tags_tag_id ON workout_tags (tag_id);
+
+-- Entry text lives in another table, so the vector is maintained by the store
+-- rather than a generated column.
+ALTER TABLE workouts ADD COLUMN search_vector TSVECTOR NOT NULL DEFAULT ''::tsvector;
+
+UPDATE workouts w SET search_vector =
+    setweight(to_tsvector('english', w.title), 'A') ||
+    setweight(to_tsvector('english', COALESCE((SELECT string_agg(e.exercise_name, ' ') FROM workout_entries e WHERE e.workout_id = w.id), '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(w.description, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE((SELECT string_agg(e.notes, ' ') FROM workout_entries e WHERE e.workout_id = w.id), '')), 'D');
+
+CREATE INDEX IF NOT EXISTS idx_workouts_search_vector ON workouts USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_workouts_search_vector;
+ALTER TABLE workouts DROP COLUMN search_vector;
+
+DROP TABLE IF EXISTS workout_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
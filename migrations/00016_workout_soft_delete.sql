@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workouts ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_workouts_trash ON workouts (user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+
+-- A trashed session no longer blocks starting a new one.
+DROP INDEX IF EXISTS idx_workouts_one_active_per_user;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_one_active_per_user
+    ON workouts (user_id) WHERE status = 'active' AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM workouts WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_workouts_one_active_per_user;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_one_active_per_user
+    ON workouts (user_id) WHERE status = 'active';
+
+DROP INDEX IF EXISTS idx_workouts_trash;
+ALTER TABLE workouts DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS workout_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    snapshot JSONB NOT NULL,
+    diff JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workout_id, revision),
+    CONSTRAINT valid_revision_action CHECK (action IN ('baseline', 'create', 'update', 'delete', 'restore', 'revert'))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS workout_tracks (
+    workout_id BIGINT PRIMARY KEY REFERENCES workouts(id) ON DELETE CASCADE,
+    sport VARCHAR(20) NOT NULL,
+    distance_meters DOUBLE PRECISION NOT NULL,
+    elapsed_seconds INTEGER NOT NULL,
+    moving_seconds INTEGER NOT NULL,
+    elevation_gain_meters DOUBLE PRECISION NOT NULL,
+    average_speed_kmh DOUBLE PRECISION NOT NULL,
+    average_pace_seconds_per_km INTEGER NOT NULL,
+    average_heart_rate INTEGER,
+    max_heart_rate INTEGER,
+    splits JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workout_track_points (
+    workout_id BIGINT NOT NULL REFERENCES workout_tracks(workout_id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    elevation DOUBLE PRECISION,
+    heart_rate INTEGER,
+    distance_meters DOUBLE PRECISION,
+    PRIMARY KEY (workout_id, seq)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_track_points;
+DROP TABLE IF EXISTS workout_tracks;
+-- +goose StatementEnd
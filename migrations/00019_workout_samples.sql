@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN birth_year INTEGER,
+    ADD COLUMN sex VARCHAR(10),
+    ADD COLUMN weight_kg DECIMAL(5,2),
+    ADD COLUMN resting_heart_rate INTEGER,
+    ADD COLUMN max_heart_rate INTEGER,
+    ADD CONSTRAINT valid_user_sex CHECK (sex IN ('male', 'female'));
+
+-- calories_source is NULL when the client supplied calories_burned and names
+-- the estimate otherwise
+ALTER TABLE workouts
+    ADD COLUMN calories_source VARCHAR(20),
+    ADD CONSTRAINT valid_calories_source CHECK (calories_source IN ('heart_rate'));
+
+CREATE TABLE IF NOT EXISTS workout_samples (
+    workout_id BIGINT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    heart_rate SMALLINT,
+    power SMALLINT,
+    cadence SMALLINT,
+    PRIMARY KEY (workout_id, recorded_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_samples;
+
+ALTER TABLE workouts
+    DROP COLUMN calories_source;
+
+ALTER TABLE users
+    DROP COLUMN birth_year,
+    DROP COLUMN sex,
+    DROP COLUMN weight_kg,
+    DROP COLUMN resting_heart_rate,
+    DROP COLUMN max_heart_rate;
+-- +goose StatementEnd
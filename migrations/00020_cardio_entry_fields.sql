@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workout_entries
+    ADD COLUMN distance_meters DOUBLE PRECISION,
+    ADD COLUMN pace_seconds_per_km INTEGER,
+    ADD COLUMN incline_percent DECIMAL(4,1),
+    ADD COLUMN resistance_level INTEGER,
+    ADD COLUMN interval_repeats INTEGER,
+    ADD COLUMN interval_work_seconds INTEGER,
+    ADD COLUMN interval_work_meters DOUBLE PRECISION,
+    ADD COLUMN interval_rest_seconds INTEGER,
+    ADD CONSTRAINT valid_cardio_metrics CHECK (
+        (distance_meters IS NULL OR distance_meters > 0) AND
+        (pace_seconds_per_km IS NULL OR pace_seconds_per_km > 0) AND
+        (incline_percent IS NULL OR incline_percent BETWEEN -20 AND 40) AND
+        (resistance_level IS NULL OR resistance_level BETWEEN 0 AND 100)
+    ),
+    ADD CONSTRAINT valid_entry_intervals CHECK (
+        interval_repeats IS NULL OR (
+            interval_repeats > 0 AND
+            (interval_work_seconds > 0 OR interval_work_meters > 0) AND
+            interval_rest_seconds >= 0
+        )
+    );
+
+ALTER TABLE workout_entries
+    DROP CONSTRAINT valid_workout_entry,
+    ADD CONSTRAINT valid_workout_entry CHECK (
+        (sets IS NOT NULL AND reps IS NOT NULL) OR
+        (duration_seconds IS NOT NULL) OR
+        (weight IS NOT NULL) OR
+        (distance_meters IS NOT NULL) OR
+        (interval_repeats IS NOT NULL)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM workout_entries
+WHERE reps IS NULL AND duration_seconds IS NULL AND weight IS NULL;
+
+ALTER TABLE workout_entries
+    DROP CONSTRAINT valid_workout_entry,
+    ADD CONSTRAINT valid_workout_entry CHECK (
+        (sets IS NOT NULL AND reps IS NOT NULL) OR
+        (duration_seconds IS NOT NULL) OR
+        (weight IS NOT NULL)
+    );
+
+ALTER TABLE workout_entries
+    DROP CONSTRAINT valid_entry_intervals,
+    DROP CONSTRAINT valid_cardio_metrics,
+    DROP COLUMN distance_meters,
+    DROP COLUMN pace_seconds_per_km,
+    DROP COLUMN incline_percent,
+    DROP COLUMN resistance_level,
+    DROP COLUMN interval_repeats,
+    DROP COLUMN interval_work_seconds,
+    DROP COLUMN interval_work_meters,
+    DROP COLUMN interval_rest_seconds;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workouts
+    DROP CONSTRAINT valid_calories_source,
+    ADD CONSTRAINT valid_calories_source CHECK (calories_source IN ('heart_rate', 'met'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE workouts SET calories_burned = 0, calories_source = NULL WHERE calories_source = 'met';
+
+ALTER TABLE workouts
+    DROP CONSTRAINT valid_calories_source,
+    ADD CONSTRAINT valid_calories_source CHECK (calories_source IN ('heart_rate'));
+-- +goose StatementEnd
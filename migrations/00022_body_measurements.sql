@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS body_measurements (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    measured_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    weight_kg DECIMAL(5,2),
+    body_fat_percent DECIMAL(4,1),
+    neck_cm DECIMAL(5,1),
+    chest_cm DECIMAL(5,1),
+    waist_cm DECIMAL(5,1),
+    hips_cm DECIMAL(5,1),
+    arm_cm DECIMAL(5,1),
+    thigh_cm DECIMAL(5,1),
+    calf_cm DECIMAL(5,1),
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT body_measurement_has_value CHECK (
+        COALESCE(weight_kg, body_fat_percent, neck_cm, chest_cm, waist_cm, hips_cm, arm_cm, thigh_cm, calf_cm) IS NOT NULL
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_measurements_user ON body_measurements (user_id, measured_at DESC);
+
+-- bodyweight exercises count the lifter's body weight, plus any added
+-- weight, towards volume
+ALTER TABLE exercises
+    ADD COLUMN bodyweight BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE exercises SET bodyweight = TRUE
+WHERE LOWER(name) IN ('push ups', 'dips', 'pull ups', 'chin ups', 'hanging leg raise', 'crunches');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE exercises
+    DROP COLUMN bodyweight;
+
+DROP TABLE IF EXISTS body_measurements;
+-- +goose StatementEnd
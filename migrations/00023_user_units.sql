@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- values are stored in metric; units only chooses how the API reads and
+-- writes them for the user
+ALTER TABLE users
+    ADD COLUMN units VARCHAR(10) NOT NULL DEFAULT 'metric',
+    ADD CONSTRAINT valid_units CHECK (units IN ('metric', 'imperial'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN units;
+-- +goose StatementEnd
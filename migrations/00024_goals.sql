@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS goals (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    goal_type VARCHAR(20) NOT NULL,
+    exercise_name VARCHAR(255),
+    target DECIMAL(10, 2) NOT NULL,
+    period VARCHAR(10),
+    deadline DATE,
+    current_value DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    achieved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_goal_type CHECK (goal_type IN ('workouts', 'distance', 'lift')),
+    CONSTRAINT valid_goal_period CHECK (period IN ('week', 'month')),
+    CONSTRAINT positive_goal_target CHECK (target > 0),
+    CONSTRAINT lift_goal_has_exercise CHECK (goal_type <> 'lift' OR exercise_name IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_user ON goals (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS goals;
+-- +goose StatementEnd
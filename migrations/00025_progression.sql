@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workout_entries
+    ADD COLUMN rpe DECIMAL(3,1),
+    ADD CONSTRAINT valid_entry_rpe CHECK (rpe IS NULL OR rpe BETWEEN 1 AND 10);
+
+ALTER TABLE workout_sets
+    ADD COLUMN rpe DECIMAL(3,1),
+    ADD CONSTRAINT valid_set_rpe CHECK (rpe IS NULL OR rpe BETWEEN 1 AND 10);
+
+-- per-exercise progression rules; exercises without a row use the defaults
+CREATE TABLE IF NOT EXISTS progression_rules (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_name VARCHAR(255) NOT NULL,
+    scheme VARCHAR(10) NOT NULL,
+    rep_min INTEGER NOT NULL,
+    rep_max INTEGER NOT NULL,
+    increment DECIMAL(5,2) NOT NULL,
+    target_rpe DECIMAL(3,1) NOT NULL,
+    deload_after INTEGER NOT NULL,
+    deload_percent DECIMAL(4,1) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_progression_scheme CHECK (scheme IN ('double', 'linear', 'rpe')),
+    CONSTRAINT valid_progression_reps CHECK (rep_min >= 1 AND rep_max >= rep_min)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_progression_rules_user_exercise
+    ON progression_rules (user_id, LOWER(exercise_name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS progression_rules;
+
+ALTER TABLE workout_sets
+    DROP COLUMN rpe;
+
+ALTER TABLE workout_entries
+    DROP COLUMN rpe;
+-- +goose StatementEnd
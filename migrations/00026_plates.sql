@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- plate inventories are kept in the unit the plates are marked in, unlike
+-- other weights, so a 45 lb plate stays a round number
+CREATE TABLE IF NOT EXISTS plate_inventories (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    unit VARCHAR(2) NOT NULL,
+    bar_weight DECIMAL(6,3) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_plate_unit CHECK (unit IN ('kg', 'lb')),
+    CONSTRAINT valid_bar_weight CHECK (bar_weight >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS plate_inventory_plates (
+    user_id BIGINT NOT NULL REFERENCES plate_inventories(user_id) ON DELETE CASCADE,
+    weight DECIMAL(6,3) NOT NULL,
+    count INTEGER NOT NULL,
+    PRIMARY KEY (user_id, weight),
+    CONSTRAINT valid_plate CHECK (weight > 0 AND count >= 0)
+);
+
+-- warm-up sets are logged with the working sets but do not count towards the
+-- entry's summary
+ALTER TABLE workout_sets
+    ADD COLUMN warmup BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workout_sets
+    DROP COLUMN warmup;
+
+DROP TABLE IF EXISTS plate_inventory_plates;
+DROP TABLE IF EXISTS plate_inventories;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workout_sets
+    ADD COLUMN started_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT valid_set_times CHECK (started_at IS NULL OR started_at <= completed_at);
+
+ALTER TABLE workout_entries
+    ADD COLUMN target_rest_seconds INTEGER,
+    ADD CONSTRAINT valid_target_rest CHECK (target_rest_seconds IS NULL OR target_rest_seconds BETWEEN 0 AND 3600);
+
+-- per-exercise rest targets; entries without their own target use these,
+-- and exercises without a row use the default
+CREATE TABLE IF NOT EXISTS exercise_rest_targets (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_name VARCHAR(255) NOT NULL,
+    rest_seconds INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_rest_seconds CHECK (rest_seconds BETWEEN 0 AND 3600)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercise_rest_targets_user_exercise
+    ON exercise_rest_targets (user_id, LOWER(exercise_name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exercise_rest_targets;
+
+ALTER TABLE workout_entries
+    DROP COLUMN target_rest_seconds;
+
+ALTER TABLE workout_sets
+    DROP COLUMN started_at;
+-- +goose StatementEnd
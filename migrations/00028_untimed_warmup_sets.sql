@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- generated warm-up sets are added before they are performed, so they have
+-- no completion time until the client logs one
+ALTER TABLE workout_sets ALTER COLUMN completed_at DROP NOT NULL;
+
+UPDATE workout_sets SET started_at = NULL, completed_at = NULL WHERE warmup;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE workout_sets SET completed_at = created_at WHERE completed_at IS NULL;
+
+ALTER TABLE workout_sets ALTER COLUMN completed_at SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleting a template used by a program would take its planned sessions and
+-- their adherence history with it, so such templates can no longer be
+-- deleted. NO ACTION rather than RESTRICT lets deleting a user still cascade
+-- through their programs and templates in one statement.
+ALTER TABLE program_days
+    DROP CONSTRAINT program_days_template_id_fkey,
+    ADD CONSTRAINT program_days_template_id_fkey FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE NO ACTION;
+
+ALTER TABLE planned_sessions
+    DROP CONSTRAINT planned_sessions_template_id_fkey,
+    ADD CONSTRAINT planned_sessions_template_id_fkey FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE NO ACTION;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE program_days
+    DROP CONSTRAINT program_days_template_id_fkey,
+    ADD CONSTRAINT program_days_template_id_fkey FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE CASCADE;
+
+ALTER TABLE planned_sessions
+    DROP CONSTRAINT planned_sessions_template_id_fkey,
+    ADD CONSTRAINT planned_sessions_template_id_fkey FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the template a workout follows matches it to the planned session it
+-- fulfils once it is completed
+ALTER TABLE workouts ADD COLUMN template_id BIGINT REFERENCES workout_templates(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workouts DROP COLUMN template_id;
+-- +goose StatementEnd
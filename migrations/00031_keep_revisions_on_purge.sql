@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- revisions outlive their workout so purging the trash keeps the audit
+-- history; they belong to the workout's owner instead, so deleting the user
+-- still removes them
+ALTER TABLE workout_revisions ADD COLUMN user_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+UPDATE workout_revisions r SET user_id = w.user_id FROM workouts w WHERE w.id = r.workout_id;
+ALTER TABLE workout_revisions
+    ALTER COLUMN user_id SET NOT NULL,
+    DROP CONSTRAINT workout_revisions_workout_id_fkey;
+CREATE INDEX IF NOT EXISTS idx_workout_revisions_user_id ON workout_revisions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM workout_revisions r WHERE NOT EXISTS (SELECT 1 FROM workouts w WHERE w.id = r.workout_id);
+ALTER TABLE workout_revisions
+    ADD CONSTRAINT workout_revisions_workout_id_fkey FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE,
+    DROP COLUMN user_id;
+-- +goose StatementEnd